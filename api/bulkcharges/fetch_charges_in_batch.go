@@ -5,7 +5,9 @@ import (
 	"net/url"
 	"strconv"
 
+	"github.com/huysamen/paystack-go/enums"
 	"github.com/huysamen/paystack-go/net"
+	"github.com/huysamen/paystack-go/optional"
 	"github.com/huysamen/paystack-go/types"
 )
 
@@ -27,8 +29,8 @@ func NewFetchInBatchRequestBuilder() *FetchInBatchRequestBuilder {
 	}
 }
 
-func (b *FetchInBatchRequestBuilder) Status(status string) *FetchInBatchRequestBuilder {
-	b.req.Status = &status
+func (b *FetchInBatchRequestBuilder) Status(status enums.BulkChargeStatus) *FetchInBatchRequestBuilder {
+	b.req.Status = optional.String(status.String())
 
 	return b
 }
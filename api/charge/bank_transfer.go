@@ -0,0 +1,337 @@
+package charge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/huysamen/paystack-go/enums"
+	"github.com/huysamen/paystack-go/types"
+)
+
+// DefaultBankTransferGrace is how long a BankTransferWatcher keeps checking after the
+// account expires, for transfers made just before the expiry that are still in flight
+const DefaultBankTransferGrace = time.Minute
+
+// ErrNoTransferAccount is returned for charges without a temporary account to pay into
+var ErrNoTransferAccount = errors.New("charge has no bank transfer account")
+
+// BankTransferAccount is the temporary account a customer pays into for a bank transfer
+// charge
+type BankTransferAccount struct {
+	Reference     string
+	AccountName   string
+	AccountNumber string
+	BankName      string
+	BankSlug      string
+	// Amount is the amount the customer must transfer, in the subunit of the currency
+	Amount    int64
+	ExpiresAt time.Time
+	// DisplayText is Paystack's instruction for the customer
+	DisplayText string
+
+	// now is the clock for the expiry, time.Now when nil
+	now func() time.Time
+}
+
+// NewBankTransferAccount reads the temporary account from the response to a charge created
+// with BankTransferDetails
+func NewBankTransferAccount(charge *types.Charge) (*BankTransferAccount, error) {
+	if charge == nil || charge.AccountNumber.Str == "" || !charge.AccountExpiresAt.Valid {
+		return nil, ErrNoTransferAccount
+	}
+
+	account := &BankTransferAccount{
+		Reference:     charge.Reference.String(),
+		AccountName:   charge.AccountName.Str,
+		AccountNumber: charge.AccountNumber.Str,
+		Amount:        charge.Amount.Int64(),
+		ExpiresAt:     charge.AccountExpiresAt.Time,
+		DisplayText:   charge.DisplayText.Str,
+	}
+	if requested := charge.RequestedAmount.Int64(); requested > 0 {
+		account.Amount = requested
+	}
+	if charge.Bank != nil {
+		account.BankName = charge.Bank.Name.String()
+		account.BankSlug = charge.Bank.Slug.String()
+	}
+
+	return account, nil
+}
+
+// Remaining returns how long the customer has left to pay, or 0 once the account expired
+func (a *BankTransferAccount) Remaining() time.Duration {
+	return max(a.ExpiresAt.Sub(a.clock()), 0)
+}
+
+// Expired reports whether the account can no longer be paid into
+func (a *BankTransferAccount) Expired() bool {
+	return !a.clock().Before(a.ExpiresAt)
+}
+
+// clock returns the current time. BankTransferWatcher uses it too, so the account and its
+// watcher agree on when it expires.
+func (a *BankTransferAccount) clock() time.Time {
+	if a.now == nil {
+		return time.Now()
+	}
+
+	return a.now()
+}
+
+// BankTransferOutcome is how a bank transfer charge ended
+type BankTransferOutcome string
+
+const (
+	BankTransferPaid      BankTransferOutcome = "paid"
+	BankTransferUnderpaid BankTransferOutcome = "underpaid"
+	BankTransferOverpaid  BankTransferOutcome = "overpaid"
+	BankTransferFailed    BankTransferOutcome = "failed"
+	BankTransferExpired   BankTransferOutcome = "expired"
+)
+
+// BankTransferResult is the outcome of watching a bank transfer charge
+type BankTransferResult struct {
+	Outcome BankTransferOutcome
+	Account *BankTransferAccount
+	// Charge is the last state of the charge, or nil if the outcome came from a webhook
+	// before any check succeeded
+	Charge *types.Charge
+	// Expected is the amount the customer was asked to pay and Paid the amount received,
+	// taken from the charge's requested_amount and amount once it is final
+	Expected int64
+	Paid     int64
+}
+
+// Difference returns the amount paid over the expected amount, negative when underpaid
+func (r *BankTransferResult) Difference() int64 {
+	return r.Paid - r.Expected
+}
+
+// BankTransferWatcher watches bank transfer charges until they are paid or their
+// account expires. It checks each charge with WaitForCompletion and ends early when a
+// charge.success webhook is passed to Notify.
+//
+// A BankTransferWatcher is safe for concurrent use.
+type BankTransferWatcher struct {
+	client      *Client
+	interval    time.Duration
+	maxInterval time.Duration
+	grace       time.Duration
+
+	mu       sync.Mutex
+	watchers map[string][]chan int64
+}
+
+// NewBankTransferWatcher creates a BankTransferWatcher that checks charges with client
+func NewBankTransferWatcher(client *Client) *BankTransferWatcher {
+	return &BankTransferWatcher{
+		client:      client,
+		interval:    DefaultWaitInterval,
+		maxInterval: DefaultWaitMaxInterval,
+		grace:       DefaultBankTransferGrace,
+		watchers:    make(map[string][]chan int64),
+	}
+}
+
+// Interval sets the delay before the first check
+func (w *BankTransferWatcher) Interval(d time.Duration) *BankTransferWatcher {
+	w.interval = d
+
+	return w
+}
+
+// MaxInterval sets the longest delay between checks
+func (w *BankTransferWatcher) MaxInterval(d time.Duration) *BankTransferWatcher {
+	w.maxInterval = d
+
+	return w
+}
+
+// Grace sets how long checks continue after the account expires
+func (w *BankTransferWatcher) Grace(d time.Duration) *BankTransferWatcher {
+	w.grace = d
+
+	return w
+}
+
+// Notify reports a charge.success webhook for reference with the amount received, e.g.
+// from a webhook.Handler's OnChargeSuccess. It returns false when no Watch is waiting for
+// the reference; the charge is then found by the next check of a later Watch.
+func (w *BankTransferWatcher) Notify(reference string, amount int64) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	watchers := w.watchers[reference]
+	for _, ch := range watchers {
+		select {
+		case ch <- amount:
+		default:
+		}
+	}
+
+	return len(watchers) > 0
+}
+
+// Watch waits until the customer pays into account, the charge fails or the account
+// expires, and returns the outcome. An amount paid that differs from the amount requested
+// is reported as BankTransferUnderpaid or BankTransferOverpaid; an account that expires
+// unpaid as BankTransferExpired. Neither is an error.
+//
+// The charge is checked with WaitForCompletion, so failed checks are retried until the
+// account expires. A response that rejects the reference, or the end of ctx, returns an
+// error.
+func (w *BankTransferWatcher) Watch(ctx context.Context, account *BankTransferAccount) (*BankTransferResult, error) {
+	ch := w.subscribe(account.Reference)
+	defer w.unsubscribe(account.Reference, ch)
+
+	waitCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	notified := make(chan int64, 1)
+	go func() {
+		select {
+		case amount := <-ch:
+			notified <- amount
+			cancel()
+		case <-waitCtx.Done():
+		}
+	}()
+
+	var (
+		last *types.Charge
+		err  error
+	)
+
+	if remaining := account.ExpiresAt.Add(w.grace).Sub(account.clock()); remaining > 0 {
+		opts := NewWaitOptions().Interval(max(w.interval, time.Millisecond)).MaxInterval(max(w.maxInterval, w.interval)).Timeout(remaining)
+		last, err = w.client.WaitForCompletion(waitCtx, account.Reference, *opts)
+	} else {
+		err = ErrWaitTimeout
+	}
+
+	select {
+	case amount := <-notified:
+		expected := expectedAmount(account, last)
+
+		return &BankTransferResult{
+			Outcome:  paymentOutcome(expected, amount),
+			Account:  account,
+			Charge:   last,
+			Expected: expected,
+			Paid:     amount,
+		}, nil
+	default:
+	}
+
+	switch {
+	case ctx.Err() != nil:
+		return nil, ctx.Err()
+	case errors.Is(err, ErrWaitTimeout):
+		// One last check, in case the payment landed while waiting
+		charge, err := w.check(ctx, account.Reference)
+		if err != nil && !errors.Is(err, errCheckFailed) {
+			return nil, err
+		}
+		if charge != nil {
+			last = charge
+		}
+		if last != nil && last.Status.IsTerminal() {
+			return w.result(account, last), nil
+		}
+
+		return &BankTransferResult{Outcome: BankTransferExpired, Account: account, Charge: last, Expected: expectedAmount(account, last)}, nil
+	case err != nil:
+		return nil, err
+	}
+
+	return w.result(account, last), nil
+}
+
+// errCheckFailed marks checks that can be retried
+var errCheckFailed = errors.New("check failed")
+
+func (w *BankTransferWatcher) check(ctx context.Context, reference string) (*types.Charge, error) {
+	rsp, err := w.client.CheckPending(ctx, *NewCheckPendingChargeRequestBuilder(reference))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errCheckFailed, err)
+	}
+	if err := rsp.Err(); err != nil {
+		return nil, fmt.Errorf("checking charge %s: %w", reference, err)
+	}
+
+	return &rsp.Data, nil
+}
+
+// result reads the outcome from the final charge. The charge's amount is what Paystack
+// received from the customer, so a transfer of the wrong amount is found by comparing it
+// with the expected amount.
+func (w *BankTransferWatcher) result(account *BankTransferAccount, charge *types.Charge) *BankTransferResult {
+	result := &BankTransferResult{
+		Outcome:  BankTransferFailed,
+		Account:  account,
+		Charge:   charge,
+		Expected: expectedAmount(account, charge),
+	}
+
+	if charge.Status == enums.ChargeStatusSuccess {
+		result.Paid = charge.Amount.Int64()
+		result.Outcome = paymentOutcome(result.Expected, result.Paid)
+	}
+
+	return result
+}
+
+func (w *BankTransferWatcher) subscribe(reference string) chan int64 {
+	ch := make(chan int64, 1)
+
+	w.mu.Lock()
+	w.watchers[reference] = append(w.watchers[reference], ch)
+	w.mu.Unlock()
+
+	return ch
+}
+
+func (w *BankTransferWatcher) unsubscribe(reference string, ch chan int64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	watchers := w.watchers[reference]
+	for i, c := range watchers {
+		if c == ch {
+			watchers = append(watchers[:i], watchers[i+1:]...)
+			break
+		}
+	}
+
+	if len(watchers) == 0 {
+		delete(w.watchers, reference)
+	} else {
+		w.watchers[reference] = watchers
+	}
+}
+
+// expectedAmount returns the amount the customer was asked to pay: the requested amount of
+// the last known charge, or the account's amount. Outcomes from webhooks and checks both
+// use it, so they agree on the same payment.
+func expectedAmount(account *BankTransferAccount, charge *types.Charge) int64 {
+	if charge != nil && charge.RequestedAmount.Int64() > 0 {
+		return charge.RequestedAmount.Int64()
+	}
+
+	return account.Amount
+}
+
+func paymentOutcome(expected, paid int64) BankTransferOutcome {
+	switch {
+	case paid < expected:
+		return BankTransferUnderpaid
+	case paid > expected:
+		return BankTransferOverpaid
+	default:
+		return BankTransferPaid
+	}
+}
@@ -0,0 +1,244 @@
+package charge
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/huysamen/paystack-go/types/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBankTransferAccount(t *testing.T) {
+	account, err := NewBankTransferAccount(loadCharge(t, "create_200_bank_transfer.json"))
+	require.NoError(t, err)
+
+	assert.Equal(t, &BankTransferAccount{
+		Reference:     "hlr4vhbdby",
+		AccountName:   "PAYSTACK CHECKOUT",
+		AccountNumber: "0123456789",
+		BankName:      "Test Bank",
+		BankSlug:      "test-bank",
+		Amount:        150000,
+		ExpiresAt:     time.Date(2023, 9, 11, 14, 44, 31, 939000000, time.UTC),
+		DisplayText:   "Please make a transfer to the account specified",
+	}, account)
+	assert.True(t, account.Expired())
+	assert.Zero(t, account.Remaining())
+
+	account.ExpiresAt = time.Now().Add(time.Hour)
+	assert.False(t, account.Expired())
+	assert.InDelta(t, time.Hour, account.Remaining(), float64(time.Minute))
+
+	now := account.ExpiresAt.Add(-time.Minute)
+	account.now = func() time.Time { return now }
+	assert.Equal(t, time.Minute, account.Remaining())
+
+	charge := loadCharge(t, "create_200_bank_transfer.json")
+	charge.Amount = data.NewInt(151500)
+	charge.RequestedAmount = data.NewInt(150000)
+	account, err = NewBankTransferAccount(charge)
+	require.NoError(t, err)
+	assert.Equal(t, int64(150000), account.Amount, "the requested amount excludes fees")
+
+	_, err = NewBankTransferAccount(loadCharge(t, "create_200_ussd.json"))
+	assert.ErrorIs(t, err, ErrNoTransferAccount)
+
+	_, err = NewBankTransferAccount(nil)
+	assert.ErrorIs(t, err, ErrNoTransferAccount)
+}
+
+func testAccount(t *testing.T, expiresIn time.Duration) *BankTransferAccount {
+	account, err := NewBankTransferAccount(loadCharge(t, "create_200_bank_transfer.json"))
+	require.NoError(t, err)
+
+	account.ExpiresAt = time.Now().Add(expiresIn)
+
+	return account
+}
+
+func TestBankTransferWatcher_Watch(t *testing.T) {
+	tests := []struct {
+		name       string
+		responses  []string
+		outcome    BankTransferOutcome
+		paid       int64
+		difference int64
+	}{
+		{
+			name:      "paid",
+			responses: []string{"check_pending_200_pending.json", "check_pending_200_bank_transfer.json"},
+			outcome:   BankTransferPaid,
+			paid:      150000,
+		},
+		{
+			name:       "underpaid",
+			responses:  []string{"check_pending_200_bank_transfer_underpaid.json"},
+			outcome:    BankTransferUnderpaid,
+			paid:       140000,
+			difference: -10000,
+		},
+		{
+			name:       "overpaid",
+			responses:  []string{"check_pending_200_bank_transfer_overpaid.json"},
+			outcome:    BankTransferOverpaid,
+			paid:       160000,
+			difference: 10000,
+		},
+		{
+			name:      "failed check is retried",
+			responses: []string{serverError, "check_pending_200_bank_transfer.json"},
+			outcome:   BankTransferPaid,
+			paid:      150000,
+		},
+		{
+			name:      "rate limited check is retried",
+			responses: []string{rateLimited, "check_pending_200_bank_transfer.json"},
+			outcome:   BankTransferPaid,
+			paid:      150000,
+		},
+		{
+			name:       "failed",
+			responses:  []string{"check_pending_200_failed.json"},
+			outcome:    BankTransferFailed,
+			difference: -150000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api, client := newScriptedAPI(t, tt.responses...)
+			account := testAccount(t, time.Hour)
+
+			result, err := NewBankTransferWatcher(client).Interval(time.Millisecond).Watch(context.Background(), account)
+			require.NoError(t, err)
+			assert.Equal(t, tt.outcome, result.Outcome)
+			assert.Equal(t, int64(150000), result.Expected)
+			assert.Equal(t, tt.paid, result.Paid)
+			assert.Equal(t, tt.difference, result.Difference())
+			assert.Same(t, account, result.Account)
+			assert.NotNil(t, result.Charge)
+			assert.Len(t, api.Paths(), len(tt.responses))
+		})
+	}
+}
+
+func TestBankTransferWatcher_Expired(t *testing.T) {
+	api, client := newScriptedAPI(t, "check_pending_200_pending.json")
+	account := testAccount(t, -time.Hour)
+
+	result, err := NewBankTransferWatcher(client).Grace(0).Watch(context.Background(), account)
+	require.NoError(t, err)
+	assert.Equal(t, BankTransferExpired, result.Outcome)
+	assert.Equal(t, "pending", result.Charge.Status.String())
+	assert.Zero(t, result.Paid)
+	assert.Equal(t, []string{"/charge/check_pending"}, api.Paths())
+}
+
+func TestBankTransferWatcher_ExpiresWhileWatching(t *testing.T) {
+	responses := []string{"check_pending_200_pending.json"}
+	for i := 0; i < 100; i++ {
+		responses = append(responses, "check_pending_200_pending.json")
+	}
+	api, client := newScriptedAPI(t, responses...)
+	account := testAccount(t, 20*time.Millisecond)
+
+	result, err := NewBankTransferWatcher(client).Interval(5*time.Millisecond).MaxInterval(5*time.Millisecond).Grace(0).Watch(context.Background(), account)
+	require.NoError(t, err)
+	assert.Equal(t, BankTransferExpired, result.Outcome)
+	assert.Equal(t, "pending", result.Charge.Status.String())
+	assert.GreaterOrEqual(t, len(api.Paths()), 2, "checked while waiting and once after the expiry")
+}
+
+func TestBankTransferWatcher_Notify(t *testing.T) {
+	api, client := newScriptedAPI(t)
+	watcher := NewBankTransferWatcher(client).Interval(time.Hour)
+	account := testAccount(t, time.Hour)
+
+	assert.False(t, watcher.Notify(account.Reference, 150000))
+
+	go func() {
+		for !watcher.Notify(account.Reference, 140000) {
+			time.Sleep(time.Millisecond)
+		}
+	}()
+
+	result, err := watcher.Watch(context.Background(), account)
+	require.NoError(t, err)
+	assert.Equal(t, BankTransferUnderpaid, result.Outcome)
+	assert.Equal(t, int64(140000), result.Paid)
+	assert.Nil(t, result.Charge)
+	assert.Empty(t, api.Paths())
+
+	assert.False(t, watcher.Notify(account.Reference, 150000))
+}
+
+func TestBankTransferWatcher_WebhookAndPollingAgree(t *testing.T) {
+	newAccount := func(t *testing.T) *BankTransferAccount {
+		// The charge was created with an amount that includes fees
+		charge := loadCharge(t, "create_200_bank_transfer.json")
+		charge.Amount = data.NewInt(151500)
+		charge.RequestedAmount = data.NewInt(150000)
+
+		account, err := NewBankTransferAccount(charge)
+		require.NoError(t, err)
+		account.ExpiresAt = time.Now().Add(time.Hour)
+
+		return account
+	}
+
+	api, client := newScriptedAPI(t, "check_pending_200_bank_transfer.json")
+	polled, err := NewBankTransferWatcher(client).Interval(time.Millisecond).Watch(context.Background(), newAccount(t))
+	require.NoError(t, err)
+	require.Len(t, api.Paths(), 1)
+
+	api, client = newScriptedAPI(t)
+	watcher := NewBankTransferWatcher(client).Interval(time.Hour)
+	account := newAccount(t)
+	go func() {
+		for !watcher.Notify(account.Reference, 150000) {
+			time.Sleep(time.Millisecond)
+		}
+	}()
+	notified, err := watcher.Watch(context.Background(), account)
+	require.NoError(t, err)
+	assert.Empty(t, api.Paths())
+
+	assert.Equal(t, BankTransferPaid, polled.Outcome)
+	assert.Equal(t, polled.Outcome, notified.Outcome)
+	assert.Equal(t, polled.Expected, notified.Expected)
+}
+
+func TestBankTransferWatcher_Clock(t *testing.T) {
+	api, client := newScriptedAPI(t, "check_pending_200_pending.json")
+
+	// The account expires in an hour by the wall clock, but its own clock is past the expiry
+	account := testAccount(t, time.Hour)
+	now := account.ExpiresAt.Add(time.Minute)
+	account.now = func() time.Time { return now }
+	require.True(t, account.Expired())
+
+	result, err := NewBankTransferWatcher(client).Grace(0).Watch(context.Background(), account)
+	require.NoError(t, err)
+	assert.Equal(t, BankTransferExpired, result.Outcome)
+	assert.Equal(t, []string{"/charge/check_pending"}, api.Paths(), "only the last check is made")
+}
+
+func TestBankTransferWatcher_Errors(t *testing.T) {
+	t.Run("rejected reference", func(t *testing.T) {
+		_, client := newScriptedAPI(t, "check_pending_400.json")
+
+		_, err := NewBankTransferWatcher(client).Interval(time.Millisecond).Watch(context.Background(), testAccount(t, time.Hour))
+		assert.ErrorContains(t, err, "checking charge hlr4vhbdby")
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		_, client := newScriptedAPI(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := NewBankTransferWatcher(client).Watch(ctx, testAccount(t, time.Hour))
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -72,6 +72,12 @@ func TestCreateResponse_JSONDeserialization(t *testing.T) {
 			expectedStatus:  true,
 			expectedMessage: "Charge attempted",
 		},
+		{
+			name:            "create charge with QR",
+			responseFile:    "create_200_qr.json",
+			expectedStatus:  true,
+			expectedMessage: "Charge attempted",
+		},
 		{
 			name:            "create charge with USSD",
 			responseFile:    "create_200_ussd.json",
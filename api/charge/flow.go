@@ -0,0 +1,244 @@
+package charge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/huysamen/paystack-go/enums"
+	"github.com/huysamen/paystack-go/types"
+)
+
+const (
+	// DefaultFlowMaxSteps is the number of requests a Flow makes after creating a charge
+	// before it gives up
+	DefaultFlowMaxSteps = 20
+	// DefaultFlowTimeout is how long a Flow drives a charge by default
+	DefaultFlowTimeout = 5 * time.Minute
+	// DefaultFlowPollInterval is how long a Flow waits between checks of a pending charge.
+	// Paystack asks for at least 10 seconds.
+	DefaultFlowPollInterval = 10 * time.Second
+)
+
+var (
+	// ErrFlowStepLimit is returned when a charge is not final after the maximum number of steps
+	ErrFlowStepLimit = errors.New("charge flow step limit reached")
+	// ErrUnexpectedStatus is returned for charge statuses a Flow cannot act on
+	ErrUnexpectedStatus = errors.New("unexpected charge status")
+)
+
+// Address is the billing address a Prompter collects for send_address
+type Address struct {
+	Street  string
+	City    string
+	State   string
+	ZipCode string
+}
+
+// Prompter collects what a charge needs from the customer. Each method receives the
+// current charge, whose DisplayText holds Paystack's instructions for the customer.
+//
+// OpenURL and PayOffline only show the customer what to do, e.g. the URL of a 3DS or
+// bank authorization page or a USSD code, after which the Flow polls the charge.
+type Prompter interface {
+	PIN(ctx context.Context, charge *types.Charge) (string, error)
+	OTP(ctx context.Context, charge *types.Charge) (string, error)
+	Phone(ctx context.Context, charge *types.Charge) (string, error)
+	Birthday(ctx context.Context, charge *types.Charge) (time.Time, error)
+	Address(ctx context.Context, charge *types.Charge) (Address, error)
+	OpenURL(ctx context.Context, charge *types.Charge) error
+	PayOffline(ctx context.Context, charge *types.Charge) error
+}
+
+// Flow drives a charge to a final status. It reads the status of each response and calls
+// SubmitPIN, SubmitOTP, SubmitPhone, SubmitBirthday, SubmitAddress or CheckPending as
+// needed, asking a Prompter for the customer's input.
+//
+// A Flow can be reused for any number of charges.
+type Flow struct {
+	client       *Client
+	prompter     Prompter
+	maxSteps     int
+	timeout      time.Duration
+	pollInterval time.Duration
+}
+
+// NewFlow creates a Flow that makes requests with client and asks prompter for input
+func NewFlow(client *Client, prompter Prompter) *Flow {
+	return &Flow{
+		client:       client,
+		prompter:     prompter,
+		maxSteps:     DefaultFlowMaxSteps,
+		timeout:      DefaultFlowTimeout,
+		pollInterval: DefaultFlowPollInterval,
+	}
+}
+
+// MaxSteps sets the number of requests made after the charge is created
+func (f *Flow) MaxSteps(n int) *Flow {
+	f.maxSteps = n
+
+	return f
+}
+
+// Timeout sets how long a charge is driven unless its context ends sooner
+func (f *Flow) Timeout(d time.Duration) *Flow {
+	f.timeout = d
+
+	return f
+}
+
+// PollInterval sets the delay before each check of a pending charge
+func (f *Flow) PollInterval(d time.Duration) *Flow {
+	f.pollInterval = d
+
+	return f
+}
+
+// Run creates a charge and drives it to success or failed. See Continue.
+func (f *Flow) Run(ctx context.Context, builder CreateRequestBuilder) (*types.Charge, error) {
+	ctx, cancel := f.withTimeout(ctx)
+	defer cancel()
+
+	rsp, err := f.client.Create(ctx, builder)
+	if err := checkResponse(rsp, err); err != nil {
+		return nil, fmt.Errorf("creating charge: %w", err)
+	}
+
+	return f.drive(ctx, &rsp.Data)
+}
+
+// Continue drives an existing charge, e.g. one created elsewhere, to success or failed.
+//
+// A failed charge is returned without an error; check its Status and GatewayResponse. When
+// the charge cannot be driven further, the last charge received is returned with the
+// error, so its reference can be checked later.
+func (f *Flow) Continue(ctx context.Context, charge *types.Charge) (*types.Charge, error) {
+	ctx, cancel := f.withTimeout(ctx)
+	defer cancel()
+
+	return f.drive(ctx, charge)
+}
+
+func (f *Flow) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if f.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, f.timeout)
+}
+
+func (f *Flow) drive(ctx context.Context, charge *types.Charge) (*types.Charge, error) {
+	for steps := 0; ; steps++ {
+		status := charge.Status
+		if status.IsTerminal() {
+			return charge, nil
+		}
+
+		if steps == f.maxSteps {
+			return charge, fmt.Errorf("%w: charge %s is %s after %d steps", ErrFlowStepLimit, charge.Reference, status, steps)
+		}
+
+		next, err := f.step(ctx, charge)
+		if err != nil {
+			return charge, fmt.Errorf("charge %s: %s: %w", charge.Reference, status, err)
+		}
+
+		charge = next
+	}
+}
+
+// step performs the action the charge's status asks for and returns the updated charge
+func (f *Flow) step(ctx context.Context, charge *types.Charge) (*types.Charge, error) {
+	reference := charge.Reference.String()
+
+	var (
+		rsp *types.Response[types.Charge]
+		err error
+	)
+
+	switch charge.Status {
+	case enums.ChargeStatusSendPIN:
+		pin, perr := f.prompter.PIN(ctx, charge)
+		if perr != nil {
+			return nil, perr
+		}
+		rsp, err = f.client.SubmitPIN(ctx, *NewSubmitPINRequestBuilder(pin, reference))
+	case enums.ChargeStatusSendOTP:
+		otp, perr := f.prompter.OTP(ctx, charge)
+		if perr != nil {
+			return nil, perr
+		}
+		rsp, err = f.client.SubmitOTP(ctx, *NewSubmitOTPRequestBuilder(otp, reference))
+	case enums.ChargeStatusSendPhone:
+		phone, perr := f.prompter.Phone(ctx, charge)
+		if perr != nil {
+			return nil, perr
+		}
+		rsp, err = f.client.SubmitPhone(ctx, *NewSubmitPhoneRequestBuilder(phone, reference))
+	case enums.ChargeStatusSendBirthday:
+		birthday, perr := f.prompter.Birthday(ctx, charge)
+		if perr != nil {
+			return nil, perr
+		}
+		rsp, err = f.client.SubmitBirthday(ctx, *NewSubmitBirthdayRequestBuilder(birthday.Format("2006-01-02"), reference))
+	case enums.ChargeStatusSendAddress:
+		address, perr := f.prompter.Address(ctx, charge)
+		if perr != nil {
+			return nil, perr
+		}
+		rsp, err = f.client.SubmitAddress(ctx, *NewSubmitAddressRequestBuilder(address.Street, address.City, address.State, address.ZipCode, reference))
+	case enums.ChargeStatusOpenURL:
+		if perr := f.prompter.OpenURL(ctx, charge); perr != nil {
+			return nil, perr
+		}
+		rsp, err = f.poll(ctx, reference)
+	case enums.ChargeStatusPayOffline:
+		if perr := f.prompter.PayOffline(ctx, charge); perr != nil {
+			return nil, perr
+		}
+		rsp, err = f.poll(ctx, reference)
+	case enums.ChargeStatusPending, enums.ChargeStatusPendingBankTransfer:
+		rsp, err = f.poll(ctx, reference)
+	default:
+		return nil, fmt.Errorf("%w %q", ErrUnexpectedStatus, charge.Status)
+	}
+
+	if err := checkResponse(rsp, err); err != nil {
+		return nil, err
+	}
+
+	next := &rsp.Data
+	if next.Reference == "" {
+		// Some responses, e.g. to submit_birthday, omit the reference
+		next.Reference = charge.Reference
+	}
+
+	return next, nil
+}
+
+// poll checks the charge after the poll interval
+func (f *Flow) poll(ctx context.Context, reference string) (*CheckPendingResponse, error) {
+	timer := time.NewTimer(f.pollInterval)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-timer.C:
+	}
+
+	return f.client.CheckPending(ctx, *NewCheckPendingChargeRequestBuilder(reference))
+}
+
+func checkResponse(rsp *types.Response[types.Charge], err error) error {
+	if err != nil {
+		return err
+	}
+	if rsp == nil {
+		return errors.New("empty response")
+	}
+
+	return rsp.Err()
+}
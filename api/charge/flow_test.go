@@ -0,0 +1,233 @@
+package charge
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/huysamen/paystack-go/internal/apitest"
+	"github.com/huysamen/paystack-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// serverError makes newScriptedAPI answer a request with a 502 without a body
+	serverError = "502"
+	// rateLimited makes newScriptedAPI answer a request with a 429
+	rateLimited = "429"
+)
+
+// newScriptedAPI answers each request with the next response fixture under
+// resources/examples/responses/charge
+func newScriptedAPI(t *testing.T, responses ...string) (*apitest.Server, *Client) {
+	api := apitest.NewServer(t)
+	for _, fixture := range responses {
+		switch fixture {
+		case serverError:
+			api.Reply(apitest.ServerError)
+		case rateLimited:
+			api.Reply(apitest.RateLimited())
+		default:
+			api.Reply(apitest.Fixture(t, "charge/"+fixture))
+		}
+	}
+
+	return api, (*Client)(api.API())
+}
+
+// scriptedPrompter answers every prompt with fixed values and records the prompts
+type scriptedPrompter struct {
+	prompts []string
+	err     error
+}
+
+func (p *scriptedPrompter) prompt(name string) error {
+	p.prompts = append(p.prompts, name)
+
+	return p.err
+}
+
+func (p *scriptedPrompter) PIN(context.Context, *types.Charge) (string, error) {
+	return "1234", p.prompt("pin")
+}
+
+func (p *scriptedPrompter) OTP(context.Context, *types.Charge) (string, error) {
+	return "123456", p.prompt("otp")
+}
+
+func (p *scriptedPrompter) Phone(context.Context, *types.Charge) (string, error) {
+	return "08012345678", p.prompt("phone")
+}
+
+func (p *scriptedPrompter) Birthday(context.Context, *types.Charge) (time.Time, error) {
+	return time.Date(1990, 4, 25, 0, 0, 0, 0, time.UTC), p.prompt("birthday")
+}
+
+func (p *scriptedPrompter) Address(context.Context, *types.Charge) (Address, error) {
+	return Address{Street: "1 Main St", City: "Lagos", State: "LA", ZipCode: "100001"}, p.prompt("address")
+}
+
+func (p *scriptedPrompter) OpenURL(_ context.Context, charge *types.Charge) error {
+	return p.prompt("open_url:" + charge.URL.Str)
+}
+
+func (p *scriptedPrompter) PayOffline(_ context.Context, charge *types.Charge) error {
+	return p.prompt("pay_offline:" + charge.DisplayText.Str)
+}
+
+func testFlow(client *Client, prompter Prompter) *Flow {
+	return NewFlow(client, prompter).PollInterval(time.Millisecond)
+}
+
+func TestFlow_Run(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses []string
+		paths     []string
+		prompts   []string
+		status    string
+	}{
+		{
+			name:      "pin then pending",
+			responses: []string{"create_200_pin.json", "submit_pin_200_pending.json", "check_pending_200.json"},
+			paths:     []string{"/charge", "/charge/submit_pin", "/charge/check_pending"},
+			prompts:   []string{"pin"},
+			status:    "success",
+		},
+		{
+			name:      "birthday then otp",
+			responses: []string{"create_200_birthday.json", "submit_birthday_200_otp.json", "submit_otp_200.json"},
+			paths:     []string{"/charge", "/charge/submit_birthday", "/charge/submit_otp"},
+			prompts:   []string{"birthday", "otp"},
+			status:    "success",
+		},
+		{
+			name:      "phone then failed check",
+			responses: []string{"create_200_phone.json", "submit_phone_200_pending.json", "check_pending_200_failed.json"},
+			paths:     []string{"/charge", "/charge/submit_phone", "/charge/check_pending"},
+			prompts:   []string{"phone"},
+			status:    "failed",
+		},
+		{
+			name:      "address",
+			responses: []string{"create_200_address.json", "submit_address_200.json"},
+			paths:     []string{"/charge", "/charge/submit_address"},
+			prompts:   []string{"address"},
+			status:    "success",
+		},
+		{
+			name:      "bank authorization url",
+			responses: []string{"create_200_bank_auth.json", "check_pending_200.json"},
+			paths:     []string{"/charge", "/charge/check_pending"},
+			prompts:   []string{"open_url:https://standard.paystack.co/close"},
+			status:    "success",
+		},
+		{
+			name:      "ussd",
+			responses: []string{"create_200_ussd.json", "check_pending_200_pending.json", "check_pending_200.json"},
+			paths:     []string{"/charge", "/charge/check_pending", "/charge/check_pending"},
+			prompts:   []string{"pay_offline:Please dial *737*33*4*453441# on your mobile phone to complete the transaction"},
+			status:    "success",
+		},
+		{
+			name:      "bank transfer",
+			responses: []string{"create_200_bank_transfer.json", "check_pending_200_bank_transfer.json"},
+			paths:     []string{"/charge", "/charge/check_pending"},
+			status:    "success",
+		},
+		{
+			name:      "immediate success",
+			responses: []string{"create_200.json"},
+			paths:     []string{"/charge"},
+			status:    "success",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api, client := newScriptedAPI(t, tt.responses...)
+			prompter := &scriptedPrompter{}
+
+			charge, err := testFlow(client, prompter).Run(context.Background(), *NewCreateRequestBuilder("customer@email.com", "10000"))
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, charge.Status.String())
+			assert.Equal(t, tt.paths, api.Paths())
+			assert.Equal(t, tt.prompts, prompter.prompts)
+		})
+	}
+}
+
+func TestFlow_SubmitsAnswers(t *testing.T) {
+	api, client := newScriptedAPI(t, "submit_birthday_200_otp.json", "submit_otp_200.json")
+
+	// The birthday response has no reference, so the flow keeps the one it had
+	charge, err := testFlow(client, &scriptedPrompter{}).Continue(context.Background(), &types.Charge{
+		Reference: "0t4gwo2ft6q0n9h",
+		Status:    "send_birthday",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "success", charge.Status.String())
+
+	require.Len(t, api.Requests(), 2)
+	assert.Equal(t, map[string]any{"birthday": "1990-04-25", "reference": "0t4gwo2ft6q0n9h"}, api.Requests()[0].Body)
+	assert.Equal(t, map[string]any{"otp": "123456", "reference": "0t4gwo2ft6q0n9h"}, api.Requests()[1].Body)
+}
+
+func TestFlow_Errors(t *testing.T) {
+	ctx := context.Background()
+	create := *NewCreateRequestBuilder("customer@email.com", "10000")
+
+	t.Run("step limit", func(t *testing.T) {
+		_, client := newScriptedAPI(t, "create_200_pin.json", "submit_pin_200_pending.json", "check_pending_200_pending.json")
+
+		charge, err := testFlow(client, &scriptedPrompter{}).MaxSteps(2).Run(ctx, create)
+		assert.ErrorIs(t, err, ErrFlowStepLimit)
+		require.NotNil(t, charge)
+		assert.Equal(t, "pending", charge.Status.String())
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		api, client := newScriptedAPI(t, "create_200_pending.json")
+
+		charge, err := NewFlow(client, &scriptedPrompter{}).Timeout(10*time.Millisecond).Run(ctx, create)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		require.NotNil(t, charge)
+		assert.Equal(t, "5bwib5v6anhe9xa", charge.Reference.String())
+		assert.Equal(t, []string{"/charge"}, api.Paths())
+	})
+
+	t.Run("prompter error", func(t *testing.T) {
+		api, client := newScriptedAPI(t, "create_200_otp.json")
+		cancelled := errors.New("customer cancelled")
+
+		charge, err := testFlow(client, &scriptedPrompter{err: cancelled}).Run(ctx, create)
+		assert.ErrorIs(t, err, cancelled)
+		assert.Equal(t, "send_otp", charge.Status.String())
+		assert.Equal(t, []string{"/charge"}, api.Paths())
+	})
+
+	t.Run("api error", func(t *testing.T) {
+		_, client := newScriptedAPI(t, "create_200_pin.json", "submit_pin_400.json")
+
+		charge, err := testFlow(client, &scriptedPrompter{}).Run(ctx, create)
+		assert.ErrorContains(t, err, "Transaction reference is invalid")
+		assert.Equal(t, "send_pin", charge.Status.String())
+	})
+
+	t.Run("create error", func(t *testing.T) {
+		_, client := newScriptedAPI(t, "create_400.json")
+
+		charge, err := testFlow(client, &scriptedPrompter{}).Run(ctx, create)
+		assert.ErrorContains(t, err, "creating charge")
+		assert.Nil(t, charge)
+	})
+
+	t.Run("unexpected status", func(t *testing.T) {
+		_, client := newScriptedAPI(t)
+
+		_, err := testFlow(client, &scriptedPrompter{}).Continue(ctx, &types.Charge{Reference: "ref", Status: "abandoned"})
+		assert.ErrorIs(t, err, ErrUnexpectedStatus)
+	})
+}
@@ -0,0 +1,101 @@
+package charge
+
+import (
+	"bytes"
+	"encoding/json"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/huysamen/paystack-go/types"
+	"github.com/huysamen/paystack-go/types/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loadCharge(t *testing.T, fixture string) *types.Charge {
+	b, err := os.ReadFile(filepath.Join("..", "..", "resources", "examples", "responses", "charge", fixture))
+	require.NoError(t, err)
+
+	var rsp CreateChargeResponse
+	require.NoError(t, json.Unmarshal(b, &rsp))
+
+	return &rsp.Data
+}
+
+func TestQRPayload(t *testing.T) {
+	qr := loadCharge(t, "create_200_qr.json")
+	payload, err := QRPayload(qr)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(payload, "000201"))
+
+	payload, err = QRPayload(loadCharge(t, "create_200_bank_auth.json"))
+	require.NoError(t, err)
+	assert.Equal(t, "https://standard.paystack.co/close", payload)
+
+	_, err = QRPayload(loadCharge(t, "create_200_ussd.json"))
+	assert.ErrorIs(t, err, ErrNoQRPayload)
+
+	_, err = QRPayload(nil)
+	assert.ErrorIs(t, err, ErrNoQRPayload)
+}
+
+func TestQRRenderer_PNG(t *testing.T) {
+	charge := loadCharge(t, "create_200_qr.json")
+
+	var buf bytes.Buffer
+	require.NoError(t, NewQRRenderer().ModuleSize(4).Border(2).PNG(&buf, charge))
+
+	img, err := png.Decode(&buf)
+	require.NoError(t, err)
+
+	// 131 bytes at medium error correction needs a version 8 symbol of 49 modules
+	side := (49 + 2*2) * 4
+	assert.Equal(t, side, img.Bounds().Dx())
+	assert.Equal(t, side, img.Bounds().Dy())
+
+	dark := func(x, y int) bool {
+		r, _, _, _ := img.At(x, y).RGBA()
+		return r == 0
+	}
+
+	// The quiet zone is light and the top left finder pattern starts after it
+	assert.False(t, dark(0, 0))
+	assert.False(t, dark(7, 7))
+	assert.True(t, dark(8, 8))
+	assert.True(t, dark(11, 11))
+	assert.False(t, dark(12, 12))
+}
+
+func TestQRRenderer_SVG(t *testing.T) {
+	charge := loadCharge(t, "create_200_qr.json")
+
+	var buf bytes.Buffer
+	require.NoError(t, NewQRRenderer().Level(QRLevelLow).SVG(&buf, charge))
+
+	svg := buf.String()
+	assert.True(t, strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg"`))
+	assert.True(t, strings.HasSuffix(svg, `"/></svg>`))
+
+	// 131 bytes at low error correction fits a version 7 symbol of 45 modules
+	assert.Contains(t, svg, `width="424" height="424" viewBox="0 0 53 53"`)
+
+	// The first row starts with the top edge of two finder patterns
+	assert.Contains(t, svg, `d="M4 4h7v1h-7z`)
+}
+
+func TestQRRenderer_Errors(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := NewQRRenderer().PNG(&buf, loadCharge(t, "create_200_ussd.json"))
+	assert.ErrorIs(t, err, ErrNoQRPayload)
+
+	err = NewQRRenderer().SVG(&buf, &types.Charge{
+		Reference: "ref",
+		QRCode:    data.NullString{Str: strings.Repeat("x", 4000), Valid: true},
+	})
+	assert.ErrorContains(t, err, "charge ref")
+	assert.Zero(t, buf.Len())
+}
@@ -0,0 +1,98 @@
+package charge
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/huysamen/paystack-go/types"
+)
+
+// USSD types accepted in USSDDetails.Type
+const (
+	USSDTypeGTBank   = "737"
+	USSDTypeUBA      = "919"
+	USSDTypeSterling = "822"
+	USSDTypeZenith   = "966"
+)
+
+var ussdBanks = map[string]string{
+	USSDTypeGTBank:   "Guaranty Trust Bank",
+	USSDTypeUBA:      "United Bank of Africa",
+	USSDTypeSterling: "Sterling Bank",
+	USSDTypeZenith:   "Zenith Bank",
+}
+
+var (
+	// ErrNoUSSDCode is returned when a charge has no USSD code to dial
+	ErrNoUSSDCode = errors.New("charge has no USSD code")
+	// ErrUnsupportedUSSDType is returned for USSD types Paystack does not support
+	ErrUnsupportedUSSDType = errors.New("unsupported USSD type")
+)
+
+// ussdPattern finds a dial string, e.g. in "Please dial *737*33*4*453441# on your mobile"
+var ussdPattern = regexp.MustCompile(`\*[0-9*]+#`)
+
+// USSDCode is the code a customer dials to pay a USSD charge
+type USSDCode struct {
+	// Type is the bank's USSD type, e.g. 737
+	Type string
+	// Bank is the name of the bank
+	Bank string
+	// Dial is the full dial string, e.g. *737*33*4*453441#
+	Dial string
+}
+
+// String returns the dial string
+func (u USSDCode) String() string {
+	return u.Dial
+}
+
+// TelURI returns a tel: URI that opens the dialler with the code, with # escaped as
+// dialler apps require
+func (u USSDCode) TelURI() string {
+	return "tel:" + strings.ReplaceAll(u.Dial, "#", "%23")
+}
+
+// USSDBank returns the name of the bank with the given USSD type
+func USSDBank(ussdType string) (string, bool) {
+	bank, ok := ussdBanks[ussdType]
+
+	return bank, ok
+}
+
+// ParseUSSD returns the code to dial for a USSD charge created with details. The code is
+// taken from the charge's ussd_code, or from its display text, and formatted as
+// *<type>*...# for the bank.
+func ParseUSSD(details USSDDetails, charge *types.Charge) (USSDCode, error) {
+	bank, ok := USSDBank(details.Type)
+	if !ok {
+		return USSDCode{}, fmt.Errorf("%w %q", ErrUnsupportedUSSDType, details.Type)
+	}
+	if charge == nil {
+		return USSDCode{}, ErrNoUSSDCode
+	}
+
+	code := strings.TrimSpace(charge.USSDCode.Str)
+	if code == "" {
+		code = ussdPattern.FindString(charge.DisplayText.Str)
+	}
+	if code == "" {
+		return USSDCode{}, fmt.Errorf("%w: %s", ErrNoUSSDCode, charge.Reference)
+	}
+
+	return USSDCode{Type: details.Type, Bank: bank, Dial: formatUSSD(details.Type, code)}, nil
+}
+
+// formatUSSD prefixes code with the bank's *<type>* unless it has it, and ends it with #
+func formatUSSD(ussdType, code string) string {
+	code = strings.Trim(strings.TrimSuffix(strings.ReplaceAll(code, " ", ""), "#"), "*")
+
+	prefix := ussdType + "*"
+	if code != ussdType && !strings.HasPrefix(code, prefix) {
+		code = prefix + code
+	}
+
+	return "*" + code + "#"
+}
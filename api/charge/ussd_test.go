@@ -0,0 +1,63 @@
+package charge
+
+import (
+	"testing"
+
+	"github.com/huysamen/paystack-go/types"
+	"github.com/huysamen/paystack-go/types/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseUSSD(t *testing.T) {
+	code, err := ParseUSSD(USSDDetails{Type: USSDTypeGTBank}, loadCharge(t, "create_200_ussd.json"))
+	require.NoError(t, err)
+	assert.Equal(t, USSDCode{Type: "737", Bank: "Guaranty Trust Bank", Dial: "*737*33*4*453441#"}, code)
+	assert.Equal(t, "*737*33*4*453441#", code.String())
+	assert.Equal(t, "tel:*737*33*4*453441%23", code.TelURI())
+
+	tests := []struct {
+		name     string
+		ussdType string
+		charge   types.Charge
+		dial     string
+	}{
+		{
+			name:     "from display text",
+			ussdType: USSDTypeUBA,
+			charge:   types.Charge{DisplayText: data.NullString{Str: "Please dial *919*4*22*987654# to pay", Valid: true}},
+			dial:     "*919*4*22*987654#",
+		},
+		{
+			name:     "adds bank prefix",
+			ussdType: USSDTypeZenith,
+			charge:   types.Charge{USSDCode: data.NullString{Str: "000*5043", Valid: true}},
+			dial:     "*966*000*5043#",
+		},
+		{
+			name:     "normalises spacing and terminator",
+			ussdType: USSDTypeSterling,
+			charge:   types.Charge{USSDCode: data.NullString{Str: " *822* 4 *12345 ", Valid: true}},
+			dial:     "*822*4*12345#",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := ParseUSSD(USSDDetails{Type: tt.ussdType}, &tt.charge)
+			require.NoError(t, err)
+			assert.Equal(t, tt.dial, code.Dial)
+		})
+	}
+}
+
+func TestParseUSSD_Errors(t *testing.T) {
+	_, err := ParseUSSD(USSDDetails{Type: "123"}, loadCharge(t, "create_200_ussd.json"))
+	assert.ErrorIs(t, err, ErrUnsupportedUSSDType)
+
+	_, err = ParseUSSD(USSDDetails{Type: USSDTypeGTBank}, loadCharge(t, "create_200_pending.json"))
+	assert.ErrorIs(t, err, ErrNoUSSDCode)
+
+	_, err = ParseUSSD(USSDDetails{Type: USSDTypeGTBank}, nil)
+	assert.ErrorIs(t, err, ErrNoUSSDCode)
+}
@@ -0,0 +1,198 @@
+package charge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/huysamen/paystack-go/enums"
+	"github.com/huysamen/paystack-go/types"
+)
+
+const (
+	// DefaultWaitInterval is the delay before the first check of a pending charge. Paystack
+	// asks for at least 10 seconds between checks.
+	DefaultWaitInterval = 10 * time.Second
+	// DefaultWaitMaxInterval caps the delay between checks
+	DefaultWaitMaxInterval = time.Minute
+	// DefaultWaitMultiplier is the factor by which the delay grows after each check
+	DefaultWaitMultiplier = 1.5
+	// DefaultWaitTimeout is how long WaitForCompletion waits by default
+	DefaultWaitTimeout = 10 * time.Minute
+)
+
+// ErrWaitTimeout is matched by a *WaitTimeoutError with errors.Is
+var ErrWaitTimeout = errors.New("timed out waiting for charge to complete")
+
+// WaitTimeoutError is returned by WaitForCompletion when the charge is not final in time
+type WaitTimeoutError struct {
+	Reference string
+	// Charge is the last state of the charge, or nil if no check succeeded
+	Charge *types.Charge
+	// Err is the error of the last check, if it failed
+	Err error
+}
+
+func (e *WaitTimeoutError) Error() string {
+	msg := fmt.Sprintf("%s: %s", ErrWaitTimeout, e.Reference)
+	if e.Charge != nil {
+		msg += fmt.Sprintf(": last status %q", e.Charge.Status)
+	}
+	if e.Err != nil {
+		msg += fmt.Sprintf(": last check failed: %v", e.Err)
+	}
+
+	return msg
+}
+
+// Is reports whether target is ErrWaitTimeout
+func (e *WaitTimeoutError) Is(target error) bool {
+	return target == ErrWaitTimeout
+}
+
+// WaitOptions configures WaitForCompletion. The zero value uses the defaults.
+type WaitOptions struct {
+	interval     time.Duration
+	maxInterval  time.Duration
+	multiplier   float64
+	timeout      time.Duration
+	onTransition func(previous enums.ChargeStatus, charge *types.Charge)
+}
+
+// NewWaitOptions creates WaitOptions with the defaults
+func NewWaitOptions() *WaitOptions {
+	return &WaitOptions{
+		interval:    DefaultWaitInterval,
+		maxInterval: DefaultWaitMaxInterval,
+		multiplier:  DefaultWaitMultiplier,
+		timeout:     DefaultWaitTimeout,
+	}
+}
+
+// Interval sets the delay before the first check
+func (o *WaitOptions) Interval(d time.Duration) *WaitOptions {
+	o.interval = d
+
+	return o
+}
+
+// MaxInterval sets the longest delay between checks
+func (o *WaitOptions) MaxInterval(d time.Duration) *WaitOptions {
+	o.maxInterval = d
+
+	return o
+}
+
+// Multiplier sets the factor by which the delay grows after each check. A multiplier of 1
+// checks at a fixed interval.
+func (o *WaitOptions) Multiplier(m float64) *WaitOptions {
+	o.multiplier = m
+
+	return o
+}
+
+// Timeout sets how long to wait unless the context ends sooner
+func (o *WaitOptions) Timeout(d time.Duration) *WaitOptions {
+	o.timeout = d
+
+	return o
+}
+
+// OnTransition sets a function that is called whenever a check returns a new status. The
+// previous status is empty for the first check.
+func (o *WaitOptions) OnTransition(fn func(previous enums.ChargeStatus, charge *types.Charge)) *WaitOptions {
+	o.onTransition = fn
+
+	return o
+}
+
+// withDefaults fills in unset options
+func (o WaitOptions) withDefaults() WaitOptions {
+	if o.interval <= 0 {
+		o.interval = DefaultWaitInterval
+	}
+	if o.maxInterval <= 0 {
+		o.maxInterval = DefaultWaitMaxInterval
+	}
+	if o.multiplier < 1 {
+		o.multiplier = DefaultWaitMultiplier
+	}
+	if o.timeout == 0 {
+		o.timeout = DefaultWaitTimeout
+	}
+
+	return o
+}
+
+// WaitForCompletion checks a pending charge, e.g. a bank transfer, USSD or mobile money
+// charge, with CheckPending until it succeeds or fails, and returns the final charge.
+//
+// The delay between checks starts at the interval and grows by the multiplier up to the
+// maximum interval. Failed checks, including rate limited and server error replies, are
+// retried; a response that rejects the reference is returned as an error straight away. Statuses that need the customer's input, such as
+// send_otp, are reported to OnTransition and checked again, since the input may be
+// submitted elsewhere; use a Flow to submit it.
+//
+// When the timeout or the context's deadline passes first, a *WaitTimeoutError with the
+// last known charge is returned. A cancelled context returns ctx.Err().
+func (c *Client) WaitForCompletion(ctx context.Context, reference string, opts WaitOptions) (*types.Charge, error) {
+	opts = opts.withDefaults()
+
+	if opts.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.timeout)
+		defer cancel()
+	}
+
+	var (
+		last    *types.Charge
+		lastErr error
+	)
+
+	delay := opts.interval
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return last, &WaitTimeoutError{Reference: reference, Charge: last, Err: lastErr}
+			}
+			return last, ctx.Err()
+		case <-timer.C:
+		}
+
+		rsp, err := c.CheckPending(ctx, *NewCheckPendingChargeRequestBuilder(reference))
+		switch {
+		case err != nil && ctx.Err() != nil:
+			// The check was cut short by the deadline; keep the last real failure
+		case err != nil:
+			lastErr = err
+		case rsp.Retryable() || rsp.StatusCode >= http.StatusInternalServerError:
+			lastErr = fmt.Errorf("checking charge %s: %w", reference, rsp.Err())
+		case rsp.Err() != nil:
+			return last, fmt.Errorf("checking charge %s: %w", reference, rsp.Err())
+		default:
+			charge := rsp.Data
+			previous := enums.ChargeStatus("")
+			if last != nil {
+				previous = last.Status
+			}
+
+			last, lastErr = &charge, nil
+
+			if charge.Status != previous && opts.onTransition != nil {
+				opts.onTransition(previous, last)
+			}
+			if charge.Status.IsTerminal() {
+				return last, nil
+			}
+		}
+
+		delay = min(time.Duration(float64(delay)*opts.multiplier), opts.maxInterval)
+		timer.Reset(delay)
+	}
+}
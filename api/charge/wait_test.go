@@ -0,0 +1,131 @@
+package charge
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/huysamen/paystack-go/enums"
+	"github.com/huysamen/paystack-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fastWait() *WaitOptions {
+	return NewWaitOptions().Interval(time.Millisecond).MaxInterval(2 * time.Millisecond)
+}
+
+func TestWaitForCompletion(t *testing.T) {
+	api, client := newScriptedAPI(t,
+		"check_pending_200_pending.json",
+		serverError,
+		"check_pending_200_pending.json",
+		"check_pending_200_otp.json",
+		"check_pending_200.json",
+	)
+
+	var transitions []string
+	opts := fastWait().OnTransition(func(previous enums.ChargeStatus, charge *types.Charge) {
+		transitions = append(transitions, string(previous)+"->"+string(charge.Status))
+	})
+
+	charge, err := client.WaitForCompletion(context.Background(), "zuvbpizfcf2fs7y", *opts)
+	require.NoError(t, err)
+	assert.Equal(t, enums.ChargeStatusSuccess, charge.Status)
+	assert.Equal(t, []string{"->pending", "pending->send_otp", "send_otp->success"}, transitions)
+
+	require.Len(t, api.Requests(), 5)
+	assert.Equal(t, "/charge/check_pending", api.Requests()[0].Path)
+	assert.Equal(t, map[string]any{"reference": "zuvbpizfcf2fs7y"}, api.Requests()[0].Body)
+}
+
+func TestWaitForCompletion_Failed(t *testing.T) {
+	_, client := newScriptedAPI(t, "check_pending_200_failed.json")
+
+	charge, err := client.WaitForCompletion(context.Background(), "ojk8k0bimgftf0x", *fastWait())
+	require.NoError(t, err)
+	assert.Equal(t, enums.ChargeStatusFailed, charge.Status)
+}
+
+func TestWaitForCompletion_TransientFailures(t *testing.T) {
+	for _, failure := range []string{rateLimited, serverError} {
+		t.Run(failure, func(t *testing.T) {
+			api, client := newScriptedAPI(t, failure, failure, "check_pending_200.json")
+
+			charge, err := client.WaitForCompletion(context.Background(), "zuvbpizfcf2fs7y", *fastWait())
+			require.NoError(t, err)
+			assert.Equal(t, enums.ChargeStatusSuccess, charge.Status)
+			assert.Len(t, api.Requests(), 3)
+		})
+	}
+
+	t.Run("rate limited until the timeout", func(t *testing.T) {
+		responses := make([]string, 100)
+		for i := range responses {
+			responses[i] = rateLimited
+		}
+		_, client := newScriptedAPI(t, responses...)
+
+		opts := NewWaitOptions().Interval(5 * time.Millisecond).Multiplier(1).Timeout(30 * time.Millisecond)
+
+		_, err := client.WaitForCompletion(context.Background(), "zuvbpizfcf2fs7y", *opts)
+
+		var timeout *WaitTimeoutError
+		require.ErrorAs(t, err, &timeout)
+		assert.ErrorContains(t, timeout.Err, "Too many requests")
+	})
+}
+
+func TestWaitForCompletion_Timeout(t *testing.T) {
+	responses := []string{"check_pending_200_pending.json"}
+	for i := 0; i < 100; i++ {
+		responses = append(responses, serverError)
+	}
+	_, client := newScriptedAPI(t, responses...)
+
+	opts := NewWaitOptions().Interval(5 * time.Millisecond).Multiplier(1).Timeout(50 * time.Millisecond)
+
+	charge, err := client.WaitForCompletion(context.Background(), "5bwib5v6anhe9xa", *opts)
+	assert.ErrorIs(t, err, ErrWaitTimeout)
+
+	var timeout *WaitTimeoutError
+	require.ErrorAs(t, err, &timeout)
+	assert.Equal(t, "5bwib5v6anhe9xa", timeout.Reference)
+	require.NotNil(t, timeout.Charge, "the last known charge is kept across failed checks")
+	assert.Equal(t, enums.ChargeStatusPending, timeout.Charge.Status)
+	assert.Same(t, charge, timeout.Charge)
+	assert.ErrorContains(t, timeout.Err, "502")
+	assert.ErrorContains(t, err, `last status "pending"`)
+}
+
+func TestWaitForCompletion_Errors(t *testing.T) {
+	t.Run("rejected reference", func(t *testing.T) {
+		api, client := newScriptedAPI(t, "check_pending_400.json")
+
+		_, err := client.WaitForCompletion(context.Background(), "unknown", *fastWait())
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, ErrWaitTimeout)
+		assert.Len(t, api.Requests(), 1)
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		_, client := newScriptedAPI(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := client.WaitForCompletion(ctx, "ref", WaitOptions{})
+		assert.True(t, errors.Is(err, context.Canceled))
+	})
+
+	t.Run("parent deadline", func(t *testing.T) {
+		_, client := newScriptedAPI(t)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		defer cancel()
+
+		_, err := client.WaitForCompletion(ctx, "ref", WaitOptions{})
+		assert.ErrorIs(t, err, ErrWaitTimeout)
+	})
+}
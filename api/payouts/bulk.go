@@ -0,0 +1,473 @@
+package payouts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/huysamen/paystack-go/api/transfers"
+	"github.com/huysamen/paystack-go/enums"
+)
+
+const (
+	// MaxBulkTransfers is the most transfers Paystack accepts in one bulk request
+	MaxBulkTransfers = 100
+	// DefaultBulkConcurrency is how many bulk requests a BulkPayer sends at once
+	DefaultBulkConcurrency = 2
+	// DefaultBulkInterval is the least time between the requests of a BulkPayer
+	DefaultBulkInterval = 500 * time.Millisecond
+	// DefaultBulkAttempts is how many times a BulkPayer tries a chunk that fails
+	DefaultBulkAttempts = 3
+	// DefaultBulkRetryDelay is how long a BulkPayer waits before retrying a failed chunk
+	DefaultBulkRetryDelay = 5 * time.Second
+)
+
+var (
+	// ErrMissingReference is returned for bulk transfers without a reference, which is
+	// needed to match the response to the transfer
+	ErrMissingReference = errors.New("bulk transfer has no reference")
+	// ErrDuplicateReference is returned when two bulk transfers share a reference
+	ErrDuplicateReference = errors.New("duplicate bulk transfer reference")
+)
+
+// BulkOutcome is what happened to one transfer of a bulk payout
+type BulkOutcome string
+
+const (
+	// BulkAccepted transfers were queued by Paystack; their final outcome arrives by
+	// webhook or from transfers.Verify
+	BulkAccepted BulkOutcome = "accepted"
+	// BulkAwaitingOTP transfers were queued but need an OTP, which no OTPWorkflow provided;
+	// finalize them with transfers.Finalize or OTPWorkflow.Finalize
+	BulkAwaitingOTP BulkOutcome = "awaiting_otp"
+	// BulkRejected transfers, or the chunk they were sent in, were refused by Paystack
+	BulkRejected BulkOutcome = "rejected"
+	// BulkUnknown transfers were in a chunk that kept failing; they may have been queued
+	// and should be verified by reference before they are sent again
+	BulkUnknown BulkOutcome = "unknown"
+	// BulkNotSent transfers never reached Paystack, because the payout was cancelled or
+	// their chunk was refused unread, e.g. when rate limited
+	BulkNotSent BulkOutcome = "not_sent"
+)
+
+// BulkResult is the result of one transfer of a bulk payout
+type BulkResult struct {
+	// Index is the position of the transfer in the slice passed to Pay
+	Index int
+	Item  transfers.BulkTransferItem
+	// Chunk is the index of the bulk request the transfer was sent in
+	Chunk          int
+	Outcome        BulkOutcome
+	TransferCode   string
+	TransferStatus enums.TransferStatus
+	// Attempts counts the bulk requests that included the transfer
+	Attempts int
+	// Error is the reason a transfer was not accepted
+	Error string
+}
+
+// BulkReport is the per-transfer result of a bulk payout, in the order of the transfers
+type BulkReport struct {
+	Results []BulkResult
+	Chunks  int
+}
+
+// Outcome returns the results with the given outcome
+func (r *BulkReport) Outcome(outcome BulkOutcome) []BulkResult {
+	var results []BulkResult
+	for _, result := range r.Results {
+		if result.Outcome == outcome {
+			results = append(results, result)
+		}
+	}
+
+	return results
+}
+
+// Accepted reports whether every transfer was queued
+func (r *BulkReport) Accepted() bool {
+	return len(r.Outcome(BulkAccepted)) == len(r.Results)
+}
+
+// BulkPayer sends any number of transfers to existing recipients with transfers.Bulk. The
+// transfers are split into chunks of at most MaxBulkTransfers, which are sent with bounded
+// concurrency and at most one request per interval.
+//
+// Each transfer must have a unique reference, which is used to match the response to the
+// transfer. A chunk that fails without a response from Paystack is retried after the
+// retry delay; before it is sent again, its transfers are verified by reference and only
+// those Paystack does not know are resent, so no transfer is sent twice.
+//
+// Transfers that need an OTP are finalized one by one with the OTPWorkflow set with OTP,
+// after the rest of their chunk has been sent.
+//
+// A BulkPayer is safe for concurrent use once configured.
+type BulkPayer struct {
+	transfers   *transfers.Client
+	otp         *OTPWorkflow
+	source      string
+	currency    string
+	chunkSize   int
+	concurrency int
+	interval    time.Duration
+	attempts    int
+	retryDelay  time.Duration
+}
+
+// NewBulkPayer creates a BulkPayer with the Transfers client of a paystack.Client
+func NewBulkPayer(transfers *transfers.Client) *BulkPayer {
+	return &BulkPayer{
+		transfers:   transfers,
+		source:      DefaultSource,
+		chunkSize:   MaxBulkTransfers,
+		concurrency: DefaultBulkConcurrency,
+		interval:    DefaultBulkInterval,
+		attempts:    DefaultBulkAttempts,
+		retryDelay:  DefaultBulkRetryDelay,
+	}
+}
+
+// Source sets the source transfers are paid from
+func (b *BulkPayer) Source(source string) *BulkPayer {
+	b.source = source
+
+	return b
+}
+
+// Currency sets the currency of the transfers, which defaults to the integration's currency
+func (b *BulkPayer) Currency(currency string) *BulkPayer {
+	b.currency = currency
+
+	return b
+}
+
+// OTP sets the workflow that finalizes transfers needing an OTP
+func (b *BulkPayer) OTP(w *OTPWorkflow) *BulkPayer {
+	b.otp = w
+
+	return b
+}
+
+// ChunkSize sets how many transfers are sent per request, at most MaxBulkTransfers
+func (b *BulkPayer) ChunkSize(n int) *BulkPayer {
+	b.chunkSize = min(max(n, 1), MaxBulkTransfers)
+
+	return b
+}
+
+// Concurrency sets how many requests are in flight at once
+func (b *BulkPayer) Concurrency(n int) *BulkPayer {
+	b.concurrency = max(n, 1)
+
+	return b
+}
+
+// Interval sets the least time between the start of two requests, including the
+// verification of transfers in failed chunks
+func (b *BulkPayer) Interval(d time.Duration) *BulkPayer {
+	b.interval = d
+
+	return b
+}
+
+// Attempts sets how many times a failed chunk is tried
+func (b *BulkPayer) Attempts(n int) *BulkPayer {
+	b.attempts = max(n, 1)
+
+	return b
+}
+
+// RetryDelay sets how long to wait before retrying a failed chunk
+func (b *BulkPayer) RetryDelay(d time.Duration) *BulkPayer {
+	b.retryDelay = d
+
+	return b
+}
+
+// Pay sends items and reports the result of each. Transfers that were not accepted are
+// reported in the BulkReport rather than as an error. Pay only returns an error for items
+// without unique references, or with the report so far when ctx ends.
+func (b *BulkPayer) Pay(ctx context.Context, items []transfers.BulkTransferItem) (*BulkReport, error) {
+	seen := make(map[string]bool, len(items))
+	for i, item := range items {
+		if item.Reference == "" {
+			return nil, fmt.Errorf("%w: transfer %d", ErrMissingReference, i)
+		}
+		if seen[item.Reference] {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateReference, item.Reference)
+		}
+		seen[item.Reference] = true
+	}
+
+	report := &BulkReport{Results: make([]BulkResult, len(items))}
+	for i, item := range items {
+		report.Results[i] = BulkResult{Index: i, Item: item, Chunk: i / b.chunkSize, Outcome: BulkNotSent}
+	}
+	report.Chunks = (len(items) + b.chunkSize - 1) / b.chunkSize
+
+	chunks := make(chan []BulkResult)
+	limit := &limiter{interval: b.interval}
+
+	var wg sync.WaitGroup
+	for i := 0; i < min(b.concurrency, report.Chunks); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for chunk := range chunks {
+				b.send(ctx, limit, chunk)
+			}
+		}()
+	}
+
+	// Each chunk is a window on report.Results, so workers fill in the report directly
+	for start := 0; start < len(items) && ctx.Err() == nil; start += b.chunkSize {
+		select {
+		case chunks <- report.Results[start:min(start+b.chunkSize, len(items))]:
+		case <-ctx.Done():
+		}
+	}
+	close(chunks)
+	wg.Wait()
+
+	return report, ctx.Err()
+}
+
+// send sends a chunk, retrying the transfers whose outcome is unknown
+func (b *BulkPayer) send(ctx context.Context, limit *limiter, chunk []BulkResult) {
+	pending := make([]*BulkResult, len(chunk))
+	for i := range chunk {
+		pending[i] = &chunk[i]
+	}
+
+	for attempt := 1; len(pending) > 0; attempt++ {
+		batch := pending
+		pending = nil
+
+		if attempt > 1 {
+			if attempt > b.attempts || !sleep(ctx, b.retryDelay) {
+				pending = batch
+				break
+			}
+
+			batch, pending = b.reconcile(ctx, limit, batch)
+			if len(batch) == 0 {
+				continue
+			}
+		}
+
+		if err := limit.wait(ctx); err != nil {
+			pending = append(pending, batch...)
+			break
+		}
+
+		pending = append(pending, b.post(ctx, limit, batch)...)
+	}
+
+	for _, result := range pending {
+		if result.Attempts == 0 && ctx.Err() != nil {
+			result.Outcome, result.Error = BulkNotSent, ctx.Err().Error()
+		}
+	}
+
+	if b.otp == nil {
+		return
+	}
+	for i := range chunk {
+		if chunk[i].Outcome == BulkAwaitingOTP && ctx.Err() == nil {
+			b.finalize(ctx, &chunk[i])
+		}
+	}
+}
+
+// finalize finalizes a queued transfer that needs an OTP with the OTP workflow
+func (b *BulkPayer) finalize(ctx context.Context, result *BulkResult) {
+	otp, err := b.otp.Finalize(ctx, OTPRequest{
+		Reference:    result.Item.Reference,
+		TransferCode: result.TransferCode,
+		Amount:       result.Item.Amount,
+		Currency:     b.currency,
+		Recipient:    result.Item.Recipient,
+		Reason:       result.Item.Reason,
+	})
+	if err == nil {
+		err = otp.Err()
+	}
+	if err != nil {
+		result.Error = err.Error()
+		return
+	}
+
+	accept(result, result.TransferCode, otp.Transfer.Status)
+}
+
+// post sends the pending transfers in one bulk request and returns those to try again:
+// transfers whose outcome is unknown, and transfers the request was refused for without
+// being read, which are marked BulkNotSent. A chunk rejected for a reference Paystack has
+// seen may have been queued by an earlier attempt, so its transfers are verified.
+func (b *BulkPayer) post(ctx context.Context, limit *limiter, pending []*BulkResult) []*BulkResult {
+	builder := transfers.NewBulkRequestBuilder(b.source)
+	if b.currency != "" {
+		builder.Currency(b.currency)
+	}
+	for _, result := range pending {
+		result.Attempts++
+		result.Outcome = BulkUnknown
+		builder.AddTransfer(result.Item)
+	}
+
+	rsp, err := b.transfers.Bulk(ctx, *builder)
+	if err != nil {
+		for _, result := range pending {
+			result.Outcome, result.Error = BulkUnknown, err.Error()
+		}
+
+		return pending
+	}
+	switch {
+	case rsp.Retryable():
+		for _, result := range pending {
+			result.Outcome, result.Error = BulkNotSent, rsp.Message
+		}
+
+		return pending
+	case rsp.Unauthorized():
+		for _, result := range pending {
+			result.Outcome, result.Error = BulkNotSent, rsp.Message
+		}
+
+		return nil
+	case rsp.Err() != nil && duplicateReference(rsp.Message):
+		unsent, unverified := b.reconcile(ctx, limit, pending)
+		for _, result := range unsent {
+			result.Outcome, result.Error = BulkNotSent, rsp.Message
+		}
+		for _, result := range unverified {
+			result.Outcome = BulkUnknown
+		}
+
+		return append(unsent, unverified...)
+	case rsp.Err() != nil:
+		for _, result := range pending {
+			result.Outcome, result.Error = BulkRejected, rsp.Message
+		}
+
+		return nil
+	}
+
+	byReference := make(map[string]transfers.BulkResponseData, len(rsp.Data))
+	for _, data := range rsp.Data {
+		byReference[data.Reference.String()] = data
+	}
+
+	var unknown []*BulkResult
+	for _, result := range pending {
+		data, ok := byReference[result.Item.Reference]
+		if !ok {
+			result.Outcome, result.Error = BulkUnknown, "missing from bulk response"
+			unknown = append(unknown, result)
+			continue
+		}
+
+		accept(result, data.TransferCode.String(), data.Status)
+	}
+
+	return unknown
+}
+
+// reconcile verifies transfers whose outcome is unknown. It returns those Paystack has not
+// seen, which are safe to send again, and those that could not be verified, which are
+// verified again on the next attempt. Transfers already known not to be sent are not
+// verified.
+func (b *BulkPayer) reconcile(ctx context.Context, limit *limiter, pending []*BulkResult) (unsent, unverified []*BulkResult) {
+	for i, result := range pending {
+		if result.Outcome == BulkNotSent {
+			unsent = append(unsent, result)
+			continue
+		}
+		if err := limit.wait(ctx); err != nil {
+			return unsent, append(unverified, pending[i:]...)
+		}
+
+		rsp, err := b.transfers.Verify(ctx, result.Item.Reference)
+		switch {
+		case err != nil:
+			result.Error = err.Error()
+			unverified = append(unverified, result)
+		case rsp.NotFound():
+			unsent = append(unsent, result)
+		case rsp.Err() != nil:
+			result.Error = rsp.Message
+			unverified = append(unverified, result)
+		default:
+			accept(result, rsp.Data.TransferCode.String(), rsp.Data.Status)
+		}
+	}
+
+	return unsent, unverified
+}
+
+// duplicateReference reports whether a bulk request was rejected for a transfer reference
+// Paystack has already seen
+func duplicateReference(message string) bool {
+	message = strings.ToLower(message)
+
+	return strings.Contains(message, "duplicate") ||
+		(strings.Contains(message, "reference") && strings.Contains(message, "already"))
+}
+
+func accept(result *BulkResult, transferCode string, status enums.TransferStatus) {
+	result.TransferCode, result.TransferStatus = transferCode, status
+	result.Outcome, result.Error = BulkAccepted, ""
+
+	if status == enums.TransferStatusOTP {
+		result.Outcome = BulkAwaitingOTP
+	}
+	if status.IsTerminal() && !status.IsSuccessful() {
+		result.Outcome, result.Error = BulkRejected, "transfer "+status.String()
+	}
+}
+
+// limiter spaces out requests shared by several goroutines
+type limiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// wait blocks until the next request may start
+func (l *limiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	at := now
+	if l.next.After(now) {
+		at = l.next
+	}
+	l.next = at.Add(l.interval)
+	l.mu.Unlock()
+
+	if !sleep(ctx, at.Sub(now)) {
+		return ctx.Err()
+	}
+
+	return nil
+}
+
+// sleep waits for d and reports whether ctx is still live
+func sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,342 @@
+package payouts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/huysamen/paystack-go/api/transfers"
+	"github.com/huysamen/paystack-go/internal/apitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bulkAPI queues every transfer it is sent, unless fail says otherwise, and answers
+// verifications from the transfers it queued
+type bulkAPI struct {
+	t *testing.T
+	// fail returns the status code to fail a bulk request with, or 0 to accept it
+	fail func(call int, references []string) int
+
+	mu       sync.Mutex
+	calls    int
+	inFlight int
+	peak     int
+	sizes    []int
+	queued   map[string]int
+	verified []string
+}
+
+func newBulkAPI(t *testing.T) *bulkAPI {
+	return &bulkAPI{t: t, queued: make(map[string]int)}
+}
+
+func (a *bulkAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if reference, ok := strings.CutPrefix(r.URL.Path, "/transfer/verify/"); ok {
+		a.mu.Lock()
+		a.verified = append(a.verified, reference)
+		_, queued := a.queued[reference]
+		a.mu.Unlock()
+
+		if !queued {
+			reply := apitest.NotFound("Transfer not found")
+			w.WriteHeader(reply.Code)
+			_, _ = w.Write([]byte(reply.Body))
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"status":true,"message":"ok","data":{"reference":%q,"status":"pending","transfer_code":"TRF_%s"}}`, reference, reference)
+		return
+	}
+
+	require.Equal(a.t, "/transfer/bulk", r.URL.Path)
+
+	var req struct {
+		Transfers []transfers.BulkTransferItem `json:"transfers"`
+	}
+	require.NoError(a.t, json.NewDecoder(r.Body).Decode(&req))
+
+	references := make([]string, len(req.Transfers))
+	for i, item := range req.Transfers {
+		references[i] = item.Reference
+	}
+
+	a.mu.Lock()
+	a.calls++
+	call := a.calls
+	a.sizes = append(a.sizes, len(req.Transfers))
+	a.inFlight++
+	a.peak = max(a.peak, a.inFlight)
+	a.mu.Unlock()
+
+	// Hold the request so concurrent requests overlap
+	time.Sleep(5 * time.Millisecond)
+
+	a.mu.Lock()
+	a.inFlight--
+	code := 0
+	if a.fail != nil {
+		code = a.fail(call, references)
+	}
+	if code == 0 || code >= 500 {
+		for _, item := range req.Transfers {
+			a.queued[item.Reference]++
+		}
+	}
+	a.mu.Unlock()
+
+	switch {
+	case code >= 500:
+		w.WriteHeader(code)
+		return
+	case code != 0:
+		w.WriteHeader(code)
+		_, _ = w.Write([]byte(`{"status":false,"message":"Insufficient balance"}`))
+		return
+	}
+
+	data := make([]string, len(req.Transfers))
+	for i, item := range req.Transfers {
+		// Answer in reverse order, so results are matched by reference rather than position
+		data[len(data)-1-i] = fmt.Sprintf(`{"reference":%q,"recipient":%q,"amount":%d,"transfer_code":"TRF_%s","currency":"NGN","status":"received"}`,
+			item.Reference, item.Recipient, item.Amount, item.Reference)
+	}
+	_, _ = fmt.Fprintf(w, `{"status":true,"message":"%d transfers queued.","data":[%s]}`, len(data), strings.Join(data, ","))
+}
+
+// testBulkPayer sends requests to server, whose queued replies come before fake's
+func testBulkPayer(t *testing.T, fake *bulkAPI, server ...*apitest.Server) *BulkPayer {
+	srv := apitest.NewServer(t)
+	if len(server) > 0 {
+		srv = server[0]
+	}
+	srv.Fallback(fake)
+
+	return NewBulkPayer((*transfers.Client)(srv.API())).
+		Interval(0).
+		RetryDelay(time.Millisecond)
+}
+
+func bulkItems(n int) []transfers.BulkTransferItem {
+	items := make([]transfers.BulkTransferItem, n)
+	for i := range items {
+		items[i] = transfers.BulkTransferItem{Amount: 1000 + i, Reference: fmt.Sprintf("bulk-%03d", i), Reason: "Payroll", Recipient: fmt.Sprintf("RCP_%d", i)}
+	}
+
+	return items
+}
+
+func TestBulkPayer_Chunks(t *testing.T) {
+	fake := newBulkAPI(t)
+
+	report, err := testBulkPayer(t, fake).Concurrency(3).Pay(context.Background(), bulkItems(250))
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, report.Chunks)
+	assert.ElementsMatch(t, []int{100, 100, 50}, fake.sizes)
+	assert.LessOrEqual(t, fake.peak, 3)
+	assert.True(t, report.Accepted())
+
+	require.Len(t, report.Results, 250)
+	for i, result := range report.Results {
+		assert.Equal(t, i, result.Index)
+		assert.Equal(t, i/100, result.Chunk)
+		assert.Equal(t, fmt.Sprintf("bulk-%03d", i), result.Item.Reference)
+		assert.Equal(t, "TRF_"+result.Item.Reference, result.TransferCode)
+		assert.Equal(t, "received", result.TransferStatus.String())
+		assert.Equal(t, 1, result.Attempts)
+	}
+}
+
+func TestBulkPayer_Concurrency(t *testing.T) {
+	fake := newBulkAPI(t)
+
+	_, err := testBulkPayer(t, fake).ChunkSize(2).Concurrency(2).Pay(context.Background(), bulkItems(20))
+	require.NoError(t, err)
+
+	assert.Len(t, fake.sizes, 10)
+	assert.Equal(t, 2, fake.peak)
+}
+
+func TestBulkPayer_RateLimit(t *testing.T) {
+	fake := newBulkAPI(t)
+
+	start := time.Now()
+	_, err := testBulkPayer(t, fake).ChunkSize(1).Concurrency(4).Interval(20*time.Millisecond).Pay(context.Background(), bulkItems(4))
+	require.NoError(t, err)
+
+	// Four requests, 20ms apart
+	assert.GreaterOrEqual(t, time.Since(start), 60*time.Millisecond)
+}
+
+func TestBulkPayer_RetriesFailedChunks(t *testing.T) {
+	t.Run("queued before failing", func(t *testing.T) {
+		fake := newBulkAPI(t)
+		fake.fail = func(call int, _ []string) int {
+			if call == 1 {
+				return http.StatusBadGateway
+			}
+			return 0
+		}
+
+		report, err := testBulkPayer(t, fake).Pay(context.Background(), bulkItems(3))
+		require.NoError(t, err)
+
+		// The failed request queued the transfers, so they are verified rather than resent
+		assert.True(t, report.Accepted())
+		assert.Equal(t, []int{3}, fake.sizes)
+		assert.Len(t, fake.verified, 3)
+		for _, n := range fake.queued {
+			assert.Equal(t, 1, n)
+		}
+	})
+
+	t.Run("never queued", func(t *testing.T) {
+		fake := newBulkAPI(t)
+		// A gateway that drops the first request before it reaches Paystack
+		srv := apitest.NewServer(t).On("/transfer/bulk", apitest.ServerError)
+
+		report, err := testBulkPayer(t, fake, srv).Pay(context.Background(), bulkItems(3))
+		require.NoError(t, err)
+
+		assert.True(t, report.Accepted())
+		assert.Equal(t, []int{3}, fake.sizes)
+		for _, result := range report.Results {
+			assert.Equal(t, 2, result.Attempts)
+		}
+	})
+
+	t.Run("rate limited", func(t *testing.T) {
+		fake := newBulkAPI(t)
+		srv := apitest.NewServer(t).On("/transfer/bulk", apitest.RateLimited())
+
+		report, err := testBulkPayer(t, fake, srv).Pay(context.Background(), bulkItems(3))
+		require.NoError(t, err)
+
+		// A refused request queued nothing, so it is sent again without verifying
+		assert.True(t, report.Accepted())
+		assert.Equal(t, []int{3}, fake.sizes)
+		assert.Empty(t, fake.verified)
+		for _, result := range report.Results {
+			assert.Equal(t, 2, result.Attempts)
+		}
+	})
+
+	t.Run("rate limited verify", func(t *testing.T) {
+		fake := newBulkAPI(t)
+		fake.fail = func(call int, _ []string) int {
+			if call == 1 {
+				return http.StatusBadGateway
+			}
+			return 0
+		}
+		srv := apitest.NewServer(t).On("/transfer/verify/bulk-000", apitest.RateLimited())
+
+		report, err := testBulkPayer(t, fake, srv).Pay(context.Background(), bulkItems(2))
+		require.NoError(t, err)
+
+		// The transfer that could not be verified is verified again, not resent
+		assert.True(t, report.Accepted())
+		assert.Equal(t, []int{2}, fake.sizes)
+		assert.Equal(t, []string{"bulk-001", "bulk-000"}, fake.verified)
+		for _, n := range fake.queued {
+			assert.Equal(t, 1, n)
+		}
+	})
+
+	t.Run("gives up", func(t *testing.T) {
+		unavailable := apitest.Reply{Code: http.StatusServiceUnavailable}
+		srv := apitest.NewServer(t).
+			On("/transfer/bulk", unavailable, unavailable).
+			On("/transfer/verify/bulk-000", apitest.NotFound("Transfer not found")).
+			On("/transfer/verify/bulk-001", apitest.NotFound("Transfer not found"))
+
+		payer := NewBulkPayer((*transfers.Client)(srv.API())).
+			Interval(0).
+			RetryDelay(time.Millisecond).
+			Attempts(2)
+
+		report, err := payer.Pay(context.Background(), bulkItems(2))
+		require.NoError(t, err)
+
+		unknown := report.Outcome(BulkUnknown)
+		require.Len(t, unknown, 2)
+		assert.Equal(t, 2, unknown[0].Attempts)
+		assert.Contains(t, unknown[0].Error, "HTTP 503")
+	})
+}
+
+func TestBulkPayer_DuplicateReference(t *testing.T) {
+	fake := newBulkAPI(t)
+	// bulk-001 was queued by an earlier run, so Paystack rejects the whole chunk
+	fake.queued["bulk-001"] = 1
+	srv := apitest.NewServer(t).On("/transfer/bulk", apitest.Rejected("Duplicate Transaction Reference"))
+
+	report, err := testBulkPayer(t, fake, srv).Pay(context.Background(), bulkItems(3))
+	require.NoError(t, err)
+
+	assert.True(t, report.Accepted())
+	assert.Equal(t, []int{2}, fake.sizes, "only the transfers Paystack has not seen are sent again")
+	assert.ElementsMatch(t, []string{"bulk-000", "bulk-001", "bulk-002"}, fake.verified)
+	assert.Equal(t, []int{2, 1, 2}, []int{report.Results[0].Attempts, report.Results[1].Attempts, report.Results[2].Attempts})
+	assert.Equal(t, "TRF_bulk-001", report.Results[1].TransferCode)
+}
+
+func TestBulkPayer_RejectedChunk(t *testing.T) {
+	fake := newBulkAPI(t)
+	fake.fail = func(_ int, references []string) int {
+		if references[0] == "bulk-002" {
+			return http.StatusBadRequest
+		}
+		return 0
+	}
+
+	report, err := testBulkPayer(t, fake).ChunkSize(2).Pay(context.Background(), bulkItems(5))
+	require.NoError(t, err)
+
+	assert.False(t, report.Accepted())
+	assert.Len(t, report.Outcome(BulkAccepted), 3)
+
+	rejected := report.Outcome(BulkRejected)
+	require.Len(t, rejected, 2)
+	assert.Equal(t, []int{2, 3}, []int{rejected[0].Index, rejected[1].Index})
+	assert.Equal(t, "Insufficient balance", rejected[0].Error)
+	assert.Equal(t, 1, rejected[0].Attempts)
+}
+
+func TestBulkPayer_Cancelled(t *testing.T) {
+	fake := newBulkAPI(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	fake.fail = func(call int, _ []string) int {
+		if call == 2 {
+			cancel()
+		}
+		return 0
+	}
+
+	report, err := testBulkPayer(t, fake).ChunkSize(1).Concurrency(1).Pay(ctx, bulkItems(3))
+	require.ErrorIs(t, err, context.Canceled)
+
+	assert.Equal(t, BulkAccepted, report.Results[0].Outcome)
+	assert.Equal(t, BulkNotSent, report.Results[2].Outcome)
+	assert.Zero(t, report.Results[2].Attempts)
+}
+
+func TestBulkPayer_References(t *testing.T) {
+	payer := NewBulkPayer(&transfers.Client{})
+
+	items := bulkItems(2)
+	items[1].Reference = ""
+	_, err := payer.Pay(context.Background(), items)
+	assert.ErrorIs(t, err, ErrMissingReference)
+
+	items[1].Reference = items[0].Reference
+	_, err = payer.Pay(context.Background(), items)
+	assert.ErrorIs(t, err, ErrDuplicateReference)
+}
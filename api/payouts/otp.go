@@ -0,0 +1,485 @@
+package payouts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/huysamen/paystack-go/api/transfers"
+	"github.com/huysamen/paystack-go/api/transferscontrol"
+	"github.com/huysamen/paystack-go/types"
+)
+
+const (
+	// DefaultOTPTimeout is how long an OTPWorkflow waits for a valid OTP in total
+	DefaultOTPTimeout = 30 * time.Minute
+	// DefaultOTPExpiry is how long an OTP is used for before a new one is sent
+	DefaultOTPExpiry = 10 * time.Minute
+	// DefaultOTPResends is how many times an OTPWorkflow asks Paystack for a new OTP
+	DefaultOTPResends = 2
+	// DefaultOTPAttempts is how many OTPs an OTPWorkflow submits before giving up
+	DefaultOTPAttempts = 3
+)
+
+var (
+	// ErrOTPExpired is returned by an OTPProvider when the OTP it was asked for expired,
+	// and wrapped by OTPResult.Err when no more OTPs may be sent
+	ErrOTPExpired = errors.New("OTP expired")
+	// ErrOTPRejected is wrapped by OTPResult.Err when Paystack rejected every OTP submitted
+	ErrOTPRejected = errors.New("OTP rejected")
+	// ErrOTPTimeout is wrapped by OTPResult.Err when no OTP arrived in time
+	ErrOTPTimeout = errors.New("timed out waiting for OTP")
+	// ErrNoOTPRequest is returned by ChannelOTPProvider.Submit when nothing waits for the OTP
+	ErrNoOTPRequest = errors.New("no OTP request waiting")
+)
+
+// OTPPurpose tells what an OTP is for
+type OTPPurpose string
+
+const (
+	// OTPPurposeTransfer OTPs finalize a transfer
+	OTPPurposeTransfer OTPPurpose = "transfer"
+	// OTPPurposeDisableOTP OTPs confirm turning off OTPs for transfers
+	OTPPurposeDisableOTP OTPPurpose = "disable_otp"
+)
+
+// OTPRequest is a request for the OTP Paystack sent to the business
+type OTPRequest struct {
+	Purpose OTPPurpose
+	// Reference and TransferCode identify the transfer; both are empty for
+	// OTPPurposeDisableOTP
+	Reference    string
+	TransferCode string
+	Amount       int
+	Currency     string
+	// Recipient is the name of the account paid, when known
+	Recipient string
+	Reason    string
+	// Attempt counts the OTPs requested for the transfer, from 1
+	Attempt int
+	// Rejection is Paystack's message for the previous OTP, e.g. "Invalid OTP"
+	Rejection string
+	// ExpiresAt is when the OTP is considered expired and a new one is sent
+	ExpiresAt time.Time
+}
+
+// otpKey identifies a request in a ChannelOTPProvider. The purpose keeps a transfer whose
+// reference happens to be "disable_otp" apart from the request to disable OTPs.
+type otpKey struct {
+	purpose   OTPPurpose
+	reference string
+}
+
+func (r OTPRequest) key() otpKey {
+	if r.Purpose == "" {
+		return otpKey{purpose: OTPPurposeTransfer, reference: r.Reference}
+	}
+
+	return otpKey{purpose: r.Purpose, reference: r.Reference}
+}
+
+// OTPProvider supplies the OTPs Paystack sends to the business, e.g. by asking an
+// administrator for them
+type OTPProvider interface {
+	// OTP returns the OTP for req, blocking until it is known. It should return when ctx
+	// ends, which happens when the OTP expires; returning ErrOTPExpired asks for a new OTP
+	// before then.
+	OTP(ctx context.Context, req OTPRequest) (string, error)
+}
+
+// OTPProviderFunc adapts a function to an OTPProvider
+type OTPProviderFunc func(ctx context.Context, req OTPRequest) (string, error)
+
+// OTP implements OTPProvider
+func (f OTPProviderFunc) OTP(ctx context.Context, req OTPRequest) (string, error) {
+	return f(ctx, req)
+}
+
+// ChannelOTPProvider is an OTPProvider filled in by another part of the application, e.g.
+// an admin UI. Requests are published on Requests and listed by Pending; the OTP an
+// administrator enters is passed to Submit with the request's reference.
+//
+// A ChannelOTPProvider is safe for concurrent use.
+type ChannelOTPProvider struct {
+	requests chan OTPRequest
+
+	mu      sync.Mutex
+	pending map[otpKey]*otpWaiter
+}
+
+type otpWaiter struct {
+	req OTPRequest
+	otp chan string
+}
+
+// NewChannelOTPProvider creates a ChannelOTPProvider that buffers up to buffer requests on
+// Requests. Requests that don't fit are still listed by Pending.
+func NewChannelOTPProvider(buffer int) *ChannelOTPProvider {
+	return &ChannelOTPProvider{
+		requests: make(chan OTPRequest, max(buffer, 0)),
+		pending:  make(map[otpKey]*otpWaiter),
+	}
+}
+
+// Requests returns the channel requests are published on
+func (p *ChannelOTPProvider) Requests() <-chan OTPRequest {
+	return p.requests
+}
+
+// Pending returns the requests waiting for an OTP
+func (p *ChannelOTPProvider) Pending() []OTPRequest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	requests := make([]OTPRequest, 0, len(p.pending))
+	for _, w := range p.pending {
+		requests = append(requests, w.req)
+	}
+
+	return requests
+}
+
+// Submit delivers the OTP for the transfer with reference. It returns ErrNoOTPRequest
+// when no request is waiting.
+func (p *ChannelOTPProvider) Submit(reference, otp string) error {
+	return p.submit(otpKey{purpose: OTPPurposeTransfer, reference: reference}, otp)
+}
+
+// SubmitDisableOTP delivers the OTP for disabling OTPs. It returns ErrNoOTPRequest when no
+// request is waiting.
+func (p *ChannelOTPProvider) SubmitDisableOTP(otp string) error {
+	return p.submit(otpKey{purpose: OTPPurposeDisableOTP}, otp)
+}
+
+// SubmitFor delivers the OTP for req, as published on Requests or listed by Pending
+func (p *ChannelOTPProvider) SubmitFor(req OTPRequest, otp string) error {
+	return p.submit(req.key(), otp)
+}
+
+func (p *ChannelOTPProvider) submit(key otpKey, otp string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	w, ok := p.pending[key]
+	if !ok {
+		return fmt.Errorf("%w: %s %s", ErrNoOTPRequest, key.purpose, key.reference)
+	}
+
+	select {
+	case w.otp <- otp:
+		delete(p.pending, key)
+		return nil
+	default:
+		return fmt.Errorf("%w: %s %s", ErrNoOTPRequest, key.purpose, key.reference)
+	}
+}
+
+// OTP implements OTPProvider
+func (p *ChannelOTPProvider) OTP(ctx context.Context, req OTPRequest) (string, error) {
+	w := &otpWaiter{req: req, otp: make(chan string, 1)}
+	key := req.key()
+
+	p.mu.Lock()
+	p.pending[key] = w
+	p.mu.Unlock()
+
+	defer func() {
+		p.mu.Lock()
+		if p.pending[key] == w {
+			delete(p.pending, key)
+		}
+		p.mu.Unlock()
+	}()
+
+	select {
+	case p.requests <- req:
+	default:
+	}
+
+	select {
+	case otp := <-w.otp:
+		return otp, nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// OTPOutcome is how an OTP workflow ended
+type OTPOutcome string
+
+const (
+	// OTPFinalized workflows had an OTP accepted
+	OTPFinalized OTPOutcome = "finalized"
+	// OTPRejected workflows had every OTP submitted rejected
+	OTPRejected OTPOutcome = "rejected"
+	// OTPExpired workflows ran out of resends without an OTP
+	OTPExpired OTPOutcome = "expired"
+	// OTPTimedOut workflows got no OTP within the timeout
+	OTPTimedOut OTPOutcome = "timed_out"
+)
+
+// OTPResult is the outcome of an OTP workflow
+type OTPResult struct {
+	Outcome OTPOutcome
+	// Transfer is the finalized transfer, for OTPFinalized transfer workflows
+	Transfer *types.Transfer
+	// Attempts counts the OTPs submitted and Resends the new OTPs sent
+	Attempts int
+	Resends  int
+	// Rejection is Paystack's message for the last OTP rejected
+	Rejection string
+}
+
+// Err returns nil for OTPFinalized results, and otherwise an error wrapping ErrOTPRejected,
+// ErrOTPExpired or ErrOTPTimeout
+func (r *OTPResult) Err() error {
+	switch r.Outcome {
+	case OTPFinalized:
+		return nil
+	case OTPRejected:
+		return fmt.Errorf("%w after %d attempts: %s", ErrOTPRejected, r.Attempts, r.Rejection)
+	case OTPExpired:
+		return fmt.Errorf("%w after %d resends", ErrOTPExpired, r.Resends)
+	default:
+		return ErrOTPTimeout
+	}
+}
+
+// OTPWorkflow finalizes transfers that need an OTP. It asks an OTPProvider for each OTP,
+// submits it, asks for another when Paystack rejects it, and has Paystack send a new OTP
+// when one is not provided before it expires.
+//
+// Payer and BulkPayer use an OTPWorkflow set with their OTP method to finalize transfers
+// without the caller's involvement.
+type OTPWorkflow struct {
+	transfers *transfers.Client
+	control   *transferscontrol.Client
+	provider  OTPProvider
+	timeout   time.Duration
+	expiry    time.Duration
+	resends   int
+	attempts  int
+	now       func() time.Time
+}
+
+// NewOTPWorkflow creates an OTPWorkflow with the Transfers and TransferControl clients of a
+// paystack.Client
+func NewOTPWorkflow(transfers *transfers.Client, control *transferscontrol.Client, provider OTPProvider) *OTPWorkflow {
+	return &OTPWorkflow{
+		transfers: transfers,
+		control:   control,
+		provider:  provider,
+		timeout:   DefaultOTPTimeout,
+		expiry:    DefaultOTPExpiry,
+		resends:   DefaultOTPResends,
+		attempts:  DefaultOTPAttempts,
+		now:       time.Now,
+	}
+}
+
+// Timeout sets how long to wait for a valid OTP in total
+func (w *OTPWorkflow) Timeout(d time.Duration) *OTPWorkflow {
+	w.timeout = d
+
+	return w
+}
+
+// Expiry sets how long to wait for each OTP before a new one is sent
+func (w *OTPWorkflow) Expiry(d time.Duration) *OTPWorkflow {
+	w.expiry = d
+
+	return w
+}
+
+// Resends sets how many times a new OTP is sent
+func (w *OTPWorkflow) Resends(n int) *OTPWorkflow {
+	w.resends = max(n, 0)
+
+	return w
+}
+
+// Attempts sets how many OTPs are submitted before giving up
+func (w *OTPWorkflow) Attempts(n int) *OTPWorkflow {
+	w.attempts = max(n, 1)
+
+	return w
+}
+
+// Finalize finalizes the transfer described by req, which needs Reference and
+// TransferCode. Running out of OTPs, attempts or time is reported in the OTPResult; an
+// error means the provider or a request failed, or ctx ended, and the transfer may still
+// need an OTP.
+func (w *OTPWorkflow) Finalize(ctx context.Context, req OTPRequest) (*OTPResult, error) {
+	req.Purpose = OTPPurposeTransfer
+
+	var transfer *types.Transfer
+
+	result, err := w.run(ctx, req,
+		func(ctx context.Context, otp string) (string, error) {
+			rsp, err := w.transfers.Finalize(ctx, *transfers.NewFinalizeRequestBuilder(req.TransferCode, otp))
+			if err != nil {
+				return "", fmt.Errorf("finalizing transfer %s: %w", req.Reference, err)
+			}
+			rejection, err := otpRejection(rsp)
+			if err != nil {
+				return "", fmt.Errorf("finalizing transfer %s: %w", req.Reference, err)
+			}
+			if rejection == "" {
+				transfer = &rsp.Data
+			}
+
+			return rejection, nil
+		},
+		func(ctx context.Context) error {
+			rsp, err := w.control.ResendOTP(ctx, *transferscontrol.NewResendOTPRequestBuilder().
+				TransferCode(req.TransferCode).
+				Reason("resend_otp"))
+			if err != nil {
+				return fmt.Errorf("resending OTP for transfer %s: %w", req.Reference, err)
+			}
+
+			return rsp.Err()
+		})
+	if result != nil {
+		result.Transfer = transfer
+	}
+
+	return result, err
+}
+
+// DisableOTP turns off OTPs for transfers, confirming the change with the OTP Paystack
+// sends to the business
+func (w *OTPWorkflow) DisableOTP(ctx context.Context) (*OTPResult, error) {
+	request := func(ctx context.Context) error {
+		rsp, err := w.control.DisableOTP(ctx)
+		if err != nil {
+			return fmt.Errorf("requesting OTP to disable transfer OTPs: %w", err)
+		}
+
+		return rsp.Err()
+	}
+
+	if err := request(ctx); err != nil {
+		return nil, err
+	}
+
+	return w.run(ctx, OTPRequest{Purpose: OTPPurposeDisableOTP},
+		func(ctx context.Context, otp string) (string, error) {
+			rsp, err := w.control.FinalizeDisableOTP(ctx, *transferscontrol.NewFinalizeDisableOTPRequestBuilder().OTP(otp))
+			if err != nil {
+				return "", fmt.Errorf("disabling transfer OTPs: %w", err)
+			}
+			rejection, err := otpRejection(rsp)
+			if err != nil {
+				return "", fmt.Errorf("disabling transfer OTPs: %w", err)
+			}
+
+			return rejection, nil
+		},
+		request)
+}
+
+// run asks for OTPs and submits them until one is accepted. submit returns Paystack's
+// message when it rejects the OTP; resend has a new OTP sent.
+func (w *OTPWorkflow) run(ctx context.Context, req OTPRequest,
+	submit func(ctx context.Context, otp string) (string, error), resend func(ctx context.Context) error,
+) (*OTPResult, error) {
+	parent := ctx
+	if w.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, w.timeout)
+		defer cancel()
+	}
+
+	result := &OTPResult{}
+	sentAt := w.now()
+
+	for {
+		req.Attempt = result.Attempts + result.Resends + 1
+		req.ExpiresAt = sentAt.Add(w.expiry)
+
+		otpCtx, cancel := context.WithDeadline(ctx, req.ExpiresAt)
+		otp, err := w.provider.OTP(otpCtx, req)
+		expired := errors.Is(err, ErrOTPExpired) || (err != nil && otpCtx.Err() != nil && ctx.Err() == nil)
+		cancel()
+
+		if err != nil && !expired {
+			if parent.Err() == nil && ctx.Err() != nil {
+				result.Outcome = OTPTimedOut
+				return result, nil
+			}
+
+			return nil, err
+		}
+
+		if !expired {
+			result.Attempts++
+
+			rejection, err := submit(ctx, otp)
+			if err != nil {
+				return nil, err
+			}
+			if rejection == "" {
+				result.Outcome = OTPFinalized
+				return result, nil
+			}
+
+			result.Rejection, req.Rejection = rejection, rejection
+			if !isExpiredOTP(rejection) {
+				if result.Attempts >= w.attempts {
+					result.Outcome = OTPRejected
+					return result, nil
+				}
+				continue
+			}
+		}
+
+		if result.Resends >= w.resends {
+			result.Outcome = OTPExpired
+			return result, nil
+		}
+		if err := resend(ctx); err != nil {
+			return nil, err
+		}
+		result.Resends++
+		sentAt = w.now()
+	}
+}
+
+// otpRejection returns Paystack's message when it rejected the OTP itself, e.g. "Invalid
+// OTP". Other failures, like rate limits, a bad secret key or a transfer that is already
+// finalized, are returned as errors rather than counted as a wrong OTP.
+func otpRejection[T any](rsp *types.Response[T]) (string, error) {
+	switch {
+	case rsp.Err() == nil:
+		return "", nil
+	case !rsp.Retryable() && !rsp.Unauthorized() && mentionsOTP(rsp.Message):
+		return rsp.Message, nil
+	default:
+		return "", rsp.Err()
+	}
+}
+
+// isExpiredOTP reports whether Paystack rejected an OTP because it expired, in which case
+// a new OTP is sent rather than the attempt counted as a wrong OTP
+func isExpiredOTP(message string) bool {
+	return mentionsOTP(message) && hasWord(message, "expired")
+}
+
+func mentionsOTP(message string) bool {
+	return hasWord(message, "otp")
+}
+
+// hasWord reports whether message contains word, ignoring case and punctuation
+func hasWord(message, word string) bool {
+	fields := strings.FieldsFunc(strings.ToLower(message), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	return slices.Contains(fields, word)
+}
@@ -0,0 +1,370 @@
+package payouts
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/huysamen/paystack-go/api/transfers"
+	"github.com/huysamen/paystack-go/api/transferscontrol"
+	"github.com/huysamen/paystack-go/internal/apitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	pathResendOTP       = "/transfer/resend_otp"
+	pathDisableOTP      = "/transfer/disable_otp"
+	pathFinalizeDisable = "/transfer/disable_otp_finalize"
+)
+
+// otpSequence is an OTPProvider that answers requests in turn and records them. An empty
+// OTP waits for the request to expire.
+type otpSequence struct {
+	mu       sync.Mutex
+	otps     []string
+	requests []OTPRequest
+}
+
+func (s *otpSequence) OTP(ctx context.Context, req OTPRequest) (string, error) {
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	otp := ""
+	if len(s.otps) > 0 {
+		otp, s.otps = s.otps[0], s.otps[1:]
+	}
+	s.mu.Unlock()
+
+	if otp == "" {
+		<-ctx.Done()
+		return "", ctx.Err()
+	}
+
+	return otp, nil
+}
+
+func testWorkflow(fake *apitest.Server, provider OTPProvider) *OTPWorkflow {
+	client := fake.API()
+
+	return NewOTPWorkflow((*transfers.Client)(client), (*transferscontrol.Client)(client), provider).
+		Expiry(50 * time.Millisecond).
+		Timeout(time.Second)
+}
+
+func otpRequest() OTPRequest {
+	return OTPRequest{Reference: "payout-1", TransferCode: "TRF_1ptvuv321ahaa7q", Amount: 50000}
+}
+
+func TestOTPWorkflow_Finalize(t *testing.T) {
+	tests := []struct {
+		name     string
+		otps     []string
+		finalize []apitest.Reply
+		resends  int
+		outcome  OTPOutcome
+		err      error
+		attempts int
+	}{
+		{
+			name:     "accepted",
+			otps:     []string{"928783"},
+			finalize: []apitest.Reply{transfer("success", "payout-1")},
+			outcome:  OTPFinalized,
+			attempts: 1,
+		},
+		{
+			name:     "wrong OTP then right",
+			otps:     []string{"000000", "928783"},
+			finalize: []apitest.Reply{apitest.Rejected("Invalid OTP"), transfer("pending", "payout-1")},
+			outcome:  OTPFinalized,
+			attempts: 2,
+		},
+		{
+			name:     "rejected",
+			otps:     []string{"1", "2", "3"},
+			finalize: []apitest.Reply{apitest.Rejected("Invalid OTP"), apitest.Rejected("Invalid OTP"), apitest.Rejected("Invalid OTP")},
+			outcome:  OTPRejected,
+			err:      ErrOTPRejected,
+			attempts: 3,
+		},
+		{
+			name:     "resent after expiring unanswered",
+			otps:     []string{"", "928783"},
+			finalize: []apitest.Reply{transfer("success", "payout-1")},
+			resends:  1,
+			outcome:  OTPFinalized,
+			attempts: 1,
+		},
+		{
+			name:     "resent when Paystack says the OTP expired",
+			otps:     []string{"111111", "928783"},
+			finalize: []apitest.Reply{apitest.Rejected("OTP has expired"), transfer("success", "payout-1")},
+			resends:  1,
+			outcome:  OTPFinalized,
+			attempts: 2,
+		},
+		{
+			name:    "out of resends",
+			resends: 2,
+			outcome: OTPExpired,
+			err:     ErrOTPExpired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := apitest.NewServer(t).On(pathFinalize, tt.finalize...)
+			for i := 0; i < tt.resends; i++ {
+				fake.On(pathResendOTP, apitest.OK(`null`))
+			}
+			provider := &otpSequence{otps: tt.otps}
+
+			result, err := testWorkflow(fake, provider).Finalize(context.Background(), otpRequest())
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.outcome, result.Outcome)
+			assert.Equal(t, tt.attempts, result.Attempts)
+			assert.Equal(t, tt.resends, result.Resends)
+			if tt.err != nil {
+				assert.ErrorIs(t, result.Err(), tt.err)
+			} else {
+				assert.NoError(t, result.Err())
+				assert.Equal(t, "payout-1", result.Transfer.Reference.String())
+			}
+		})
+	}
+}
+
+func TestOTPWorkflow_FinalizeFailures(t *testing.T) {
+	tests := []struct {
+		name  string
+		reply apitest.Reply
+	}{
+		{name: "rate limited", reply: apitest.RateLimited()},
+		{name: "unauthorized", reply: apitest.Unauthorized()},
+		{name: "already finalized", reply: apitest.Rejected("Transfer has already been finalized")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := apitest.NewServer(t).On(pathFinalize, tt.reply)
+			provider := &otpSequence{otps: []string{"928783", "123456"}}
+
+			result, err := testWorkflow(fake, provider).Finalize(context.Background(), otpRequest())
+			require.Error(t, err)
+			assert.Nil(t, result)
+			assert.Len(t, provider.requests, 1)
+			assert.Equal(t, []string{pathFinalize}, fake.Paths())
+		})
+	}
+}
+
+func TestOTPWorkflow_DisableOTPRateLimited(t *testing.T) {
+	fake := apitest.NewServer(t).
+		On(pathDisableOTP, apitest.OK(`null`)).
+		On(pathFinalizeDisable, apitest.RateLimited())
+	provider := &otpSequence{otps: []string{"928783", "123456"}}
+
+	_, err := testWorkflow(fake, provider).DisableOTP(context.Background())
+	require.Error(t, err)
+	assert.Len(t, provider.requests, 1)
+}
+
+func TestIsExpiredOTP(t *testing.T) {
+	assert.True(t, isExpiredOTP("OTP has expired"))
+	assert.True(t, isExpiredOTP("Expired OTP. Please request a new one"))
+	assert.False(t, isExpiredOTP("Invalid OTP"))
+	assert.False(t, isExpiredOTP("Transfer request has expired"))
+	assert.False(t, isExpiredOTP("Your session expired"))
+}
+
+func TestOTPWorkflow_Requests(t *testing.T) {
+	fake := apitest.NewServer(t).
+		On(pathFinalize, apitest.Rejected("Invalid OTP"), transfer("success", "payout-1")).
+		On(pathResendOTP, apitest.OK(`null`))
+	provider := &otpSequence{otps: []string{"000000", "", "928783"}}
+
+	_, err := testWorkflow(fake, provider).Finalize(context.Background(), otpRequest())
+	require.NoError(t, err)
+
+	require.Len(t, provider.requests, 3)
+	assert.Equal(t, []int{1, 2, 3}, []int{provider.requests[0].Attempt, provider.requests[1].Attempt, provider.requests[2].Attempt})
+	assert.Equal(t, OTPPurposeTransfer, provider.requests[0].Purpose)
+	assert.Empty(t, provider.requests[0].Rejection)
+	assert.Equal(t, "Invalid OTP", provider.requests[1].Rejection)
+	assert.Equal(t, provider.requests[0].ExpiresAt, provider.requests[1].ExpiresAt)
+	assert.True(t, provider.requests[2].ExpiresAt.After(provider.requests[1].ExpiresAt))
+
+	assert.Equal(t, map[string]any{"transfer_code": "TRF_1ptvuv321ahaa7q", "reason": "resend_otp"}, fake.Body(pathResendOTP))
+}
+
+func TestOTPWorkflow_Timeout(t *testing.T) {
+	fake := apitest.NewServer(t)
+	workflow := testWorkflow(fake, &otpSequence{}).Timeout(20 * time.Millisecond)
+
+	result, err := workflow.Finalize(context.Background(), otpRequest())
+	require.NoError(t, err)
+	assert.Equal(t, OTPTimedOut, result.Outcome)
+	assert.ErrorIs(t, result.Err(), ErrOTPTimeout)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = workflow.Finalize(ctx, otpRequest())
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestOTPWorkflow_ProviderError(t *testing.T) {
+	boom := errors.New("admin UI unavailable")
+	provider := OTPProviderFunc(func(context.Context, OTPRequest) (string, error) { return "", boom })
+
+	_, err := testWorkflow(apitest.NewServer(t), provider).Finalize(context.Background(), otpRequest())
+	assert.ErrorIs(t, err, boom)
+}
+
+func TestOTPWorkflow_DisableOTP(t *testing.T) {
+	fake := apitest.NewServer(t).
+		On(pathDisableOTP, apitest.OK(`null`)).
+		On(pathFinalizeDisable, apitest.OK(`null`))
+	provider := &otpSequence{otps: []string{"928783"}}
+
+	result, err := testWorkflow(fake, provider).DisableOTP(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, OTPFinalized, result.Outcome)
+	assert.Equal(t, OTPPurposeDisableOTP, provider.requests[0].Purpose)
+	assert.Equal(t, map[string]any{"otp": "928783"}, fake.Body(pathFinalizeDisable))
+	assert.Equal(t, []string{pathDisableOTP, pathFinalizeDisable}, fake.Paths())
+}
+
+func TestChannelOTPProvider(t *testing.T) {
+	provider := NewChannelOTPProvider(1)
+
+	assert.ErrorIs(t, provider.Submit("payout-1", "928783"), ErrNoOTPRequest)
+
+	done := make(chan string)
+	go func() {
+		otp, err := provider.OTP(context.Background(), otpRequest())
+		assert.NoError(t, err)
+		done <- otp
+	}()
+
+	req := <-provider.Requests()
+	assert.Equal(t, "payout-1", req.Reference)
+	assert.Equal(t, []OTPRequest{req}, provider.Pending())
+
+	require.NoError(t, provider.Submit("payout-1", "928783"))
+	assert.Equal(t, "928783", <-done)
+	assert.Empty(t, provider.Pending())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := provider.OTP(ctx, otpRequest())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Empty(t, provider.Pending())
+}
+
+func TestChannelOTPProvider_DisableOTP(t *testing.T) {
+	provider := NewChannelOTPProvider(2)
+
+	transfer := make(chan string)
+	disable := make(chan string)
+	go func() {
+		otp, err := provider.OTP(context.Background(), OTPRequest{Purpose: OTPPurposeTransfer, Reference: "disable_otp"})
+		assert.NoError(t, err)
+		transfer <- otp
+	}()
+	go func() {
+		otp, err := provider.OTP(context.Background(), OTPRequest{Purpose: OTPPurposeDisableOTP})
+		assert.NoError(t, err)
+		disable <- otp
+	}()
+
+	<-provider.Requests()
+	<-provider.Requests()
+	assert.Len(t, provider.Pending(), 2)
+
+	require.NoError(t, provider.SubmitDisableOTP("111111"))
+	assert.Equal(t, "111111", <-disable)
+	assert.ErrorIs(t, provider.SubmitDisableOTP("111111"), ErrNoOTPRequest)
+
+	require.NoError(t, provider.Submit("disable_otp", "222222"))
+	assert.Equal(t, "222222", <-transfer)
+	assert.Empty(t, provider.Pending())
+}
+
+func TestPayer_OTPWorkflow(t *testing.T) {
+	fake := apitest.NewServer(t).
+		On(pathResolve, resolved("JANE DOE")).
+		On(pathRecipient, recipient("RCP_1")).
+		On(pathTransfer, transfer("otp", "payout-1")).
+		On(pathFinalize, transfer("pending", "payout-1")).
+		On(pathVerify, transfer("success", "payout-1"))
+	provider := NewChannelOTPProvider(1)
+
+	go func() {
+		req := <-provider.Requests()
+		assert.Equal(t, "JANE DOE", req.Recipient)
+		assert.NoError(t, provider.Submit(req.Reference, "928783"))
+	}()
+
+	payer := testPayer(fake, NewMemoryStore()).OTP(testWorkflow(fake, provider))
+
+	got, err := payer.Pay(context.Background(), payout())
+	require.NoError(t, err)
+	assert.Equal(t, StatusSucceeded, got.Status)
+	assert.Equal(t, []string{pathResolve, pathRecipient, pathTransfer, pathFinalize, pathVerify}, fake.Paths())
+}
+
+func TestPayer_OTPWorkflowFails(t *testing.T) {
+	fake := apitest.NewServer(t).
+		On(pathResolve, resolved("JANE DOE")).
+		On(pathRecipient, recipient("RCP_1")).
+		On(pathTransfer, transfer("otp", "payout-1")).
+		On(pathResendOTP, apitest.OK(`null`))
+	store := NewMemoryStore()
+	payer := testPayer(fake, store).OTP(testWorkflow(fake, &otpSequence{}).Resends(1))
+
+	got, err := payer.Pay(context.Background(), payout())
+	require.ErrorIs(t, err, ErrOTPExpired)
+	assert.Equal(t, StatusAwaitingOTP, got.Status)
+	assert.Equal(t, StepFinalizeTransfer, got.Step)
+
+	// The OTP can still be entered by hand
+	fake.On(pathFinalize, transfer("success", "payout-1"))
+	got, err = payer.Finalize(context.Background(), "payout-1", "928783")
+	require.NoError(t, err)
+	assert.Equal(t, StatusSucceeded, got.Status)
+}
+
+func TestBulkPayer_OTPWorkflow(t *testing.T) {
+	fake := apitest.NewServer(t).
+		On("/transfer/bulk", apitest.OK(`[
+			{"reference":"bulk-000","transfer_code":"TRF_a","status":"otp"},
+			{"reference":"bulk-001","transfer_code":"TRF_b","status":"otp"}
+		]`)).
+		On(pathFinalize, transfer("pending", "bulk-000"), apitest.Rejected("Invalid OTP"))
+	client := (*transfers.Client)(fake.API())
+
+	items := bulkItems(2)
+
+	report, err := NewBulkPayer(client).Interval(0).Pay(context.Background(), items)
+	require.NoError(t, err)
+	assert.Len(t, report.Outcome(BulkAwaitingOTP), 2)
+
+	fake.On("/transfer/bulk", apitest.OK(`[
+		{"reference":"bulk-000","transfer_code":"TRF_a","status":"otp"},
+		{"reference":"bulk-001","transfer_code":"TRF_b","status":"otp"}
+	]`))
+	workflow := testWorkflow(fake, &otpSequence{otps: []string{"928783", "000000"}}).Attempts(1)
+
+	report, err = NewBulkPayer(client).Interval(0).OTP(workflow).Pay(context.Background(), items)
+	require.NoError(t, err)
+
+	assert.Equal(t, BulkAccepted, report.Results[0].Outcome)
+	assert.Equal(t, "pending", report.Results[0].TransferStatus.String())
+	assert.Equal(t, BulkAwaitingOTP, report.Results[1].Outcome)
+	assert.Contains(t, report.Results[1].Error, "Invalid OTP")
+	assert.Equal(t, map[string]any{"transfer_code": "TRF_b", "otp": "000000"}, fake.Body(pathFinalize))
+}
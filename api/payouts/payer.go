@@ -0,0 +1,434 @@
+package payouts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/huysamen/paystack-go/api/transferrecipients"
+	"github.com/huysamen/paystack-go/api/transfers"
+	"github.com/huysamen/paystack-go/api/verification"
+	"github.com/huysamen/paystack-go/api/webhook"
+	"github.com/huysamen/paystack-go/enums"
+	"github.com/huysamen/paystack-go/types"
+)
+
+// DefaultSource is the source transfers are paid from
+const DefaultSource = "balance"
+
+// TransferWaiter waits for the final outcome of a transfer. It is implemented by
+// *webhook.Awaiter.
+type TransferWaiter interface {
+	WaitTransfer(ctx context.Context, reference string) (*webhook.AwaitResult, error)
+}
+
+// Payer pays bank accounts end to end. For each payout it resolves the account, creates a
+// transfer recipient for it or reuses the one saved for the account, initiates the
+// transfer, finalizes it with an OTP when the integration requires one, and waits for the
+// outcome from a webhook or a poll of the API.
+//
+// Progress is saved in a Store after every step. A payout interrupted by a crash or an
+// error resumes from its last step with Resume, or with Pay and the same reference. A
+// transfer whose initiation may have reached Paystack is verified by reference before it
+// is initiated again, so a payout never sends two transfers.
+//
+// Don't run the same payout from two goroutines at once.
+type Payer struct {
+	verification *verification.Client
+	recipients   *transferrecipients.Client
+	transfers    *transfers.Client
+	store        Store
+	waiter       TransferWaiter
+	otp          *OTPWorkflow
+	source       string
+	now          func() time.Time
+}
+
+// NewPayer creates a Payer with the Verification, TransferRecipients and Transfers clients
+// of a paystack.Client. Outcomes are polled with a webhook.Awaiter on the Transfers client
+// unless another is set with Waiter.
+func NewPayer(verification *verification.Client, recipients *transferrecipients.Client, transfers *transfers.Client, store Store) *Payer {
+	return &Payer{
+		verification: verification,
+		recipients:   recipients,
+		transfers:    transfers,
+		store:        store,
+		waiter:       webhook.NewAwaiter(nil, transfers, nil),
+		source:       DefaultSource,
+		now:          time.Now,
+	}
+}
+
+// Waiter sets how transfer outcomes are awaited, e.g. a webhook.Awaiter registered with the
+// application's webhook.Handler so transfer webhooks end the wait early
+func (p *Payer) Waiter(w TransferWaiter) *Payer {
+	p.waiter = w
+
+	return p
+}
+
+// OTP sets the workflow that finalizes transfers needing an OTP. Without one, such payouts
+// stop with StatusAwaitingOTP until their OTP is passed to Finalize.
+func (p *Payer) OTP(w *OTPWorkflow) *Payer {
+	p.otp = w
+
+	return p
+}
+
+// Source sets the source transfers are paid from
+func (p *Payer) Source(source string) *Payer {
+	p.source = source
+
+	return p
+}
+
+// Pay runs the payout built by builder until it reaches a final status or needs an OTP.
+// Paying an existing reference resumes that payout; a reference used for a different
+// account or amount returns ErrReferenceConflict.
+//
+// A payout that stops early, e.g. on a network error or when ctx ends, is returned with its
+// saved progress and the error. Without an OTPWorkflow, a payout waiting for an OTP is
+// returned with StatusAwaitingOTP and no error; pass the OTP to Finalize. With one, a
+// payout whose OTP workflow fails keeps StatusAwaitingOTP and is returned with an error
+// wrapping ErrOTPRejected, ErrOTPExpired or ErrOTPTimeout.
+func (p *Payer) Pay(ctx context.Context, builder PayoutRequestBuilder) (*Payout, error) {
+	payout := builder.Build()
+
+	existing, err := p.store.Load(ctx, payout.Reference)
+	switch {
+	case err == nil:
+		if !existing.sameRequest(payout) {
+			return existing, fmt.Errorf("%w: %s", ErrReferenceConflict, payout.Reference)
+		}
+
+		return p.resume(ctx, existing)
+	case !errors.Is(err, ErrPayoutNotFound):
+		return nil, err
+	}
+
+	payout.Step, payout.Status = StepResolveAccount, StatusPending
+	payout.CreatedAt = p.now()
+	if err := p.save(ctx, payout); err != nil {
+		return nil, err
+	}
+
+	return p.run(ctx, payout)
+}
+
+// Resume continues the payout with reference from its last saved step
+func (p *Payer) Resume(ctx context.Context, reference string) (*Payout, error) {
+	payout, err := p.store.Load(ctx, reference)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.resume(ctx, payout)
+}
+
+// Finalize completes a payout waiting for an OTP with the OTP sent to the business, then
+// waits for the outcome like Pay. A rejected OTP returns an error and leaves the payout
+// waiting for another.
+func (p *Payer) Finalize(ctx context.Context, reference, otp string) (*Payout, error) {
+	payout, err := p.store.Load(ctx, reference)
+	if err != nil {
+		return nil, err
+	}
+	if payout.Step != StepFinalizeTransfer {
+		return payout, fmt.Errorf("%w: %s is %s", ErrNotAwaitingOTP, reference, payout.Status)
+	}
+
+	payout.Failure = ""
+	if err := p.finalize(ctx, payout, otp); err != nil {
+		payout.Failure = err.Error()
+		if saveErr := p.save(ctx, payout); saveErr != nil {
+			return payout, errors.Join(err, saveErr)
+		}
+
+		return payout, err
+	}
+
+	if err := p.save(ctx, payout); err != nil {
+		return payout, err
+	}
+
+	return p.run(ctx, payout)
+}
+
+// resume runs a loaded payout. A payout that was waiting for an OTP is verified first, in
+// case it was finalized before the process stopped.
+func (p *Payer) resume(ctx context.Context, payout *Payout) (*Payout, error) {
+	if payout.Step == StepFinalizeTransfer {
+		transfer, found, err := p.verify(ctx, payout.Reference)
+		if err != nil {
+			return payout, err
+		}
+		if found && transfer.Status != enums.TransferStatusOTP {
+			p.advance(payout, transfer.Status)
+			if err := p.save(ctx, payout); err != nil {
+				return payout, err
+			}
+		}
+	}
+
+	return p.run(ctx, payout)
+}
+
+// run performs the payout's steps until it is done, needs an OTP that no OTPWorkflow
+// provides, or a step fails. Steps that end the payout set its status and return nil; an
+// error means the step did not complete and can be retried.
+func (p *Payer) run(ctx context.Context, payout *Payout) (*Payout, error) {
+	for !payout.Done() {
+		if payout.Status == StatusAwaitingOTP && p.otp == nil {
+			break
+		}
+
+		payout.Failure = ""
+
+		var err error
+		switch payout.Step {
+		case StepResolveAccount:
+			err = p.resolve(ctx, payout)
+		case StepCreateRecipient:
+			err = p.createRecipient(ctx, payout)
+		case StepInitiateTransfer:
+			err = p.initiate(ctx, payout)
+		case StepFinalizeTransfer:
+			err = p.finalizeWithOTP(ctx, payout)
+		case StepAwaitOutcome:
+			err = p.await(ctx, payout)
+		default:
+			err = fmt.Errorf("payout %s has unexpected step %q", payout.Reference, payout.Step)
+		}
+
+		if err != nil {
+			payout.Failure = err.Error()
+			if saveErr := p.save(ctx, payout); saveErr != nil {
+				return payout, errors.Join(err, saveErr)
+			}
+
+			return payout, err
+		}
+
+		if err := p.save(ctx, payout); err != nil {
+			return payout, err
+		}
+	}
+
+	return payout, nil
+}
+
+func (p *Payer) resolve(ctx context.Context, payout *Payout) error {
+	rsp, err := p.verification.ResolveAccount(ctx, *verification.NewResolveAccountRequestBuilder().
+		AccountNumber(payout.AccountNumber).
+		BankCode(payout.BankCode))
+	if err != nil {
+		return fmt.Errorf("resolving account %s: %w", payout.AccountNumber, err)
+	}
+	if rejected(rsp) {
+		p.fail(payout, StatusInvalidAccount, rsp.Message)
+		return nil
+	}
+	if err := rsp.Err(); err != nil {
+		return fmt.Errorf("resolving account %s: %w", payout.AccountNumber, err)
+	}
+
+	payout.AccountName = rsp.Data.AccountName.String()
+	payout.Step = StepCreateRecipient
+
+	return nil
+}
+
+func (p *Payer) createRecipient(ctx context.Context, payout *Payout) error {
+	key := RecipientKey{BankCode: payout.BankCode, AccountNumber: payout.AccountNumber, Currency: payout.Currency}
+
+	code, err := p.store.Recipient(ctx, key)
+	switch {
+	case err == nil:
+		payout.RecipientCode = code
+		payout.Step = StepInitiateTransfer
+		return nil
+	case !errors.Is(err, ErrRecipientNotFound):
+		return err
+	}
+
+	builder := transferrecipients.NewCreateRequestBuilder(payout.RecipientType, payout.AccountName, payout.AccountNumber, payout.BankCode)
+	if payout.Currency != "" {
+		builder.Currency(payout.Currency)
+	}
+
+	rsp, err := p.recipients.Create(ctx, *builder)
+	if err != nil {
+		return fmt.Errorf("creating recipient for %s: %w", payout.AccountNumber, err)
+	}
+	if rejected(rsp) {
+		p.fail(payout, StatusFailed, rsp.Message)
+		return nil
+	}
+	if err := rsp.Err(); err != nil {
+		return fmt.Errorf("creating recipient for %s: %w", payout.AccountNumber, err)
+	}
+
+	payout.RecipientCode = rsp.Data.RecipientCode.String()
+	if err := p.store.SaveRecipient(ctx, key, payout.RecipientCode); err != nil {
+		return err
+	}
+
+	payout.Step = StepInitiateTransfer
+
+	return nil
+}
+
+func (p *Payer) initiate(ctx context.Context, payout *Payout) error {
+	if payout.Initiated {
+		transfer, found, err := p.verify(ctx, payout.Reference)
+		if err != nil {
+			return err
+		}
+		if found {
+			payout.TransferCode = transfer.TransferCode.String()
+			p.advance(payout, transfer.Status)
+			return nil
+		}
+	} else {
+		// Saved before the request, so an interrupted request is verified when resumed
+		payout.Initiated = true
+		if err := p.save(ctx, payout); err != nil {
+			return err
+		}
+	}
+
+	builder := transfers.NewInitiateRequestBuilder(p.source, payout.Amount, payout.RecipientCode).
+		Reference(payout.Reference)
+	if payout.Reason != "" {
+		builder.Reason(payout.Reason)
+	}
+	if payout.Currency != "" {
+		builder.Currency(payout.Currency)
+	}
+
+	rsp, err := p.transfers.Initiate(ctx, *builder)
+	if err != nil {
+		return fmt.Errorf("initiating transfer %s: %w", payout.Reference, err)
+	}
+	if rejected(rsp) {
+		p.fail(payout, StatusFailed, rsp.Message)
+		return nil
+	}
+	if err := rsp.Err(); err != nil {
+		return fmt.Errorf("initiating transfer %s: %w", payout.Reference, err)
+	}
+
+	payout.TransferCode = rsp.Data.TransferCode.String()
+	p.advance(payout, rsp.Data.Status)
+
+	return nil
+}
+
+func (p *Payer) finalize(ctx context.Context, payout *Payout, otp string) error {
+	rsp, err := p.transfers.Finalize(ctx, *transfers.NewFinalizeRequestBuilder(payout.TransferCode, otp))
+	if err != nil {
+		return fmt.Errorf("finalizing transfer %s: %w", payout.Reference, err)
+	}
+	if err := rsp.Err(); err != nil {
+		return fmt.Errorf("finalizing transfer %s: %w", payout.Reference, err)
+	}
+
+	p.advance(payout, rsp.Data.Status)
+
+	return nil
+}
+
+// finalizeWithOTP finalizes the transfer with an OTP from the OTP workflow
+func (p *Payer) finalizeWithOTP(ctx context.Context, payout *Payout) error {
+	result, err := p.otp.Finalize(ctx, OTPRequest{
+		Reference:    payout.Reference,
+		TransferCode: payout.TransferCode,
+		Amount:       payout.Amount,
+		Currency:     payout.Currency,
+		Recipient:    payout.AccountName,
+		Reason:       payout.Reason,
+	})
+	if err != nil {
+		return err
+	}
+	if err := result.Err(); err != nil {
+		return fmt.Errorf("finalizing transfer %s: %w", payout.Reference, err)
+	}
+
+	p.advance(payout, result.Transfer.Status)
+
+	return nil
+}
+
+func (p *Payer) await(ctx context.Context, payout *Payout) error {
+	result, err := p.waiter.WaitTransfer(ctx, payout.Reference)
+	if err != nil {
+		return fmt.Errorf("awaiting transfer %s: %w", payout.Reference, err)
+	}
+
+	payout.TransferStatus = enums.TransferStatus(result.Status)
+
+	switch result.Outcome {
+	case webhook.OutcomeSuccess:
+		payout.Status, payout.Step = StatusSucceeded, StepDone
+	case webhook.OutcomeReversed:
+		p.fail(payout, StatusReversed, "transfer "+result.Status)
+	default:
+		p.fail(payout, StatusFailed, "transfer "+result.Status)
+	}
+
+	return nil
+}
+
+// verify fetches the transfer with reference. It returns false when Paystack has no such
+// transfer, and an error when the lookup failed for any other reason.
+func (p *Payer) verify(ctx context.Context, reference string) (*types.Transfer, bool, error) {
+	rsp, err := p.transfers.Verify(ctx, reference)
+	if err != nil {
+		return nil, false, fmt.Errorf("verifying transfer %s: %w", reference, err)
+	}
+	if rsp.NotFound() {
+		return nil, false, nil
+	}
+	if err := rsp.Err(); err != nil {
+		return nil, false, fmt.Errorf("verifying transfer %s: %w", reference, err)
+	}
+
+	return &rsp.Data, true, nil
+}
+
+// advance moves the payout to the step that follows a transfer status
+func (p *Payer) advance(payout *Payout, status enums.TransferStatus) {
+	payout.TransferStatus = status
+
+	switch {
+	case status == enums.TransferStatusOTP:
+		payout.Status, payout.Step = StatusAwaitingOTP, StepFinalizeTransfer
+	case status.IsSuccessful():
+		payout.Status, payout.Step = StatusSucceeded, StepDone
+	case status == enums.TransferStatusReversed:
+		p.fail(payout, StatusReversed, "transfer "+status.String())
+	case status.IsTerminal():
+		p.fail(payout, StatusFailed, "transfer "+status.String())
+	default:
+		payout.Status, payout.Step = StatusPending, StepAwaitOutcome
+	}
+}
+
+// rejected reports whether Paystack declined a request for its content, which ends the
+// payout. Rate limits and key errors are not rejections; the step is retried.
+func rejected[T any](rsp *types.Response[T]) bool {
+	return rsp.Err() != nil && !rsp.Retryable() && !rsp.Unauthorized()
+}
+
+func (p *Payer) fail(payout *Payout, status Status, reason string) {
+	payout.Status, payout.Step = status, StepDone
+	payout.Failure = reason
+}
+
+func (p *Payer) save(ctx context.Context, payout *Payout) error {
+	payout.UpdatedAt = p.now()
+
+	return p.store.Save(ctx, payout)
+}
@@ -0,0 +1,385 @@
+package payouts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/huysamen/paystack-go/api/transferrecipients"
+	"github.com/huysamen/paystack-go/api/transfers"
+	"github.com/huysamen/paystack-go/api/verification"
+	"github.com/huysamen/paystack-go/api/webhook"
+	"github.com/huysamen/paystack-go/internal/apitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func resolved(name string) apitest.Reply {
+	return apitest.OK(fmt.Sprintf(`{"account_number":"0001234567","account_name":%q}`, name))
+}
+
+func recipient(code string) apitest.Reply {
+	return apitest.OK(fmt.Sprintf(`{"recipient_code":%q,"type":"nuban","name":"Jane Doe"}`, code))
+}
+
+func transfer(status, reference string) apitest.Reply {
+	return apitest.OK(fmt.Sprintf(`{"id":14,"amount":50000,"currency":"NGN","status":%q,"reference":%q,"transfer_code":"TRF_1ptvuv321ahaa7q"}`,
+		status, reference))
+}
+
+const (
+	pathResolve   = "/bank/resolve"
+	pathRecipient = "/transferrecipient"
+	pathTransfer  = "/transfer"
+	pathFinalize  = "/transfer/finalize_transfer"
+	pathVerify    = "/transfer/verify/payout-1"
+)
+
+func testPayer(fake *apitest.Server, store Store) *Payer {
+	client := fake.API()
+	transfersClient := (*transfers.Client)(client)
+
+	return NewPayer((*verification.Client)(client), (*transferrecipients.Client)(client), transfersClient, store).
+		Waiter(webhook.NewAwaiter(nil, transfersClient, nil).Backoff(apitest.Backoff).Timeout(time.Second))
+}
+
+func payout() PayoutRequestBuilder {
+	return *NewPayoutRequestBuilder("058", "0001234567", 50000, "payout-1").Reason("Invoice 42")
+}
+
+func TestPayer_Pay(t *testing.T) {
+	fake := apitest.NewServer(t).
+		On(pathResolve, resolved("JANE DOE")).
+		On(pathRecipient, recipient("RCP_1")).
+		On(pathTransfer, transfer("pending", "payout-1")).
+		On(pathVerify, transfer("pending", "payout-1"), transfer("success", "payout-1"))
+	store := NewMemoryStore()
+
+	got, err := testPayer(fake, store).Pay(context.Background(), payout())
+	require.NoError(t, err)
+
+	assert.Equal(t, StatusSucceeded, got.Status)
+	assert.Equal(t, StepDone, got.Step)
+	assert.Equal(t, "JANE DOE", got.AccountName)
+	assert.Equal(t, "RCP_1", got.RecipientCode)
+	assert.Equal(t, "TRF_1ptvuv321ahaa7q", got.TransferCode)
+	assert.Empty(t, got.Failure)
+	assert.True(t, got.Done())
+
+	assert.Equal(t, []string{pathResolve, pathRecipient, pathTransfer, pathVerify, pathVerify}, fake.Paths())
+	assert.Equal(t, "JANE DOE", fake.Body(pathRecipient)["name"])
+	assert.Equal(t, map[string]any{
+		"source": "balance", "amount": float64(50000), "recipient": "RCP_1", "reason": "Invoice 42", "reference": "payout-1",
+	}, fake.Body(pathTransfer))
+
+	saved, err := store.Load(context.Background(), "payout-1")
+	require.NoError(t, err)
+	assert.Equal(t, got, saved)
+}
+
+func TestPayer_ReusesRecipient(t *testing.T) {
+	fake := apitest.NewServer(t).
+		On(pathResolve, resolved("JANE DOE")).
+		On(pathTransfer, transfer("success", "payout-1"))
+	store := NewMemoryStore()
+	require.NoError(t, store.SaveRecipient(context.Background(), RecipientKey{BankCode: "058", AccountNumber: "0001234567"}, "RCP_saved"))
+
+	got, err := testPayer(fake, store).Pay(context.Background(), payout())
+	require.NoError(t, err)
+
+	assert.Equal(t, StatusSucceeded, got.Status)
+	assert.Equal(t, "RCP_saved", got.RecipientCode)
+	assert.Equal(t, []string{pathResolve, pathTransfer}, fake.Paths())
+}
+
+func TestPayer_FinalStates(t *testing.T) {
+	tests := []struct {
+		name    string
+		fake    func(*apitest.Server)
+		status  Status
+		failure string
+	}{
+		{
+			name:    "unresolvable account",
+			fake:    func(a *apitest.Server) { a.On(pathResolve, apitest.Rejected("Could not resolve account name")) },
+			status:  StatusInvalidAccount,
+			failure: "Could not resolve account name",
+		},
+		{
+			name: "rejected transfer",
+			fake: func(a *apitest.Server) {
+				a.On(pathResolve, resolved("JANE DOE")).On(pathRecipient, recipient("RCP_1")).
+					On(pathTransfer, apitest.Rejected("Your balance is not enough to fulfil this request"))
+			},
+			status:  StatusFailed,
+			failure: "Your balance is not enough to fulfil this request",
+		},
+		{
+			name: "failed at the bank",
+			fake: func(a *apitest.Server) {
+				a.On(pathResolve, resolved("JANE DOE")).On(pathRecipient, recipient("RCP_1")).
+					On(pathTransfer, transfer("pending", "payout-1")).On(pathVerify, transfer("failed", "payout-1"))
+			},
+			status:  StatusFailed,
+			failure: "transfer failed",
+		},
+		{
+			name: "reversed",
+			fake: func(a *apitest.Server) {
+				a.On(pathResolve, resolved("JANE DOE")).On(pathRecipient, recipient("RCP_1")).
+					On(pathTransfer, transfer("pending", "payout-1")).On(pathVerify, transfer("reversed", "payout-1"))
+			},
+			status:  StatusReversed,
+			failure: "transfer reversed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := apitest.NewServer(t)
+			tt.fake(fake)
+
+			got, err := testPayer(fake, NewMemoryStore()).Pay(context.Background(), payout())
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.status, got.Status)
+			assert.Equal(t, StepDone, got.Step)
+			assert.Equal(t, tt.failure, got.Failure)
+		})
+	}
+}
+
+func TestPayer_TransientRejections(t *testing.T) {
+	tests := []struct {
+		name string
+		fake func(*apitest.Server)
+		step Step
+	}{
+		{
+			name: "rate limited resolve",
+			fake: func(a *apitest.Server) { a.On(pathResolve, apitest.RateLimited()) },
+			step: StepResolveAccount,
+		},
+		{
+			name: "unauthorized recipient",
+			fake: func(a *apitest.Server) {
+				a.On(pathResolve, resolved("JANE DOE")).On(pathRecipient, apitest.Unauthorized())
+			},
+			step: StepCreateRecipient,
+		},
+		{
+			name: "rate limited transfer",
+			fake: func(a *apitest.Server) {
+				a.On(pathResolve, resolved("JANE DOE")).On(pathRecipient, recipient("RCP_1")).
+					On(pathTransfer, apitest.RateLimited())
+			},
+			step: StepInitiateTransfer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := apitest.NewServer(t)
+			tt.fake(fake)
+			payer := testPayer(fake, NewMemoryStore())
+
+			got, err := payer.Pay(context.Background(), payout())
+			require.Error(t, err)
+			assert.Equal(t, StatusPending, got.Status)
+			assert.Equal(t, tt.step, got.Step)
+			assert.False(t, got.Done())
+
+			// The step is retried when the payout resumes
+			fake.On(pathResolve, resolved("JANE DOE")).On(pathRecipient, recipient("RCP_1")).
+				On(pathVerify, apitest.NotFound("Transfer not found")).
+				On(pathTransfer, transfer("success", "payout-1"))
+			got, err = payer.Resume(context.Background(), "payout-1")
+			require.NoError(t, err)
+			assert.Equal(t, StatusSucceeded, got.Status)
+		})
+	}
+
+	t.Run("rate limited verify", func(t *testing.T) {
+		fake := apitest.NewServer(t).
+			On(pathResolve, resolved("JANE DOE")).
+			On(pathRecipient, recipient("RCP_1")).
+			On(pathTransfer, apitest.ServerError)
+		payer := testPayer(fake, NewMemoryStore())
+
+		_, err := payer.Pay(context.Background(), payout())
+		require.Error(t, err)
+
+		// A failed lookup is not taken to mean the transfer never reached Paystack
+		fake.On(pathVerify, apitest.RateLimited())
+		got, err := payer.Resume(context.Background(), "payout-1")
+		require.Error(t, err)
+		assert.Equal(t, StepInitiateTransfer, got.Step)
+		assert.Equal(t, []string{pathResolve, pathRecipient, pathTransfer, pathVerify}, fake.Paths())
+	})
+}
+
+func TestPayer_OTP(t *testing.T) {
+	fake := apitest.NewServer(t).
+		On(pathResolve, resolved("JANE DOE")).
+		On(pathRecipient, recipient("RCP_1")).
+		On(pathTransfer, transfer("otp", "payout-1"))
+	payer := testPayer(fake, NewMemoryStore())
+
+	got, err := payer.Pay(context.Background(), payout())
+	require.NoError(t, err)
+	assert.Equal(t, StatusAwaitingOTP, got.Status)
+	assert.Equal(t, StepFinalizeTransfer, got.Step)
+
+	fake.On(pathFinalize, apitest.Rejected("Invalid OTP"))
+	got, err = payer.Finalize(context.Background(), "payout-1", "000000")
+	require.Error(t, err)
+	assert.Equal(t, StatusAwaitingOTP, got.Status)
+	assert.Contains(t, got.Failure, "Invalid OTP")
+
+	fake.On(pathFinalize, transfer("success", "payout-1"))
+	got, err = payer.Finalize(context.Background(), "payout-1", "928783")
+	require.NoError(t, err)
+	assert.Equal(t, StatusSucceeded, got.Status)
+	assert.Empty(t, got.Failure)
+	assert.Equal(t, map[string]any{"transfer_code": "TRF_1ptvuv321ahaa7q", "otp": "928783"}, fake.Body(pathFinalize))
+
+	_, err = payer.Finalize(context.Background(), "payout-1", "928783")
+	assert.ErrorIs(t, err, ErrNotAwaitingOTP)
+}
+
+func TestPayer_ResumeAfterOTPFinalizedElsewhere(t *testing.T) {
+	fake := apitest.NewServer(t).
+		On(pathResolve, resolved("JANE DOE")).
+		On(pathRecipient, recipient("RCP_1")).
+		On(pathTransfer, transfer("otp", "payout-1"))
+	payer := testPayer(fake, NewMemoryStore())
+
+	_, err := payer.Pay(context.Background(), payout())
+	require.NoError(t, err)
+
+	fake.On(pathVerify, transfer("success", "payout-1"))
+	got, err := payer.Resume(context.Background(), "payout-1")
+	require.NoError(t, err)
+	assert.Equal(t, StatusSucceeded, got.Status)
+}
+
+func TestPayer_ResumesWithoutDoubleSending(t *testing.T) {
+	t.Run("initiated before the crash", func(t *testing.T) {
+		fake := apitest.NewServer(t).
+			On(pathResolve, resolved("JANE DOE")).
+			On(pathRecipient, recipient("RCP_1")).
+			On(pathTransfer, apitest.ServerError)
+		store := NewMemoryStore()
+		payer := testPayer(fake, store)
+
+		got, err := payer.Pay(context.Background(), payout())
+		require.Error(t, err)
+		assert.Equal(t, StatusPending, got.Status)
+		assert.Equal(t, StepInitiateTransfer, got.Step)
+		assert.True(t, got.Initiated)
+		assert.NotEmpty(t, got.Failure)
+
+		// The transfer reached Paystack, so it is adopted rather than initiated again
+		fake.On(pathVerify, transfer("pending", "payout-1"), transfer("success", "payout-1"))
+		got, err = payer.Pay(context.Background(), payout())
+		require.NoError(t, err)
+		assert.Equal(t, StatusSucceeded, got.Status)
+		assert.Equal(t, "TRF_1ptvuv321ahaa7q", got.TransferCode)
+		assert.Equal(t, []string{pathResolve, pathRecipient, pathTransfer, pathVerify, pathVerify}, fake.Paths())
+	})
+
+	t.Run("never reached Paystack", func(t *testing.T) {
+		fake := apitest.NewServer(t).
+			On(pathResolve, resolved("JANE DOE")).
+			On(pathRecipient, recipient("RCP_1")).
+			On(pathTransfer, apitest.ServerError)
+		payer := testPayer(fake, NewMemoryStore())
+
+		_, err := payer.Pay(context.Background(), payout())
+		require.Error(t, err)
+
+		fake.On(pathVerify, apitest.Rejected("Transfer not found"), transfer("success", "payout-1")).
+			On(pathTransfer, transfer("pending", "payout-1"))
+		got, err := payer.Resume(context.Background(), "payout-1")
+		require.NoError(t, err)
+		assert.Equal(t, StatusSucceeded, got.Status)
+		assert.Equal(t, []string{pathResolve, pathRecipient, pathTransfer, pathVerify, pathTransfer, pathVerify}, fake.Paths())
+	})
+
+	t.Run("resolve failed", func(t *testing.T) {
+		fake := apitest.NewServer(t).On(pathResolve, apitest.ServerError)
+		payer := testPayer(fake, NewMemoryStore())
+
+		got, err := payer.Pay(context.Background(), payout())
+		require.Error(t, err)
+		assert.Equal(t, StepResolveAccount, got.Step)
+
+		fake.On(pathResolve, resolved("JANE DOE")).On(pathRecipient, recipient("RCP_1")).
+			On(pathTransfer, transfer("success", "payout-1"))
+		got, err = payer.Resume(context.Background(), "payout-1")
+		require.NoError(t, err)
+		assert.Equal(t, StatusSucceeded, got.Status)
+	})
+}
+
+func TestPayer_Idempotent(t *testing.T) {
+	fake := apitest.NewServer(t).
+		On(pathResolve, resolved("JANE DOE")).
+		On(pathRecipient, recipient("RCP_1")).
+		On(pathTransfer, transfer("success", "payout-1"))
+	payer := testPayer(fake, NewMemoryStore())
+
+	first, err := payer.Pay(context.Background(), payout())
+	require.NoError(t, err)
+
+	again, err := payer.Pay(context.Background(), payout())
+	require.NoError(t, err)
+	assert.Equal(t, first, again)
+	assert.Len(t, fake.Paths(), 3)
+
+	_, err = payer.Pay(context.Background(), *NewPayoutRequestBuilder("058", "0001234567", 99000, "payout-1"))
+	assert.ErrorIs(t, err, ErrReferenceConflict)
+}
+
+func TestPayer_WebhookOutcome(t *testing.T) {
+	fake := apitest.NewServer(t).
+		On(pathResolve, resolved("JANE DOE")).
+		On(pathRecipient, recipient("RCP_1")).
+		On(pathTransfer, transfer("pending", "payout-1"))
+
+	client := fake.API()
+
+	// Without a client to poll, only the webhook can settle the transfer
+	awaiter := webhook.NewAwaiter(nil, nil, nil).Timeout(time.Second)
+	payer := NewPayer((*verification.Client)(client), (*transferrecipients.Client)(client), (*transfers.Client)(client), NewMemoryStore()).
+		Waiter(awaiter)
+
+	// The webhook may arrive before the payout waits for it
+	event := &webhook.Event{Event: webhook.EventTransferSuccess, Data: json.RawMessage(`{"reference":"payout-1","status":"success"}`)}
+	require.NoError(t, awaiter.Notify(context.Background(), event))
+
+	got, err := payer.Pay(context.Background(), payout())
+	require.NoError(t, err)
+	assert.Equal(t, StatusSucceeded, got.Status)
+}
+
+func TestPayer_AwaitTimeout(t *testing.T) {
+	fake := apitest.NewServer(t).
+		On(pathResolve, resolved("JANE DOE")).
+		On(pathRecipient, recipient("RCP_1")).
+		On(pathTransfer, transfer("pending", "payout-1"))
+
+	client := fake.API()
+
+	payer := NewPayer((*verification.Client)(client), (*transferrecipients.Client)(client), (*transfers.Client)(client), NewMemoryStore()).
+		Waiter(webhook.NewAwaiter(nil, nil, nil).Timeout(10 * time.Millisecond))
+
+	got, err := payer.Pay(context.Background(), payout())
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, webhook.ErrAwaitTimeout))
+	assert.Equal(t, StatusPending, got.Status)
+	assert.Equal(t, StepAwaitOutcome, got.Step)
+}
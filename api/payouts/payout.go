@@ -0,0 +1,236 @@
+package payouts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/huysamen/paystack-go/enums"
+)
+
+var (
+	// ErrPayoutNotFound is returned by a Store for unknown references
+	ErrPayoutNotFound = errors.New("payout not found")
+	// ErrRecipientNotFound is returned by a Store for accounts without a saved recipient
+	ErrRecipientNotFound = errors.New("recipient not found")
+	// ErrReferenceConflict is returned when a reference is reused for a different payout
+	ErrReferenceConflict = errors.New("reference already used for a different payout")
+	// ErrNotAwaitingOTP is returned when an OTP is submitted for a payout that does not need one
+	ErrNotAwaitingOTP = errors.New("payout is not awaiting an OTP")
+)
+
+// Step is the next step of a payout
+type Step string
+
+const (
+	StepResolveAccount   Step = "resolve_account"
+	StepCreateRecipient  Step = "create_recipient"
+	StepInitiateTransfer Step = "initiate_transfer"
+	StepFinalizeTransfer Step = "finalize_transfer"
+	StepAwaitOutcome     Step = "await_outcome"
+	StepDone             Step = "done"
+)
+
+// Status is the state of a payout
+type Status string
+
+const (
+	// StatusPending payouts are still in progress
+	StatusPending Status = "pending"
+	// StatusAwaitingOTP payouts wait for the OTP sent to the business before the transfer
+	// is sent
+	StatusAwaitingOTP Status = "awaiting_otp"
+	// StatusSucceeded payouts reached the recipient's account
+	StatusSucceeded Status = "succeeded"
+	// StatusFailed payouts were rejected by Paystack or failed at the bank
+	StatusFailed Status = "failed"
+	// StatusReversed payouts were sent, then returned to the balance
+	StatusReversed Status = "reversed"
+	// StatusInvalidAccount payouts were stopped because the account could not be resolved
+	StatusInvalidAccount Status = "invalid_account"
+)
+
+// IsFinal reports whether no further step will change the status
+func (s Status) IsFinal() bool {
+	switch s {
+	case StatusSucceeded, StatusFailed, StatusReversed, StatusInvalidAccount:
+		return true
+	default:
+		return false
+	}
+}
+
+// Payout is the persisted progress of a transfer to a bank account
+type Payout struct {
+	// Reference identifies the payout and is sent as the transfer reference
+	Reference     string                      `json:"reference"`
+	BankCode      string                      `json:"bank_code"`
+	AccountNumber string                      `json:"account_number"`
+	Amount        int                         `json:"amount"`
+	Currency      string                      `json:"currency,omitempty"`
+	Reason        string                      `json:"reason,omitempty"`
+	RecipientType enums.TransferRecipientType `json:"recipient_type"`
+	// AccountName is the name the bank resolved the account to
+	AccountName   string `json:"account_name,omitempty"`
+	RecipientCode string `json:"recipient_code,omitempty"`
+	TransferCode  string `json:"transfer_code,omitempty"`
+	Step          Step   `json:"step"`
+	Status        Status `json:"status"`
+	// TransferStatus is the last status of the transfer reported by Paystack
+	TransferStatus enums.TransferStatus `json:"transfer_status,omitempty"`
+	// Initiated is set before the transfer is initiated, so a payout interrupted during
+	// the request is verified before the transfer is initiated again
+	Initiated bool `json:"initiated"`
+	// Failure is the reason a payout failed, or the error of the last step that did not
+	// complete
+	Failure   string    `json:"failure,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Done reports whether the payout reached a final status
+func (p *Payout) Done() bool {
+	return p.Status.IsFinal()
+}
+
+func (p *Payout) clone() *Payout {
+	c := *p
+
+	return &c
+}
+
+// sameRequest reports whether q asks for the same transfer as p
+func (p *Payout) sameRequest(q *Payout) bool {
+	return p.BankCode == q.BankCode && p.AccountNumber == q.AccountNumber && p.Amount == q.Amount &&
+		p.Currency == q.Currency
+}
+
+// RecipientKey identifies the account a transfer recipient pays into
+type RecipientKey struct {
+	BankCode      string `json:"bank_code"`
+	AccountNumber string `json:"account_number"`
+	Currency      string `json:"currency,omitempty"`
+}
+
+func (k RecipientKey) String() string {
+	return fmt.Sprintf("%s/%s/%s", k.BankCode, k.AccountNumber, k.Currency)
+}
+
+// Store persists payouts so they resume where they stopped after a crash, and the
+// recipient codes created for accounts so later payouts to them reuse the recipient
+type Store interface {
+	// Load returns the payout with reference, or ErrPayoutNotFound
+	Load(ctx context.Context, reference string) (*Payout, error)
+	Save(ctx context.Context, payout *Payout) error
+	// Recipient returns the recipient code saved for key, or ErrRecipientNotFound
+	Recipient(ctx context.Context, key RecipientKey) (string, error)
+	SaveRecipient(ctx context.Context, key RecipientKey, recipientCode string) error
+}
+
+// MemoryStore is an in-memory Store. It is safe for concurrent use.
+type MemoryStore struct {
+	mu         sync.Mutex
+	payouts    map[string]*Payout
+	recipients map[RecipientKey]string
+}
+
+// NewMemoryStore creates an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		payouts:    make(map[string]*Payout),
+		recipients: make(map[RecipientKey]string),
+	}
+}
+
+// Load implements Store
+func (s *MemoryStore) Load(_ context.Context, reference string) (*Payout, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	payout, ok := s.payouts[reference]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrPayoutNotFound, reference)
+	}
+
+	return payout.clone(), nil
+}
+
+// Save implements Store
+func (s *MemoryStore) Save(_ context.Context, payout *Payout) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.payouts[payout.Reference] = payout.clone()
+
+	return nil
+}
+
+// Recipient implements Store
+func (s *MemoryStore) Recipient(_ context.Context, key RecipientKey) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	code, ok := s.recipients[key]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrRecipientNotFound, key)
+	}
+
+	return code, nil
+}
+
+// SaveRecipient implements Store
+func (s *MemoryStore) SaveRecipient(_ context.Context, key RecipientKey, recipientCode string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.recipients[key] = recipientCode
+
+	return nil
+}
+
+// PayoutRequestBuilder builds the payout passed to Payer.Pay
+type PayoutRequestBuilder struct {
+	payout Payout
+}
+
+// NewPayoutRequestBuilder creates a payout of amount, in the subunit of the currency, to
+// an account. The reference must be unique per payout; paying again with the same
+// reference resumes the payout instead of sending another transfer.
+func NewPayoutRequestBuilder(bankCode, accountNumber string, amount int, reference string) *PayoutRequestBuilder {
+	return &PayoutRequestBuilder{
+		payout: Payout{
+			Reference:     reference,
+			BankCode:      bankCode,
+			AccountNumber: accountNumber,
+			Amount:        amount,
+			RecipientType: enums.TransferRecipientTypeNuban,
+		},
+	}
+}
+
+// Currency sets the currency of the transfer, which defaults to the integration's currency
+func (b *PayoutRequestBuilder) Currency(currency string) *PayoutRequestBuilder {
+	b.payout.Currency = currency
+
+	return b
+}
+
+// Reason sets the narration of the transfer
+func (b *PayoutRequestBuilder) Reason(reason string) *PayoutRequestBuilder {
+	b.payout.Reason = reason
+
+	return b
+}
+
+// RecipientType sets the type of recipient created for the account, nuban by default
+func (b *PayoutRequestBuilder) RecipientType(recipientType enums.TransferRecipientType) *PayoutRequestBuilder {
+	b.payout.RecipientType = recipientType
+
+	return b
+}
+
+func (b *PayoutRequestBuilder) Build() *Payout {
+	return b.payout.clone()
+}
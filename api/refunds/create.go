@@ -3,6 +3,7 @@ package refunds
 import (
 	"context"
 
+	"github.com/huysamen/paystack-go/enums"
 	"github.com/huysamen/paystack-go/net"
 	"github.com/huysamen/paystack-go/types"
 	"github.com/huysamen/paystack-go/types/data"
@@ -59,7 +60,7 @@ type CreateResponseData struct {
 	Channel        *string            `json:"channel"`
 	MerchantNote   data.NullString    `json:"merchant_note"`
 	CustomerNote   data.NullString    `json:"customer_note"`
-	Status         data.String        `json:"status"`
+	Status         enums.RefundStatus `json:"status"`
 	RefundedBy     data.String        `json:"refunded_by"`
 	ExpectedAt     data.NullTime      `json:"expected_at"`
 	Currency       data.String        `json:"currency"`
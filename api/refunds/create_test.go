@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/huysamen/paystack-go/enums"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -89,6 +90,7 @@ func TestCreateResponse_FieldByFieldValidation(t *testing.T) {
 		assert.Equal(t, false, rawData["fully_deducted"], "fully_deducted in JSON should match")
 
 		assert.Equal(t, "pending", rawData["status"], "status in JSON should match")
+		assert.Equal(t, enums.RefundStatusPending, response.Data.Status, "status in struct should match")
 
 		assert.Equal(t, "test@me.com", rawData["refunded_by"], "refunded_by in JSON should match")
 		assert.Equal(t, "test@me.com", response.Data.RefundedBy.String(), "refunded_by in struct should match")
@@ -2,6 +2,8 @@ package refunds
 
 import (
 	"context"
+	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/huysamen/paystack-go/enums"
@@ -76,6 +78,31 @@ func (b *ListRequestBuilder) Build() *listRequest {
 	return b.req
 }
 
+func (r *listRequest) toQuery() string {
+	params := url.Values{}
+
+	if r.Transaction != nil {
+		params.Add("transaction", *r.Transaction)
+	}
+	if r.Currency != nil {
+		params.Add("currency", *r.Currency)
+	}
+	if r.From != nil {
+		params.Add("from", r.From.Format("2006-01-02T15:04:05.999Z"))
+	}
+	if r.To != nil {
+		params.Add("to", r.To.Format("2006-01-02T15:04:05.999Z"))
+	}
+	if r.PerPage != nil {
+		params.Add("perPage", strconv.Itoa(*r.PerPage))
+	}
+	if r.Page != nil {
+		params.Add("page", strconv.Itoa(*r.Page))
+	}
+
+	return params.Encode()
+}
+
 // ListRefund represents a refund in list responses with different field types
 type ListRefund struct {
 	ID             data.Int             `json:"id"`
@@ -103,5 +130,11 @@ type ListResponseData = []ListRefund
 type ListResponse = types.Response[ListResponseData]
 
 func (c *Client) List(ctx context.Context, builder ListRequestBuilder) (*ListResponse, error) {
-	return net.Get[ListResponseData](ctx, c.Client, c.Secret, basePath, c.BaseURL)
+	path := basePath
+
+	if query := builder.Build().toQuery(); query != "" {
+		path += "?" + query
+	}
+
+	return net.Get[ListResponseData](ctx, c.Client, c.Secret, path, c.BaseURL)
 }
@@ -359,3 +359,24 @@ func TestListResponse_JSONRoundTrip(t *testing.T) {
 		}
 	})
 }
+
+func TestListRequest_ToQuery(t *testing.T) {
+	from := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	query := NewListRequestBuilder().
+		Transaction("T987654321").
+		Currency("NGN").
+		From(from).
+		PerPage(50).
+		Page(2).
+		Build().
+		toQuery()
+
+	assert.Contains(t, query, "transaction=T987654321")
+	assert.Contains(t, query, "currency=NGN")
+	assert.Contains(t, query, "from=2023-06-01T00%3A00%3A00Z")
+	assert.Contains(t, query, "perPage=50")
+	assert.Contains(t, query, "page=2")
+
+	assert.Empty(t, NewListRequestBuilder().Build().toQuery())
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/huysamen/paystack-go/enums"
 	"github.com/huysamen/paystack-go/net"
 	"github.com/huysamen/paystack-go/types"
 	"github.com/huysamen/paystack-go/types/data"
@@ -47,20 +48,20 @@ func (b *CreateRequestBuilder) Build() *createRequest {
 
 // When creating, API returns numeric IDs for customer and plan; use a narrow shape
 type CreateResponseData struct {
-	Customer         data.Int            `json:"customer"`
-	Plan             data.Int            `json:"plan"`
-	Integration      data.Int            `json:"integration"`
-	Domain           data.String         `json:"domain"`
-	Start            data.Int            `json:"start"`
-	Status           data.String         `json:"status"`
-	Quantity         data.Int            `json:"quantity"`
-	Amount           data.Int            `json:"amount"`
-	Authorization    types.Authorization `json:"authorization"`
-	SubscriptionCode data.String         `json:"subscription_code"`
-	EmailToken       data.String         `json:"email_token"`
-	ID               data.Int            `json:"id"`
-	CreatedAt        data.Time           `json:"createdAt"`
-	UpdatedAt        data.Time           `json:"updatedAt"`
+	Customer         data.Int                 `json:"customer"`
+	Plan             data.Int                 `json:"plan"`
+	Integration      data.Int                 `json:"integration"`
+	Domain           data.String              `json:"domain"`
+	Start            data.Int                 `json:"start"`
+	Status           enums.SubscriptionStatus `json:"status"`
+	Quantity         data.Int                 `json:"quantity"`
+	Amount           data.Int                 `json:"amount"`
+	Authorization    types.Authorization      `json:"authorization"`
+	SubscriptionCode data.String              `json:"subscription_code"`
+	EmailToken       data.String              `json:"email_token"`
+	ID               data.Int                 `json:"id"`
+	CreatedAt        data.Time                `json:"createdAt"`
+	UpdatedAt        data.Time                `json:"updatedAt"`
 }
 type CreateResponse = types.Response[CreateResponseData]
 
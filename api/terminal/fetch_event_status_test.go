@@ -1,11 +1,15 @@
 package terminal
 
 import (
+	"context"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -18,3 +22,22 @@ func TestTerminal_FetchEventStatus_JSONDeserialization(t *testing.T) {
 	err = json.Unmarshal(b, &rsp)
 	require.NoError(t, err)
 }
+
+func TestTerminal_FetchEventStatus_Request(t *testing.T) {
+	var got *http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":true,"message":"ok","data":null}`))
+	}))
+	defer srv.Close()
+
+	client := &Client{Client: srv.Client(), Secret: "sk_test", BaseURL: srv.URL}
+
+	_, err := client.FetchEventStatus(context.Background(), "30", "616d721e8c5cd40a0cdd54a6")
+	require.NoError(t, err)
+
+	require.NotNil(t, got)
+	assert.Equal(t, "/terminal/30/events/616d721e8c5cd40a0cdd54a6", got.URL.Path)
+	assert.Empty(t, got.URL.RawQuery)
+}
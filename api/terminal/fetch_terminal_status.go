@@ -14,5 +14,5 @@ type FetchTerminalStatusResponse = types.Response[FetchTerminalStatusResponseDat
 func (c *Client) FetchTerminalStatus(ctx context.Context, terminalID string) (*FetchTerminalStatusResponse, error) {
 	endpoint := fmt.Sprintf("%s/%s/presence", basePath, terminalID)
 
-	return net.Get[FetchTerminalStatusResponseData](ctx, c.Client, c.Secret, endpoint, "", c.BaseURL)
+	return net.Get[FetchTerminalStatusResponseData](ctx, c.Client, c.Secret, endpoint, c.BaseURL)
 }
@@ -0,0 +1,221 @@
+package transactions
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/huysamen/paystack-go/enums"
+	"github.com/huysamen/paystack-go/types"
+)
+
+// DeclineCategory groups declines by what can be done about them
+type DeclineCategory string
+
+const (
+	// DeclineSoft is a temporary decline, e.g. insufficient funds, that may pass if retried
+	DeclineSoft DeclineCategory = "soft"
+	// DeclineHard is a permanent decline of the card or account
+	DeclineHard DeclineCategory = "hard"
+	// DeclineFraud is a decline for a lost, stolen or suspicious card
+	DeclineFraud DeclineCategory = "fraud"
+	// DeclineExpiredCard is a decline for a card past its expiry date
+	DeclineExpiredCard DeclineCategory = "expired_card"
+	// DeclineAuthenticationRequired means the customer must authenticate the payment
+	DeclineAuthenticationRequired DeclineCategory = "authentication_required"
+)
+
+// DeclineAction is the recommended next step for a decline
+type DeclineAction string
+
+const (
+	// ActionRetry retries the charge with the same authorization after the retry delay
+	ActionRetry DeclineAction = "retry"
+	// ActionUpdatePaymentMethod asks the customer for another card or account
+	ActionUpdatePaymentMethod DeclineAction = "update_payment_method"
+	// ActionReauthenticate sends the customer through checkout to authorize the payment
+	ActionReauthenticate DeclineAction = "reauthenticate"
+	// ActionStop stops charging the authorization and flags it for review
+	ActionStop DeclineAction = "stop"
+)
+
+// DefaultSoftDeclineDelay is the retry delay of soft declines whose rule sets none
+const DefaultSoftDeclineDelay = 24 * time.Hour
+
+// DefaultUnknownDeclineDelay is the retry delay of failures that match no rule, which are
+// treated as soft declines
+const DefaultUnknownDeclineDelay = 72 * time.Hour
+
+// DeclineRule classifies gateway responses that contain Phrase. Matching ignores case,
+// punctuation and repeated spaces.
+type DeclineRule struct {
+	Phrase   string
+	Category DeclineCategory
+	// RetryAfter is the retry delay of a soft decline; zero uses DefaultSoftDeclineDelay
+	RetryAfter time.Duration
+}
+
+// defaultDeclineRules are checked in order, so more specific phrases come first
+var defaultDeclineRules = []DeclineRule{
+	{Phrase: "expired card", Category: DeclineExpiredCard},
+	{Phrase: "card expired", Category: DeclineExpiredCard},
+	{Phrase: "card has expired", Category: DeclineExpiredCard},
+
+	{Phrase: "lost card", Category: DeclineFraud},
+	{Phrase: "stolen card", Category: DeclineFraud},
+	{Phrase: "pick up card", Category: DeclineFraud},
+	{Phrase: "pickup card", Category: DeclineFraud},
+	{Phrase: "suspected fraud", Category: DeclineFraud},
+	{Phrase: "security violation", Category: DeclineFraud},
+	{Phrase: "fraud", Category: DeclineFraud},
+
+	{Phrase: "authentication required", Category: DeclineAuthenticationRequired},
+	{Phrase: "authentication failed", Category: DeclineAuthenticationRequired},
+	{Phrase: "3d secure", Category: DeclineAuthenticationRequired},
+	{Phrase: "incorrect pin", Category: DeclineAuthenticationRequired},
+	{Phrase: "incorrect otp", Category: DeclineAuthenticationRequired},
+	{Phrase: "token not generated", Category: DeclineAuthenticationRequired},
+
+	{Phrase: "insufficient funds", Category: DeclineSoft},
+	{Phrase: "not sufficient funds", Category: DeclineSoft},
+	{Phrase: "exceeds withdrawal", Category: DeclineSoft},
+	{Phrase: "withdrawal limit", Category: DeclineSoft},
+	{Phrase: "exceeds limit", Category: DeclineSoft},
+	{Phrase: "do not honor", Category: DeclineSoft},
+	{Phrase: "do not honour", Category: DeclineSoft},
+	{Phrase: "issuer or switch inoperative", Category: DeclineSoft, RetryAfter: time.Hour},
+	{Phrase: "issuer unavailable", Category: DeclineSoft, RetryAfter: time.Hour},
+	{Phrase: "system malfunction", Category: DeclineSoft, RetryAfter: time.Hour},
+	{Phrase: "timed out", Category: DeclineSoft, RetryAfter: 15 * time.Minute},
+	{Phrase: "timeout", Category: DeclineSoft, RetryAfter: 15 * time.Minute},
+	{Phrase: "try again", Category: DeclineSoft, RetryAfter: time.Hour},
+
+	{Phrase: "pin tries exceeded", Category: DeclineHard},
+	{Phrase: "transaction not permitted", Category: DeclineHard},
+	{Phrase: "restricted card", Category: DeclineHard},
+	{Phrase: "invalid card", Category: DeclineHard},
+	{Phrase: "invalid account", Category: DeclineHard},
+	{Phrase: "no such issuer", Category: DeclineHard},
+	{Phrase: "account closed", Category: DeclineHard},
+	{Phrase: "closed account", Category: DeclineHard},
+	{Phrase: "blocked", Category: DeclineHard},
+	{Phrase: "not reusable", Category: DeclineHard},
+}
+
+// DefaultDeclineRules returns a copy of the rules a new DeclineClassifier starts with
+func DefaultDeclineRules() []DeclineRule {
+	return append([]DeclineRule(nil), defaultDeclineRules...)
+}
+
+// Decline is the classification of a failed charge
+type Decline struct {
+	Category DeclineCategory
+	Action   DeclineAction
+	// RetryAfter is how long to wait before retrying a soft decline, and zero otherwise
+	RetryAfter      time.Duration
+	Status          string
+	GatewayResponse string
+	// Known is false when the gateway response matched no rule
+	Known bool
+}
+
+// Retryable reports whether the charge may succeed if retried with the same authorization
+func (d Decline) Retryable() bool {
+	return d.Category == DeclineSoft
+}
+
+// DeclineClassifier maps charge statuses and free-text gateway responses, e.g.
+// "Insufficient Funds", to a DeclineCategory with a recommended action and retry delay.
+//
+// Add rules before sharing a DeclineClassifier between goroutines.
+type DeclineClassifier struct {
+	rules []DeclineRule
+}
+
+// NewDeclineClassifier creates a DeclineClassifier with the default rules
+func NewDeclineClassifier() *DeclineClassifier {
+	return &DeclineClassifier{rules: DefaultDeclineRules()}
+}
+
+// Rule adds a rule, which is checked before the rules already added
+func (c *DeclineClassifier) Rule(phrase string, category DeclineCategory, retryAfter time.Duration) *DeclineClassifier {
+	c.rules = append([]DeclineRule{{Phrase: phrase, Category: category, RetryAfter: retryAfter}}, c.rules...)
+
+	return c
+}
+
+// Classify classifies a transaction or charge status and its gateway response. It returns
+// false when the status is not a decline, e.g. success or pending.
+//
+// Failed and reversed charges are classified by their gateway response; those that match
+// no rule are soft declines retried after DefaultUnknownDeclineDelay. Abandoned
+// transactions and charges waiting for a PIN, OTP or redirect need authentication.
+func (c *DeclineClassifier) Classify(status, gatewayResponse string) (Decline, bool) {
+	d := Decline{Status: status, GatewayResponse: gatewayResponse}
+
+	switch status {
+	case string(enums.TransactionStatusFailed), string(enums.TransactionStatusReversed):
+	case string(enums.TransactionStatusAbandoned),
+		string(enums.ChargeStatusSendPIN), string(enums.ChargeStatusSendOTP), string(enums.ChargeStatusSendPhone),
+		string(enums.ChargeStatusSendBirthday), string(enums.ChargeStatusSendAddress), string(enums.ChargeStatusOpenURL):
+		d.Category, d.Action, d.Known = DeclineAuthenticationRequired, ActionReauthenticate, true
+		return d, true
+	default:
+		return d, false
+	}
+
+	text := normalizeDecline(gatewayResponse)
+	for _, rule := range c.rules {
+		if phrase := normalizeDecline(rule.Phrase); phrase != "" && strings.Contains(text, phrase) {
+			d.Category, d.Known = rule.Category, true
+			d.RetryAfter = rule.RetryAfter
+			break
+		}
+	}
+
+	if !d.Known {
+		d.Category, d.RetryAfter = DeclineSoft, DefaultUnknownDeclineDelay
+	}
+
+	d.Action = declineAction(d.Category)
+	if d.Category != DeclineSoft {
+		d.RetryAfter = 0
+	} else if d.RetryAfter <= 0 {
+		d.RetryAfter = DefaultSoftDeclineDelay
+	}
+
+	return d, true
+}
+
+// ClassifyTransaction classifies a transaction, e.g. the result of ChargeAuthorization
+func (c *DeclineClassifier) ClassifyTransaction(tx *types.Transaction) (Decline, bool) {
+	return c.Classify(tx.Status.String(), tx.GatewayResponse.String())
+}
+
+// ClassifyCharge classifies a charge created with the Charge API
+func (c *DeclineClassifier) ClassifyCharge(charge *types.Charge) (Decline, bool) {
+	return c.Classify(charge.Status.String(), charge.GatewayResponse.String())
+}
+
+func declineAction(category DeclineCategory) DeclineAction {
+	switch category {
+	case DeclineSoft:
+		return ActionRetry
+	case DeclineFraud:
+		return ActionStop
+	case DeclineAuthenticationRequired:
+		return ActionReauthenticate
+	default:
+		return ActionUpdatePaymentMethod
+	}
+}
+
+// normalizeDecline lowercases s and turns each run of punctuation and spaces into one
+// space, so "Do Not Honor." and "do-not honor" match alike
+func normalizeDecline(s string) string {
+	fields := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	return strings.Join(fields, " ")
+}
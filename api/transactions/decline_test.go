@@ -0,0 +1,105 @@
+package transactions
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/huysamen/paystack-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeclineClassifier_Classify(t *testing.T) {
+	tests := []struct {
+		status          string
+		gatewayResponse string
+		category        DeclineCategory
+		action          DeclineAction
+		retryAfter      time.Duration
+		known           bool
+	}{
+		{"failed", "Insufficient Funds", DeclineSoft, ActionRetry, DefaultSoftDeclineDelay, true},
+		{"failed", "Declined: Do Not Honor.", DeclineSoft, ActionRetry, DefaultSoftDeclineDelay, true},
+		{"failed", "Issuer or Switch Inoperative", DeclineSoft, ActionRetry, time.Hour, true},
+		{"failed", "Expired Card", DeclineExpiredCard, ActionUpdatePaymentMethod, 0, true},
+		{"failed", "Pick-up card (no fraud)", DeclineFraud, ActionStop, 0, true},
+		{"failed", "Stolen Card, Pick Up", DeclineFraud, ActionStop, 0, true},
+		{"failed", "Transaction Not Permitted to Cardholder", DeclineHard, ActionUpdatePaymentMethod, 0, true},
+		{"failed", "Authentication required", DeclineAuthenticationRequired, ActionReauthenticate, 0, true},
+		{"reversed", "RESTRICTED CARD", DeclineHard, ActionUpdatePaymentMethod, 0, true},
+		{"failed", "Something unusual", DeclineSoft, ActionRetry, DefaultUnknownDeclineDelay, false},
+		{"abandoned", "The transaction was not completed", DeclineAuthenticationRequired, ActionReauthenticate, 0, true},
+		{"send_otp", "", DeclineAuthenticationRequired, ActionReauthenticate, 0, true},
+		{"open_url", "", DeclineAuthenticationRequired, ActionReauthenticate, 0, true},
+	}
+
+	c := NewDeclineClassifier()
+	for _, tt := range tests {
+		t.Run(tt.status+" "+tt.gatewayResponse, func(t *testing.T) {
+			d, ok := c.Classify(tt.status, tt.gatewayResponse)
+			require.True(t, ok)
+			assert.Equal(t, Decline{
+				Category:        tt.category,
+				Action:          tt.action,
+				RetryAfter:      tt.retryAfter,
+				Status:          tt.status,
+				GatewayResponse: tt.gatewayResponse,
+				Known:           tt.known,
+			}, d)
+			assert.Equal(t, tt.category == DeclineSoft, d.Retryable())
+		})
+	}
+}
+
+func TestDeclineClassifier_NotDeclined(t *testing.T) {
+	c := NewDeclineClassifier()
+
+	for _, status := range []string{"success", "pending", "ongoing", "queued", "pay_offline", ""} {
+		_, ok := c.Classify(status, "Insufficient Funds")
+		assert.False(t, ok, status)
+	}
+}
+
+func TestDeclineClassifier_Rule(t *testing.T) {
+	c := NewDeclineClassifier().
+		Rule("Insufficient Funds", DeclineSoft, 3*24*time.Hour).
+		Rule("Velocity check", DeclineFraud, time.Hour)
+
+	d, _ := c.Classify("failed", "insufficient funds")
+	assert.Equal(t, 3*24*time.Hour, d.RetryAfter)
+
+	// Retry delays only apply to soft declines
+	d, _ = c.Classify("failed", "Velocity Check Failed")
+	assert.Equal(t, DeclineFraud, d.Category)
+	assert.Zero(t, d.RetryAfter)
+
+	// Rules added to one classifier leave the defaults alone
+	d, _ = NewDeclineClassifier().Classify("failed", "Velocity check failed")
+	assert.False(t, d.Known)
+	assert.Len(t, DefaultDeclineRules(), len(defaultDeclineRules))
+}
+
+func TestDeclineClassifier_ClassifyTransaction(t *testing.T) {
+	b, err := os.ReadFile(filepath.Join("..", "..", "resources", "examples", "responses", "transactions", "charge_authorization_200.json"))
+	require.NoError(t, err)
+
+	var rsp ChargeAuthorizationResponse
+	require.NoError(t, json.Unmarshal(b, &rsp))
+
+	c := NewDeclineClassifier()
+	_, ok := c.ClassifyTransaction(&rsp.Data)
+	assert.False(t, ok)
+
+	rsp.Data.Status = "failed"
+	rsp.Data.GatewayResponse = "Insufficient Funds"
+	d, ok := c.ClassifyTransaction(&rsp.Data)
+	require.True(t, ok)
+	assert.Equal(t, DeclineSoft, d.Category)
+
+	d, ok = c.ClassifyCharge(&types.Charge{Status: "failed", GatewayResponse: "Expired Card"})
+	require.True(t, ok)
+	assert.Equal(t, DeclineExpiredCard, d.Category)
+}
@@ -75,8 +75,8 @@ func (b *ExportRequestBuilder) Customer(customer uint64) *ExportRequestBuilder {
 	return b
 }
 
-func (b *ExportRequestBuilder) Status(status string) *ExportRequestBuilder {
-	b.req.Status = optional.String(status)
+func (b *ExportRequestBuilder) Status(status enums.TransactionStatus) *ExportRequestBuilder {
+	b.req.Status = optional.String(status.String())
 
 	return b
 }
@@ -163,12 +163,13 @@ type ExportResponseData struct {
 type ExportResponse = types.Response[ExportResponseData]
 
 func (c *Client) Export(ctx context.Context, builder ExportRequestBuilder) (*ExportResponse, error) {
-	req := builder.Build()
-	query := ""
+	path := fmt.Sprintf("%s%s", basePath, transactionExportPath)
 
-	if req != nil {
-		query = req.toQuery()
+	if req := builder.Build(); req != nil {
+		if query := req.toQuery(); query != "" {
+			path += "?" + query
+		}
 	}
 
-	return net.Get[ExportResponseData](ctx, c.Client, c.Secret, fmt.Sprintf("%s%s", basePath, transactionExportPath), query, c.BaseURL)
+	return net.Get[ExportResponseData](ctx, c.Client, c.Secret, path, c.BaseURL)
 }
@@ -1,7 +1,10 @@
 package transactions
 
 import (
+	"context"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
@@ -35,3 +38,23 @@ func TestTransactions_Export_Builder(t *testing.T) {
 	assert.Contains(t, q, "amount=1000")
 	assert.Contains(t, q, "settled=true")
 }
+
+func TestTransactions_Export_Request(t *testing.T) {
+	var got *http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":true,"message":"ok","data":null}`))
+	}))
+	defer srv.Close()
+
+	client := &Client{Client: srv.Client(), Secret: "sk_test", BaseURL: srv.URL}
+
+	_, err := client.Export(context.Background(), *NewExportRequestBuilder().PerPage(10).Status("success"))
+	require.NoError(t, err)
+
+	require.NotNil(t, got)
+	assert.Equal(t, "/transaction/export", got.URL.Path)
+	assert.Equal(t, "10", got.URL.Query().Get("perPage"))
+	assert.Equal(t, "success", got.URL.Query().Get("status"))
+}
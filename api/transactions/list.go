@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/huysamen/paystack-go/enums"
 	"github.com/huysamen/paystack-go/net"
 	"github.com/huysamen/paystack-go/optional"
 	"github.com/huysamen/paystack-go/types"
@@ -56,8 +57,8 @@ func (b *ListRequestBuilder) TerminalID(terminalID string) *ListRequestBuilder {
 	return b
 }
 
-func (b *ListRequestBuilder) Status(status string) *ListRequestBuilder {
-	b.req.Status = optional.String(status)
+func (b *ListRequestBuilder) Status(status enums.TransactionStatus) *ListRequestBuilder {
+	b.req.Status = optional.String(status.String())
 
 	return b
 }
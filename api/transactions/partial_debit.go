@@ -62,9 +62,27 @@ func (b *PartialDebitRequestBuilder) Build() *partialDebitRequest {
 	return &b.request
 }
 
-// Partial debit returns an integer plan field (0) in example responses; use a narrow shape here
+// Partial debit returns an integer plan field (0) in example responses, so it can't reuse
+// types.Transaction
 type PartialDebitResponseData struct {
-	Plan data.Int `json:"plan"`
+	ID              data.Uint               `json:"id"`
+	Domain          data.String             `json:"domain"`
+	Status          enums.TransactionStatus `json:"status"`
+	Reference       data.String             `json:"reference"`
+	Amount          data.Int                `json:"amount"`
+	RequestedAmount data.Int                `json:"requested_amount"`
+	Currency        enums.Currency          `json:"currency"`
+	Message         data.NullString         `json:"message"`
+	GatewayResponse data.String             `json:"gateway_response"`
+	TransactionDate data.NullTime           `json:"transaction_date"`
+	Channel         enums.Channel           `json:"channel"`
+	IPAddress       data.NullString         `json:"ip_address"`
+	Metadata        types.Metadata          `json:"metadata"`
+	Log             *types.TransactionLog   `json:"log"`
+	Fees            data.Int                `json:"fees"`
+	Authorization   types.Authorization     `json:"authorization"`
+	Customer        types.Customer          `json:"customer"`
+	Plan            data.Int                `json:"plan"`
 }
 type PartialDebitResponse = types.Response[PartialDebitResponseData]
 
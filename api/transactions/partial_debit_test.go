@@ -19,6 +19,10 @@ func TestTransactions_PartialDebit_JSONDeserialization(t *testing.T) {
 	err = json.Unmarshal(b, &rsp)
 	require.NoError(t, err)
 	assert.True(t, rsp.Status.Bool())
+	assert.Equal(t, "success", rsp.Data.Status.String())
+	assert.Equal(t, "ofuhmnzw05vny9j", rsp.Data.Reference.String())
+	assert.Equal(t, int64(50000), rsp.Data.Amount.Int64())
+	assert.Equal(t, "AUTH_uh8bcl3zbn", rsp.Data.Authorization.AuthorizationCode.String())
 }
 
 func TestTransactions_PartialDebit_Builder(t *testing.T) {
@@ -0,0 +1,612 @@
+package transactions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/huysamen/paystack-go/enums"
+	"github.com/huysamen/paystack-go/types"
+)
+
+// DefaultRecheckDelay is how long a RecurringCharger waits before verifying an attempt whose
+// outcome is unknown, e.g. after a network error or a queued charge
+const DefaultRecheckDelay = 10 * time.Minute
+
+// DefaultRetrySchedule retries soft declines after one, three and five days
+var DefaultRetrySchedule = []time.Duration{24 * time.Hour, 3 * 24 * time.Hour, 5 * 24 * time.Hour}
+
+// ErrRecurringChargeNotFound is returned by a RecurringStore for unknown IDs
+var ErrRecurringChargeNotFound = errors.New("recurring charge not found")
+
+// ErrPartialDebitCurrency is returned for charges without a currency when partial debits
+// are enabled
+var ErrPartialDebitCurrency = errors.New("partial debits need a currency")
+
+// RecurringStatus is the state of a RecurringCharge
+type RecurringStatus string
+
+const (
+	// RecurringScheduled charges have another attempt due at NextAttemptAt
+	RecurringScheduled RecurringStatus = "scheduled"
+	// RecurringCollected charges collected the full amount
+	RecurringCollected RecurringStatus = "collected"
+	// RecurringPartiallyCollected charges collected part of the amount with a partial debit
+	RecurringPartiallyCollected RecurringStatus = "partially_collected"
+	// RecurringFailed charges were declined with no retries left
+	RecurringFailed RecurringStatus = "failed"
+)
+
+// AttemptKind tells which operation an attempt used
+type AttemptKind string
+
+const (
+	AttemptChargeAuthorization AttemptKind = "charge_authorization"
+	AttemptPartialDebit        AttemptKind = "partial_debit"
+)
+
+// ChargeAttempt is one request made for a RecurringCharge
+type ChargeAttempt struct {
+	// Round counts the scheduled tries, from 1. A partial debit shares the round of the
+	// charge it falls back from.
+	Round     int         `json:"round"`
+	Kind      AttemptKind `json:"kind"`
+	Reference string      `json:"reference"`
+	// TransactionID is the ID of the Paystack transaction, once known
+	TransactionID uint64 `json:"transaction_id,omitempty"`
+	Amount        int    `json:"amount"`
+	// AtLeast is the floor of a partial debit
+	AtLeast   int                     `json:"at_least,omitempty"`
+	Collected int                     `json:"collected"`
+	Status    enums.TransactionStatus `json:"status"`
+	// GatewayResponse is the gateway response of the transaction, or the message of a
+	// rejected request
+	GatewayResponse string   `json:"gateway_response,omitempty"`
+	Decline         *Decline `json:"decline,omitempty"`
+	// Error is the error of a request whose outcome is unknown
+	Error string    `json:"error,omitempty"`
+	At    time.Time `json:"at"`
+}
+
+// settled reports whether the attempt's outcome is known
+func (a *ChargeAttempt) settled() bool {
+	return a.Status.IsTerminal() || a.unsent()
+}
+
+// unsent reports whether verifying the attempt found no transaction
+func (a *ChargeAttempt) unsent() bool {
+	return a.Status == enums.TransactionStatusAbandoned && a.Decline == nil && a.Error != ""
+}
+
+// RecurringCharge is a charge of a saved authorization with all its attempts
+type RecurringCharge struct {
+	// ID identifies the charge, e.g. an invoice number, and prefixes the reference of each
+	// attempt
+	ID                string          `json:"id"`
+	Email             string          `json:"email"`
+	AuthorizationCode string          `json:"authorization_code"`
+	Amount            int             `json:"amount"`
+	Currency          enums.Currency  `json:"currency,omitempty"`
+	Metadata          map[string]any  `json:"metadata,omitempty"`
+	Status            RecurringStatus `json:"status"`
+	Collected         int             `json:"collected"`
+	// NextAttemptAt is when a scheduled charge is due
+	NextAttemptAt time.Time       `json:"next_attempt_at,omitempty"`
+	Attempts      []ChargeAttempt `json:"attempts"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+// Outstanding returns the amount still to collect
+func (r *RecurringCharge) Outstanding() int {
+	return max(r.Amount-r.Collected, 0)
+}
+
+// Done reports whether no more attempts will be made
+func (r *RecurringCharge) Done() bool {
+	return r.Status != RecurringScheduled
+}
+
+// Attempt returns the latest attempt made with reference
+func (r *RecurringCharge) Attempt(reference string) (ChargeAttempt, bool) {
+	for i := len(r.Attempts) - 1; i >= 0; i-- {
+		if r.Attempts[i].Reference == reference {
+			return r.Attempts[i], true
+		}
+	}
+
+	return ChargeAttempt{}, false
+}
+
+func (r *RecurringCharge) last() *ChargeAttempt {
+	if len(r.Attempts) == 0 {
+		return nil
+	}
+
+	return &r.Attempts[len(r.Attempts)-1]
+}
+
+func (r *RecurringCharge) clone() *RecurringCharge {
+	c := *r
+	c.Metadata = maps.Clone(r.Metadata)
+	c.Attempts = append([]ChargeAttempt(nil), r.Attempts...)
+
+	return &c
+}
+
+// RecurringStore persists recurring charges so attempts survive restarts and their history
+// can be queried
+type RecurringStore interface {
+	// Load returns the charge with id, or ErrRecurringChargeNotFound
+	Load(ctx context.Context, id string) (*RecurringCharge, error)
+	Save(ctx context.Context, charge *RecurringCharge) error
+	// Due returns the IDs of scheduled charges whose next attempt is at or before t
+	Due(ctx context.Context, t time.Time) ([]string, error)
+}
+
+// MemoryRecurringStore is an in-memory RecurringStore. It is safe for concurrent use.
+type MemoryRecurringStore struct {
+	mu      sync.Mutex
+	charges map[string]*RecurringCharge
+}
+
+// NewMemoryRecurringStore creates an empty MemoryRecurringStore
+func NewMemoryRecurringStore() *MemoryRecurringStore {
+	return &MemoryRecurringStore{charges: make(map[string]*RecurringCharge)}
+}
+
+// Load implements RecurringStore
+func (s *MemoryRecurringStore) Load(_ context.Context, id string) (*RecurringCharge, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	charge, ok := s.charges[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrRecurringChargeNotFound, id)
+	}
+
+	return charge.clone(), nil
+}
+
+// Save implements RecurringStore
+func (s *MemoryRecurringStore) Save(_ context.Context, charge *RecurringCharge) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.charges[charge.ID] = charge.clone()
+
+	return nil
+}
+
+// Due implements RecurringStore. IDs are ordered by when they fell due.
+func (s *MemoryRecurringStore) Due(_ context.Context, t time.Time) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var due []*RecurringCharge
+	for _, charge := range s.charges {
+		if charge.Status == RecurringScheduled && !charge.NextAttemptAt.After(t) {
+			due = append(due, charge)
+		}
+	}
+
+	sort.Slice(due, func(i, j int) bool {
+		if due[i].NextAttemptAt.Equal(due[j].NextAttemptAt) {
+			return due[i].ID < due[j].ID
+		}
+		return due[i].NextAttemptAt.Before(due[j].NextAttemptAt)
+	})
+
+	ids := make([]string, len(due))
+	for i, charge := range due {
+		ids[i] = charge.ID
+	}
+
+	return ids, nil
+}
+
+// RecurringChargeRequestBuilder describes a charge for a RecurringCharger
+type RecurringChargeRequestBuilder struct {
+	charge RecurringCharge
+}
+
+// NewRecurringChargeRequestBuilder creates a builder for a charge of amount to the
+// authorization. The id must be unique per billing period, e.g. an invoice number, since
+// charging the same id twice returns the first charge.
+func NewRecurringChargeRequestBuilder(id, email, authorizationCode string, amount int) *RecurringChargeRequestBuilder {
+	return &RecurringChargeRequestBuilder{
+		charge: RecurringCharge{
+			ID:                id,
+			Email:             email,
+			AuthorizationCode: authorizationCode,
+			Amount:            amount,
+		},
+	}
+}
+
+func (b *RecurringChargeRequestBuilder) Currency(currency enums.Currency) *RecurringChargeRequestBuilder {
+	b.charge.Currency = currency
+
+	return b
+}
+
+func (b *RecurringChargeRequestBuilder) Metadata(metadata map[string]any) *RecurringChargeRequestBuilder {
+	b.charge.Metadata = metadata
+
+	return b
+}
+
+// RecurringCharger collects recurring payments from saved authorizations. It charges the
+// full amount with ChargeAuthorization and, when partial debits are enabled, falls back to
+// PartialDebit on insufficient funds. Soft declines are retried on a schedule; hard
+// declines, fraud, expired cards and declines that need authentication end the charge.
+//
+// Every attempt is recorded in a RecurringStore with its reference, <id>-<round> for full
+// charges and <id>-<round>-partial for partial debits. The references are deterministic, so
+// Paystack rejects a request that was already made, and attempts whose outcome is unknown
+// are verified by reference before anything is retried.
+//
+// Retries happen when a charge is resumed with Resume or RunDue, e.g. from a cron job.
+// Don't resume the same charge from two goroutines at once.
+type RecurringCharger struct {
+	client       *Client
+	store        RecurringStore
+	classifier   *DeclineClassifier
+	schedule     []time.Duration
+	partialDebit bool
+	atLeast      int
+	recheck      time.Duration
+	now          func() time.Time
+}
+
+// NewRecurringCharger creates a RecurringCharger that charges with client and records
+// charges in store. Partial debits are disabled.
+func NewRecurringCharger(client *Client, store RecurringStore) *RecurringCharger {
+	return &RecurringCharger{
+		client:     client,
+		store:      store,
+		classifier: NewDeclineClassifier(),
+		schedule:   DefaultRetrySchedule,
+		recheck:    DefaultRecheckDelay,
+		now:        time.Now,
+	}
+}
+
+// Classifier sets the DeclineClassifier that decides which declines are retried
+func (r *RecurringCharger) Classifier(c *DeclineClassifier) *RecurringCharger {
+	r.classifier = c
+
+	return r
+}
+
+// Schedule sets the delays before each retry of a soft decline, after which the charge
+// fails. A decline whose RetryAfter is longer waits that long instead.
+func (r *RecurringCharger) Schedule(delays ...time.Duration) *RecurringCharger {
+	r.schedule = delays
+
+	return r
+}
+
+// PartialDebit enables the partial debit fallback on insufficient funds, collecting at
+// least atLeast. Partial debits need a currency, so Charge then rejects charges without one
+// with ErrPartialDebitCurrency.
+func (r *RecurringCharger) PartialDebit(atLeast int) *RecurringCharger {
+	r.partialDebit = true
+	r.atLeast = atLeast
+
+	return r
+}
+
+// RecheckDelay sets how long to wait before verifying an attempt whose outcome is unknown
+func (r *RecurringCharger) RecheckDelay(d time.Duration) *RecurringCharger {
+	r.recheck = d
+
+	return r
+}
+
+// Charge makes the first attempt of a new charge and returns it. When a charge with the
+// same id exists it is resumed instead, so calling Charge again is safe.
+//
+// A declined charge is not an error; check its Status. Errors are returned when a request
+// or the store fails, or Paystack refuses a request without looking at it, e.g. when rate
+// limited; the attempt is still recorded and verified when the charge resumes.
+func (r *RecurringCharger) Charge(ctx context.Context, builder RecurringChargeRequestBuilder) (*RecurringCharge, error) {
+	if r.partialDebit && builder.charge.Currency == "" {
+		return nil, fmt.Errorf("%w: %s", ErrPartialDebitCurrency, builder.charge.ID)
+	}
+
+	charge, err := r.store.Load(ctx, builder.charge.ID)
+	switch {
+	case err == nil:
+		return r.resume(ctx, charge)
+	case !errors.Is(err, ErrRecurringChargeNotFound):
+		return nil, err
+	}
+
+	now := r.now()
+	charge = builder.charge.clone()
+	charge.Status = RecurringScheduled
+	charge.NextAttemptAt = now
+	charge.CreatedAt = now
+	charge.UpdatedAt = now
+
+	if err := r.store.Save(ctx, charge); err != nil {
+		return nil, err
+	}
+
+	return r.resume(ctx, charge)
+}
+
+// Resume makes the next attempt of a charge if it is due, and returns the charge
+func (r *RecurringCharger) Resume(ctx context.Context, id string) (*RecurringCharge, error) {
+	charge, err := r.store.Load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.resume(ctx, charge)
+}
+
+// RunDue resumes every charge that is due and returns how many it resumed. It carries on
+// past failing charges and returns their errors joined.
+func (r *RecurringCharger) RunDue(ctx context.Context) (int, error) {
+	ids, err := r.store.Due(ctx, r.now())
+	if err != nil {
+		return 0, err
+	}
+
+	var errs []error
+	for i, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return i, err
+		}
+		if _, err := r.Resume(ctx, id); err != nil {
+			errs = append(errs, fmt.Errorf("recurring charge %s: %w", id, err))
+		}
+	}
+
+	return len(ids), errors.Join(errs...)
+}
+
+// resume makes requests until the charge is done or waiting for its next attempt
+func (r *RecurringCharger) resume(ctx context.Context, charge *RecurringCharge) (*RecurringCharge, error) {
+	for !charge.Done() && !r.now().Before(charge.NextAttemptAt) {
+		last := charge.last()
+
+		var err error
+		switch {
+		case last != nil && !last.settled():
+			err = r.verify(ctx, last)
+		case last != nil && last.unsent():
+			// Repeat the request that never reached Paystack, with the same reference
+			if last.Kind == AttemptPartialDebit {
+				err = r.partial(ctx, charge, last.Round)
+			} else {
+				err = r.full(ctx, charge, last.Round)
+			}
+		case last != nil && r.fallsBack(last):
+			err = r.partial(ctx, charge, last.Round)
+		default:
+			round := 1
+			if last != nil {
+				round = last.Round + 1
+			}
+			err = r.full(ctx, charge, round)
+		}
+
+		r.settle(charge)
+
+		if serr := r.store.Save(ctx, charge); serr != nil {
+			return charge, errors.Join(err, serr)
+		}
+		if err != nil {
+			return charge, err
+		}
+	}
+
+	return charge, nil
+}
+
+func (r *RecurringCharger) full(ctx context.Context, charge *RecurringCharge, round int) error {
+	attempt := ChargeAttempt{
+		Round:     round,
+		Kind:      AttemptChargeAuthorization,
+		Reference: fmt.Sprintf("%s-%d", charge.ID, round),
+		Amount:    charge.Outstanding(),
+		At:        r.now(),
+	}
+
+	metadata := maps.Clone(charge.Metadata)
+	if metadata == nil {
+		metadata = make(map[string]any)
+	}
+	metadata["recurring_charge_id"] = charge.ID
+	metadata["recurring_charge_round"] = strconv.Itoa(round)
+
+	builder := NewChargeAuthorizationRequestBuilder().
+		Amount(attempt.Amount).
+		Email(charge.Email).
+		AuthorizationCode(charge.AuthorizationCode).
+		Reference(attempt.Reference).
+		Metadata(types.Metadata{Metadata: metadata, Valid: true})
+	if charge.Currency != "" {
+		builder.Currency(charge.Currency)
+	}
+
+	rsp, err := r.client.ChargeAuthorization(ctx, *builder)
+	switch {
+	case err != nil:
+		attempt.Error = err.Error()
+	case refused(rsp):
+		err = fmt.Errorf("charging %s: %w", attempt.Reference, rsp.Err())
+		attempt.Error = err.Error()
+	case duplicateReference(rsp.Message):
+		// An earlier request with this reference reached Paystack after all
+		attempt.Error = rsp.Message
+	case rsp.Err() != nil:
+		r.reject(&attempt, rsp.Message)
+	default:
+		r.record(&attempt, rsp.Data.ID.Uint64(), rsp.Data.Status, rsp.Data.Amount.Int64(), rsp.Data.GatewayResponse.String())
+	}
+
+	charge.Attempts = append(charge.Attempts, attempt)
+
+	return err
+}
+
+func (r *RecurringCharger) partial(ctx context.Context, charge *RecurringCharge, round int) error {
+	attempt := ChargeAttempt{
+		Round:     round,
+		Kind:      AttemptPartialDebit,
+		Reference: fmt.Sprintf("%s-%d-partial", charge.ID, round),
+		Amount:    charge.Outstanding(),
+		AtLeast:   min(r.atLeast, charge.Outstanding()),
+		At:        r.now(),
+	}
+
+	if charge.Currency == "" {
+		return fmt.Errorf("%w: %s", ErrPartialDebitCurrency, charge.ID)
+	}
+
+	builder := NewPartialDebitRequestBuilder().
+		AuthorizationCode(charge.AuthorizationCode).
+		Currency(charge.Currency).
+		Amount(attempt.Amount).
+		Email(charge.Email).
+		Reference(attempt.Reference)
+	if attempt.AtLeast > 0 {
+		builder.AtLeast(strconv.Itoa(attempt.AtLeast))
+	}
+
+	rsp, err := r.client.PartialDebit(ctx, *builder)
+	switch {
+	case err != nil:
+		attempt.Error = err.Error()
+	case refused(rsp):
+		err = fmt.Errorf("debiting %s: %w", attempt.Reference, rsp.Err())
+		attempt.Error = err.Error()
+	case duplicateReference(rsp.Message):
+		// An earlier request with this reference reached Paystack after all
+		attempt.Error = rsp.Message
+	case rsp.Err() != nil:
+		r.reject(&attempt, rsp.Message)
+	default:
+		r.record(&attempt, rsp.Data.ID.Uint64(), rsp.Data.Status, rsp.Data.Amount.Int64(), rsp.Data.GatewayResponse.String())
+	}
+
+	charge.Attempts = append(charge.Attempts, attempt)
+
+	return err
+}
+
+// verify looks up an attempt whose outcome is unknown by its reference. Only a reply that
+// no transaction has the reference marks it unsent; any other failure leaves it unknown.
+func (r *RecurringCharger) verify(ctx context.Context, attempt *ChargeAttempt) error {
+	attempt.At = r.now()
+
+	rsp, err := r.client.Verify(ctx, attempt.Reference)
+	switch {
+	case err != nil:
+		attempt.Error = err.Error()
+		return err
+	case rsp.NotFound():
+		// No transaction has the reference, so the request never reached Paystack
+		attempt.Status = enums.TransactionStatusAbandoned
+		attempt.Error = rsp.Message
+	case rsp.Err() != nil:
+		err := fmt.Errorf("verifying %s: %w", attempt.Reference, rsp.Err())
+		attempt.Error = err.Error()
+		return err
+	default:
+		attempt.Error = ""
+		r.record(attempt, rsp.Data.ID.Uint64(), rsp.Data.Status, rsp.Data.Amount.Int64(), rsp.Data.GatewayResponse.String())
+	}
+
+	return nil
+}
+
+func (r *RecurringCharger) record(attempt *ChargeAttempt, id uint64, status enums.TransactionStatus, amount int64, gatewayResponse string) {
+	attempt.TransactionID = id
+	attempt.Status = status
+	attempt.GatewayResponse = gatewayResponse
+
+	if status == enums.TransactionStatusSuccess {
+		attempt.Collected = int(amount)
+	} else if d, ok := r.classifier.Classify(status.String(), gatewayResponse); ok {
+		attempt.Decline = &d
+	}
+}
+
+// refused reports whether Paystack turned a request down without looking at it, so its
+// outcome says nothing about the customer's card
+func refused[T any](rsp *types.Response[T]) bool {
+	return rsp.Retryable() || rsp.Unauthorized()
+}
+
+// duplicateReference reports whether Paystack rejected a request because a transaction
+// already has its reference. The attempt's outcome is then that transaction's, so it is
+// verified rather than recorded as a decline.
+func duplicateReference(message string) bool {
+	message = strings.ToLower(message)
+
+	return strings.Contains(message, "duplicate") ||
+		(strings.Contains(message, "reference") && strings.Contains(message, "already"))
+}
+
+// reject records a request Paystack declined, e.g. for an invalid authorization code
+func (r *RecurringCharger) reject(attempt *ChargeAttempt, message string) {
+	attempt.Status = enums.TransactionStatusFailed
+	attempt.GatewayResponse = message
+
+	d, _ := r.classifier.Classify(attempt.Status.String(), message)
+	attempt.Decline = &d
+}
+
+// fallsBack reports whether a declined full charge should be followed by a partial debit
+func (r *RecurringCharger) fallsBack(attempt *ChargeAttempt) bool {
+	return r.partialDebit &&
+		attempt.Kind == AttemptChargeAuthorization &&
+		attempt.Decline != nil &&
+		insufficientFunds(attempt.GatewayResponse)
+}
+
+// settle updates the charge's status from its last attempt
+func (r *RecurringCharger) settle(charge *RecurringCharge) {
+	now := r.now()
+	charge.UpdatedAt = now
+
+	last := charge.last()
+	switch {
+	case last == nil:
+	case last.unsent():
+		charge.NextAttemptAt = now
+	case !last.settled():
+		charge.NextAttemptAt = now.Add(r.recheck)
+	case last.Status == enums.TransactionStatusSuccess:
+		charge.Collected += last.Collected
+		charge.NextAttemptAt = time.Time{}
+		charge.Status = RecurringCollected
+		if charge.Outstanding() > 0 {
+			charge.Status = RecurringPartiallyCollected
+		}
+	case r.fallsBack(last):
+		charge.NextAttemptAt = now
+	case last.Decline != nil && last.Decline.Retryable() && last.Round <= len(r.schedule):
+		charge.NextAttemptAt = now.Add(max(r.schedule[last.Round-1], last.Decline.RetryAfter))
+	default:
+		charge.NextAttemptAt = time.Time{}
+		charge.Status = RecurringFailed
+	}
+}
+
+func insufficientFunds(gatewayResponse string) bool {
+	text := normalizeDecline(gatewayResponse)
+
+	return strings.Contains(text, "insufficient funds") || strings.Contains(text, "not sufficient funds")
+}
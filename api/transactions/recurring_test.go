@@ -0,0 +1,382 @@
+package transactions
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/huysamen/paystack-go/internal/apitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func transaction(status, reference string, amount int, gatewayResponse string) apitest.Reply {
+	return apitest.OK(fmt.Sprintf(`{"id":4099546180,"status":%q,"reference":%q,"amount":%d,"gateway_response":%q,"currency":"NGN"}`,
+		status, reference, amount, gatewayResponse))
+}
+
+func newRecurringAPI(t *testing.T, replies ...apitest.Reply) (*apitest.Server, *Client) {
+	api := apitest.NewServer(t).Reply(replies...)
+
+	return api, (*Client)(api.API())
+}
+
+// clock is a settable test clock
+type clock struct {
+	t time.Time
+}
+
+func (c *clock) now() time.Time {
+	return c.t
+}
+
+func testCharger(client *Client) (*RecurringCharger, *clock) {
+	c := &clock{t: time.Date(2024, 8, 1, 9, 0, 0, 0, time.UTC)}
+
+	r := NewRecurringCharger(client, NewMemoryRecurringStore())
+	r.now = c.now
+
+	return r, c
+}
+
+func invoice() RecurringChargeRequestBuilder {
+	return *NewRecurringChargeRequestBuilder("INV-001", "demo@test.com", "AUTH_uh8bcl3zbn", 50000).
+		Currency("NGN").
+		Metadata(map[string]any{"plan": "gold"})
+}
+
+const (
+	chargePath  = "/transaction/charge_authorization"
+	partialPath = "/transaction/partial_debit"
+)
+
+func TestRecurringCharger_Collected(t *testing.T) {
+	api, client := newRecurringAPI(t, transaction("success", "INV-001-1", 50000, "Approved"))
+	charger, _ := testCharger(client)
+
+	charge, err := charger.Charge(context.Background(), invoice())
+	require.NoError(t, err)
+	assert.Equal(t, RecurringCollected, charge.Status)
+	assert.Equal(t, 50000, charge.Collected)
+	assert.Zero(t, charge.Outstanding())
+	assert.True(t, charge.Done())
+
+	require.Len(t, charge.Attempts, 1)
+	attempt, ok := charge.Attempt("INV-001-1")
+	require.True(t, ok)
+	assert.Equal(t, uint64(4099546180), attempt.TransactionID)
+	assert.Equal(t, AttemptChargeAuthorization, attempt.Kind)
+
+	require.Len(t, api.Requests(), 1)
+	assert.Equal(t, map[string]any{
+		"amount":             float64(50000),
+		"email":              "demo@test.com",
+		"authorization_code": "AUTH_uh8bcl3zbn",
+		"reference":          "INV-001-1",
+		"currency":           "NGN",
+		"metadata":           map[string]any{"plan": "gold", "recurring_charge_id": "INV-001", "recurring_charge_round": "1"},
+	}, api.Requests()[0].Body)
+
+	// Charging the same invoice again makes no request
+	again, err := charger.Charge(context.Background(), invoice())
+	require.NoError(t, err)
+	assert.Equal(t, RecurringCollected, again.Status)
+	assert.Len(t, api.Requests(), 1)
+}
+
+func TestRecurringCharger_PartialDebit(t *testing.T) {
+	api, client := newRecurringAPI(t,
+		transaction("failed", "INV-001-1", 50000, "Insufficient Funds"),
+		transaction("success", "INV-001-1-partial", 30000, "Approved"),
+	)
+	charger, _ := testCharger(client)
+	charger.PartialDebit(10000)
+
+	charge, err := charger.Charge(context.Background(), invoice())
+	require.NoError(t, err)
+	assert.Equal(t, RecurringPartiallyCollected, charge.Status)
+	assert.Equal(t, 30000, charge.Collected)
+	assert.Equal(t, 20000, charge.Outstanding())
+
+	require.Len(t, charge.Attempts, 2)
+	assert.Equal(t, DeclineSoft, charge.Attempts[0].Decline.Category)
+	assert.Equal(t, ChargeAttempt{
+		Round:           1,
+		Kind:            AttemptPartialDebit,
+		Reference:       "INV-001-1-partial",
+		TransactionID:   4099546180,
+		Amount:          50000,
+		AtLeast:         10000,
+		Collected:       30000,
+		Status:          "success",
+		GatewayResponse: "Approved",
+		At:              charge.Attempts[1].At,
+	}, charge.Attempts[1])
+
+	assert.Equal(t, []string{chargePath, partialPath}, api.Paths())
+	assert.Equal(t, "10000", api.Requests()[1].Body["at_least"])
+	assert.Equal(t, "INV-001-1-partial", api.Requests()[1].Body["reference"])
+}
+
+func TestRecurringCharger_RetriesSoftDeclines(t *testing.T) {
+	api, client := newRecurringAPI(t,
+		transaction("failed", "INV-001-1", 50000, "Insufficient Funds"),
+		transaction("failed", "INV-001-2", 50000, "Issuer or Switch Inoperative"),
+		transaction("success", "INV-001-3", 50000, "Approved"),
+	)
+	charger, clock := testCharger(client)
+	charger.Schedule(time.Hour, 2*time.Hour)
+	ctx := context.Background()
+
+	charge, err := charger.Charge(ctx, invoice())
+	require.NoError(t, err)
+	assert.Equal(t, RecurringScheduled, charge.Status)
+
+	// Insufficient funds waits a day, longer than the schedule's hour
+	assert.Equal(t, clock.t.Add(24*time.Hour), charge.NextAttemptAt)
+
+	n, err := charger.RunDue(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	clock.t = charge.NextAttemptAt
+	n, err = charger.RunDue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	charge, err = charger.Resume(ctx, "INV-001")
+	require.NoError(t, err)
+	assert.Equal(t, clock.t.Add(2*time.Hour), charge.NextAttemptAt)
+
+	clock.t = charge.NextAttemptAt
+	charge, err = charger.Resume(ctx, "INV-001")
+	require.NoError(t, err)
+	assert.Equal(t, RecurringCollected, charge.Status)
+	assert.Equal(t, []int{1, 2, 3}, rounds(charge))
+	assert.Len(t, api.Requests(), 3)
+}
+
+func TestRecurringCharger_Fails(t *testing.T) {
+	t.Run("hard decline", func(t *testing.T) {
+		_, client := newRecurringAPI(t, transaction("failed", "INV-001-1", 50000, "Expired Card"))
+		charger, _ := testCharger(client)
+
+		charge, err := charger.Charge(context.Background(), invoice())
+		require.NoError(t, err)
+		assert.Equal(t, RecurringFailed, charge.Status)
+		assert.Equal(t, DeclineExpiredCard, charge.Attempts[0].Decline.Category)
+		assert.True(t, charge.NextAttemptAt.IsZero())
+	})
+
+	t.Run("retries exhausted", func(t *testing.T) {
+		_, client := newRecurringAPI(t,
+			transaction("failed", "INV-001-1", 50000, "Do Not Honor"),
+			transaction("failed", "INV-001-2", 50000, "Do Not Honor"),
+		)
+		charger, clock := testCharger(client)
+		charger.Schedule(time.Hour)
+
+		charge, err := charger.Charge(context.Background(), invoice())
+		require.NoError(t, err)
+
+		clock.t = charge.NextAttemptAt
+		charge, err = charger.Resume(context.Background(), "INV-001")
+		require.NoError(t, err)
+		assert.Equal(t, RecurringFailed, charge.Status)
+		assert.Len(t, charge.Attempts, 2)
+	})
+
+	t.Run("rejected request", func(t *testing.T) {
+		_, client := newRecurringAPI(t, apitest.Rejected("Invalid authorization code"))
+		charger, _ := testCharger(client)
+		charger.Classifier(NewDeclineClassifier().Rule("invalid authorization", DeclineHard, 0))
+
+		charge, err := charger.Charge(context.Background(), invoice())
+		require.NoError(t, err)
+		assert.Equal(t, RecurringFailed, charge.Status)
+		assert.Equal(t, "Invalid authorization code", charge.Attempts[0].GatewayResponse)
+	})
+}
+
+func TestRecurringCharger_UnknownOutcome(t *testing.T) {
+	t.Run("verified as collected", func(t *testing.T) {
+		api, client := newRecurringAPI(t,
+			apitest.ServerError,
+			transaction("success", "INV-001-1", 50000, "Approved"),
+		)
+		charger, clock := testCharger(client)
+
+		charge, err := charger.Charge(context.Background(), invoice())
+		assert.ErrorContains(t, err, "paystack server error")
+		require.NotNil(t, charge)
+		assert.Equal(t, RecurringScheduled, charge.Status)
+		assert.NotEmpty(t, charge.Attempts[0].Error)
+		assert.Equal(t, clock.t.Add(DefaultRecheckDelay), charge.NextAttemptAt)
+
+		clock.t = charge.NextAttemptAt
+		charge, err = charger.Resume(context.Background(), "INV-001")
+		require.NoError(t, err)
+		assert.Equal(t, RecurringCollected, charge.Status)
+		assert.Len(t, charge.Attempts, 1)
+		assert.Empty(t, charge.Attempts[0].Error)
+		assert.Equal(t, []string{chargePath, "/transaction/verify/INV-001-1"}, api.Paths())
+	})
+
+	t.Run("never reached paystack", func(t *testing.T) {
+		api, client := newRecurringAPI(t,
+			apitest.ServerError,
+			apitest.Rejected("Transaction reference not found"),
+			transaction("success", "INV-001-1", 50000, "Approved"),
+		)
+		charger, clock := testCharger(client)
+
+		charge, err := charger.Charge(context.Background(), invoice())
+		require.Error(t, err)
+
+		clock.t = charge.NextAttemptAt
+		charge, err = charger.Resume(context.Background(), "INV-001")
+		require.NoError(t, err)
+		assert.Equal(t, RecurringCollected, charge.Status)
+
+		// The round is repeated with the same reference
+		assert.Equal(t, []int{1, 1}, rounds(charge))
+		assert.Equal(t, []string{chargePath, "/transaction/verify/INV-001-1", chargePath}, api.Paths())
+		assert.Equal(t, "INV-001-1", api.Requests()[2].Body["reference"])
+
+		attempt, ok := charge.Attempt("INV-001-1")
+		require.True(t, ok)
+		assert.Equal(t, "success", attempt.Status.String())
+	})
+	t.Run("resent reference already used", func(t *testing.T) {
+		api, client := newRecurringAPI(t,
+			apitest.ServerError,
+			// The first request is not visible yet, so it looks unsent
+			apitest.NotFound("Transaction reference not found"),
+			apitest.Rejected("Duplicate Transaction Reference"),
+			transaction("success", "INV-001-1", 50000, "Approved"),
+		)
+		charger, clock := testCharger(client)
+
+		charge, err := charger.Charge(context.Background(), invoice())
+		require.Error(t, err)
+
+		clock.t = charge.NextAttemptAt
+		charge, err = charger.Resume(context.Background(), "INV-001")
+		require.NoError(t, err)
+		assert.Equal(t, RecurringScheduled, charge.Status)
+		assert.Equal(t, clock.t.Add(DefaultRecheckDelay), charge.NextAttemptAt, "verified again, not charged in a new round")
+
+		last := charge.Attempts[len(charge.Attempts)-1]
+		assert.Nil(t, last.Decline, "not recorded as a decline")
+		assert.Empty(t, last.Status)
+		assert.Equal(t, "Duplicate Transaction Reference", last.Error)
+
+		clock.t = charge.NextAttemptAt
+		charge, err = charger.Resume(context.Background(), "INV-001")
+		require.NoError(t, err)
+		assert.Equal(t, RecurringCollected, charge.Status)
+		assert.Equal(t, 50000, charge.Collected)
+		assert.Equal(t, []int{1, 1}, rounds(charge))
+		assert.Equal(t, []string{chargePath, "/transaction/verify/INV-001-1", chargePath, "/transaction/verify/INV-001-1"}, api.Paths())
+	})
+}
+
+func TestRecurringCharger_RefusedRequests(t *testing.T) {
+	t.Run("rate limited verify stays unverified", func(t *testing.T) {
+		api, client := newRecurringAPI(t,
+			apitest.ServerError,
+			apitest.RateLimited(),
+			transaction("success", "INV-001-1", 50000, "Approved"),
+		)
+		charger, clock := testCharger(client)
+
+		charge, err := charger.Charge(context.Background(), invoice())
+		require.Error(t, err)
+
+		clock.t = charge.NextAttemptAt
+		charge, err = charger.Resume(context.Background(), "INV-001")
+		assert.ErrorContains(t, err, "Too many requests")
+		assert.Equal(t, RecurringScheduled, charge.Status)
+		assert.Equal(t, clock.t.Add(DefaultRecheckDelay), charge.NextAttemptAt)
+		require.Len(t, charge.Attempts, 1)
+		assert.Empty(t, charge.Attempts[0].Status)
+
+		// The charge is verified again rather than sent twice
+		clock.t = charge.NextAttemptAt
+		charge, err = charger.Resume(context.Background(), "INV-001")
+		require.NoError(t, err)
+		assert.Equal(t, RecurringCollected, charge.Status)
+		assert.Equal(t, []string{chargePath, "/transaction/verify/INV-001-1", "/transaction/verify/INV-001-1"}, api.Paths())
+	})
+
+	for name, reply := range map[string]apitest.Reply{
+		"rate limited charge": apitest.RateLimited(),
+		"unauthorized charge": apitest.Unauthorized(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, client := newRecurringAPI(t, reply)
+			charger, clock := testCharger(client)
+
+			charge, err := charger.Charge(context.Background(), invoice())
+			require.Error(t, err)
+			assert.Equal(t, RecurringScheduled, charge.Status)
+			assert.Equal(t, clock.t.Add(DefaultRecheckDelay), charge.NextAttemptAt)
+
+			require.Len(t, charge.Attempts, 1)
+			assert.Nil(t, charge.Attempts[0].Decline, "not recorded as a decline")
+			assert.Empty(t, charge.Attempts[0].Status)
+			assert.NotEmpty(t, charge.Attempts[0].Error)
+		})
+	}
+}
+
+func TestRecurringCharger_PartialDebitNeedsCurrency(t *testing.T) {
+	api, client := newRecurringAPI(t)
+	charger, _ := testCharger(client)
+	charger.PartialDebit(10000)
+
+	_, err := charger.Charge(context.Background(), *NewRecurringChargeRequestBuilder("INV-001", "demo@test.com", "AUTH_uh8bcl3zbn", 50000))
+	assert.ErrorIs(t, err, ErrPartialDebitCurrency)
+	assert.Empty(t, api.Paths())
+}
+
+func TestMemoryRecurringStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryRecurringStore()
+	at := time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := store.Load(ctx, "missing")
+	assert.ErrorIs(t, err, ErrRecurringChargeNotFound)
+
+	for i, offset := range []time.Duration{2 * time.Hour, time.Hour, 3 * time.Hour} {
+		require.NoError(t, store.Save(ctx, &RecurringCharge{
+			ID:            fmt.Sprintf("INV-%d", i),
+			Status:        RecurringScheduled,
+			NextAttemptAt: at.Add(offset),
+		}))
+	}
+	require.NoError(t, store.Save(ctx, &RecurringCharge{ID: "INV-done", Status: RecurringCollected}))
+
+	due, err := store.Due(ctx, at.Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"INV-1", "INV-0"}, due)
+
+	// Loaded charges are copies
+	charge, err := store.Load(ctx, "INV-0")
+	require.NoError(t, err)
+	charge.Attempts = append(charge.Attempts, ChargeAttempt{Reference: "INV-0-1"})
+
+	charge, err = store.Load(ctx, "INV-0")
+	require.NoError(t, err)
+	assert.Empty(t, charge.Attempts)
+}
+
+func rounds(charge *RecurringCharge) []int {
+	var rounds []int
+	for _, a := range charge.Attempts {
+		rounds = append(rounds, a.Round)
+	}
+
+	return rounds
+}
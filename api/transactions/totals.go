@@ -103,12 +103,13 @@ type TotalsResponseData struct {
 type TotalsResponse = types.Response[TotalsResponseData]
 
 func (c *Client) Totals(ctx context.Context, builder TotalsRequestBuilder) (*TotalsResponse, error) {
-	req := builder.Build()
-	query := ""
+	path := fmt.Sprintf("%s/totals", basePath)
 
-	if req != nil {
-		query = req.toQuery()
+	if req := builder.Build(); req != nil {
+		if query := req.toQuery(); query != "" {
+			path += "?" + query
+		}
 	}
 
-	return net.Get[TotalsResponseData](ctx, c.Client, c.Secret, fmt.Sprintf("%s/totals", basePath), query, c.BaseURL)
+	return net.Get[TotalsResponseData](ctx, c.Client, c.Secret, path, c.BaseURL)
 }
@@ -1,10 +1,14 @@
 package transactions
 
 import (
+	"context"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -22,3 +26,24 @@ func TestTransactions_Totals_JSONDeserialization(t *testing.T) {
 	assert.True(t, rsp.Status.Bool())
 	assert.NotNil(t, rsp.Data)
 }
+
+func TestTransactions_Totals_Request(t *testing.T) {
+	var got *http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":true,"message":"ok","data":null}`))
+	}))
+	defer srv.Close()
+
+	client := &Client{Client: srv.Client(), Secret: "sk_test", BaseURL: srv.URL}
+
+	_, err := client.Totals(context.Background(), *NewTotalsRequestBuilder().PerPage(10).DateRange(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)))
+	require.NoError(t, err)
+
+	require.NotNil(t, got)
+	assert.Equal(t, "/transaction/totals", got.URL.Path)
+	assert.Equal(t, "10", got.URL.Query().Get("perPage"))
+	assert.Equal(t, "2024-01-01T00:00:00Z", got.URL.Query().Get("from"))
+	assert.Equal(t, "2024-01-31T00:00:00Z", got.URL.Query().Get("to"))
+}
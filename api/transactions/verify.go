@@ -12,5 +12,5 @@ type VerifyResponseData = types.Transaction
 type VerifyResponse = types.Response[VerifyResponseData]
 
 func (c *Client) Verify(ctx context.Context, reference string) (*VerifyResponse, error) {
-	return net.Get[VerifyResponseData](ctx, c.Client, c.Secret, fmt.Sprintf("%s%s/%s", basePath, transactionVerifyPath, reference), "", c.BaseURL)
+	return net.Get[VerifyResponseData](ctx, c.Client, c.Secret, fmt.Sprintf("%s%s/%s", basePath, transactionVerifyPath, reference), c.BaseURL)
 }
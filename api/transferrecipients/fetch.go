@@ -12,5 +12,5 @@ type FetchResponseData = types.Recipient
 type FetchResponse = types.Response[FetchResponseData]
 
 func (c *Client) Fetch(ctx context.Context, idOrCode string) (*FetchResponse, error) {
-	return net.Get[FetchResponseData](ctx, c.Client, c.Secret, fmt.Sprintf("%s/%s", basePath, idOrCode), "", c.BaseURL)
+	return net.Get[FetchResponseData](ctx, c.Client, c.Secret, fmt.Sprintf("%s/%s", basePath, idOrCode), c.BaseURL)
 }
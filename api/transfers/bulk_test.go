@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/huysamen/paystack-go/enums"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -18,6 +19,10 @@ func TestTransfers_Bulk_JSONDeserialization(t *testing.T) {
 	var rsp BulkResponse
 	err = json.Unmarshal(b, &rsp)
 	require.NoError(t, err)
+	require.NotEmpty(t, rsp.Data)
+	for _, item := range rsp.Data {
+		assert.Equal(t, enums.TransferStatusReceived, item.Status)
+	}
 }
 
 func TestTransfers_Bulk_Builder(t *testing.T) {
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/huysamen/paystack-go/enums"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -18,6 +19,7 @@ func TestTransfers_Initiate_JSONDeserialization(t *testing.T) {
 	var rsp InitiateResponse
 	err = json.Unmarshal(b, &rsp)
 	require.NoError(t, err)
+	assert.Equal(t, enums.TransferStatusOTP, rsp.Data.Status)
 }
 
 func TestTransfers_Initiate_Builder(t *testing.T) {
@@ -6,6 +6,7 @@ import (
 	"net/url"
 	"time"
 
+	"github.com/huysamen/paystack-go/enums"
 	"github.com/huysamen/paystack-go/net"
 	"github.com/huysamen/paystack-go/optional"
 	"github.com/huysamen/paystack-go/types"
@@ -15,6 +16,7 @@ type listRequest struct {
 	PerPage   *int
 	Page      *int
 	Recipient *int       // Filter by recipient ID
+	Status    *string    // Filter by transfer status
 	From      *time.Time // Start date filter
 	To        *time.Time // End date filter
 }
@@ -47,6 +49,12 @@ func (b *ListRequestBuilder) Recipient(recipient int) *ListRequestBuilder {
 	return b
 }
 
+func (b *ListRequestBuilder) Status(status enums.TransferStatus) *ListRequestBuilder {
+	b.req.Status = optional.String(status.String())
+
+	return b
+}
+
 func (b *ListRequestBuilder) DateRange(from, to time.Time) *ListRequestBuilder {
 	b.req.From = optional.Time(from)
 	b.req.To = optional.Time(to)
@@ -82,6 +90,9 @@ func (r *listRequest) toQuery() string {
 	if r.Recipient != nil {
 		params.Add("recipient", fmt.Sprintf("%d", *r.Recipient))
 	}
+	if r.Status != nil {
+		params.Add("status", *r.Status)
+	}
 	if r.From != nil {
 		params.Add("from", r.From.Format("2006-01-02T15:04:05.999Z"))
 	}
@@ -96,12 +107,11 @@ type ListResponseData = []types.Transfer
 type ListResponse = types.Response[ListResponseData]
 
 func (c *Client) List(ctx context.Context, builder ListRequestBuilder) (*ListResponse, error) {
-	req := builder.Build()
-	query := ""
+	path := basePath
 
-	if req != nil {
-		query = req.toQuery()
+	if query := builder.Build().toQuery(); query != "" {
+		path += "?" + query
 	}
 
-	return net.Get[ListResponseData](ctx, c.Client, c.Secret, basePath, query, c.BaseURL)
+	return net.Get[ListResponseData](ctx, c.Client, c.Secret, path, c.BaseURL)
 }
@@ -1,12 +1,17 @@
 package transfers
 
 import (
+	"context"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/huysamen/paystack-go/enums"
+
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -24,7 +29,35 @@ func TestTransfers_List_JSONDeserialization(t *testing.T) {
 func TestTransfers_List_Builder(t *testing.T) {
 	from := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
 	to := time.Date(2023, 1, 31, 0, 0, 0, 0, time.UTC)
-	q := NewListRequestBuilder().PerPage(10).Page(1).Recipient(1).DateRange(from, to).Build().toQuery()
+	q := NewListRequestBuilder().PerPage(10).Page(1).Recipient(1).Status(enums.TransferStatusReversed).DateRange(from, to).Build().toQuery()
 	assert.Contains(t, q, "perPage=10")
 	assert.Contains(t, q, "page=1")
+	assert.Contains(t, q, "status=reversed")
+}
+
+func TestTransfers_List_Request(t *testing.T) {
+	var got *http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":true,"message":"Transfers retrieved","data":[{"reference":"payout-1","status":"success"}]}`))
+	}))
+	defer srv.Close()
+
+	client := &Client{Client: srv.Client(), Secret: "sk_test", BaseURL: srv.URL}
+
+	rsp, err := client.List(context.Background(), *NewListRequestBuilder().PerPage(50).Status(enums.TransferStatusSuccess))
+	require.NoError(t, err)
+	require.Len(t, rsp.Data, 1)
+	assert.Equal(t, "payout-1", rsp.Data[0].Reference.String())
+
+	require.NotNil(t, got)
+	assert.Equal(t, "/transfer", got.URL.Path)
+	assert.Equal(t, "50", got.URL.Query().Get("perPage"))
+	assert.Equal(t, "success", got.URL.Query().Get("status"))
+
+	_, err = client.List(context.Background(), *NewListRequestBuilder())
+	require.NoError(t, err)
+	assert.Equal(t, "/transfer", got.URL.Path)
+	assert.Empty(t, got.URL.RawQuery)
 }
@@ -11,5 +11,5 @@ type CheckBalanceResponseData = []types.Balance
 type CheckBalanceResponse = types.Response[CheckBalanceResponseData]
 
 func (c *Client) CheckBalance(ctx context.Context) (*CheckBalanceResponse, error) {
-	return net.Get[CheckBalanceResponseData](ctx, c.Client, c.Secret, basePath, "", c.BaseURL)
+	return net.Get[CheckBalanceResponseData](ctx, c.Client, c.Secret, basePath, c.BaseURL)
 }
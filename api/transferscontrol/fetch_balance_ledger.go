@@ -11,5 +11,5 @@ type FetchBalanceLedgerResponseData = []types.BalanceLedger
 type FetchBalanceLedgerResponse = types.Response[FetchBalanceLedgerResponseData]
 
 func (c *Client) FetchBalanceLedger(ctx context.Context) (*FetchBalanceLedgerResponse, error) {
-	return net.Get[FetchBalanceLedgerResponseData](ctx, c.Client, c.Secret, "/balance/ledger", "", c.BaseURL)
+	return net.Get[FetchBalanceLedgerResponseData](ctx, c.Client, c.Secret, "/balance/ledger", c.BaseURL)
 }
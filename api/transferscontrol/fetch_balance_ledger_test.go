@@ -1,7 +1,10 @@
 package transferscontrol
 
 import (
+	"context"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
@@ -20,3 +23,22 @@ func TestTransfersControl_FetchBalanceLedger_JSONDeserialization(t *testing.T) {
 	require.NoError(t, err)
 	assert.True(t, rsp.Status.Bool())
 }
+
+func TestTransfersControl_FetchBalanceLedger_Request(t *testing.T) {
+	var got *http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":true,"message":"ok","data":null}`))
+	}))
+	defer srv.Close()
+
+	client := &Client{Client: srv.Client(), Secret: "sk_test", BaseURL: srv.URL}
+
+	_, err := client.FetchBalanceLedger(context.Background())
+	require.NoError(t, err)
+
+	require.NotNil(t, got)
+	assert.Equal(t, "/balance/ledger", got.URL.Path)
+	assert.Empty(t, got.URL.RawQuery)
+}
@@ -44,5 +44,5 @@ func (c *Client) ResolveAccount(ctx context.Context, builder ResolveAccountReque
 	req := builder.Build()
 	endpoint := fmt.Sprintf("%s?account_number=%s&bank_code=%s", accountResolveBasePath, req.AccountNumber, req.BankCode)
 
-	return net.Get[ResolveAccountResponseData](ctx, c.Client, c.Secret, endpoint, "", c.BaseURL)
+	return net.Get[ResolveAccountResponseData](ctx, c.Client, c.Secret, endpoint, c.BaseURL)
 }
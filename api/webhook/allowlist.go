@@ -0,0 +1,173 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// DefaultAllowedIPs are the addresses Paystack publishes as the source of its webhooks
+var DefaultAllowedIPs = []string{
+	"52.31.139.75",
+	"52.49.173.169",
+	"52.214.14.220",
+}
+
+// ErrSourceNotAllowed is returned when a webhook request comes from an address that is not
+// in the allowlist
+var ErrSourceNotAllowed = errors.New("webhook source not allowed")
+
+// IPAllowlist restricts the addresses webhooks are accepted from. Behind a load balancer or
+// reverse proxy, configure the proxies with TrustProxies so the client address is taken
+// from the X-Forwarded-For or X-Real-IP header they set.
+type IPAllowlist struct {
+	allowed []netip.Prefix
+	proxies []netip.Prefix
+}
+
+// NewIPAllowlist creates an allowlist of the given IP addresses and CIDR ranges, or of
+// DefaultAllowedIPs when none are given
+func NewIPAllowlist(allowed ...string) (*IPAllowlist, error) {
+	if len(allowed) == 0 {
+		allowed = DefaultAllowedIPs
+	}
+
+	prefixes, err := parsePrefixes(allowed)
+	if err != nil {
+		return nil, err
+	}
+
+	return &IPAllowlist{allowed: prefixes}, nil
+}
+
+// TrustProxies sets the IP addresses and CIDR ranges of proxies whose forwarding headers
+// are trusted. Headers from any other peer are ignored.
+func (a *IPAllowlist) TrustProxies(proxies ...string) error {
+	prefixes, err := parsePrefixes(proxies)
+	if err != nil {
+		return err
+	}
+
+	a.proxies = prefixes
+
+	return nil
+}
+
+// Allows returns true if ip is in the allowlist
+func (a *IPAllowlist) Allows(ip netip.Addr) bool {
+	return contains(a.allowed, ip)
+}
+
+// ClientIP returns the address of the client that sent r. When the peer is a trusted proxy
+// the address is taken from X-Forwarded-For, skipping trusted proxies from the right, or
+// from X-Real-IP when X-Forwarded-For is absent.
+func (a *IPAllowlist) ClientIP(r *http.Request) (netip.Addr, error) {
+	peer, err := parseAddr(r.RemoteAddr)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("invalid remote address %q: %w", r.RemoteAddr, err)
+	}
+
+	if !contains(a.proxies, peer) {
+		return peer, nil
+	}
+
+	if forwarded := r.Header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+		hops := strings.Split(strings.Join(forwarded, ","), ",")
+
+		client := peer
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop, err := parseAddr(strings.TrimSpace(hops[i]))
+			if err != nil {
+				return netip.Addr{}, fmt.Errorf("invalid X-Forwarded-For entry %q: %w", hops[i], err)
+			}
+
+			client = hop
+			if !contains(a.proxies, hop) {
+				break
+			}
+		}
+
+		return client, nil
+	}
+
+	if realIP := r.Header.Get("X-Real-IP"); realIP != "" {
+		client, err := parseAddr(strings.TrimSpace(realIP))
+		if err != nil {
+			return netip.Addr{}, fmt.Errorf("invalid X-Real-IP %q: %w", realIP, err)
+		}
+
+		return client, nil
+	}
+
+	return peer, nil
+}
+
+// Check returns an error wrapping ErrSourceNotAllowed if r was not sent from an allowed
+// address
+func (a *IPAllowlist) Check(r *http.Request) error {
+	ip, err := a.ClientIP(r)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrSourceNotAllowed, err)
+	}
+
+	if !a.Allows(ip) {
+		return fmt.Errorf("%w: %s", ErrSourceNotAllowed, ip)
+	}
+
+	return nil
+}
+
+func parsePrefixes(values []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(values))
+
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+
+		if strings.Contains(v, "/") {
+			prefix, err := netip.ParsePrefix(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid CIDR %q: %w", v, err)
+			}
+
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+
+		addr, err := netip.ParseAddr(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid IP address %q: %w", v, err)
+		}
+
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+
+	return prefixes, nil
+}
+
+// parseAddr parses an IP address with an optional port, as found in RemoteAddr
+func parseAddr(s string) (netip.Addr, error) {
+	if host, _, err := net.SplitHostPort(s); err == nil {
+		s = host
+	}
+
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+
+	return addr.Unmap().WithZone(""), nil
+}
+
+func contains(prefixes []netip.Prefix, ip netip.Addr) bool {
+	for _, p := range prefixes {
+		if p.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,212 @@
+package webhook
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewIPAllowlist(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []string
+		ip      string
+		allows  bool
+		wantErr bool
+	}{
+		{
+			name:   "defaults to Paystack IPs",
+			ip:     "52.214.14.220",
+			allows: true,
+		},
+		{
+			name: "default rejects other IPs",
+			ip:   "52.214.14.221",
+		},
+		{
+			name:    "custom CIDR",
+			allowed: []string{"10.0.0.0/8"},
+			ip:      "10.20.30.40",
+			allows:  true,
+		},
+		{
+			name:    "custom CIDR with host bits",
+			allowed: []string{"192.168.1.10/24"},
+			ip:      "192.168.1.200",
+			allows:  true,
+		},
+		{
+			name:    "custom IPv6 address",
+			allowed: []string{"2001:db8::1"},
+			ip:      "2001:db8::1",
+			allows:  true,
+		},
+		{
+			name:    "IPv4-mapped IPv6 matches IPv4 entry",
+			allowed: []string{"52.31.139.75"},
+			ip:      "::ffff:52.31.139.75",
+			allows:  true,
+		},
+		{
+			name:    "invalid IP",
+			allowed: []string{"not-an-ip"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid CIDR",
+			allowed: []string{"10.0.0.0/99"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := NewIPAllowlist(tt.allowed...)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			ip, err := parseAddr(tt.ip)
+			require.NoError(t, err)
+			assert.Equal(t, tt.allows, a.Allows(ip))
+		})
+	}
+}
+
+func TestIPAllowlist_ClientIP(t *testing.T) {
+	tests := []struct {
+		name       string
+		remoteAddr string
+		headers    map[string]string
+		expected   string
+		wantErr    bool
+	}{
+		{
+			name:       "uses peer address without trusted proxy",
+			remoteAddr: "52.31.139.75:4312",
+			expected:   "52.31.139.75",
+		},
+		{
+			name:       "ignores headers from untrusted peers",
+			remoteAddr: "203.0.113.9:4312",
+			headers:    map[string]string{"X-Forwarded-For": "52.31.139.75"},
+			expected:   "203.0.113.9",
+		},
+		{
+			name:       "uses X-Forwarded-For from trusted proxy",
+			remoteAddr: "10.0.0.2:4312",
+			headers:    map[string]string{"X-Forwarded-For": "52.31.139.75"},
+			expected:   "52.31.139.75",
+		},
+		{
+			name:       "skips trusted proxies from the right",
+			remoteAddr: "10.0.0.2:4312",
+			headers:    map[string]string{"X-Forwarded-For": "1.2.3.4, 52.31.139.75, 10.0.0.5"},
+			expected:   "52.31.139.75",
+		},
+		{
+			name:       "does not trust spoofed entries left of the client",
+			remoteAddr: "10.0.0.2:4312",
+			headers:    map[string]string{"X-Forwarded-For": "52.31.139.75, 203.0.113.9"},
+			expected:   "203.0.113.9",
+		},
+		{
+			name:       "uses X-Real-IP from trusted proxy",
+			remoteAddr: "10.0.0.2:4312",
+			headers:    map[string]string{"X-Real-IP": "52.49.173.169"},
+			expected:   "52.49.173.169",
+		},
+		{
+			name:       "uses peer when trusted proxy sets no header",
+			remoteAddr: "10.0.0.2:4312",
+			expected:   "10.0.0.2",
+		},
+		{
+			name:       "rejects invalid X-Forwarded-For",
+			remoteAddr: "10.0.0.2:4312",
+			headers:    map[string]string{"X-Forwarded-For": "garbage"},
+			wantErr:    true,
+		},
+		{
+			name:       "rejects invalid remote address",
+			remoteAddr: "garbage",
+			wantErr:    true,
+		},
+	}
+
+	a, err := NewIPAllowlist()
+	require.NoError(t, err)
+	require.NoError(t, a.TrustProxies("10.0.0.0/8"))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/webhook", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+
+			ip, err := a.ClientIP(req)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, netip.MustParseAddr(tt.expected), ip)
+		})
+	}
+}
+
+func TestIPAllowlist_TrustProxies_InvalidCIDR(t *testing.T) {
+	a, err := NewIPAllowlist()
+	require.NoError(t, err)
+
+	assert.Error(t, a.TrustProxies("10.0.0.0/99"))
+}
+
+func TestValidator_WithIPAllowlist(t *testing.T) {
+	a, err := NewIPAllowlist()
+	require.NoError(t, err)
+
+	v := NewValidator(testSecret).WithIPAllowlist(a)
+	body := readFixtureBytes(t, "charge.success")
+
+	req := newSignedRequest(t, body)
+	req.RemoteAddr = "52.31.139.75:443"
+	_, err = v.ValidateRequest(req)
+	assert.NoError(t, err)
+
+	req = newSignedRequest(t, body)
+	req.RemoteAddr = "203.0.113.9:443"
+	_, err = v.ValidateRequest(req)
+	assert.ErrorIs(t, err, ErrSourceNotAllowed)
+	assert.NotErrorIs(t, err, ErrInvalidSignature)
+	assert.Contains(t, err.Error(), "203.0.113.9")
+}
+
+func TestHandler_RejectsDisallowedSources(t *testing.T) {
+	a, err := NewIPAllowlist()
+	require.NoError(t, err)
+
+	var status int
+	h := NewHandler(NewValidator(testSecret).WithIPAllowlist(a)).
+		OnError(func(r *http.Request, e *Event, s int, err error) {
+			status = s
+			assert.ErrorIs(t, err, ErrSourceNotAllowed)
+		})
+
+	req := newSignedRequest(t, readFixtureBytes(t, "charge.success"))
+	req.RemoteAddr = "203.0.113.9:443"
+
+	assert.Equal(t, http.StatusForbidden, serve(h, req).Code)
+	assert.Equal(t, http.StatusForbidden, status)
+}
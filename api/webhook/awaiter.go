@@ -0,0 +1,437 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/huysamen/paystack-go/api/charge"
+	"github.com/huysamen/paystack-go/enums"
+	"github.com/huysamen/paystack-go/types"
+)
+
+const (
+	// DefaultAwaitTimeout is how long an Awaiter waits for an outcome by default
+	DefaultAwaitTimeout = 15 * time.Minute
+	// DefaultOutcomeRetention is how long an Awaiter keeps outcomes that arrive by webhook
+	// before anyone waits for them
+	DefaultOutcomeRetention = 10 * time.Minute
+)
+
+// DefaultPollBackoff polls after 10s, 20s, 40s, ... up to 2 minutes. Paystack asks for at
+// least 10 seconds between checks of a pending charge.
+var DefaultPollBackoff = ExponentialBackoff(10*time.Second, 2*time.Minute)
+
+// ErrAwaitTimeout is wrapped by the error returned when no final outcome arrives in time
+var ErrAwaitTimeout = errors.New("timed out waiting for outcome")
+
+// Outcome is the final state of a transaction, charge or transfer
+type Outcome string
+
+const (
+	OutcomeSuccess   Outcome = "success"
+	OutcomeFailed    Outcome = "failed"
+	OutcomeReversed  Outcome = "reversed"
+	OutcomeAbandoned Outcome = "abandoned"
+)
+
+// OutcomeSource reports how an Awaiter learned an outcome
+type OutcomeSource string
+
+const (
+	SourceWebhook OutcomeSource = "webhook"
+	SourcePoll    OutcomeSource = "poll"
+)
+
+// AwaitResult is the final outcome for a reference
+type AwaitResult struct {
+	Reference string
+	Outcome   Outcome
+	// Status is the status reported by the webhook or the API, e.g. "blocked" for a transfer
+	// with a failed outcome
+	Status string
+	Source OutcomeSource
+	// Event is the webhook event that settled the reference, or nil when it was polled
+	Event *Event
+}
+
+// ChargeChecker checks pending charges. It is implemented by *charge.Client.
+type ChargeChecker interface {
+	CheckPending(ctx context.Context, builder charge.CheckPendingRequestBuilder) (*charge.CheckPendingResponse, error)
+}
+
+// statusFunc fetches the current status of a reference
+type statusFunc func(ctx context.Context, reference string) (string, error)
+
+// outcomeKind separates the references of transactions, which charges share, from those of
+// transfers, so a transaction and a transfer with the same reference do not collide
+type outcomeKind string
+
+const (
+	kindTransaction outcomeKind = "transaction"
+	kindTransfer    outcomeKind = "transfer"
+)
+
+// outcomeKey identifies the object an outcome belongs to
+type outcomeKey struct {
+	kind      outcomeKind
+	reference string
+}
+
+// Awaiter lets callers block until a transaction, charge or transfer reaches a final
+// outcome, whichever comes first of its webhook and a poll of the API. Webhook handlers
+// feed events in with Notify; callers wait with WaitTransaction, WaitCharge or
+// WaitTransfer. Outcomes are correlated by reference, separately for transactions and
+// transfers. A reversal replaces the success it follows.
+//
+// Webhooks only report successful charges, and may be delivered to another instance of
+// the application, so the Awaiter also polls the API with backoff until the outcome is
+// known. An Awaiter is safe for concurrent use.
+type Awaiter struct {
+	transactions TransactionVerifier
+	transfers    TransferVerifier
+	charges      ChargeChecker
+	backoff      Backoff
+	timeout      time.Duration
+	retention    time.Duration
+	now          func() time.Time
+
+	mu       sync.Mutex
+	waiters  map[outcomeKey][]chan *AwaitResult
+	outcomes map[outcomeKey]*AwaitResult
+	expiry   map[outcomeKey]time.Time
+}
+
+// NewAwaiter creates an Awaiter that polls with the given clients, usually the
+// Transactions, Transfers and Charges clients of a paystack.Client. Waits whose client is
+// nil rely on webhooks alone.
+func NewAwaiter(transactions TransactionVerifier, transfers TransferVerifier, charges ChargeChecker) *Awaiter {
+	return &Awaiter{
+		transactions: transactions,
+		transfers:    transfers,
+		charges:      charges,
+		backoff:      DefaultPollBackoff,
+		timeout:      DefaultAwaitTimeout,
+		retention:    DefaultOutcomeRetention,
+		now:          time.Now,
+		waiters:      make(map[outcomeKey][]chan *AwaitResult),
+		outcomes:     make(map[outcomeKey]*AwaitResult),
+		expiry:       make(map[outcomeKey]time.Time),
+	}
+}
+
+// Backoff sets the delay before each poll
+func (a *Awaiter) Backoff(b Backoff) *Awaiter {
+	a.backoff = b
+
+	return a
+}
+
+// Timeout sets how long a wait lasts unless its context ends sooner
+func (a *Awaiter) Timeout(d time.Duration) *Awaiter {
+	a.timeout = d
+
+	return a
+}
+
+// Retention sets how long outcomes that arrive by webhook before anyone waits for them
+// are kept
+func (a *Awaiter) Retention(d time.Duration) *Awaiter {
+	a.retention = d
+
+	return a
+}
+
+// Notify records the outcome carried by event, if any, and releases its waiters. It has
+// the signature of an EventHandlerFunc, so it can be registered with Handler.On, or called
+// from existing handlers.
+func (a *Awaiter) Notify(_ context.Context, event *Event) error {
+	var (
+		outcome Outcome
+		kind    outcomeKind
+	)
+
+	switch event.Event {
+	case EventChargeSuccess:
+		outcome, kind = OutcomeSuccess, kindTransaction
+	case EventTransferSuccess:
+		outcome, kind = OutcomeSuccess, kindTransfer
+	case EventTransferFailed:
+		outcome, kind = OutcomeFailed, kindTransfer
+	case EventTransferReversed:
+		outcome, kind = OutcomeReversed, kindTransfer
+	default:
+		return nil
+	}
+
+	// The charge and transfer events share these fields
+	var payload struct {
+		Reference string `json:"reference"`
+		Status    string `json:"status"`
+	}
+	if err := json.Unmarshal(event.Data, &payload); err != nil {
+		return fmt.Errorf("%w: decoding %s: %w", ErrInvalidPayload, event.Event, err)
+	}
+	if payload.Reference == "" {
+		return nil
+	}
+
+	a.settle(kind, &AwaitResult{
+		Reference: payload.Reference,
+		Outcome:   outcome,
+		Status:    payload.Status,
+		Source:    SourceWebhook,
+		Event:     event,
+	})
+
+	return nil
+}
+
+// Register registers Notify on h for the events that carry an outcome. It replaces any
+// handlers registered for those events, so call Notify from your handlers instead when
+// you also handle them.
+func (a *Awaiter) Register(h *Handler) *Handler {
+	for _, event := range []string{EventChargeSuccess, EventTransferSuccess, EventTransferFailed, EventTransferReversed} {
+		h.On(event, a.Notify)
+	}
+
+	return h
+}
+
+// WaitTransaction waits for the outcome of a transaction, e.g. one started with
+// transactions.Initialize, polling transactions.Verify. Initialized transactions are
+// reported as abandoned until the customer pays, so abandoned is only returned when the
+// wait times out with the transaction still abandoned.
+func (a *Awaiter) WaitTransaction(ctx context.Context, reference string) (*AwaitResult, error) {
+	var poll statusFunc
+	if a.transactions != nil {
+		poll = func(ctx context.Context, reference string) (string, error) {
+			rsp, err := a.transactions.Verify(ctx, reference)
+			if err := checkPolled(rsp, err); err != nil {
+				return "", err
+			}
+
+			return rsp.Data.Status.String(), nil
+		}
+	}
+
+	return a.wait(ctx, outcomeKey{kindTransaction, reference}, poll, transactionOutcome)
+}
+
+// WaitCharge waits for the outcome of a charge started with charge.Create, polling
+// charge.CheckPending
+func (a *Awaiter) WaitCharge(ctx context.Context, reference string) (*AwaitResult, error) {
+	var poll statusFunc
+	if a.charges != nil {
+		poll = func(ctx context.Context, reference string) (string, error) {
+			rsp, err := a.charges.CheckPending(ctx, *charge.NewCheckPendingChargeRequestBuilder(reference))
+			if err := checkPolled(rsp, err); err != nil {
+				return "", err
+			}
+
+			return rsp.Data.Status.String(), nil
+		}
+	}
+
+	return a.wait(ctx, outcomeKey{kindTransaction, reference}, poll, transactionOutcome)
+}
+
+// WaitTransfer waits for the outcome of a transfer started with transfers.Initiate,
+// polling transfers.Verify
+func (a *Awaiter) WaitTransfer(ctx context.Context, reference string) (*AwaitResult, error) {
+	var poll statusFunc
+	if a.transfers != nil {
+		poll = func(ctx context.Context, reference string) (string, error) {
+			rsp, err := a.transfers.Verify(ctx, reference)
+			if err := checkPolled(rsp, err); err != nil {
+				return "", err
+			}
+
+			return rsp.Data.Status.String(), nil
+		}
+	}
+
+	return a.wait(ctx, outcomeKey{kindTransfer, reference}, poll, transferOutcome)
+}
+
+func (a *Awaiter) wait(ctx context.Context, key outcomeKey, poll statusFunc, outcomeOf func(string) (Outcome, bool)) (*AwaitResult, error) {
+	ch, result := a.subscribe(key)
+	if result != nil {
+		return result, nil
+	}
+	defer a.unsubscribe(key, ch)
+
+	reference := key.reference
+
+	if a.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, a.timeout)
+		defer cancel()
+	}
+
+	var (
+		attempts   int
+		lastStatus string
+		lastErr    error
+	)
+
+	timer := time.NewTimer(a.backoff(1))
+	defer timer.Stop()
+
+	if poll == nil {
+		timer.Stop()
+	}
+
+	for {
+		select {
+		case result := <-ch:
+			return result, nil
+		case <-ctx.Done():
+			if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, ctx.Err()
+			}
+			if lastStatus == string(OutcomeAbandoned) {
+				return &AwaitResult{Reference: reference, Outcome: OutcomeAbandoned, Status: lastStatus, Source: SourcePoll}, nil
+			}
+
+			err := fmt.Errorf("%w: %s", ErrAwaitTimeout, reference)
+			if lastStatus != "" {
+				err = fmt.Errorf("%w: last status %q", err, lastStatus)
+			}
+			if lastErr != nil {
+				err = fmt.Errorf("%w: last poll failed: %v", err, lastErr)
+			}
+
+			return nil, err
+		case <-timer.C:
+		}
+
+		attempts++
+
+		status, err := poll(ctx, reference)
+		if err != nil {
+			lastErr = err
+		} else {
+			lastStatus, lastErr = status, nil
+
+			if outcome, ok := outcomeOf(status); ok {
+				result := &AwaitResult{Reference: reference, Outcome: outcome, Status: status, Source: SourcePoll}
+				a.settle(key.kind, result)
+
+				return result, nil
+			}
+		}
+
+		timer.Reset(a.backoff(attempts + 1))
+	}
+}
+
+// subscribe returns a channel that receives the outcome for key, or the outcome itself
+// when it is already known
+func (a *Awaiter) subscribe(key outcomeKey) (chan *AwaitResult, *AwaitResult) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if result, ok := a.outcomes[key]; ok && a.now().Before(a.expiry[key]) {
+		return nil, result
+	}
+
+	ch := make(chan *AwaitResult, 1)
+	a.waiters[key] = append(a.waiters[key], ch)
+
+	return ch, nil
+}
+
+func (a *Awaiter) unsubscribe(key outcomeKey, ch chan *AwaitResult) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	waiters := a.waiters[key]
+	for i, w := range waiters {
+		if w == ch {
+			waiters = append(waiters[:i], waiters[i+1:]...)
+			break
+		}
+	}
+
+	if len(waiters) == 0 {
+		delete(a.waiters, key)
+	} else {
+		a.waiters[key] = waiters
+	}
+}
+
+// settle releases the waiters for the result's reference and keeps the result for waits
+// that start later. The first outcome is kept, unless a reversal follows it.
+func (a *Awaiter) settle(kind outcomeKind, result *AwaitResult) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.now()
+	for key, expiry := range a.expiry {
+		if !now.Before(expiry) {
+			delete(a.outcomes, key)
+			delete(a.expiry, key)
+		}
+	}
+
+	key := outcomeKey{kind, result.Reference}
+	if known, ok := a.outcomes[key]; !ok || (result.Outcome == OutcomeReversed && known.Outcome != OutcomeReversed) {
+		a.outcomes[key] = result
+		a.expiry[key] = now.Add(a.retention)
+	}
+
+	for _, ch := range a.waiters[key] {
+		select {
+		case ch <- result:
+		default:
+		}
+	}
+	delete(a.waiters, key)
+}
+
+// transactionOutcome maps the status of a transaction or charge to its outcome. Abandoned
+// is not final, since that is the status of a transaction the customer has not paid yet.
+func transactionOutcome(status string) (Outcome, bool) {
+	switch s := enums.TransactionStatus(status); {
+	case !s.IsTerminal():
+		return "", false
+	case s.IsSuccessful():
+		return OutcomeSuccess, true
+	case s == enums.TransactionStatusReversed:
+		return OutcomeReversed, true
+	default:
+		return OutcomeFailed, true
+	}
+}
+
+// transferOutcome maps the status of a transfer to its outcome. Rejected and blocked
+// transfers have failed.
+func transferOutcome(status string) (Outcome, bool) {
+	switch s := enums.TransferStatus(status); {
+	case !s.IsTerminal():
+		return "", false
+	case s.IsSuccessful():
+		return OutcomeSuccess, true
+	case s == enums.TransferStatusReversed:
+		return OutcomeReversed, true
+	case s == enums.TransferStatusAbandoned:
+		return OutcomeAbandoned, true
+	default:
+		return OutcomeFailed, true
+	}
+}
+
+func checkPolled[T any](rsp *types.Response[T], err error) error {
+	if err != nil {
+		return err
+	}
+	if rsp == nil {
+		return errors.New("empty response")
+	}
+
+	return rsp.Err()
+}
@@ -0,0 +1,310 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/huysamen/paystack-go/api/charge"
+	"github.com/huysamen/paystack-go/api/transactions"
+	"github.com/huysamen/paystack-go/api/transfers"
+	"github.com/huysamen/paystack-go/enums"
+	"github.com/huysamen/paystack-go/types"
+	"github.com/huysamen/paystack-go/types/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// statusSequence returns the given statuses in turn, repeating the last one
+type statusSequence struct {
+	mu       sync.Mutex
+	statuses []string
+	err      error
+	polls    int
+}
+
+func (s *statusSequence) next() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.polls++
+	if s.err != nil {
+		return "", s.err
+	}
+
+	status := s.statuses[0]
+	if len(s.statuses) > 1 {
+		s.statuses = s.statuses[1:]
+	}
+
+	return status, nil
+}
+
+func (s *statusSequence) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.polls
+}
+
+func (s *statusSequence) Verify(_ context.Context, reference string) (*transactions.VerifyResponse, error) {
+	status, err := s.next()
+	if err != nil {
+		return nil, err
+	}
+
+	return &transactions.VerifyResponse{Status: data.NewBool(true), Data: types.Transaction{Reference: data.NewString(reference), Status: enums.TransactionStatus(status)}}, nil
+}
+
+type transferSequence struct{ *statusSequence }
+
+func (s transferSequence) Verify(_ context.Context, reference string) (*transfers.VerifyResponse, error) {
+	status, err := s.next()
+	if err != nil {
+		return nil, err
+	}
+
+	return &transfers.VerifyResponse{Status: data.NewBool(true), Data: types.Transfer{Reference: data.NewString(reference), Status: enums.TransferStatus(status)}}, nil
+}
+
+type chargeSequence struct{ *statusSequence }
+
+func (s chargeSequence) CheckPending(_ context.Context, builder charge.CheckPendingRequestBuilder) (*charge.CheckPendingResponse, error) {
+	status, err := s.next()
+	if err != nil {
+		return nil, err
+	}
+
+	return &charge.CheckPendingResponse{Status: data.NewBool(true), Data: types.Charge{Reference: data.NewString(builder.Build().Reference), Status: enums.ChargeStatus(status)}}, nil
+}
+
+func fastPolling(int) time.Duration { return time.Millisecond }
+
+func TestAwaiter_WebhookArrivesWhileWaiting(t *testing.T) {
+	polls := &statusSequence{statuses: []string{"pending"}}
+	a := NewAwaiter(nil, transferSequence{polls}, nil).Backoff(func(int) time.Duration { return time.Hour })
+
+	results := make(chan *AwaitResult, 1)
+	go func() {
+		result, err := a.WaitTransfer(context.Background(), "1jhbs3ozmen0k7y5efmw")
+		assert.NoError(t, err)
+		results <- result
+	}()
+
+	// Give the waiter time to subscribe; an outcome that arrives first is retained anyway
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, a.Notify(context.Background(), readEventFixture(t, "transfer.success")))
+
+	select {
+	case result := <-results:
+		assert.Equal(t, OutcomeSuccess, result.Outcome)
+		assert.Equal(t, SourceWebhook, result.Source)
+		assert.Equal(t, "success", result.Status)
+		require.NotNil(t, result.Event)
+		assert.Equal(t, EventTransferSuccess, result.Event.Event)
+	case <-time.After(5 * time.Second):
+		t.Fatal("waiter was not released")
+	}
+
+	assert.Equal(t, 0, polls.count())
+}
+
+func TestAwaiter_WebhookArrivesBeforeWait(t *testing.T) {
+	a := NewAwaiter(nil, nil, nil).Timeout(time.Millisecond)
+
+	h := a.Register(NewHandler(NewValidator(testSecret)))
+	require.Equal(t, 200, serve(h, newSignedRequest(t, readFixtureBytes(t, "charge.success"))).Code)
+
+	result, err := a.WaitTransaction(context.Background(), "qTPrJoy9Bx")
+	require.NoError(t, err)
+	assert.Equal(t, OutcomeSuccess, result.Outcome)
+	assert.Equal(t, SourceWebhook, result.Source)
+
+	result, err = a.WaitCharge(context.Background(), "qTPrJoy9Bx")
+	require.NoError(t, err, "charges and transactions share references")
+	assert.Equal(t, OutcomeSuccess, result.Outcome)
+}
+
+func TestAwaiter_RetentionExpires(t *testing.T) {
+	clock := newTestClock()
+	a := NewAwaiter(nil, nil, nil).Timeout(time.Millisecond).Retention(time.Minute)
+	a.now = clock.now
+
+	require.NoError(t, a.Notify(context.Background(), readEventFixture(t, "transfer.reversed")))
+
+	result, err := a.WaitTransfer(context.Background(), "jvrjckwenm")
+	require.NoError(t, err)
+	assert.Equal(t, OutcomeReversed, result.Outcome)
+
+	clock.advance(time.Minute)
+
+	_, err = a.WaitTransfer(context.Background(), "jvrjckwenm")
+	assert.ErrorIs(t, err, ErrAwaitTimeout)
+}
+
+func TestAwaiter_ReversalReplacesSuccess(t *testing.T) {
+	a := NewAwaiter(nil, nil, nil).Timeout(time.Millisecond)
+
+	success := readEventFixture(t, "transfer.success")
+	reversed := readEventFixture(t, "transfer.success")
+	reversed.Event = EventTransferReversed
+
+	require.NoError(t, a.Notify(context.Background(), success))
+	require.NoError(t, a.Notify(context.Background(), reversed))
+
+	result, err := a.WaitTransfer(context.Background(), "1jhbs3ozmen0k7y5efmw")
+	require.NoError(t, err)
+	assert.Equal(t, OutcomeReversed, result.Outcome)
+
+	// A late redelivery of the success does not undo the reversal
+	require.NoError(t, a.Notify(context.Background(), success))
+
+	result, err = a.WaitTransfer(context.Background(), "1jhbs3ozmen0k7y5efmw")
+	require.NoError(t, err)
+	assert.Equal(t, OutcomeReversed, result.Outcome)
+}
+
+func TestAwaiter_TransactionsAndTransfersDoNotCollide(t *testing.T) {
+	a := NewAwaiter(nil, nil, nil).Timeout(time.Millisecond)
+
+	require.NoError(t, a.Notify(context.Background(), readEventFixture(t, "charge.success")))
+
+	_, err := a.WaitTransfer(context.Background(), "qTPrJoy9Bx")
+	assert.ErrorIs(t, err, ErrAwaitTimeout, "a charge does not settle a transfer with the same reference")
+
+	require.NoError(t, a.Notify(context.Background(), readEventFixture(t, "transfer.success")))
+
+	_, err = a.WaitTransaction(context.Background(), "1jhbs3ozmen0k7y5efmw")
+	assert.ErrorIs(t, err, ErrAwaitTimeout, "a transfer does not settle a transaction with the same reference")
+
+	result, err := a.WaitTransaction(context.Background(), "qTPrJoy9Bx")
+	require.NoError(t, err)
+	assert.Equal(t, OutcomeSuccess, result.Outcome)
+}
+
+func TestAwaiter_PollingFallback(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []string
+		wait     func(a *Awaiter, polls *statusSequence) (*AwaitResult, error)
+		expected Outcome
+	}{
+		{
+			name:     "transaction",
+			statuses: []string{"abandoned", "ongoing", "success"},
+			wait: func(a *Awaiter, polls *statusSequence) (*AwaitResult, error) {
+				a.transactions = polls
+				return a.WaitTransaction(context.Background(), "ref")
+			},
+			expected: OutcomeSuccess,
+		},
+		{
+			name:     "charge",
+			statuses: []string{"pending", "send_otp", "failed"},
+			wait: func(a *Awaiter, polls *statusSequence) (*AwaitResult, error) {
+				a.charges = chargeSequence{polls}
+				return a.WaitCharge(context.Background(), "ref")
+			},
+			expected: OutcomeFailed,
+		},
+		{
+			name:     "transfer",
+			statuses: []string{"otp", "pending", "reversed"},
+			wait: func(a *Awaiter, polls *statusSequence) (*AwaitResult, error) {
+				a.transfers = transferSequence{polls}
+				return a.WaitTransfer(context.Background(), "ref")
+			},
+			expected: OutcomeReversed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			polls := &statusSequence{statuses: tt.statuses}
+			a := NewAwaiter(nil, nil, nil).Backoff(fastPolling)
+
+			result, err := tt.wait(a, polls)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result.Outcome)
+			assert.Equal(t, SourcePoll, result.Source)
+			assert.Equal(t, "ref", result.Reference)
+			assert.Nil(t, result.Event)
+			assert.Equal(t, 3, polls.count())
+
+			// Later waits for the same reference reuse the outcome
+			again, err := tt.wait(a, polls)
+			require.NoError(t, err)
+			assert.Same(t, result, again)
+			assert.Equal(t, 3, polls.count())
+		})
+	}
+}
+
+func TestAwaiter_Timeouts(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("abandoned transactions", func(t *testing.T) {
+		a := NewAwaiter(&statusSequence{statuses: []string{"abandoned"}}, nil, nil).
+			Backoff(fastPolling).
+			Timeout(20 * time.Millisecond)
+
+		result, err := a.WaitTransaction(ctx, "ref")
+		require.NoError(t, err)
+		assert.Equal(t, OutcomeAbandoned, result.Outcome)
+	})
+
+	t.Run("pending transfers", func(t *testing.T) {
+		a := NewAwaiter(nil, transferSequence{&statusSequence{statuses: []string{"pending"}}}, nil).
+			Backoff(fastPolling).
+			Timeout(20 * time.Millisecond)
+
+		_, err := a.WaitTransfer(ctx, "ref")
+		assert.ErrorIs(t, err, ErrAwaitTimeout)
+		assert.ErrorContains(t, err, `last status "pending"`)
+	})
+
+	t.Run("failing polls", func(t *testing.T) {
+		a := NewAwaiter(&statusSequence{err: errors.New("connection reset")}, nil, nil).
+			Backoff(fastPolling).
+			Timeout(20 * time.Millisecond)
+
+		_, err := a.WaitTransaction(ctx, "ref")
+		assert.ErrorIs(t, err, ErrAwaitTimeout)
+		assert.ErrorContains(t, err, "connection reset")
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		a := NewAwaiter(nil, nil, nil)
+
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		_, err := a.WaitTransaction(cancelled, "ref")
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.NotErrorIs(t, err, ErrAwaitTimeout)
+	})
+}
+
+func TestTransferOutcome(t *testing.T) {
+	tests := map[string]Outcome{
+		"success":   OutcomeSuccess,
+		"failed":    OutcomeFailed,
+		"rejected":  OutcomeFailed,
+		"blocked":   OutcomeFailed,
+		"reversed":  OutcomeReversed,
+		"abandoned": OutcomeAbandoned,
+	}
+
+	for status, expected := range tests {
+		outcome, final := transferOutcome(status)
+		assert.True(t, final, status)
+		assert.Equal(t, expected, outcome, status)
+	}
+
+	for _, status := range []string{"pending", "otp", "processing", "received", ""} {
+		_, final := transferOutcome(status)
+		assert.False(t, final, status)
+	}
+}
@@ -0,0 +1,302 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// EventBuilder builds webhook events of type T for testing handlers. Create one with the
+// constructor for an event, e.g. NewChargeSuccess, optionally seed it from a fixture, then
+// adjust the data with With.
+type EventBuilder[T any] struct {
+	event  string
+	data   T
+	decode func(*Event) (*T, error)
+	encode func(*T) ([]byte, error)
+	err    error
+}
+
+func newEventBuilder[T any](event string, decode func(*Event) (*T, error)) *EventBuilder[T] {
+	return &EventBuilder[T]{
+		event:  event,
+		decode: decode,
+		encode: encodeEventData[T],
+	}
+}
+
+// encodeEventData marshals data without its empty strings. Fields left unset on a builder
+// would otherwise be sent as "", which Paystack never sends and which enum fields reject.
+func encodeEventData[T any](data *T) ([]byte, error) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+
+	var v any
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(omitEmptyStrings(v))
+}
+
+// omitEmptyStrings removes object members that are empty strings from a decoded JSON value
+func omitEmptyStrings(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, member := range v {
+			if member == "" {
+				delete(v, k)
+				continue
+			}
+			v[k] = omitEmptyStrings(member)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = omitEmptyStrings(item)
+		}
+	}
+
+	return v
+}
+
+// Data replaces the event data
+func (b *EventBuilder[T]) Data(data T) *EventBuilder[T] {
+	b.data = data
+
+	return b
+}
+
+// With calls fn to modify the event data
+func (b *EventBuilder[T]) With(fn func(*T)) *EventBuilder[T] {
+	fn(&b.data)
+
+	return b
+}
+
+// Seed replaces the event data with the data of a webhook payload, e.g. one of the fixtures
+// in resources/examples/webhook
+func (b *EventBuilder[T]) Seed(payload []byte) *EventBuilder[T] {
+	var event Event
+	if err := json.Unmarshal(payload, &event); err != nil {
+		b.err = fmt.Errorf("failed to parse seed payload: %w", err)
+		return b
+	}
+
+	if event.Event != "" && event.Event != b.event {
+		b.err = fmt.Errorf("seed payload is a %s event, not %s", event.Event, b.event)
+		return b
+	}
+
+	data, err := b.decode(&event)
+	if err != nil {
+		b.err = fmt.Errorf("failed to decode seed payload: %w", err)
+		return b
+	}
+
+	b.data = *data
+
+	return b
+}
+
+// SeedFile seeds the event data from the webhook payload in the file at path
+func (b *EventBuilder[T]) SeedFile(path string) *EventBuilder[T] {
+	payload, err := os.ReadFile(path)
+	if err != nil {
+		b.err = fmt.Errorf("failed to read seed file: %w", err)
+		return b
+	}
+
+	return b.Seed(payload)
+}
+
+// SeedDir seeds the event data from the file named after the event type in dir, e.g.
+// "charge.success.json" in resources/examples/webhook
+func (b *EventBuilder[T]) SeedDir(dir string) *EventBuilder[T] {
+	return b.SeedFile(filepath.Join(dir, b.event+".json"))
+}
+
+// Build returns the event in Paystack's envelope
+func (b *EventBuilder[T]) Build() (*Event, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	data, err := b.encode(&b.data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s data: %w", b.event, err)
+	}
+
+	return &Event{
+		Event: b.event,
+		Data:  data,
+	}, nil
+}
+
+// Payload returns the JSON encoded event, as Paystack would send it
+func (b *EventBuilder[T]) Payload() ([]byte, error) {
+	event, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(event)
+}
+
+// Request returns a POST request delivering the event to url, signed by signer
+func (b *EventBuilder[T]) Request(ctx context.Context, signer *Signer, url string) (*http.Request, error) {
+	event, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return signer.NewRequest(ctx, url, event)
+}
+
+// NewChargeSuccess creates a builder for charge.success events
+func NewChargeSuccess() *EventBuilder[ChargeSuccessEvent] {
+	return newEventBuilder(EventChargeSuccess, (*Event).AsChargeSuccess)
+}
+
+// NewChargeDisputeCreate creates a builder for charge.dispute.create events
+func NewChargeDisputeCreate() *EventBuilder[ChargeDisputeEvent] {
+	return newEventBuilder(EventChargeDisputeCreate, (*Event).AsChargeDispute)
+}
+
+// NewChargeDisputeRemind creates a builder for charge.dispute.remind events
+func NewChargeDisputeRemind() *EventBuilder[ChargeDisputeEvent] {
+	return newEventBuilder(EventChargeDisputeRemind, (*Event).AsChargeDispute)
+}
+
+// NewChargeDisputeResolve creates a builder for charge.dispute.resolve events
+func NewChargeDisputeResolve() *EventBuilder[ChargeDisputeEvent] {
+	return newEventBuilder(EventChargeDisputeResolve, (*Event).AsChargeDispute)
+}
+
+// NewCustomerIdentificationFailed creates a builder for customeridentification.failed events
+func NewCustomerIdentificationFailed() *EventBuilder[CustomerIdentificationFailedEvent] {
+	return newEventBuilder(EventCustomerIdentificationFailed, (*Event).AsCustomerIdentificationFailed)
+}
+
+// NewCustomerIdentificationSuccess creates a builder for customeridentification.success events
+func NewCustomerIdentificationSuccess() *EventBuilder[CustomerIdentificationSuccessEvent] {
+	return newEventBuilder(EventCustomerIdentificationSuccess, (*Event).AsCustomerIdentificationSuccess)
+}
+
+// NewDedicatedAccountAssignFailed creates a builder for dedicatedaccount.assign.failed events
+func NewDedicatedAccountAssignFailed() *EventBuilder[DedicatedAccountEvent] {
+	return newEventBuilder(EventDedicatedAccountAssignFailed, (*Event).AsDedicatedAccount)
+}
+
+// NewDedicatedAccountAssignSuccess creates a builder for dedicatedaccount.assign.success events
+func NewDedicatedAccountAssignSuccess() *EventBuilder[DedicatedAccountEvent] {
+	return newEventBuilder(EventDedicatedAccountAssignSuccess, (*Event).AsDedicatedAccount)
+}
+
+// NewInvoiceCreate creates a builder for invoice.create events
+func NewInvoiceCreate() *EventBuilder[InvoiceCreateEvent] {
+	return newEventBuilder(EventInvoiceCreate, (*Event).AsInvoiceCreate)
+}
+
+// NewInvoicePaymentFailed creates a builder for invoice.payment_failed events
+func NewInvoicePaymentFailed() *EventBuilder[InvoicePaymentFailedEvent] {
+	return newEventBuilder(EventInvoicePaymentFailed, (*Event).AsInvoicePaymentFailed)
+}
+
+// NewInvoiceUpdate creates a builder for invoice.update events
+func NewInvoiceUpdate() *EventBuilder[InvoiceUpdateEvent] {
+	return newEventBuilder(EventInvoiceUpdate, (*Event).AsInvoiceUpdate)
+}
+
+// NewPaymentRequestPending creates a builder for paymentrequest.pending events
+func NewPaymentRequestPending() *EventBuilder[PaymentRequestEvent] {
+	return newEventBuilder(EventPaymentRequestPending, (*Event).AsPaymentRequest)
+}
+
+// NewPaymentRequestSuccess creates a builder for paymentrequest.success events
+func NewPaymentRequestSuccess() *EventBuilder[PaymentRequestEvent] {
+	return newEventBuilder(EventPaymentRequestSuccess, (*Event).AsPaymentRequest)
+}
+
+// NewRefundFailed creates a builder for refund.failed events
+func NewRefundFailed() *EventBuilder[RefundFailedEvent] {
+	return newEventBuilder(EventRefundFailed, (*Event).AsRefundFailed)
+}
+
+// NewRefundPending creates a builder for refund.pending events
+func NewRefundPending() *EventBuilder[RefundPendingEvent] {
+	return newEventBuilder(EventRefundPending, (*Event).AsRefundPending)
+}
+
+// NewRefundProcessed creates a builder for refund.processed events
+func NewRefundProcessed() *EventBuilder[RefundProcessedEvent] {
+	return newEventBuilder(EventRefundProcessed, (*Event).AsRefundProcessed)
+}
+
+// NewRefundProcessing creates a builder for refund.processing events
+func NewRefundProcessing() *EventBuilder[RefundPendingEvent] {
+	return newEventBuilder(EventRefundProcessing, (*Event).AsRefundPending)
+}
+
+// NewSubscriptionCreate creates a builder for subscription.create events
+func NewSubscriptionCreate() *EventBuilder[SubscriptionCreateEvent] {
+	return newEventBuilder(EventSubscriptionCreate, (*Event).AsSubscriptionCreate)
+}
+
+// NewSubscriptionDisable creates a builder for subscription.disable events
+func NewSubscriptionDisable() *EventBuilder[SubscriptionDisableEvent] {
+	return newEventBuilder(EventSubscriptionDisable, (*Event).AsSubscriptionDisable)
+}
+
+// NewSubscriptionExpiringCards creates a builder for subscription.expiring_cards events
+func NewSubscriptionExpiringCards() *EventBuilder[SubscriptionExpiringCardsEvent] {
+	return newEventBuilder(EventSubscriptionExpiringCards, (*Event).AsSubscriptionExpiringCards)
+}
+
+// NewSubscriptionNotRenew creates a builder for subscription.not_renew events
+func NewSubscriptionNotRenew() *EventBuilder[SubscriptionNotRenewEvent] {
+	return newEventBuilder(EventSubscriptionNotRenew, (*Event).AsSubscriptionNotRenew)
+}
+
+// NewTransferFailed creates a builder for transfer.failed events
+func NewTransferFailed() *EventBuilder[TransferFailedEvent] {
+	return newEventBuilder(EventTransferFailed, (*Event).AsTransferFailed)
+}
+
+// NewTransferReversed creates a builder for transfer.reversed events
+func NewTransferReversed() *EventBuilder[TransferReversedEvent] {
+	return newEventBuilder(EventTransferReversed, (*Event).AsTransferReversed)
+}
+
+// NewTransferSuccess creates a builder for transfer.success events
+func NewTransferSuccess() *EventBuilder[TransferSuccessEvent] {
+	return newEventBuilder(EventTransferSuccess, (*Event).AsTransferSuccess)
+}
+
+// NewBankTransferRejected creates a builder for bank.transfer.rejected events
+func NewBankTransferRejected() *EventBuilder[BankTransferRejectedEvent] {
+	return newEventBuilder(EventBankTransferRejected, (*Event).AsBankTransferRejected)
+}
+
+// NewDirectDebitAuthorizationActive creates a builder for direct_debit.authorization.active events
+func NewDirectDebitAuthorizationActive() *EventBuilder[DirectDebitAuthorizationEvent] {
+	return newEventBuilder(EventDirectDebitAuthorizationActive, (*Event).AsDirectDebitAuthorization)
+}
+
+// NewDirectDebitAuthorizationCreated creates a builder for direct_debit.authorization.created events
+func NewDirectDebitAuthorizationCreated() *EventBuilder[DirectDebitAuthorizationEvent] {
+	return newEventBuilder(EventDirectDebitAuthorizationCreated, (*Event).AsDirectDebitAuthorization)
+}
+
+// NewTransferApprovalRequired creates a builder for transferrequest.approval-required events
+func NewTransferApprovalRequired() *EventBuilder[TransferApprovalEvent] {
+	return newEventBuilder(EventTransferApprovalRequired, (*Event).AsTransferApproval)
+}
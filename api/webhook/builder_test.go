@@ -0,0 +1,163 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/huysamen/paystack-go/types"
+	"github.com/huysamen/paystack-go/types/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var fixtureDir = filepath.Join("..", "..", "resources", "examples", "webhook")
+
+// roundTrip seeds b from the fixture for its event, builds the event, and checks that
+// decoding and encoding it again produces the same payload
+func roundTrip[T any](b *EventBuilder[T]) func(t *testing.T) {
+	return func(t *testing.T) {
+		event, err := b.SeedDir(fixtureDir).Build()
+		require.NoError(t, err)
+		assert.Equal(t, b.event, event.Event)
+
+		decoded, err := b.decode(event)
+		require.NoError(t, err)
+
+		again, err := b.Data(*decoded).Build()
+		require.NoError(t, err)
+		assert.JSONEq(t, string(event.Data), string(again.Data))
+	}
+}
+
+func TestEventBuilders_RoundTripFixtures(t *testing.T) {
+	tests := map[string]func(t *testing.T){
+		EventChargeSuccess:                   roundTrip(NewChargeSuccess()),
+		EventChargeDisputeCreate:             roundTrip(NewChargeDisputeCreate()),
+		EventChargeDisputeRemind:             roundTrip(NewChargeDisputeRemind()),
+		EventChargeDisputeResolve:            roundTrip(NewChargeDisputeResolve()),
+		EventCustomerIdentificationFailed:    roundTrip(NewCustomerIdentificationFailed()),
+		EventCustomerIdentificationSuccess:   roundTrip(NewCustomerIdentificationSuccess()),
+		EventDedicatedAccountAssignFailed:    roundTrip(NewDedicatedAccountAssignFailed()),
+		EventDedicatedAccountAssignSuccess:   roundTrip(NewDedicatedAccountAssignSuccess()),
+		EventInvoiceCreate:                   roundTrip(NewInvoiceCreate()),
+		EventInvoicePaymentFailed:            roundTrip(NewInvoicePaymentFailed()),
+		EventInvoiceUpdate:                   roundTrip(NewInvoiceUpdate()),
+		EventPaymentRequestPending:           roundTrip(NewPaymentRequestPending()),
+		EventPaymentRequestSuccess:           roundTrip(NewPaymentRequestSuccess()),
+		EventRefundFailed:                    roundTrip(NewRefundFailed()),
+		EventRefundPending:                   roundTrip(NewRefundPending()),
+		EventRefundProcessed:                 roundTrip(NewRefundProcessed()),
+		EventRefundProcessing:                roundTrip(NewRefundProcessing()),
+		EventSubscriptionCreate:              roundTrip(NewSubscriptionCreate()),
+		EventSubscriptionDisable:             roundTrip(NewSubscriptionDisable()),
+		EventSubscriptionExpiringCards:       roundTrip(NewSubscriptionExpiringCards()),
+		EventSubscriptionNotRenew:            roundTrip(NewSubscriptionNotRenew()),
+		EventTransferFailed:                  roundTrip(NewTransferFailed()),
+		EventTransferReversed:                roundTrip(NewTransferReversed()),
+		EventTransferSuccess:                 roundTrip(NewTransferSuccess()),
+		EventBankTransferRejected:            roundTrip(NewBankTransferRejected()),
+		EventDirectDebitAuthorizationActive:  roundTrip(NewDirectDebitAuthorizationActive()),
+		EventDirectDebitAuthorizationCreated: roundTrip(NewDirectDebitAuthorizationCreated()),
+		EventTransferApprovalRequired:        roundTrip(NewTransferApprovalRequired()),
+	}
+
+	for name, test := range tests {
+		t.Run(name, test)
+	}
+}
+
+func TestEventBuilder_With(t *testing.T) {
+	event, err := NewTransferFailed().
+		SeedDir(fixtureDir).
+		With(func(e *TransferFailedEvent) {
+			e.Reference = "payout_123"
+			e.Amount = 50000
+		}).
+		Build()
+	require.NoError(t, err)
+
+	decoded, err := event.AsTransferFailed()
+	require.NoError(t, err)
+	assert.Equal(t, "payout_123", decoded.Reference.String())
+	assert.Equal(t, int64(50000), decoded.Amount.Int64())
+	assert.Equal(t, "TRF_chs98y5rykjb47w", decoded.TransferCode.String(), "seeded fields are kept")
+}
+
+func TestEventBuilder_Data(t *testing.T) {
+	event, err := NewChargeSuccess().
+		Data(ChargeSuccessEvent{
+			Reference: "ref_1",
+			Amount:    10000,
+			Status:    "success",
+			Customer:  &types.Customer{Email: "customer@example.com"},
+			PaidAt:    data.NewNullTime(time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)),
+		}).
+		Build()
+	require.NoError(t, err)
+
+	// Unset fields, including enums such as Currency, are left out rather than sent as ""
+	assert.NotContains(t, string(event.Data), `""`)
+
+	decoded, err := event.AsChargeSuccess()
+	require.NoError(t, err)
+	assert.Equal(t, "ref_1", decoded.Reference.String())
+	assert.Equal(t, "customer@example.com", decoded.Customer.Email.String())
+	assert.Equal(t, time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC), decoded.PaidAt.Time)
+}
+
+func TestEventBuilder_SeedErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *EventBuilder[ChargeSuccessEvent]
+	}{
+		{
+			name:    "missing file",
+			builder: NewChargeSuccess().SeedFile("does-not-exist.json"),
+		},
+		{
+			name:    "invalid JSON",
+			builder: NewChargeSuccess().Seed([]byte(`{`)),
+		},
+		{
+			name:    "different event type",
+			builder: NewChargeSuccess().SeedFile(filepath.Join(fixtureDir, "transfer.success.json")),
+		},
+		{
+			name:    "data that does not decode",
+			builder: NewChargeSuccess().Seed([]byte(`{"event":"charge.success","data":{"amount":{}}}`)),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.builder.Build()
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestEventBuilder_Request(t *testing.T) {
+	var got *ChargeSuccessEvent
+	h := NewHandler(NewValidator(testSecret)).
+		OnChargeSuccess(func(ctx context.Context, e *ChargeSuccessEvent) error {
+			got = e
+			return nil
+		})
+
+	req, err := NewChargeSuccess().
+		SeedDir(fixtureDir).
+		With(func(e *ChargeSuccessEvent) { e.Reference = "order_42" }).
+		Request(context.Background(), NewSigner(testSecret), "http://localhost/webhook")
+	require.NoError(t, err)
+
+	assert.Equal(t, http.MethodPost, req.Method)
+	assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+	assert.NotEmpty(t, req.Header.Get(SignatureHeader))
+
+	assert.Equal(t, http.StatusOK, serve(h, req).Code)
+	require.NotNil(t, got)
+	assert.Equal(t, "order_42", got.Reference.String())
+}
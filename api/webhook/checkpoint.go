@@ -0,0 +1,141 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// CheckpointStore persists how far an EventPoller has recovered each source, so polling
+// resumes where it stopped after a restart
+type CheckpointStore interface {
+	// Load returns the checkpoint of source, or the zero time when there is none
+	Load(ctx context.Context, source string) (time.Time, error)
+	Save(ctx context.Context, source string, checkpoint time.Time) error
+}
+
+// MemoryCheckpointStore is an in-memory CheckpointStore. It is safe for concurrent use.
+type MemoryCheckpointStore struct {
+	mu          sync.Mutex
+	checkpoints map[string]time.Time
+}
+
+// NewMemoryCheckpointStore creates an empty MemoryCheckpointStore
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{
+		checkpoints: make(map[string]time.Time),
+	}
+}
+
+// Load implements CheckpointStore
+func (s *MemoryCheckpointStore) Load(_ context.Context, source string) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.checkpoints[source], nil
+}
+
+// Save implements CheckpointStore
+func (s *MemoryCheckpointStore) Save(_ context.Context, source string, checkpoint time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.checkpoints[source] = checkpoint
+
+	return nil
+}
+
+// FileCheckpointStore is a CheckpointStore that keeps all checkpoints in one JSON file,
+// replaced atomically on every save. It is safe for concurrent use within one process.
+type FileCheckpointStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileCheckpointStore creates a FileCheckpointStore that stores checkpoints in the file
+// at path, creating its directory if needed
+func NewFileCheckpointStore(path string) (*FileCheckpointStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+
+	return &FileCheckpointStore{path: path}, nil
+}
+
+// Load implements CheckpointStore
+func (s *FileCheckpointStore) Load(_ context.Context, source string) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	checkpoints, err := s.read()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return checkpoints[source], nil
+}
+
+// Save implements CheckpointStore
+func (s *FileCheckpointStore) Save(_ context.Context, source string, checkpoint time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	checkpoints, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	checkpoints[source] = checkpoint
+
+	b, err := json.Marshal(checkpoints)
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoints: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write checkpoints: %w", err)
+	}
+
+	_, err = tmp.Write(b)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), s.path)
+	}
+
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write checkpoints: %w", err)
+	}
+
+	return nil
+}
+
+func (s *FileCheckpointStore) read() (map[string]time.Time, error) {
+	checkpoints := make(map[string]time.Time)
+
+	b, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return checkpoints, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoints: %w", err)
+	}
+
+	if err := json.Unmarshal(b, &checkpoints); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoints: %w", err)
+	}
+
+	return checkpoints, nil
+}
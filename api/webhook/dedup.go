@@ -0,0 +1,292 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/huysamen/paystack-go/types/data"
+)
+
+// DefaultDedupTTL is how long processed events are remembered by default, comfortably
+// longer than the 72 hours over which Paystack retries a webhook
+const DefaultDedupTTL = 7 * 24 * time.Hour
+
+// DefaultDedupLease is how long an event is reserved while its handler runs by default. If
+// the process dies before the handler returns, the event can be processed again once the
+// lease expires.
+const DefaultDedupLease = 5 * time.Minute
+
+// ErrDuplicateEvent is returned by Deduplicator.Do when the event was already processed,
+// or is being processed by a concurrent delivery
+var ErrDuplicateEvent = errors.New("duplicate webhook event")
+
+// DedupStore records the keys of processed events. Implementations backed by shared
+// storage (e.g. Redis SET NX, or an SQL table with a unique key) make deduplication work
+// across processes.
+type DedupStore interface {
+	// Reserve atomically records key for ttl. It returns false if key is already recorded
+	// and has not expired.
+	Reserve(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	// Extend sets key, reserved by this process, to expire ttl from now
+	Extend(ctx context.Context, key string, ttl time.Duration) error
+	// Release removes key so the event can be processed again
+	Release(ctx context.Context, key string) error
+}
+
+// EventKey returns a stable identity for event made of the event type, the id and
+// reference of its data when present, and a hash of the data payload, e.g.
+// "charge.success:302961:qTPrJoy9Bx:9f86d0...". Redeliveries of the same event produce
+// the same key, while updates to the same entity (e.g. dispute reminders) do not.
+func EventKey(event *Event) string {
+	var ids struct {
+		ID        data.String `json:"id"`
+		Reference data.String `json:"reference"`
+	}
+	_ = json.Unmarshal(event.Data, &ids) // Payloads that are not objects only contribute their hash
+
+	var compact bytes.Buffer
+	payload := []byte(event.Data)
+	if err := json.Compact(&compact, event.Data); err == nil {
+		payload = compact.Bytes()
+	}
+
+	sum := sha256.Sum256(payload)
+
+	return strings.Join([]string{event.Event, ids.ID.String(), ids.Reference.String(), hex.EncodeToString(sum[:])}, ":")
+}
+
+// StateKey returns the identity of the state change reported by event, e.g.
+// "state:transfer.success:1jhbs3ozmen0k7y5efmw", for events an entity emits at most once:
+// charge.success, transfer.success, transfer.failed, transfer.reversed, refund.* and
+// charge.dispute.create and .resolve. It returns "" for other events and for payloads
+// without an identifier.
+//
+// Unlike EventKey it ignores the rest of the payload, so an event synthesized by an
+// EventPoller has the same StateKey as the webhook it stands in for. Charges and transfers
+// are identified by reference; refunds and disputes by id, or refund_reference for refunds
+// without one.
+func StateKey(event *Event) string {
+	var ids struct {
+		ID              data.String `json:"id"`
+		Reference       data.String `json:"reference"`
+		RefundReference data.String `json:"refund_reference"`
+	}
+	if err := json.Unmarshal(event.Data, &ids); err != nil {
+		return ""
+	}
+
+	var id string
+
+	switch event.Event {
+	case EventChargeSuccess, EventTransferSuccess, EventTransferFailed, EventTransferReversed:
+		id = ids.Reference.String()
+	case EventRefundPending, EventRefundProcessing, EventRefundProcessed, EventRefundFailed:
+		id = ids.ID.String()
+		if id == "" {
+			id = ids.RefundReference.String()
+		}
+	case EventChargeDisputeCreate, EventChargeDisputeResolve:
+		id = ids.ID.String()
+	}
+
+	if id == "" {
+		return ""
+	}
+
+	return "state:" + event.Event + ":" + id
+}
+
+// Deduplicator ensures each logical webhook event is processed once
+type Deduplicator struct {
+	store         DedupStore
+	ttl           time.Duration
+	lease         time.Duration
+	onExtendError func(event *Event, err error)
+}
+
+// NewDeduplicator creates a Deduplicator that remembers events in store for ttl. A ttl of
+// zero uses DefaultDedupTTL.
+func NewDeduplicator(store DedupStore, ttl time.Duration) *Deduplicator {
+	if ttl <= 0 {
+		ttl = DefaultDedupTTL
+	}
+
+	return &Deduplicator{
+		store: store,
+		ttl:   ttl,
+		lease: DefaultDedupLease,
+	}
+}
+
+// Lease sets how long an event is reserved while its handler runs. It should be longer
+// than the slowest handler, or a concurrent redelivery may be processed alongside it.
+func (d *Deduplicator) Lease(lease time.Duration) *Deduplicator {
+	if lease > 0 {
+		d.lease = lease
+	}
+
+	return d
+}
+
+// OnExtendError sets a function that is called when the keys of a processed event could not
+// be kept for the TTL. The event is still reported as processed; its keys expire with the
+// lease, after which a redelivery would be processed again.
+func (d *Deduplicator) OnExtendError(fn func(event *Event, err error)) *Deduplicator {
+	d.onExtendError = fn
+
+	return d
+}
+
+// Do calls fn unless the event was already processed, in which case it returns
+// ErrDuplicateEvent without calling fn. Events are identified by their EventKey and, when
+// they have one, their StateKey. If fn fails the event is released so a retry from
+// Paystack is processed again.
+//
+// Keys are reserved for the lease while fn runs and kept for the TTL once it succeeds, so
+// an event whose handler never returned is not skipped for the whole TTL.
+func (d *Deduplicator) Do(ctx context.Context, event *Event, fn EventHandlerFunc) error {
+	key := EventKey(event)
+	lease := min(d.lease, d.ttl)
+
+	reserved, err := d.store.Reserve(ctx, key, lease)
+	if err != nil {
+		return err
+	}
+
+	if !reserved {
+		return ErrDuplicateEvent
+	}
+
+	keys := []string{key}
+
+	if state := StateKey(event); state != "" {
+		reserved, err := d.store.Reserve(ctx, state, lease)
+		if err != nil {
+			return d.release(ctx, keys, err)
+		}
+
+		// The state change arrived in another payload, e.g. from an EventPoller. The
+		// EventKey is released, so if that payload's handler fails and releases the state,
+		// a redelivery of this payload is processed rather than skipped.
+		if !reserved {
+			return d.release(ctx, keys, ErrDuplicateEvent)
+		}
+
+		keys = append(keys, state)
+	}
+
+	if err := fn(ctx, event); err != nil {
+		return d.release(ctx, keys, err)
+	}
+
+	// The event was processed, so failing here would have it retried and handled twice.
+	// Failures are reported to OnExtendError instead, and the keys expire with the lease.
+	var errs []error
+	for _, key := range keys {
+		if err := d.store.Extend(ctx, key, d.ttl); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 && d.onExtendError != nil {
+		d.onExtendError(event, errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// release releases keys after err and returns err, joined with any release failures
+func (d *Deduplicator) release(ctx context.Context, keys []string, err error) error {
+	errs := []error{err}
+	for _, key := range keys {
+		if releaseErr := d.store.Release(ctx, key); releaseErr != nil {
+			errs = append(errs, releaseErr)
+		}
+	}
+
+	if len(errs) == 1 {
+		return err
+	}
+
+	return errors.Join(errs...)
+}
+
+// memoryDedupSweepInterval is the number of reservations between sweeps of expired keys
+const memoryDedupSweepInterval = 1024
+
+// MemoryDedupStore is an in-memory DedupStore for single process deployments and tests.
+// It is safe for concurrent use.
+type MemoryDedupStore struct {
+	mu      sync.Mutex
+	entries map[string]time.Time
+	count   int
+	now     func() time.Time
+}
+
+// NewMemoryDedupStore creates an empty MemoryDedupStore
+func NewMemoryDedupStore() *MemoryDedupStore {
+	return &MemoryDedupStore{
+		entries: make(map[string]time.Time),
+		now:     time.Now,
+	}
+}
+
+// Reserve implements DedupStore
+func (s *MemoryDedupStore) Reserve(_ context.Context, key string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+
+	s.count++
+	if s.count%memoryDedupSweepInterval == 0 {
+		for k, expires := range s.entries {
+			if !now.Before(expires) {
+				delete(s.entries, k)
+			}
+		}
+	}
+
+	if expires, ok := s.entries[key]; ok && now.Before(expires) {
+		return false, nil
+	}
+
+	s.entries[key] = now.Add(ttl)
+
+	return true, nil
+}
+
+// Extend implements DedupStore
+func (s *MemoryDedupStore) Extend(_ context.Context, key string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[key] = s.now().Add(ttl)
+
+	return nil
+}
+
+// Release implements DedupStore
+func (s *MemoryDedupStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+
+	return nil
+}
+
+// Len returns the number of keys held, including expired keys not yet swept
+func (s *MemoryDedupStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.entries)
+}
@@ -0,0 +1,387 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventKey(t *testing.T) {
+	charge := readEventFixture(t, "charge.success")
+	key := EventKey(charge)
+
+	assert.True(t, strings.HasPrefix(key, "charge.success:302961:qTPrJoy9Bx:"), key)
+
+	tests := []struct {
+		name  string
+		event *Event
+		same  bool
+	}{
+		{
+			name:  "same event",
+			event: readEventFixture(t, "charge.success"),
+			same:  true,
+		},
+		{
+			name:  "same payload with different formatting",
+			event: &Event{Event: EventChargeSuccess, Data: indent(t, charge.Data)},
+			same:  true,
+		},
+		{
+			name:  "different event type with same payload",
+			event: &Event{Event: "charge.other", Data: charge.Data},
+		},
+		{
+			name:  "same entity with changed payload",
+			event: &Event{Event: EventChargeSuccess, Data: []byte(`{"id":302961,"reference":"qTPrJoy9Bx","status":"reversed"}`)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.same {
+				assert.Equal(t, key, EventKey(tt.event))
+			} else {
+				assert.NotEqual(t, key, EventKey(tt.event))
+			}
+		})
+	}
+}
+
+func TestEventKey_NonObjectPayload(t *testing.T) {
+	e := readEventFixture(t, "subscription.expiring_cards")
+
+	key := EventKey(e)
+	assert.True(t, strings.HasPrefix(key, "subscription.expiring_cards:::"), key)
+	assert.Equal(t, key, EventKey(readEventFixture(t, "subscription.expiring_cards")))
+}
+
+func TestStateKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		event    *Event
+		expected string
+	}{
+		{
+			name:     "charge by reference",
+			event:    readEventFixture(t, "charge.success"),
+			expected: "state:charge.success:qTPrJoy9Bx",
+		},
+		{
+			name:     "changed charge payload",
+			event:    &Event{Event: EventChargeSuccess, Data: []byte(`{"id":1,"reference":"qTPrJoy9Bx","status":"success"}`)},
+			expected: "state:charge.success:qTPrJoy9Bx",
+		},
+		{
+			name:     "transfer by reference",
+			event:    readEventFixture(t, "transfer.success"),
+			expected: "state:transfer.success:1jhbs3ozmen0k7y5efmw",
+		},
+		{
+			name:     "refund by id",
+			event:    &Event{Event: EventRefundProcessed, Data: []byte(`{"id":42,"refund_reference":"ref"}`)},
+			expected: "state:refund.processed:42",
+		},
+		{
+			name:     "refund by refund reference",
+			event:    readEventFixture(t, "refund.processed"),
+			expected: "state:refund.processed:132013318360",
+		},
+		{
+			name:     "dispute by id",
+			event:    &Event{Event: EventChargeDisputeResolve, Data: []byte(`{"id":7}`)},
+			expected: "state:charge.dispute.resolve:7",
+		},
+		{
+			name:  "uncovered event",
+			event: readEventFixture(t, "subscription.create"),
+		},
+		{
+			name:  "missing reference",
+			event: &Event{Event: EventChargeSuccess, Data: []byte(`{"id":1}`)},
+		},
+		{
+			name:  "non-object payload",
+			event: &Event{Event: EventChargeSuccess, Data: []byte(`[]`)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, StateKey(tt.event))
+		})
+	}
+}
+
+func indent(t *testing.T, b []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, json.Indent(&buf, b, "", "    "))
+	return buf.Bytes()
+}
+
+func TestMemoryDedupStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	s := NewMemoryDedupStore()
+	s.now = func() time.Time { return now }
+
+	ok, err := s.Reserve(ctx, "a", time.Hour)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = s.Reserve(ctx, "a", time.Hour)
+	require.NoError(t, err)
+	assert.False(t, ok, "reserved keys are rejected")
+
+	now = now.Add(time.Hour)
+	ok, err = s.Reserve(ctx, "a", time.Hour)
+	require.NoError(t, err)
+	assert.True(t, ok, "expired keys can be reserved again")
+
+	require.NoError(t, s.Release(ctx, "a"))
+	ok, err = s.Reserve(ctx, "a", time.Hour)
+	require.NoError(t, err)
+	assert.True(t, ok, "released keys can be reserved again")
+}
+
+func TestMemoryDedupStore_SweepsExpiredKeys(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	s := NewMemoryDedupStore()
+	s.now = func() time.Time { return now }
+
+	_, _ = s.Reserve(ctx, "old", time.Minute)
+	now = now.Add(time.Hour)
+
+	for i := 0; i < memoryDedupSweepInterval; i++ {
+		_, _ = s.Reserve(ctx, "new", time.Minute)
+	}
+
+	assert.Equal(t, 1, s.Len())
+}
+
+func TestDeduplicator_Do(t *testing.T) {
+	ctx := context.Background()
+	event := readEventFixture(t, "charge.success")
+	d := NewDeduplicator(NewMemoryDedupStore(), 0)
+
+	calls := 0
+	fn := func(context.Context, *Event) error {
+		calls++
+		return nil
+	}
+
+	require.NoError(t, d.Do(ctx, event, fn))
+	assert.ErrorIs(t, d.Do(ctx, event, fn), ErrDuplicateEvent)
+	assert.Equal(t, 1, calls)
+}
+
+func TestDeduplicator_ReleasesFailedEvents(t *testing.T) {
+	ctx := context.Background()
+	event := readEventFixture(t, "transfer.success")
+	d := NewDeduplicator(NewMemoryDedupStore(), time.Hour)
+
+	failure := errors.New("database down")
+	err := d.Do(ctx, event, func(context.Context, *Event) error { return failure })
+	assert.ErrorIs(t, err, failure)
+
+	calls := 0
+	require.NoError(t, d.Do(ctx, event, func(context.Context, *Event) error {
+		calls++
+		return nil
+	}))
+	assert.Equal(t, 1, calls, "a failed event is processed on retry")
+}
+
+func TestDeduplicator_StateHeldByAnotherPayload(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryDedupStore()
+	d := NewDeduplicator(store, time.Hour)
+
+	webhook := readEventFixture(t, "transfer.success")
+	polled := &Event{Event: EventTransferSuccess, Data: []byte(`{"reference":"1jhbs3ozmen0k7y5efmw","status":"success"}`)}
+	require.Equal(t, StateKey(webhook), StateKey(polled))
+
+	failure := errors.New("database down")
+	err := d.Do(ctx, polled, func(context.Context, *Event) error {
+		// The webhook arrives while the polled event is being handled
+		assert.ErrorIs(t, d.Do(ctx, webhook, func(context.Context, *Event) error { return nil }), ErrDuplicateEvent)
+		_, held := store.entries[EventKey(webhook)]
+		assert.False(t, held, "the webhook's own key is released")
+		return failure
+	})
+	require.ErrorIs(t, err, failure)
+
+	// The polled event failed, so Paystack's retry of the webhook is processed
+	calls := 0
+	require.NoError(t, d.Do(ctx, webhook, func(context.Context, *Event) error {
+		calls++
+		return nil
+	}))
+	assert.Equal(t, 1, calls)
+}
+
+type failingDedupStore struct{ err error }
+
+func (s failingDedupStore) Reserve(context.Context, string, time.Duration) (bool, error) {
+	return false, s.err
+}
+
+func (s failingDedupStore) Extend(context.Context, string, time.Duration) error { return s.err }
+
+func (s failingDedupStore) Release(context.Context, string) error { return s.err }
+
+func TestDeduplicator_Lease(t *testing.T) {
+	ctx := context.Background()
+	event := readEventFixture(t, "charge.success")
+
+	now := time.Now()
+	store := NewMemoryDedupStore()
+	store.now = func() time.Time { return now }
+	d := NewDeduplicator(store, time.Hour).Lease(time.Minute)
+
+	// A handler that never finished, e.g. because the process died, holds the event for the
+	// lease only
+	require.NoError(t, d.Do(ctx, event, func(context.Context, *Event) error {
+		assert.ErrorIs(t, d.Do(ctx, event, func(context.Context, *Event) error { return nil }), ErrDuplicateEvent)
+		assert.Equal(t, now.Add(time.Minute), store.entries[EventKey(event)])
+		assert.Equal(t, now.Add(time.Minute), store.entries[StateKey(event)])
+		return nil
+	}))
+
+	// Once the handler succeeds, the event is held for the TTL
+	assert.Equal(t, now.Add(time.Hour), store.entries[EventKey(event)])
+	assert.Equal(t, now.Add(time.Hour), store.entries[StateKey(event)])
+
+	now = now.Add(2 * time.Minute)
+	assert.ErrorIs(t, d.Do(ctx, event, func(context.Context, *Event) error { return nil }), ErrDuplicateEvent)
+}
+
+func TestDeduplicator_StoreErrors(t *testing.T) {
+	storeErr := errors.New("redis unavailable")
+	d := NewDeduplicator(failingDedupStore{err: storeErr}, 0)
+
+	called := false
+	err := d.Do(context.Background(), readEventFixture(t, "charge.success"), func(context.Context, *Event) error {
+		called = true
+		return nil
+	})
+
+	assert.ErrorIs(t, err, storeErr)
+	assert.False(t, called)
+}
+
+// extendFailingDedupStore is a MemoryDedupStore whose Extend fails
+type extendFailingDedupStore struct {
+	*MemoryDedupStore
+	err error
+}
+
+func (s extendFailingDedupStore) Extend(context.Context, string, time.Duration) error { return s.err }
+
+func TestDeduplicator_ExtendErrors(t *testing.T) {
+	storeErr := errors.New("redis unavailable")
+	event := readEventFixture(t, "charge.success")
+
+	var reported []error
+	d := NewDeduplicator(extendFailingDedupStore{MemoryDedupStore: NewMemoryDedupStore(), err: storeErr}, 0).
+		OnExtendError(func(e *Event, err error) {
+			assert.Equal(t, event, e)
+			reported = append(reported, err)
+		})
+
+	calls := 0
+	require.NoError(t, d.Do(context.Background(), event, func(context.Context, *Event) error {
+		calls++
+		return nil
+	}))
+	assert.Equal(t, 1, calls)
+
+	require.Len(t, reported, 1)
+	assert.ErrorIs(t, reported[0], storeErr)
+
+	// The keys are still held for the lease, so a redelivery is a duplicate
+	assert.ErrorIs(t, d.Do(context.Background(), event, func(context.Context, *Event) error {
+		calls++
+		return nil
+	}), ErrDuplicateEvent)
+	assert.Equal(t, 1, calls)
+}
+
+func TestHandler_Deduplicate_ExtendErrors(t *testing.T) {
+	storeErr := errors.New("redis unavailable")
+
+	var handled atomic.Int32
+	h := NewHandler(NewValidator(testSecret)).
+		Deduplicate(NewDeduplicator(extendFailingDedupStore{MemoryDedupStore: NewMemoryDedupStore(), err: storeErr}, 0)).
+		OnChargeSuccess(func(context.Context, *ChargeSuccessEvent) error {
+			handled.Add(1)
+			return nil
+		})
+
+	body := readFixtureBytes(t, "charge.success")
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, http.StatusOK, serve(h, newSignedRequest(t, body)).Code)
+	}
+	assert.Equal(t, int32(1), handled.Load())
+}
+
+func TestHandler_Deduplicate(t *testing.T) {
+	var handled, duplicates atomic.Int32
+
+	h := NewHandler(NewValidator(testSecret)).
+		Deduplicate(NewDeduplicator(NewMemoryDedupStore(), 0)).
+		OnDuplicate(func(r *http.Request, e *Event) { duplicates.Add(1) }).
+		OnChargeSuccess(func(context.Context, *ChargeSuccessEvent) error {
+			handled.Add(1)
+			return nil
+		})
+
+	body := readFixtureBytes(t, "charge.success")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, http.StatusOK, serve(h, newSignedRequest(t, body)).Code)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), handled.Load())
+	assert.Equal(t, int32(9), duplicates.Load())
+}
+
+func TestHandler_Deduplicate_RetriesFailedEvents(t *testing.T) {
+	fail := true
+
+	h := NewHandler(NewValidator(testSecret)).
+		Deduplicate(NewDeduplicator(NewMemoryDedupStore(), 0)).
+		OnChargeSuccess(func(context.Context, *ChargeSuccessEvent) error {
+			if fail {
+				return errors.New("database down")
+			}
+			return nil
+		})
+
+	body := readFixtureBytes(t, "charge.success")
+
+	assert.Equal(t, http.StatusInternalServerError, serve(h, newSignedRequest(t, body)).Code)
+
+	fail = false
+	assert.Equal(t, http.StatusOK, serve(h, newSignedRequest(t, body)).Code)
+}
@@ -1,6 +1,9 @@
 package webhook
 
 import (
+	"encoding/json"
+
+	"github.com/huysamen/paystack-go/enums"
 	"github.com/huysamen/paystack-go/types"
 	"github.com/huysamen/paystack-go/types/data"
 )
@@ -8,43 +11,43 @@ import (
 type DisputeHistoryEntry struct {
 	Status    data.String `json:"status"`
 	By        data.String `json:"by"`
-	CreatedAt data.Time   `json:"created_at"`
+	CreatedAt data.Time   `json:"createdAt"`
 }
 
 type DisputeMessage struct {
 	Sender    data.String `json:"sender"`
 	Body      data.String `json:"body"`
-	CreatedAt data.Time   `json:"created_at"`
+	CreatedAt data.Time   `json:"createdAt"`
 }
 
 type ChargeSuccessEvent struct {
-	ID                 data.Int             `json:"id"`
-	Domain             data.String          `json:"domain"`
-	Status             data.String          `json:"status"`
-	Reference          data.String          `json:"reference"`
-	ReceiptNumber      data.NullString      `json:"receipt_number"`
-	Amount             data.Int             `json:"amount"`
-	Message            data.NullString      `json:"message"`
-	GatewayResponse    data.String          `json:"gateway_response"`
-	PaidAt             data.NullTime        `json:"paid_at"`
-	CreatedAt          data.Time            `json:"created_at"`
-	Channel            data.String          `json:"channel"`
-	Currency           data.String          `json:"currency"`
-	IPAddress          data.String          `json:"ip_address"`
-	Metadata           types.Metadata       `json:"metadata"`
-	Log                *any                 `json:"log"`
-	Fees               data.Int             `json:"fees"`
-	FeesSplit          *any                 `json:"fees_split"`
-	Authorization      *types.Authorization `json:"authorization"`
-	Customer           *types.Customer      `json:"customer"`
-	Plan               *any                 `json:"plan"`
-	Split              *any                 `json:"split"`
-	OrderID            *any                 `json:"order_id"`
-	PaidAt2            data.NullTime        `json:"paidAt"`
-	RequestedAmount    data.Int             `json:"requested_amount"`
-	PosTransactionData *any                 `json:"pos_transaction_data"`
-	Source             *any                 `json:"source"`
-	FeesBreakdown      *any                 `json:"fees_breakdown"`
+	ID                 data.Int                  `json:"id"`
+	Domain             data.String               `json:"domain"`
+	Status             enums.TransactionStatus   `json:"status"`
+	Reference          data.String               `json:"reference"`
+	ReceiptNumber      data.NullString           `json:"receipt_number"`
+	Amount             data.Int                  `json:"amount"`
+	Message            data.NullString           `json:"message"`
+	GatewayResponse    data.String               `json:"gateway_response"`
+	PaidAt             data.NullTime             `json:"paid_at"`
+	CreatedAt          data.Time                 `json:"created_at"`
+	Channel            data.String               `json:"channel"`
+	Currency           data.String               `json:"currency"`
+	IPAddress          data.String               `json:"ip_address"`
+	Metadata           types.Metadata            `json:"metadata"`
+	Log                *types.TransactionLog     `json:"log"`
+	Fees               data.Int                  `json:"fees"`
+	FeesSplit          *types.FeesSplit          `json:"fees_split"`
+	Authorization      *types.Authorization      `json:"authorization"`
+	Customer           *types.Customer           `json:"customer"`
+	Plan               *types.Plan               `json:"plan"`
+	Split              *types.TransactionSplit   `json:"split"`
+	OrderID            data.NullString           `json:"order_id"`
+	PaidAt2            data.NullTime             `json:"paidAt"`
+	RequestedAmount    data.Int                  `json:"requested_amount"`
+	PosTransactionData *types.POSTransactionData `json:"pos_transaction_data"`
+	Source             *types.TransactionSource  `json:"source"`
+	FeesBreakdown      []types.FeeBreakdown      `json:"fees_breakdown"`
 }
 
 type ChargeDisputeEvent struct {
@@ -62,8 +65,8 @@ type ChargeDisputeEvent struct {
 	Last4                data.String           `json:"last4"`
 	DueAt                data.NullTime         `json:"dueAt"`
 	ResolvedAt           data.NullTime         `json:"resolvedAt"`
-	Evidence             *any                  `json:"evidence"`
-	Attachments          *any                  `json:"attachments"`
+	Evidence             *types.Evidence       `json:"evidence"`
+	Attachments          data.NullString       `json:"attachments"`
 	Note                 data.NullString       `json:"note"`
 	History              []DisputeHistoryEntry `json:"history"`
 	Messages             []DisputeMessage      `json:"messages"`
@@ -88,56 +91,58 @@ type DedicatedAccountEvent struct {
 }
 
 type InvoicePaymentFailedEvent struct {
-	ID          data.Int        `json:"id"`
-	Domain      data.String     `json:"domain"`
-	InvoiceCode data.String     `json:"invoice_code"`
-	Amount      data.Int        `json:"amount"`
-	PeriodStart data.Time       `json:"period_start"`
-	PeriodEnd   data.Time       `json:"period_end"`
-	Status      data.String     `json:"status"`
-	Paid        data.Bool       `json:"paid"`
-	Currency    data.String     `json:"currency"`
-	Customer    *types.Customer `json:"customer"`
-	Transaction *any            `json:"transaction"`
-	CreatedAt   data.Time       `json:"created_at"`
-	UpdatedAt   data.Time       `json:"updated_at"`
+	ID           data.Int            `json:"id"`
+	Domain       data.String         `json:"domain"`
+	InvoiceCode  data.String         `json:"invoice_code"`
+	Amount       data.Int            `json:"amount"`
+	PeriodStart  data.Time           `json:"period_start"`
+	PeriodEnd    data.Time           `json:"period_end"`
+	Status       data.String         `json:"status"`
+	Paid         data.Bool           `json:"paid"`
+	Currency     data.String         `json:"currency"`
+	Customer     *types.Customer     `json:"customer"`
+	Transaction  *types.Transaction  `json:"transaction"`
+	Subscription *types.Subscription `json:"subscription"`
+	CreatedAt    data.Time           `json:"created_at"`
+	UpdatedAt    data.Time           `json:"updated_at"`
 }
 
 type InvoiceUpdateEvent struct {
-	ID          data.Int        `json:"id"`
-	Domain      data.String     `json:"domain"`
-	InvoiceCode data.String     `json:"invoice_code"`
-	Amount      data.Int        `json:"amount"`
-	PeriodStart data.Time       `json:"period_start"`
-	PeriodEnd   data.Time       `json:"period_end"`
-	Status      data.String     `json:"status"`
-	Paid        data.Bool       `json:"paid"`
-	Currency    data.String     `json:"currency"`
-	Customer    *types.Customer `json:"customer"`
-	Transaction *any            `json:"transaction"`
-	CreatedAt   data.Time       `json:"created_at"`
-	UpdatedAt   data.Time       `json:"updated_at"`
+	ID           data.Int            `json:"id"`
+	Domain       data.String         `json:"domain"`
+	InvoiceCode  data.String         `json:"invoice_code"`
+	Amount       data.Int            `json:"amount"`
+	PeriodStart  data.Time           `json:"period_start"`
+	PeriodEnd    data.Time           `json:"period_end"`
+	Status       data.String         `json:"status"`
+	Paid         data.Bool           `json:"paid"`
+	Currency     data.String         `json:"currency"`
+	Customer     *types.Customer     `json:"customer"`
+	Transaction  *types.Transaction  `json:"transaction"`
+	Subscription *types.Subscription `json:"subscription"`
+	CreatedAt    data.Time           `json:"created_at"`
+	UpdatedAt    data.Time           `json:"updated_at"`
 }
 
 type PaymentRequestEvent struct {
-	ID               data.Int        `json:"id"`
-	Domain           data.String     `json:"domain"`
-	Amount           data.Int        `json:"amount"`
-	Currency         data.String     `json:"currency"`
-	DueDate          data.NullTime   `json:"due_date"`
-	HasInvoice       data.Bool       `json:"has_invoice"`
-	InvoiceNumber    data.NullString `json:"invoice_number"`
-	Description      data.String     `json:"description"`
-	PDF_URL          data.NullString `json:"pdf_url"`
-	LineItems        []any           `json:"line_items"`
-	Tax              []any           `json:"tax"`
-	RequestCode      data.String     `json:"request_code"`
-	Status           data.String     `json:"status"`
-	Paid             data.Bool       `json:"paid"`
-	PaidAt           data.NullTime   `json:"paid_at"`
-	Metadata         types.Metadata  `json:"metadata"`
-	Notifications    []any           `json:"notifications"`
-	OfflineReference data.NullString `json:"offline_reference"`
+	ID               data.Int             `json:"id"`
+	Domain           data.String          `json:"domain"`
+	Amount           data.Int             `json:"amount"`
+	Currency         data.String          `json:"currency"`
+	DueDate          data.NullTime        `json:"due_date"`
+	HasInvoice       data.Bool            `json:"has_invoice"`
+	InvoiceNumber    data.NullInt         `json:"invoice_number"`
+	Description      data.String          `json:"description"`
+	PDF_URL          data.NullString      `json:"pdf_url"`
+	LineItems        []types.LineItem     `json:"line_items"`
+	Tax              []types.Tax          `json:"tax"`
+	RequestCode      data.String          `json:"request_code"`
+	Status           data.String          `json:"status"`
+	Paid             data.Bool            `json:"paid"`
+	PaidAt           data.NullTime        `json:"paid_at"`
+	Metadata         types.Metadata       `json:"metadata"`
+	Notifications    []types.Notification `json:"notifications"`
+	OfflineReference data.NullString      `json:"offline_reference"`
 	// In webhooks this can be ID or object; accept loosely
 	Customer  types.Metadata `json:"customer"`
 	CreatedAt data.Time      `json:"created_at"`
@@ -148,17 +153,20 @@ type RefundFailedEvent struct {
 	Integration    data.Int           `json:"integration"`
 	Domain         data.String        `json:"domain"`
 	Transaction    *types.Transaction `json:"transaction"`
-	Dispute        *any               `json:"dispute"`
+	Dispute        *types.Dispute     `json:"dispute"`
 	Amount         data.Int           `json:"amount"`
 	DeductedAmount data.Int           `json:"deducted_amount"`
 	FullyDeducted  data.Bool          `json:"fully_deducted"`
 	Currency       data.String        `json:"currency"`
 	Status         data.String        `json:"status"`
-	RefundedBy     data.String        `json:"refunded_by"`
-	RefundedAt     data.NullTime      `json:"refunded_at"`
-	ExpectedAt     data.Time          `json:"expected_at"`
-	CreatedAt      data.Time          `json:"created_at"`
-	UpdatedAt      data.Time          `json:"updated_at"`
+	// RefundReference identifies the refund for refunds.Client.Fetch
+	RefundReference      data.NullString `json:"refund_reference"`
+	TransactionReference data.NullString `json:"transaction_reference"`
+	RefundedBy           data.String     `json:"refunded_by"`
+	RefundedAt           data.NullTime   `json:"refunded_at"`
+	ExpectedAt           data.Time       `json:"expected_at"`
+	CreatedAt            data.Time       `json:"created_at"`
+	UpdatedAt            data.Time       `json:"updated_at"`
 }
 
 type RefundPendingEvent struct {
@@ -166,58 +174,121 @@ type RefundPendingEvent struct {
 	Integration    data.Int           `json:"integration"`
 	Domain         data.String        `json:"domain"`
 	Transaction    *types.Transaction `json:"transaction"`
-	Dispute        *any               `json:"dispute"`
+	Dispute        *types.Dispute     `json:"dispute"`
 	Amount         data.Int           `json:"amount"`
 	DeductedAmount data.Int           `json:"deducted_amount"`
 	FullyDeducted  data.Bool          `json:"fully_deducted"`
 	Currency       data.String        `json:"currency"`
 	Status         data.String        `json:"status"`
-	RefundedBy     data.String        `json:"refunded_by"`
-	RefundedAt     data.NullTime      `json:"refunded_at"`
-	ExpectedAt     data.Time          `json:"expected_at"`
-	CreatedAt      data.Time          `json:"created_at"`
-	UpdatedAt      data.Time          `json:"updated_at"`
+	// RefundReference identifies the refund for refunds.Client.Fetch
+	RefundReference      data.NullString `json:"refund_reference"`
+	TransactionReference data.NullString `json:"transaction_reference"`
+	RefundedBy           data.String     `json:"refunded_by"`
+	RefundedAt           data.NullTime   `json:"refunded_at"`
+	ExpectedAt           data.Time       `json:"expected_at"`
+	CreatedAt            data.Time       `json:"created_at"`
+	UpdatedAt            data.Time       `json:"updated_at"`
 }
 
 type SubscriptionDisableEvent struct {
-	ID               data.Int             `json:"id"`
-	Domain           data.String          `json:"domain"`
-	Status           data.String          `json:"status"`
-	SubscriptionCode data.String          `json:"subscription_code"`
-	EmailToken       data.String          `json:"email_token"`
-	Amount           data.Int             `json:"amount"`
-	CronExpression   data.String          `json:"cron_expression"`
-	NextPaymentDate  data.NullTime        `json:"next_payment_date"`
-	OpenInvoice      data.NullString      `json:"open_invoice"`
-	Customer         *types.Customer      `json:"customer"`
-	Plan             *any                 `json:"plan"`
-	Authorization    *types.Authorization `json:"authorization"`
-	Invoices         []any                `json:"invoices"`
-	CreatedAt        data.Time            `json:"created_at"`
-	UpdatedAt        data.Time            `json:"updated_at"`
+	ID               data.Int                 `json:"id"`
+	Domain           data.String              `json:"domain"`
+	Status           enums.SubscriptionStatus `json:"status"`
+	SubscriptionCode data.String              `json:"subscription_code"`
+	EmailToken       data.String              `json:"email_token"`
+	Amount           data.Int                 `json:"amount"`
+	CronExpression   data.String              `json:"cron_expression"`
+	NextPaymentDate  data.NullTime            `json:"next_payment_date"`
+	OpenInvoice      data.NullString          `json:"open_invoice"`
+	Customer         *types.Customer          `json:"customer"`
+	Plan             *types.Plan              `json:"plan"`
+	Authorization    *types.Authorization     `json:"authorization"`
+	Invoices         []SubscriptionInvoice    `json:"invoices"`
+	CreatedAt        data.Time                `json:"created_at"`
+	UpdatedAt        data.Time                `json:"updated_at"`
 }
 
 type SubscriptionNotRenewEvent struct {
-	ID               data.Int             `json:"id"`
-	Domain           data.String          `json:"domain"`
-	Status           data.String          `json:"status"`
-	SubscriptionCode data.String          `json:"subscription_code"`
-	EmailToken       data.String          `json:"email_token"`
-	Amount           data.Int             `json:"amount"`
-	CronExpression   data.String          `json:"cron_expression"`
-	NextPaymentDate  data.NullTime        `json:"next_payment_date"`
-	OpenInvoice      data.NullString      `json:"open_invoice"`
-	Customer         *types.Customer      `json:"customer"`
-	Plan             *any                 `json:"plan"`
-	Authorization    *types.Authorization `json:"authorization"`
-	Invoices         []any                `json:"invoices"`
-	CreatedAt        data.Time            `json:"created_at"`
-	UpdatedAt        data.Time            `json:"updated_at"`
+	ID               data.Int                 `json:"id"`
+	Domain           data.String              `json:"domain"`
+	Status           enums.SubscriptionStatus `json:"status"`
+	SubscriptionCode data.String              `json:"subscription_code"`
+	EmailToken       data.String              `json:"email_token"`
+	Amount           data.Int                 `json:"amount"`
+	CronExpression   data.String              `json:"cron_expression"`
+	NextPaymentDate  data.NullTime            `json:"next_payment_date"`
+	OpenInvoice      data.NullString          `json:"open_invoice"`
+	Customer         *types.Customer          `json:"customer"`
+	Plan             *types.Plan              `json:"plan"`
+	Authorization    *types.Authorization     `json:"authorization"`
+	Invoices         []SubscriptionInvoice    `json:"invoices"`
+	CreatedAt        data.Time                `json:"created_at"`
+	UpdatedAt        data.Time                `json:"updated_at"`
 }
 
+// SubscriptionExpiringCardsEvent lists the subscriptions whose cards expire this month.
+// Paystack sends the entries as a bare array rather than an object.
 type SubscriptionExpiringCardsEvent struct {
-	// Expiring cards payload is an array of entries; we accept Metadata for flexibility
-	Entries types.Metadata `json:"-"`
+	Entries []ExpiringCard
+}
+
+func (e *SubscriptionExpiringCardsEvent) UnmarshalJSON(b []byte) error {
+	return json.Unmarshal(b, &e.Entries)
+}
+
+func (e SubscriptionExpiringCardsEvent) MarshalJSON() ([]byte, error) {
+	if e.Entries == nil {
+		return []byte("[]"), nil
+	}
+
+	return json.Marshal(e.Entries)
+}
+
+// ExpiringCard is a subscription charged to a card that is about to expire
+type ExpiringCard struct {
+	ExpiryDate   data.String         `json:"expiry_date"`
+	Description  data.String         `json:"description"`
+	Brand        data.String         `json:"brand"`
+	Subscription *types.Subscription `json:"subscription"`
+	Customer     *types.Customer     `json:"customer"`
+}
+
+// TransferSession identifies the provider session a transfer was processed in
+type TransferSession struct {
+	ID       data.NullString `json:"id"`
+	Provider data.NullString `json:"provider"`
+}
+
+// TransferSourceDetails describes the account a transfer was funded from. Paystack sends
+// null for transfers from the balance.
+type TransferSourceDetails struct {
+	Type          data.String `json:"type"`
+	AccountName   data.String `json:"account_name"`
+	AccountNumber data.String `json:"account_number"`
+	BankCode      data.String `json:"bank_code"`
+}
+
+// TransferFailures explains why a transfer failed. Paystack sends null for transfers that
+// have not failed.
+type TransferFailures struct {
+	Reason          data.String `json:"reason"`
+	GatewayResponse data.String `json:"gateway_response"`
+}
+
+// SubscriptionInvoice is an invoice raised for one period of a subscription
+type SubscriptionInvoice struct {
+	ID            data.Int             `json:"id"`
+	Domain        data.String          `json:"domain"`
+	InvoiceCode   data.String          `json:"invoice_code"`
+	Amount        data.Int             `json:"amount"`
+	PeriodStart   data.Time            `json:"period_start"`
+	PeriodEnd     data.Time            `json:"period_end"`
+	Status        data.String          `json:"status"`
+	Paid          data.Bool            `json:"paid"`
+	PaidAt        data.NullTime        `json:"paid_at"`
+	Description   data.NullString      `json:"description"`
+	Authorization *types.Authorization `json:"authorization"`
+	CreatedAt     data.Time            `json:"created_at"`
 }
 
 type CustomerIdentificationFailedEvent struct {
@@ -250,99 +321,99 @@ type CustomerIdentification struct {
 }
 
 type TransferSuccessEvent struct {
-	Amount        data.Int        `json:"amount"`
-	Currency      data.String     `json:"currency"`
-	Domain        data.String     `json:"domain"`
-	Failures      *any            `json:"failures"`
-	ID            data.Int        `json:"id"`
-	Integration   types.Metadata  `json:"integration"`
-	Reason        data.String     `json:"reason"`
-	Reference     data.String     `json:"reference"`
-	Source        data.String     `json:"source"`
-	SourceDetails *any            `json:"source_details"`
-	Status        data.String     `json:"status"`
-	TitanCode     data.NullString `json:"titan_code"`
-	TransferCode  data.String     `json:"transfer_code"`
-	TransferredAt data.NullTime   `json:"transferred_at"`
-	Recipient     types.Recipient `json:"recipient"`
-	Session       *any            `json:"session"`
-	CreatedAt     data.Time       `json:"created_at"`
-	UpdatedAt     data.Time       `json:"updated_at"`
+	Amount        data.Int               `json:"amount"`
+	Currency      data.String            `json:"currency"`
+	Domain        data.String            `json:"domain"`
+	Failures      *TransferFailures      `json:"failures"`
+	ID            data.Int               `json:"id"`
+	Integration   types.Metadata         `json:"integration"`
+	Reason        data.String            `json:"reason"`
+	Reference     data.String            `json:"reference"`
+	Source        data.String            `json:"source"`
+	SourceDetails *TransferSourceDetails `json:"source_details"`
+	Status        enums.TransferStatus   `json:"status"`
+	TitanCode     data.NullString        `json:"titan_code"`
+	TransferCode  data.String            `json:"transfer_code"`
+	TransferredAt data.NullTime          `json:"transferred_at"`
+	Recipient     types.Recipient        `json:"recipient"`
+	Session       *TransferSession       `json:"session"`
+	CreatedAt     data.Time              `json:"created_at"`
+	UpdatedAt     data.Time              `json:"updated_at"`
 }
 
 type TransferFailedEvent struct {
-	Amount        data.Int        `json:"amount"`
-	Currency      data.String     `json:"currency"`
-	Domain        data.String     `json:"domain"`
-	Failures      *any            `json:"failures"`
-	ID            data.Int        `json:"id"`
-	Integration   types.Metadata  `json:"integration"`
-	Reason        data.String     `json:"reason"`
-	Reference     data.String     `json:"reference"`
-	Source        data.String     `json:"source"`
-	SourceDetails *any            `json:"source_details"`
-	Status        data.String     `json:"status"`
-	TitanCode     data.NullString `json:"titan_code"`
-	TransferCode  data.String     `json:"transfer_code"`
-	TransferredAt data.NullTime   `json:"transferred_at"`
-	Recipient     types.Recipient `json:"recipient"`
-	Session       *any            `json:"session"`
-	CreatedAt     data.Time       `json:"created_at"`
-	UpdatedAt     data.Time       `json:"updated_at"`
+	Amount        data.Int               `json:"amount"`
+	Currency      data.String            `json:"currency"`
+	Domain        data.String            `json:"domain"`
+	Failures      *TransferFailures      `json:"failures"`
+	ID            data.Int               `json:"id"`
+	Integration   types.Metadata         `json:"integration"`
+	Reason        data.String            `json:"reason"`
+	Reference     data.String            `json:"reference"`
+	Source        data.String            `json:"source"`
+	SourceDetails *TransferSourceDetails `json:"source_details"`
+	Status        enums.TransferStatus   `json:"status"`
+	TitanCode     data.NullString        `json:"titan_code"`
+	TransferCode  data.String            `json:"transfer_code"`
+	TransferredAt data.NullTime          `json:"transferred_at"`
+	Recipient     types.Recipient        `json:"recipient"`
+	Session       *TransferSession       `json:"session"`
+	CreatedAt     data.Time              `json:"created_at"`
+	UpdatedAt     data.Time              `json:"updated_at"`
 }
 
 type TransferReversedEvent struct {
-	Amount        data.Int        `json:"amount"`
-	Currency      data.String     `json:"currency"`
-	Domain        data.String     `json:"domain"`
-	Failures      *any            `json:"failures"`
-	ID            data.Int        `json:"id"`
-	Integration   types.Metadata  `json:"integration"`
-	Reason        data.String     `json:"reason"`
-	Reference     data.String     `json:"reference"`
-	Source        data.String     `json:"source"`
-	SourceDetails *any            `json:"source_details"`
-	Status        data.String     `json:"status"`
-	TitanCode     data.NullString `json:"titan_code"`
-	TransferCode  data.String     `json:"transfer_code"`
-	TransferredAt data.NullTime   `json:"transferred_at"`
-	Recipient     types.Recipient `json:"recipient"`
-	Session       *any            `json:"session"`
-	CreatedAt     data.Time       `json:"created_at"`
-	UpdatedAt     data.Time       `json:"updated_at"`
+	Amount        data.Int               `json:"amount"`
+	Currency      data.String            `json:"currency"`
+	Domain        data.String            `json:"domain"`
+	Failures      *TransferFailures      `json:"failures"`
+	ID            data.Int               `json:"id"`
+	Integration   types.Metadata         `json:"integration"`
+	Reason        data.String            `json:"reason"`
+	Reference     data.String            `json:"reference"`
+	Source        data.String            `json:"source"`
+	SourceDetails *TransferSourceDetails `json:"source_details"`
+	Status        enums.TransferStatus   `json:"status"`
+	TitanCode     data.NullString        `json:"titan_code"`
+	TransferCode  data.String            `json:"transfer_code"`
+	TransferredAt data.NullTime          `json:"transferred_at"`
+	Recipient     types.Recipient        `json:"recipient"`
+	Session       *TransferSession       `json:"session"`
+	CreatedAt     data.Time              `json:"created_at"`
+	UpdatedAt     data.Time              `json:"updated_at"`
 }
 
 type SubscriptionCreateEvent struct {
-	Domain           data.String          `json:"domain"`
-	Status           data.String          `json:"status"`
-	SubscriptionCode data.String          `json:"subscription_code"`
-	Amount           data.Int             `json:"amount"`
-	CronExpression   data.String          `json:"cron_expression"`
-	NextPaymentDate  data.Time            `json:"next_payment_date"`
-	OpenInvoice      data.NullString      `json:"open_invoice"`
-	Integration      data.Int             `json:"integration"`
-	Plan             *any                 `json:"plan"`
-	Authorization    *types.Authorization `json:"authorization"`
-	Customer         *types.Customer      `json:"customer"`
-	ID               data.Int             `json:"id"`
-	CreatedAt        data.Time            `json:"created_at"`
-	UpdatedAt        data.Time            `json:"updated_at"`
+	Domain           data.String              `json:"domain"`
+	Status           enums.SubscriptionStatus `json:"status"`
+	SubscriptionCode data.String              `json:"subscription_code"`
+	Amount           data.Int                 `json:"amount"`
+	CronExpression   data.String              `json:"cron_expression"`
+	NextPaymentDate  data.Time                `json:"next_payment_date"`
+	OpenInvoice      data.NullString          `json:"open_invoice"`
+	Integration      data.Int                 `json:"integration"`
+	Plan             *types.Plan              `json:"plan"`
+	Authorization    *types.Authorization     `json:"authorization"`
+	Customer         *types.Customer          `json:"customer"`
+	ID               data.Int                 `json:"id"`
+	CreatedAt        data.Time                `json:"created_at"`
+	UpdatedAt        data.Time                `json:"updated_at"`
 }
 
 type InvoiceCreateEvent struct {
-	Domain       data.String     `json:"domain"`
-	InvoiceCode  data.String     `json:"invoice_code"`
-	Amount       data.Int        `json:"amount"`
-	PeriodStart  data.Time       `json:"period_start"`
-	PeriodEnd    data.Time       `json:"period_end"`
-	Status       data.String     `json:"status"`
-	Paid         data.Bool       `json:"paid"`
-	Currency     data.String     `json:"currency"`
-	Customer     *types.Customer `json:"customer"`
-	Subscription *any            `json:"subscription"`
-	ID           data.Int        `json:"id"`
-	CreatedAt    data.Time       `json:"created_at"`
-	UpdatedAt    data.Time       `json:"updated_at"`
+	Domain       data.String         `json:"domain"`
+	InvoiceCode  data.String         `json:"invoice_code"`
+	Amount       data.Int            `json:"amount"`
+	PeriodStart  data.Time           `json:"period_start"`
+	PeriodEnd    data.Time           `json:"period_end"`
+	Status       data.String         `json:"status"`
+	Paid         data.Bool           `json:"paid"`
+	Currency     data.String         `json:"currency"`
+	Customer     *types.Customer     `json:"customer"`
+	Subscription *types.Subscription `json:"subscription"`
+	ID           data.Int            `json:"id"`
+	CreatedAt    data.Time           `json:"created_at"`
+	UpdatedAt    data.Time           `json:"updated_at"`
 }
 
 type RefundProcessedEvent struct {
@@ -350,15 +421,80 @@ type RefundProcessedEvent struct {
 	Integration    data.Int           `json:"integration"`
 	Domain         data.String        `json:"domain"`
 	Transaction    *types.Transaction `json:"transaction"`
-	Dispute        *any               `json:"dispute"`
+	Dispute        *types.Dispute     `json:"dispute"`
 	Amount         data.Int           `json:"amount"`
 	DeductedAmount data.Int           `json:"deducted_amount"`
 	FullyDeducted  data.Bool          `json:"fully_deducted"`
 	Currency       data.String        `json:"currency"`
 	Status         data.String        `json:"status"`
-	RefundedBy     data.String        `json:"refunded_by"`
-	RefundedAt     data.NullTime      `json:"refunded_at"`
-	ExpectedAt     data.Time          `json:"expected_at"`
-	CreatedAt      data.Time          `json:"created_at"`
-	UpdatedAt      data.Time          `json:"updated_at"`
+	// RefundReference identifies the refund for refunds.Client.Fetch
+	RefundReference      data.NullString `json:"refund_reference"`
+	TransactionReference data.NullString `json:"transaction_reference"`
+	RefundedBy           data.String     `json:"refunded_by"`
+	RefundedAt           data.NullTime   `json:"refunded_at"`
+	ExpectedAt           data.Time       `json:"expected_at"`
+	CreatedAt            data.Time       `json:"created_at"`
+	UpdatedAt            data.Time       `json:"updated_at"`
+}
+
+// DirectDebitAuthorizationEvent is sent when a direct debit mandate is created and again
+// when the customer's bank activates it
+type DirectDebitAuthorizationEvent struct {
+	AuthorizationCode data.String     `json:"authorization_code"`
+	Active            data.Bool       `json:"active"`
+	Reusable          data.Bool       `json:"reusable"`
+	Signature         data.NullString `json:"signature"`
+	AccountName       data.NullString `json:"account_name"`
+	Bank              data.String     `json:"bank"`
+	Brand             data.String     `json:"brand"`
+	BIN               data.NullString `json:"bin"`
+	Last4             data.String     `json:"last4"`
+	ExpMonth          data.String     `json:"exp_month"`
+	ExpYear           data.String     `json:"exp_year"`
+	CardType          data.String     `json:"card_type"`
+	Channel           data.String     `json:"channel"`
+	CountryCode       data.String     `json:"country_code"`
+	Reference         data.String     `json:"reference"`
+	Domain            data.String     `json:"domain"`
+	Integration       data.Int        `json:"integration"`
+	Customer          *types.Customer `json:"customer"`
+}
+
+// TransferApprovalEvent is sent to the transfer approval URL when a transfer is waiting to
+// be approved, before its OTP is finalized
+type TransferApprovalEvent struct {
+	ID            data.Int               `json:"id"`
+	Integration   data.Int               `json:"integration"`
+	Domain        data.String            `json:"domain"`
+	Amount        data.Int               `json:"amount"`
+	Currency      data.String            `json:"currency"`
+	Reason        data.String            `json:"reason"`
+	Reference     data.String            `json:"reference"`
+	Source        data.String            `json:"source"`
+	SourceDetails *TransferSourceDetails `json:"source_details"`
+	Status        data.String            `json:"status"`
+	Failures      *TransferFailures      `json:"failures"`
+	TitanCode     data.NullString        `json:"titan_code"`
+	TransferCode  data.String            `json:"transfer_code"`
+	TransferredAt data.NullTime          `json:"transferred_at"`
+	Recipient     data.Int               `json:"recipient"`
+	Request       data.Int               `json:"request"`
+	CreatedAt     data.Time              `json:"createdAt"`
+	UpdatedAt     data.Time              `json:"updatedAt"`
+}
+
+// BankTransferRejectedEvent is sent when a pay with transfer payment is rejected, e.g.
+// because the amount received does not match the amount expected
+type BankTransferRejectedEvent struct {
+	ID              data.Int             `json:"id"`
+	Domain          data.String          `json:"domain"`
+	Status          data.String          `json:"status"`
+	Reference       data.String          `json:"reference"`
+	Amount          data.Int             `json:"amount"`
+	Currency        data.String          `json:"currency"`
+	Channel         data.String          `json:"channel"`
+	GatewayResponse data.String          `json:"gateway_response"`
+	Authorization   *types.Authorization `json:"authorization"`
+	Customer        *types.Customer      `json:"customer"`
+	CreatedAt       data.Time            `json:"created_at"`
 }
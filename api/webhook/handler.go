@@ -0,0 +1,375 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+)
+
+// DefaultMaxBodyBytes is the default limit on the size of a webhook request body
+const DefaultMaxBodyBytes int64 = 1 << 20 // 1 MiB
+
+// EventHandlerFunc handles a webhook event after its signature has been validated
+type EventHandlerFunc func(ctx context.Context, event *Event) error
+
+// ErrorHandlerFunc is called for every request the Handler rejects or fails to process.
+// event is nil when the request failed before the event could be parsed.
+type ErrorHandlerFunc func(r *http.Request, event *Event, status int, err error)
+
+// DuplicateHandlerFunc is called for every event the Handler skips as a duplicate
+type DuplicateHandlerFunc func(r *http.Request, event *Event)
+
+// ErrPanic is wrapped by the error passed to the error handler when an event handler panics
+var ErrPanic = errors.New("webhook handler panicked")
+
+// Handler is an http.Handler that validates Paystack webhooks and dispatches them to typed
+// event handlers. It responds with:
+//
+//   - 200 when the event was handled, no handler is registered for it, or it is a
+//     duplicate skipped by the Deduplicator
+//   - 400 when the body is not a valid event or cannot be decoded into its event type
+//   - 401 when the signature is invalid
+//   - 403 when the source address is not in the Validator's IP allowlist
+//   - 405 when the request is not a POST
+//   - 413 when the body exceeds the size limit
+//   - 422 when the event does not match the API, see Reconcile
+//   - 500 when a handler returns an error or panics, so Paystack retries the event
+type Handler struct {
+	validator    *Validator
+	handlers     map[string]EventHandlerFunc
+	fallback     EventHandlerFunc
+	onError      ErrorHandlerFunc
+	dedup        *Deduplicator
+	onDuplicate  DuplicateHandlerFunc
+	reconciler   *Reconciler
+	maxBodyBytes int64
+}
+
+// NewHandler creates a Handler that validates requests with validator
+func NewHandler(validator *Validator) *Handler {
+	return &Handler{
+		validator:    validator,
+		handlers:     make(map[string]EventHandlerFunc),
+		maxBodyBytes: DefaultMaxBodyBytes,
+	}
+}
+
+// MaxBodyBytes sets the maximum accepted request body size
+func (h *Handler) MaxBodyBytes(n int64) *Handler {
+	h.maxBodyBytes = n
+
+	return h
+}
+
+// OnError sets a function that is called for every rejected or failed request, e.g. for logging
+func (h *Handler) OnError(fn ErrorHandlerFunc) *Handler {
+	h.onError = fn
+
+	return h
+}
+
+// Deduplicate makes the Handler process each logical event once. Redeliveries of an event
+// that was processed, or is being processed, are acknowledged without calling handlers.
+func (h *Handler) Deduplicate(d *Deduplicator) *Handler {
+	h.dedup = d
+
+	return h
+}
+
+// OnDuplicate sets a function that is called for every event skipped as a duplicate
+func (h *Handler) OnDuplicate(fn DuplicateHandlerFunc) *Handler {
+	h.onDuplicate = fn
+
+	return h
+}
+
+// Reconcile makes the Handler check money-moving events against the API with r before
+// calling their handler. Events without a handler are not reconciled. Mismatches are
+// rejected with an error wrapping ErrReconciliationMismatch; failures to reach the API are
+// treated like handler errors, so Paystack retries the event.
+func (h *Handler) Reconcile(r *Reconciler) *Handler {
+	h.reconciler = r
+
+	return h
+}
+
+// On registers fn for the given event type, replacing any existing handler
+func (h *Handler) On(event string, fn EventHandlerFunc) *Handler {
+	h.handlers[event] = fn
+
+	return h
+}
+
+// Fallback registers fn for events without a registered handler. Without a fallback such
+// events are acknowledged with a 200 response.
+func (h *Handler) Fallback(fn EventHandlerFunc) *Handler {
+	h.fallback = fn
+
+	return h
+}
+
+// ServeHTTP implements http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		h.fail(w, r, nil, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+
+	if h.maxBodyBytes > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, h.maxBodyBytes)
+	}
+
+	event, err := h.validator.ValidateRequest(r)
+	if err != nil {
+		h.fail(w, r, nil, statusForError(err), err)
+		return
+	}
+
+	ctx := WithEvent(r.Context(), event)
+
+	if h.dedup != nil {
+		err = h.dedup.Do(ctx, event, h.dispatch)
+	} else {
+		err = h.dispatch(ctx, event)
+	}
+
+	if errors.Is(err, ErrDuplicateEvent) {
+		if h.onDuplicate != nil {
+			h.onDuplicate(r, event)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if err != nil {
+		h.fail(w, r, event, statusForError(err), err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) dispatch(ctx context.Context, event *Event) (err error) {
+	fn, ok := h.handlers[event.Event]
+	if !ok {
+		fn = h.fallback
+	}
+
+	if fn == nil {
+		return nil
+	}
+
+	if h.reconciler != nil {
+		if err := h.reconciler.Reconcile(ctx, event); err != nil {
+			return err
+		}
+	}
+
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("%w: %v\n%s", ErrPanic, rec, debug.Stack())
+		}
+	}()
+
+	return fn(ctx, event)
+}
+
+func (h *Handler) fail(w http.ResponseWriter, r *http.Request, event *Event, status int, err error) {
+	if h.onError != nil {
+		h.onError(r, event, status, err)
+	}
+
+	http.Error(w, http.StatusText(status), status)
+}
+
+func statusForError(err error) int {
+	var maxBytesErr *http.MaxBytesError
+
+	switch {
+	case errors.As(err, &maxBytesErr):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, ErrInvalidSignature):
+		return http.StatusUnauthorized
+	case errors.Is(err, ErrSourceNotAllowed):
+		return http.StatusForbidden
+	case errors.Is(err, ErrInvalidPayload):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrReconciliationMismatch):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// on registers a handler that decodes the event with decode before calling fn. Decoding
+// failures are reported as ErrInvalidPayload.
+func on[T any](h *Handler, event string, decode func(*Event) (*T, error), fn func(context.Context, *T) error) *Handler {
+	return h.On(event, func(ctx context.Context, e *Event) error {
+		data, err := decode(e)
+		if err != nil {
+			return fmt.Errorf("%w: decoding %s: %w", ErrInvalidPayload, e.Event, err)
+		}
+
+		return fn(ctx, data)
+	})
+}
+
+type eventKey struct{}
+
+// WithEvent returns a context carrying the raw webhook event
+func WithEvent(ctx context.Context, event *Event) context.Context {
+	return context.WithValue(ctx, eventKey{}, event)
+}
+
+// EventFromContext returns the raw webhook event being handled, or nil if there is none.
+// Typed handlers can use it to access the event type or the undecoded payload.
+func EventFromContext(ctx context.Context) *Event {
+	event, _ := ctx.Value(eventKey{}).(*Event)
+
+	return event
+}
+
+// OnChargeSuccess registers fn for charge.success events
+func (h *Handler) OnChargeSuccess(fn func(context.Context, *ChargeSuccessEvent) error) *Handler {
+	return on(h, EventChargeSuccess, (*Event).AsChargeSuccess, fn)
+}
+
+// OnChargeDisputeCreate registers fn for charge.dispute.create events
+func (h *Handler) OnChargeDisputeCreate(fn func(context.Context, *ChargeDisputeEvent) error) *Handler {
+	return on(h, EventChargeDisputeCreate, (*Event).AsChargeDispute, fn)
+}
+
+// OnChargeDisputeRemind registers fn for charge.dispute.remind events
+func (h *Handler) OnChargeDisputeRemind(fn func(context.Context, *ChargeDisputeEvent) error) *Handler {
+	return on(h, EventChargeDisputeRemind, (*Event).AsChargeDispute, fn)
+}
+
+// OnChargeDisputeResolve registers fn for charge.dispute.resolve events
+func (h *Handler) OnChargeDisputeResolve(fn func(context.Context, *ChargeDisputeEvent) error) *Handler {
+	return on(h, EventChargeDisputeResolve, (*Event).AsChargeDispute, fn)
+}
+
+// OnCustomerIdentificationFailed registers fn for customeridentification.failed events
+func (h *Handler) OnCustomerIdentificationFailed(fn func(context.Context, *CustomerIdentificationFailedEvent) error) *Handler {
+	return on(h, EventCustomerIdentificationFailed, (*Event).AsCustomerIdentificationFailed, fn)
+}
+
+// OnCustomerIdentificationSuccess registers fn for customeridentification.success events
+func (h *Handler) OnCustomerIdentificationSuccess(fn func(context.Context, *CustomerIdentificationSuccessEvent) error) *Handler {
+	return on(h, EventCustomerIdentificationSuccess, (*Event).AsCustomerIdentificationSuccess, fn)
+}
+
+// OnDedicatedAccountAssignFailed registers fn for dedicatedaccount.assign.failed events
+func (h *Handler) OnDedicatedAccountAssignFailed(fn func(context.Context, *DedicatedAccountEvent) error) *Handler {
+	return on(h, EventDedicatedAccountAssignFailed, (*Event).AsDedicatedAccount, fn)
+}
+
+// OnDedicatedAccountAssignSuccess registers fn for dedicatedaccount.assign.success events
+func (h *Handler) OnDedicatedAccountAssignSuccess(fn func(context.Context, *DedicatedAccountEvent) error) *Handler {
+	return on(h, EventDedicatedAccountAssignSuccess, (*Event).AsDedicatedAccount, fn)
+}
+
+// OnInvoiceCreate registers fn for invoice.create events
+func (h *Handler) OnInvoiceCreate(fn func(context.Context, *InvoiceCreateEvent) error) *Handler {
+	return on(h, EventInvoiceCreate, (*Event).AsInvoiceCreate, fn)
+}
+
+// OnInvoicePaymentFailed registers fn for invoice.payment_failed events
+func (h *Handler) OnInvoicePaymentFailed(fn func(context.Context, *InvoicePaymentFailedEvent) error) *Handler {
+	return on(h, EventInvoicePaymentFailed, (*Event).AsInvoicePaymentFailed, fn)
+}
+
+// OnInvoiceUpdate registers fn for invoice.update events
+func (h *Handler) OnInvoiceUpdate(fn func(context.Context, *InvoiceUpdateEvent) error) *Handler {
+	return on(h, EventInvoiceUpdate, (*Event).AsInvoiceUpdate, fn)
+}
+
+// OnPaymentRequestPending registers fn for paymentrequest.pending events
+func (h *Handler) OnPaymentRequestPending(fn func(context.Context, *PaymentRequestEvent) error) *Handler {
+	return on(h, EventPaymentRequestPending, (*Event).AsPaymentRequest, fn)
+}
+
+// OnPaymentRequestSuccess registers fn for paymentrequest.success events
+func (h *Handler) OnPaymentRequestSuccess(fn func(context.Context, *PaymentRequestEvent) error) *Handler {
+	return on(h, EventPaymentRequestSuccess, (*Event).AsPaymentRequest, fn)
+}
+
+// OnRefundFailed registers fn for refund.failed events
+func (h *Handler) OnRefundFailed(fn func(context.Context, *RefundFailedEvent) error) *Handler {
+	return on(h, EventRefundFailed, (*Event).AsRefundFailed, fn)
+}
+
+// OnRefundPending registers fn for refund.pending events
+func (h *Handler) OnRefundPending(fn func(context.Context, *RefundPendingEvent) error) *Handler {
+	return on(h, EventRefundPending, (*Event).AsRefundPending, fn)
+}
+
+// OnRefundProcessed registers fn for refund.processed events
+func (h *Handler) OnRefundProcessed(fn func(context.Context, *RefundProcessedEvent) error) *Handler {
+	return on(h, EventRefundProcessed, (*Event).AsRefundProcessed, fn)
+}
+
+// OnRefundProcessing registers fn for refund.processing events, which share the
+// refund.pending payload
+func (h *Handler) OnRefundProcessing(fn func(context.Context, *RefundPendingEvent) error) *Handler {
+	return on(h, EventRefundProcessing, (*Event).AsRefundPending, fn)
+}
+
+// OnSubscriptionCreate registers fn for subscription.create events
+func (h *Handler) OnSubscriptionCreate(fn func(context.Context, *SubscriptionCreateEvent) error) *Handler {
+	return on(h, EventSubscriptionCreate, (*Event).AsSubscriptionCreate, fn)
+}
+
+// OnSubscriptionDisable registers fn for subscription.disable events
+func (h *Handler) OnSubscriptionDisable(fn func(context.Context, *SubscriptionDisableEvent) error) *Handler {
+	return on(h, EventSubscriptionDisable, (*Event).AsSubscriptionDisable, fn)
+}
+
+// OnSubscriptionExpiringCards registers fn for subscription.expiring_cards events
+func (h *Handler) OnSubscriptionExpiringCards(fn func(context.Context, *SubscriptionExpiringCardsEvent) error) *Handler {
+	return on(h, EventSubscriptionExpiringCards, (*Event).AsSubscriptionExpiringCards, fn)
+}
+
+// OnSubscriptionNotRenew registers fn for subscription.not_renew events
+func (h *Handler) OnSubscriptionNotRenew(fn func(context.Context, *SubscriptionNotRenewEvent) error) *Handler {
+	return on(h, EventSubscriptionNotRenew, (*Event).AsSubscriptionNotRenew, fn)
+}
+
+// OnTransferFailed registers fn for transfer.failed events
+func (h *Handler) OnTransferFailed(fn func(context.Context, *TransferFailedEvent) error) *Handler {
+	return on(h, EventTransferFailed, (*Event).AsTransferFailed, fn)
+}
+
+// OnTransferReversed registers fn for transfer.reversed events
+func (h *Handler) OnTransferReversed(fn func(context.Context, *TransferReversedEvent) error) *Handler {
+	return on(h, EventTransferReversed, (*Event).AsTransferReversed, fn)
+}
+
+// OnTransferSuccess registers fn for transfer.success events
+func (h *Handler) OnTransferSuccess(fn func(context.Context, *TransferSuccessEvent) error) *Handler {
+	return on(h, EventTransferSuccess, (*Event).AsTransferSuccess, fn)
+}
+
+// OnBankTransferRejected registers fn for bank.transfer.rejected events
+func (h *Handler) OnBankTransferRejected(fn func(context.Context, *BankTransferRejectedEvent) error) *Handler {
+	return on(h, EventBankTransferRejected, (*Event).AsBankTransferRejected, fn)
+}
+
+// OnDirectDebitAuthorizationActive registers fn for direct_debit.authorization.active events
+func (h *Handler) OnDirectDebitAuthorizationActive(fn func(context.Context, *DirectDebitAuthorizationEvent) error) *Handler {
+	return on(h, EventDirectDebitAuthorizationActive, (*Event).AsDirectDebitAuthorization, fn)
+}
+
+// OnDirectDebitAuthorizationCreated registers fn for direct_debit.authorization.created events
+func (h *Handler) OnDirectDebitAuthorizationCreated(fn func(context.Context, *DirectDebitAuthorizationEvent) error) *Handler {
+	return on(h, EventDirectDebitAuthorizationCreated, (*Event).AsDirectDebitAuthorization, fn)
+}
+
+// OnTransferApprovalRequired registers fn for transferrequest.approval-required events
+func (h *Handler) OnTransferApprovalRequired(fn func(context.Context, *TransferApprovalEvent) error) *Handler {
+	return on(h, EventTransferApprovalRequired, (*Event).AsTransferApproval, fn)
+}
@@ -0,0 +1,254 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "sk_test_secret"
+
+func readFixtureBytes(t *testing.T, name string) []byte {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join(fixtureDir, name+".json"))
+	require.NoError(t, err)
+	return b
+}
+
+func newSignedRequest(t *testing.T, body []byte) *http.Request {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader(body))
+	req.Header.Set(SignatureHeader, NewSigner(testSecret).Sign(body))
+	return req
+}
+
+func serve(h http.Handler, req *http.Request) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandler_DispatchesTypedEvents(t *testing.T) {
+	var got *ChargeSuccessEvent
+
+	h := NewHandler(NewValidator(testSecret)).
+		OnChargeSuccess(func(ctx context.Context, e *ChargeSuccessEvent) error {
+			got = e
+			return nil
+		})
+
+	rec := serve(h, newSignedRequest(t, readFixtureBytes(t, "charge.success")))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	require.NotNil(t, got)
+	assert.Equal(t, "qTPrJoy9Bx", got.Reference.String())
+}
+
+func TestHandler_AllRegistrationsDecodeFixtures(t *testing.T) {
+	called := make(map[string]bool)
+	mark := func(ctx context.Context) error {
+		called[EventFromContext(ctx).Event] = true
+		return nil
+	}
+
+	h := NewHandler(NewValidator(testSecret)).
+		OnChargeSuccess(func(ctx context.Context, _ *ChargeSuccessEvent) error { return mark(ctx) }).
+		OnChargeDisputeCreate(func(ctx context.Context, _ *ChargeDisputeEvent) error { return mark(ctx) }).
+		OnChargeDisputeRemind(func(ctx context.Context, _ *ChargeDisputeEvent) error { return mark(ctx) }).
+		OnChargeDisputeResolve(func(ctx context.Context, _ *ChargeDisputeEvent) error { return mark(ctx) }).
+		OnCustomerIdentificationFailed(func(ctx context.Context, _ *CustomerIdentificationFailedEvent) error { return mark(ctx) }).
+		OnCustomerIdentificationSuccess(func(ctx context.Context, _ *CustomerIdentificationSuccessEvent) error { return mark(ctx) }).
+		OnDedicatedAccountAssignFailed(func(ctx context.Context, _ *DedicatedAccountEvent) error { return mark(ctx) }).
+		OnDedicatedAccountAssignSuccess(func(ctx context.Context, _ *DedicatedAccountEvent) error { return mark(ctx) }).
+		OnInvoiceCreate(func(ctx context.Context, _ *InvoiceCreateEvent) error { return mark(ctx) }).
+		OnInvoicePaymentFailed(func(ctx context.Context, _ *InvoicePaymentFailedEvent) error { return mark(ctx) }).
+		OnInvoiceUpdate(func(ctx context.Context, _ *InvoiceUpdateEvent) error { return mark(ctx) }).
+		OnPaymentRequestPending(func(ctx context.Context, _ *PaymentRequestEvent) error { return mark(ctx) }).
+		OnPaymentRequestSuccess(func(ctx context.Context, _ *PaymentRequestEvent) error { return mark(ctx) }).
+		OnRefundFailed(func(ctx context.Context, _ *RefundFailedEvent) error { return mark(ctx) }).
+		OnRefundPending(func(ctx context.Context, _ *RefundPendingEvent) error { return mark(ctx) }).
+		OnRefundProcessed(func(ctx context.Context, _ *RefundProcessedEvent) error { return mark(ctx) }).
+		OnRefundProcessing(func(ctx context.Context, _ *RefundPendingEvent) error { return mark(ctx) }).
+		OnSubscriptionCreate(func(ctx context.Context, _ *SubscriptionCreateEvent) error { return mark(ctx) }).
+		OnSubscriptionDisable(func(ctx context.Context, _ *SubscriptionDisableEvent) error { return mark(ctx) }).
+		OnSubscriptionExpiringCards(func(ctx context.Context, _ *SubscriptionExpiringCardsEvent) error { return mark(ctx) }).
+		OnSubscriptionNotRenew(func(ctx context.Context, _ *SubscriptionNotRenewEvent) error { return mark(ctx) }).
+		OnTransferFailed(func(ctx context.Context, _ *TransferFailedEvent) error { return mark(ctx) }).
+		OnTransferReversed(func(ctx context.Context, _ *TransferReversedEvent) error { return mark(ctx) }).
+		OnTransferSuccess(func(ctx context.Context, _ *TransferSuccessEvent) error { return mark(ctx) }).
+		OnBankTransferRejected(func(ctx context.Context, _ *BankTransferRejectedEvent) error { return mark(ctx) }).
+		OnDirectDebitAuthorizationActive(func(ctx context.Context, _ *DirectDebitAuthorizationEvent) error { return mark(ctx) }).
+		OnDirectDebitAuthorizationCreated(func(ctx context.Context, _ *DirectDebitAuthorizationEvent) error { return mark(ctx) }).
+		OnTransferApprovalRequired(func(ctx context.Context, _ *TransferApprovalEvent) error { return mark(ctx) })
+
+	entries, err := os.ReadDir(fixtureDir)
+	require.NoError(t, err)
+
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".json")
+
+		t.Run(name, func(t *testing.T) {
+			payload := readFixtureBytes(t, name)
+
+			var event Event
+			require.NoError(t, json.Unmarshal(payload, &event))
+
+			rec := serve(h, newSignedRequest(t, payload))
+			assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+			assert.True(t, called[event.Event], "handler for %s not called", event.Event)
+		})
+	}
+}
+
+func TestHandler_Responses(t *testing.T) {
+	chargeSuccess := readFixtureBytes(t, "charge.success")
+
+	tests := []struct {
+		name     string
+		request  func(t *testing.T) *http.Request
+		handler  func(context.Context, *ChargeSuccessEvent) error
+		expected int
+		err      error
+	}{
+		{
+			name:     "acknowledges handled events",
+			request:  func(t *testing.T) *http.Request { return newSignedRequest(t, chargeSuccess) },
+			handler:  func(context.Context, *ChargeSuccessEvent) error { return nil },
+			expected: http.StatusOK,
+		},
+		{
+			name: "acknowledges events without a handler",
+			request: func(t *testing.T) *http.Request {
+				return newSignedRequest(t, readFixtureBytes(t, "transfer.success"))
+			},
+			expected: http.StatusOK,
+		},
+		{
+			name: "rejects invalid signatures",
+			request: func(t *testing.T) *http.Request {
+				req := newSignedRequest(t, chargeSuccess)
+				req.Header.Set(SignatureHeader, "invalid")
+				return req
+			},
+			expected: http.StatusUnauthorized,
+			err:      ErrInvalidSignature,
+		},
+		{
+			name:     "rejects malformed bodies",
+			request:  func(t *testing.T) *http.Request { return newSignedRequest(t, []byte(`{"event":`)) },
+			expected: http.StatusBadRequest,
+			err:      ErrInvalidPayload,
+		},
+		{
+			name: "rejects payloads that do not decode into the event type",
+			request: func(t *testing.T) *http.Request {
+				return newSignedRequest(t, []byte(`{"event":"charge.success","data":{"amount":{"a":1}}}`))
+			},
+			handler:  func(context.Context, *ChargeSuccessEvent) error { return nil },
+			expected: http.StatusBadRequest,
+			err:      ErrInvalidPayload,
+		},
+		{
+			name: "rejects non-POST requests",
+			request: func(t *testing.T) *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/webhook", nil)
+			},
+			expected: http.StatusMethodNotAllowed,
+		},
+		{
+			name:     "returns 500 when the handler fails",
+			request:  func(t *testing.T) *http.Request { return newSignedRequest(t, chargeSuccess) },
+			handler:  func(context.Context, *ChargeSuccessEvent) error { return errors.New("database down") },
+			expected: http.StatusInternalServerError,
+		},
+		{
+			name:     "recovers from panics",
+			request:  func(t *testing.T) *http.Request { return newSignedRequest(t, chargeSuccess) },
+			handler:  func(context.Context, *ChargeSuccessEvent) error { panic("boom") },
+			expected: http.StatusInternalServerError,
+			err:      ErrPanic,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotErr error
+
+			h := NewHandler(NewValidator(testSecret)).
+				OnError(func(r *http.Request, event *Event, status int, err error) {
+					assert.Equal(t, tt.expected, status)
+					gotErr = err
+				})
+
+			if tt.handler != nil {
+				h.OnChargeSuccess(tt.handler)
+			}
+
+			rec := serve(h, tt.request(t))
+
+			assert.Equal(t, tt.expected, rec.Code)
+			if tt.err != nil {
+				assert.ErrorIs(t, gotErr, tt.err)
+			}
+			if tt.expected == http.StatusOK {
+				assert.NoError(t, gotErr)
+			}
+		})
+	}
+}
+
+func TestHandler_BodySizeLimit(t *testing.T) {
+	h := NewHandler(NewValidator(testSecret)).MaxBodyBytes(16)
+
+	rec := serve(h, newSignedRequest(t, readFixtureBytes(t, "charge.success")))
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+}
+
+func TestHandler_Fallback(t *testing.T) {
+	var got string
+
+	h := NewHandler(NewValidator(testSecret)).
+		OnChargeSuccess(func(context.Context, *ChargeSuccessEvent) error { return nil }).
+		Fallback(func(ctx context.Context, e *Event) error {
+			got = e.Event
+			return nil
+		})
+
+	rec := serve(h, newSignedRequest(t, []byte(`{"event":"something.new","data":{}}`)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "something.new", got)
+
+	got = ""
+	rec = serve(h, newSignedRequest(t, readFixtureBytes(t, "charge.success")))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, got, "fallback is not called for registered events")
+}
+
+func TestHandler_PropagatesContext(t *testing.T) {
+	type key struct{}
+
+	h := NewHandler(NewValidator(testSecret)).
+		OnChargeSuccess(func(ctx context.Context, e *ChargeSuccessEvent) error {
+			assert.Equal(t, "value", ctx.Value(key{}))
+			require.NotNil(t, EventFromContext(ctx))
+			assert.Equal(t, EventChargeSuccess, EventFromContext(ctx).Event)
+			return nil
+		})
+
+	req := newSignedRequest(t, readFixtureBytes(t, "charge.success"))
+	req = req.WithContext(context.WithValue(req.Context(), key{}, "value"))
+
+	assert.Equal(t, http.StatusOK, serve(h, req).Code)
+	assert.Nil(t, EventFromContext(context.Background()))
+}
@@ -0,0 +1,292 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultMaxAttempts is how many times the Processor tries an event by default before
+	// moving it to the dead-letter store
+	DefaultMaxAttempts = 8
+	// DefaultPollInterval is how long Processor.Run waits by default when the queue is empty
+	DefaultPollInterval = time.Second
+)
+
+// Backoff returns the delay before retrying an event that has failed attempts times
+type Backoff func(attempts int) time.Duration
+
+// ExponentialBackoff returns a Backoff that doubles the delay after every failed attempt,
+// starting at base and capped at max
+func ExponentialBackoff(base, max time.Duration) Backoff {
+	return func(attempts int) time.Duration {
+		delay := base
+		for i := 1; i < attempts && delay < max; i++ {
+			delay *= 2
+		}
+
+		if delay > max {
+			return max
+		}
+
+		return delay
+	}
+}
+
+// DefaultBackoff retries after 1s, 2s, 4s, ... up to 10 minutes
+var DefaultBackoff = ExponentialBackoff(time.Second, 10*time.Minute)
+
+// FailureHandlerFunc is called for every failed processing attempt. deadLettered is true
+// when the event was moved to the dead-letter store and will not be retried.
+type FailureHandlerFunc func(event *QueuedEvent, err error, deadLettered bool)
+
+// Processor processes webhooks asynchronously. As an http.Handler it validates each
+// request and persists the event to a Queue before responding 200, so slow handlers do
+// not delay the response to Paystack. Run then dispatches queued events to the handlers
+// registered on the Handler, retrying failures with backoff and moving events that keep
+// failing, or can never succeed, to a DeadLetterStore.
+//
+// Events are dispatched with the Handler's Deduplicator when it has one. Since events
+// can be redelivered after a crash, handlers should be idempotent.
+type Processor struct {
+	handler      *Handler
+	queue        Queue
+	deadLetters  DeadLetterStore
+	maxAttempts  int
+	backoff      Backoff
+	pollInterval time.Duration
+	onFailure    FailureHandlerFunc
+	now          func() time.Time
+}
+
+// NewProcessor creates a Processor that validates requests and dispatches events with
+// handler, queues them in queue and moves failed events to deadLetters
+func NewProcessor(handler *Handler, queue Queue, deadLetters DeadLetterStore) *Processor {
+	return &Processor{
+		handler:      handler,
+		queue:        queue,
+		deadLetters:  deadLetters,
+		maxAttempts:  DefaultMaxAttempts,
+		backoff:      DefaultBackoff,
+		pollInterval: DefaultPollInterval,
+		now:          time.Now,
+	}
+}
+
+// MaxAttempts sets how many times an event is tried before it is dead-lettered
+func (p *Processor) MaxAttempts(n int) *Processor {
+	p.maxAttempts = n
+
+	return p
+}
+
+// Backoff sets the delay between attempts
+func (p *Processor) Backoff(b Backoff) *Processor {
+	p.backoff = b
+
+	return p
+}
+
+// PollInterval sets how long Run waits before polling an empty queue again
+func (p *Processor) PollInterval(d time.Duration) *Processor {
+	p.pollInterval = d
+
+	return p
+}
+
+// OnFailure sets a function that is called for every failed attempt, e.g. for logging
+func (p *Processor) OnFailure(fn FailureHandlerFunc) *Processor {
+	p.onFailure = fn
+
+	return p
+}
+
+// ServeHTTP implements http.Handler. It responds 200 once the event is queued, 500 if it
+// could not be queued so Paystack retries it, and otherwise like Handler.ServeHTTP.
+func (p *Processor) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h := p.handler
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		h.fail(w, r, nil, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+
+	if h.maxBodyBytes > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, h.maxBodyBytes)
+	}
+
+	event, err := h.validator.ValidateRequest(r)
+	if err != nil {
+		h.fail(w, r, nil, statusForError(err), err)
+		return
+	}
+
+	if err := p.Enqueue(r.Context(), event); err != nil {
+		h.fail(w, r, event, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// Enqueue queues a validated event for processing
+func (p *Processor) Enqueue(ctx context.Context, event *Event) error {
+	now := p.now()
+
+	err := p.queue.Enqueue(ctx, &QueuedEvent{
+		ID:            EventKey(event),
+		Event:         *event,
+		ReceivedAt:    now,
+		NextAttemptAt: now,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to queue %s event: %w", event.Event, err)
+	}
+
+	return nil
+}
+
+// ProcessNext processes the next due event. It returns false when the queue is empty.
+// Handler failures are retried or dead-lettered and do not cause an error; errors are
+// only returned when the queue or dead-letter store fails.
+func (p *Processor) ProcessNext(ctx context.Context) (bool, error) {
+	queued, err := p.queue.Dequeue(ctx)
+	if errors.Is(err, ErrQueueEmpty) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	event := queued.Event
+	handlerCtx := WithEvent(ctx, &event)
+
+	h := p.handler
+	if h.dedup != nil {
+		err = h.dedup.Do(handlerCtx, &event, h.dispatch)
+	} else {
+		err = h.dispatch(handlerCtx, &event)
+	}
+
+	if err == nil || errors.Is(err, ErrDuplicateEvent) {
+		return true, p.queue.Ack(ctx, queued.ID)
+	}
+
+	queued.Attempts++
+	queued.LastError = err.Error()
+
+	// Payloads that do not decode or do not match the API will never succeed, so they are
+	// not retried
+	if errors.Is(err, ErrInvalidPayload) || errors.Is(err, ErrReconciliationMismatch) || queued.Attempts >= p.maxAttempts {
+		return true, p.deadLetter(ctx, queued, err)
+	}
+
+	queued.NextAttemptAt = p.now().Add(p.backoff(queued.Attempts))
+	if p.onFailure != nil {
+		p.onFailure(queued, err, false)
+	}
+
+	return true, p.queue.Retry(ctx, queued)
+}
+
+func (p *Processor) deadLetter(ctx context.Context, queued *QueuedEvent, err error) error {
+	queued.DeadLetteredAt = p.now()
+
+	if putErr := p.deadLetters.Put(ctx, queued); putErr != nil {
+		return fmt.Errorf("failed to dead-letter event %s: %w", queued.ID, putErr)
+	}
+
+	if p.onFailure != nil {
+		p.onFailure(queued, err, true)
+	}
+
+	return p.queue.Ack(ctx, queued.ID)
+}
+
+// Run processes events with the given number of concurrent workers until ctx is
+// cancelled, then returns ctx.Err(). Queue and dead-letter store errors are reported to
+// the failure handler with a nil event and processing continues after the poll interval.
+func (p *Processor) Run(ctx context.Context, workers int) error {
+	if workers < 1 {
+		workers = 1
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.work(ctx)
+		}()
+	}
+
+	wg.Wait()
+
+	return ctx.Err()
+}
+
+func (p *Processor) work(ctx context.Context) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		processed, err := p.ProcessNext(ctx)
+		if err != nil && p.onFailure != nil && ctx.Err() == nil {
+			p.onFailure(nil, err, false)
+		}
+
+		if processed && err == nil {
+			timer.Reset(0)
+		} else {
+			timer.Reset(p.pollInterval)
+		}
+	}
+}
+
+// Redrive moves the dead-lettered event with the given ID back to the queue, with its
+// attempts reset, so it is processed again
+func (p *Processor) Redrive(ctx context.Context, id string) error {
+	queued, err := p.deadLetters.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	queued.Attempts = 0
+	queued.LastError = ""
+	queued.NextAttemptAt = p.now()
+	queued.DeadLetteredAt = time.Time{}
+
+	if err := p.queue.Enqueue(ctx, queued); err != nil {
+		return fmt.Errorf("failed to requeue event %s: %w", id, err)
+	}
+
+	return p.deadLetters.Delete(ctx, id)
+}
+
+// RedriveAll moves every dead-lettered event back to the queue and returns the number of
+// events moved
+func (p *Processor) RedriveAll(ctx context.Context) (int, error) {
+	events, err := p.deadLetters.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, e := range events {
+		if err := p.Redrive(ctx, e.ID); err != nil {
+			return i, err
+		}
+	}
+
+	return len(events), nil
+}
@@ -0,0 +1,327 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type failingQueue struct {
+	*MemoryQueue
+}
+
+func (failingQueue) Enqueue(context.Context, *QueuedEvent) error {
+	return errors.New("disk full")
+}
+
+func newTestProcessor(h *Handler) (*Processor, *MemoryQueue, *MemoryDeadLetterStore, *testClock) {
+	clock := newTestClock()
+
+	queue := NewMemoryQueue()
+	queue.now = clock.now
+	deadLetters := NewMemoryDeadLetterStore()
+
+	p := NewProcessor(h, queue, deadLetters)
+	p.now = clock.now
+
+	return p, queue, deadLetters, clock
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(time.Second, 10*time.Second)
+
+	tests := []struct {
+		attempts int
+		expected time.Duration
+	}{
+		{attempts: 1, expected: time.Second},
+		{attempts: 2, expected: 2 * time.Second},
+		{attempts: 3, expected: 4 * time.Second},
+		{attempts: 4, expected: 8 * time.Second},
+		{attempts: 5, expected: 10 * time.Second},
+		{attempts: 100, expected: 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, backoff(tt.attempts), "attempts %d", tt.attempts)
+	}
+}
+
+func TestProcessor_ServeHTTP(t *testing.T) {
+	var called atomic.Bool
+	h := NewHandler(NewValidator(testSecret)).
+		OnChargeSuccess(func(context.Context, *ChargeSuccessEvent) error {
+			called.Store(true)
+			return nil
+		})
+
+	p, queue, _, _ := newTestProcessor(h)
+
+	rec := serve(p, newSignedRequest(t, readFixtureBytes(t, "charge.success")))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.False(t, called.Load(), "handlers run asynchronously")
+	assert.Equal(t, 1, queue.Len())
+
+	rec = serve(p, newSignedRequest(t, readFixtureBytes(t, "charge.success")))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 1, queue.Len(), "redeliveries are not queued twice")
+
+	req := newSignedRequest(t, readFixtureBytes(t, "charge.success"))
+	req.Header.Set(SignatureHeader, "invalid")
+	assert.Equal(t, http.StatusUnauthorized, serve(p, req).Code)
+	assert.Equal(t, 1, queue.Len())
+
+	processed, err := p.ProcessNext(context.Background())
+	require.NoError(t, err)
+	assert.True(t, processed)
+	assert.True(t, called.Load())
+	assert.Equal(t, 0, queue.Len())
+}
+
+func TestProcessor_ServeHTTP_QueueFailure(t *testing.T) {
+	var status int
+	h := NewHandler(NewValidator(testSecret)).
+		OnError(func(r *http.Request, e *Event, s int, err error) {
+			status = s
+		})
+
+	p := NewProcessor(h, failingQueue{NewMemoryQueue()}, NewMemoryDeadLetterStore())
+
+	rec := serve(p, newSignedRequest(t, readFixtureBytes(t, "charge.success")))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code, "Paystack must retry events that were not queued")
+	assert.Equal(t, http.StatusInternalServerError, status)
+}
+
+func TestProcessor_RetriesWithBackoff(t *testing.T) {
+	ctx := context.Background()
+
+	var calls int
+	h := NewHandler(NewValidator(testSecret)).
+		OnChargeSuccess(func(context.Context, *ChargeSuccessEvent) error {
+			calls++
+			if calls < 3 {
+				return errors.New("ledger unavailable")
+			}
+			return nil
+		})
+
+	var failures []int
+	p, queue, deadLetters, clock := newTestProcessor(h)
+	p.Backoff(ExponentialBackoff(time.Second, time.Minute)).
+		OnFailure(func(e *QueuedEvent, err error, deadLettered bool) {
+			assert.False(t, deadLettered)
+			failures = append(failures, e.Attempts)
+		})
+
+	event := readEventFixture(t, "charge.success")
+	require.NoError(t, p.Enqueue(ctx, event))
+
+	processed, err := p.ProcessNext(ctx)
+	require.NoError(t, err)
+	assert.True(t, processed)
+
+	processed, err = p.ProcessNext(ctx)
+	require.NoError(t, err)
+	assert.False(t, processed, "the retry waits for the backoff")
+
+	clock.advance(time.Second)
+	_, err = p.ProcessNext(ctx)
+	require.NoError(t, err)
+
+	clock.advance(time.Second)
+	processed, err = p.ProcessNext(ctx)
+	require.NoError(t, err)
+	assert.False(t, processed, "the second retry waits twice as long")
+
+	clock.advance(time.Second)
+	_, err = p.ProcessNext(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, []int{1, 2}, failures)
+	assert.Equal(t, 0, queue.Len())
+
+	dead, err := deadLetters.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, dead)
+}
+
+func TestProcessor_DeadLetters(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		payload  string
+		handler  func(context.Context, *ChargeSuccessEvent) error
+		attempts int
+	}{
+		{
+			name:     "after max attempts",
+			payload:  "charge.success",
+			handler:  func(context.Context, *ChargeSuccessEvent) error { return errors.New("always fails") },
+			attempts: 3,
+		},
+		{
+			name:     "panicking handlers",
+			payload:  "charge.success",
+			handler:  func(context.Context, *ChargeSuccessEvent) error { panic("boom") },
+			attempts: 3,
+		},
+		{
+			name:     "immediately for payloads that do not decode",
+			payload:  `{"event":"charge.success","data":{"amount":{"a":1}}}`,
+			handler:  func(context.Context, *ChargeSuccessEvent) error { return nil },
+			attempts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(NewValidator(testSecret)).OnChargeSuccess(tt.handler)
+
+			var deadLettered *QueuedEvent
+			p, queue, deadLetters, clock := newTestProcessor(h)
+			p.MaxAttempts(3).
+				Backoff(func(int) time.Duration { return time.Second }).
+				OnFailure(func(e *QueuedEvent, err error, dead bool) {
+					if dead {
+						deadLettered = e
+					}
+				})
+
+			payload := []byte(tt.payload)
+			if tt.payload == "charge.success" {
+				payload = readFixtureBytes(t, tt.payload)
+			}
+			require.Equal(t, http.StatusOK, serve(p, newSignedRequest(t, payload)).Code)
+
+			for i := 0; i < 5; i++ {
+				_, err := p.ProcessNext(ctx)
+				require.NoError(t, err)
+				clock.advance(time.Second)
+			}
+
+			assert.Equal(t, 0, queue.Len())
+			require.NotNil(t, deadLettered)
+			assert.Equal(t, tt.attempts, deadLettered.Attempts)
+			assert.NotEmpty(t, deadLettered.LastError)
+
+			dead, err := deadLetters.List(ctx)
+			require.NoError(t, err)
+			require.Len(t, dead, 1)
+			assert.Equal(t, tt.attempts, dead[0].Attempts)
+			assert.False(t, dead[0].DeadLetteredAt.IsZero())
+		})
+	}
+}
+
+func TestProcessor_Redrive(t *testing.T) {
+	ctx := context.Background()
+
+	var healthy atomic.Bool
+	var processed []string
+	h := NewHandler(NewValidator(testSecret)).
+		Fallback(func(ctx context.Context, e *Event) error {
+			if !healthy.Load() {
+				return errors.New("downstream unavailable")
+			}
+			processed = append(processed, e.Event)
+			return nil
+		})
+
+	p, queue, deadLetters, _ := newTestProcessor(h)
+	p.MaxAttempts(1)
+
+	for _, name := range []string{"transfer.success", "transfer.failed"} {
+		require.NoError(t, p.Enqueue(ctx, readEventFixture(t, name)))
+		_, err := p.ProcessNext(ctx)
+		require.NoError(t, err)
+	}
+
+	dead, err := deadLetters.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, dead, 2)
+
+	assert.ErrorIs(t, p.Redrive(ctx, "missing"), ErrEventNotFound)
+
+	healthy.Store(true)
+
+	require.NoError(t, p.Redrive(ctx, dead[0].ID))
+	assert.Equal(t, 1, queue.Len())
+
+	n, err := p.RedriveAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, 2, queue.Len())
+
+	for {
+		ok, err := p.ProcessNext(ctx)
+		require.NoError(t, err)
+		if !ok {
+			break
+		}
+	}
+
+	assert.ElementsMatch(t, []string{EventTransferSuccess, EventTransferFailed}, processed)
+
+	dead, err = deadLetters.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, dead)
+}
+
+func TestProcessor_Deduplicates(t *testing.T) {
+	ctx := context.Background()
+
+	var calls int
+	h := NewHandler(NewValidator(testSecret)).
+		Deduplicate(NewDeduplicator(NewMemoryDedupStore(), 0)).
+		OnChargeSuccess(func(context.Context, *ChargeSuccessEvent) error {
+			calls++
+			return nil
+		})
+
+	p, queue, _, _ := newTestProcessor(h)
+	event := readEventFixture(t, "charge.success")
+
+	for i := 0; i < 2; i++ {
+		// A redelivery arriving after the first delivery was processed is queued again
+		require.NoError(t, p.Enqueue(ctx, event))
+		_, err := p.ProcessNext(ctx)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, 0, queue.Len())
+}
+
+func TestProcessor_Run(t *testing.T) {
+	done := make(chan struct{})
+	h := NewHandler(NewValidator(testSecret)).
+		OnTransferSuccess(func(context.Context, *TransferSuccessEvent) error {
+			close(done)
+			return nil
+		})
+
+	p := NewProcessor(h, NewMemoryQueue(), NewMemoryDeadLetterStore()).PollInterval(time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() { errs <- p.Run(ctx, 2) }()
+
+	require.NoError(t, p.Enqueue(ctx, readEventFixture(t, "transfer.success")))
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("event was not processed")
+	}
+
+	cancel()
+	assert.ErrorIs(t, <-errs, context.Canceled)
+}
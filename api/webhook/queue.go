@@ -0,0 +1,235 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultVisibilityTimeout is how long a dequeued event stays hidden from other consumers
+// by default. If it is neither acknowledged nor retried in that time, e.g. because the
+// process crashed, it is delivered again.
+const DefaultVisibilityTimeout = 5 * time.Minute
+
+var (
+	// ErrQueueEmpty is returned by Queue.Dequeue when no event is due for processing
+	ErrQueueEmpty = errors.New("webhook queue is empty")
+	// ErrEventNotFound is returned when a queued or dead-lettered event does not exist
+	ErrEventNotFound = errors.New("webhook event not found")
+)
+
+// QueuedEvent is a validated webhook event persisted for asynchronous processing
+type QueuedEvent struct {
+	// ID identifies the event in queues and dead-letter stores. Events are enqueued with
+	// their EventKey, so redeliveries of a queued event are not queued twice.
+	ID            string    `json:"id"`
+	Event         Event     `json:"event"`
+	Attempts      int       `json:"attempts"`
+	ReceivedAt    time.Time `json:"received_at"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+	LastError     string    `json:"last_error,omitempty"`
+	// DeadLetteredAt is set when the event is moved to a dead-letter store
+	DeadLetteredAt time.Time `json:"dead_lettered_at"`
+}
+
+// Queue persists webhook events between receipt and processing. Implementations backed
+// by durable storage (files, an SQL table, a message broker) let the Processor acknowledge
+// Paystack immediately without losing events on a crash.
+type Queue interface {
+	// Enqueue stores event. It does nothing if an event with the same ID is already queued.
+	Enqueue(ctx context.Context, event *QueuedEvent) error
+	// Dequeue claims the event with the earliest NextAttemptAt that is due. The event is
+	// hidden from other consumers until it is acknowledged, retried or its visibility
+	// timeout expires. It returns ErrQueueEmpty when no event is due.
+	Dequeue(ctx context.Context) (*QueuedEvent, error)
+	// Ack removes a processed event
+	Ack(ctx context.Context, id string) error
+	// Retry stores the updated Attempts, LastError and NextAttemptAt of a claimed event and
+	// makes it available again at NextAttemptAt
+	Retry(ctx context.Context, event *QueuedEvent) error
+}
+
+// DeadLetterStore keeps events that failed processing permanently, for inspection and
+// re-driving with Processor.Redrive
+type DeadLetterStore interface {
+	Put(ctx context.Context, event *QueuedEvent) error
+	// Get returns the event with the given ID, or an error wrapping ErrEventNotFound
+	Get(ctx context.Context, id string) (*QueuedEvent, error)
+	// List returns all dead-lettered events, oldest first
+	List(ctx context.Context) ([]*QueuedEvent, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// MemoryQueue is an in-memory Queue for tests and deployments that can afford to lose
+// queued events on restart. It is safe for concurrent use.
+type MemoryQueue struct {
+	mu         sync.Mutex
+	events     map[string]*QueuedEvent
+	visibility time.Duration
+	now        func() time.Time
+}
+
+// NewMemoryQueue creates an empty MemoryQueue
+func NewMemoryQueue() *MemoryQueue {
+	return &MemoryQueue{
+		events:     make(map[string]*QueuedEvent),
+		visibility: DefaultVisibilityTimeout,
+		now:        time.Now,
+	}
+}
+
+// VisibilityTimeout sets how long a dequeued event stays hidden before it is redelivered
+func (q *MemoryQueue) VisibilityTimeout(d time.Duration) *MemoryQueue {
+	q.visibility = d
+
+	return q
+}
+
+// Enqueue implements Queue
+func (q *MemoryQueue) Enqueue(_ context.Context, event *QueuedEvent) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if _, ok := q.events[event.ID]; ok {
+		return nil
+	}
+
+	e := *event
+	q.events[event.ID] = &e
+
+	return nil
+}
+
+// Dequeue implements Queue
+func (q *MemoryQueue) Dequeue(_ context.Context) (*QueuedEvent, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := q.now()
+
+	var next *QueuedEvent
+	for _, e := range q.events {
+		if e.NextAttemptAt.After(now) {
+			continue
+		}
+
+		if next == nil || e.NextAttemptAt.Before(next.NextAttemptAt) {
+			next = e
+		}
+	}
+
+	if next == nil {
+		return nil, ErrQueueEmpty
+	}
+
+	claimed := *next
+	next.NextAttemptAt = now.Add(q.visibility)
+
+	return &claimed, nil
+}
+
+// Ack implements Queue
+func (q *MemoryQueue) Ack(_ context.Context, id string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	delete(q.events, id)
+
+	return nil
+}
+
+// Retry implements Queue
+func (q *MemoryQueue) Retry(_ context.Context, event *QueuedEvent) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	e := *event
+	q.events[event.ID] = &e
+
+	return nil
+}
+
+// Len returns the number of queued events, including claimed ones
+func (q *MemoryQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.events)
+}
+
+// MemoryDeadLetterStore is an in-memory DeadLetterStore. It is safe for concurrent use.
+type MemoryDeadLetterStore struct {
+	mu     sync.Mutex
+	events map[string]*QueuedEvent
+}
+
+// NewMemoryDeadLetterStore creates an empty MemoryDeadLetterStore
+func NewMemoryDeadLetterStore() *MemoryDeadLetterStore {
+	return &MemoryDeadLetterStore{
+		events: make(map[string]*QueuedEvent),
+	}
+}
+
+// Put implements DeadLetterStore
+func (s *MemoryDeadLetterStore) Put(_ context.Context, event *QueuedEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e := *event
+	s.events[event.ID] = &e
+
+	return nil
+}
+
+// Get implements DeadLetterStore
+func (s *MemoryDeadLetterStore) Get(_ context.Context, id string) (*QueuedEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.events[id]
+	if !ok {
+		return nil, ErrEventNotFound
+	}
+
+	event := *e
+
+	return &event, nil
+}
+
+// List implements DeadLetterStore
+func (s *MemoryDeadLetterStore) List(_ context.Context) ([]*QueuedEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	events := make([]*QueuedEvent, 0, len(s.events))
+	for _, e := range s.events {
+		event := *e
+		events = append(events, &event)
+	}
+
+	sortByDeadLetteredAt(events)
+
+	return events, nil
+}
+
+// Delete implements DeadLetterStore
+func (s *MemoryDeadLetterStore) Delete(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.events, id)
+
+	return nil
+}
+
+func sortByDeadLetteredAt(events []*QueuedEvent) {
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].DeadLetteredAt.Equal(events[j].DeadLetteredAt) {
+			return events[i].ID < events[j].ID
+		}
+
+		return events[i].DeadLetteredAt.Before(events[j].DeadLetteredAt)
+	})
+}
@@ -0,0 +1,379 @@
+package webhook
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileQueue is a Queue that stores each event as a JSON file in a directory. Files are
+// written atomically, so queued events survive crashes and restarts. It is safe for
+// concurrent use within one process; use a queue backed by shared storage, such as an SQL
+// table, to consume events from several processes.
+//
+// The directory is read once when the queue is opened and the queue keeps track of when
+// each event is due, so events must only be added through the queue. Files that cannot be
+// parsed, e.g. because they were truncated, are renamed with a ".corrupt" suffix and left
+// for inspection.
+type FileQueue struct {
+	dir        fileDir
+	visibility time.Duration
+	now        func() time.Time
+
+	// due holds the NextAttemptAt of every queued event by ID
+	due map[string]time.Time
+}
+
+// NewFileQueue creates a FileQueue in dir, creating the directory if needed
+func NewFileQueue(dir string) (*FileQueue, error) {
+	d, err := newFileDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := d.list()
+	if err != nil {
+		return nil, err
+	}
+
+	due := make(map[string]time.Time, len(events))
+	for _, e := range events {
+		due[e.ID] = e.NextAttemptAt
+	}
+
+	return &FileQueue{
+		dir:        d,
+		visibility: DefaultVisibilityTimeout,
+		now:        time.Now,
+		due:        due,
+	}, nil
+}
+
+// VisibilityTimeout sets how long a dequeued event stays hidden before it is redelivered
+func (q *FileQueue) VisibilityTimeout(d time.Duration) *FileQueue {
+	q.visibility = d
+
+	return q
+}
+
+// Enqueue implements Queue
+func (q *FileQueue) Enqueue(_ context.Context, event *QueuedEvent) error {
+	q.dir.mu.Lock()
+	defer q.dir.mu.Unlock()
+
+	if _, ok := q.due[event.ID]; ok {
+		return nil
+	}
+
+	if err := q.dir.write(event); err != nil {
+		return err
+	}
+
+	q.due[event.ID] = event.NextAttemptAt
+
+	return nil
+}
+
+// Dequeue implements Queue
+func (q *FileQueue) Dequeue(_ context.Context) (*QueuedEvent, error) {
+	q.dir.mu.Lock()
+	defer q.dir.mu.Unlock()
+
+	now := q.now()
+
+	for {
+		id, ok := q.nextDue(now)
+		if !ok {
+			return nil, ErrQueueEmpty
+		}
+
+		next, err := q.dir.read(id)
+		if errors.Is(err, ErrEventNotFound) || errors.Is(err, errCorruptEvent) {
+			// Removed or quarantined outside the queue
+			delete(q.due, id)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		claimed := *next
+		next.NextAttemptAt = now.Add(q.visibility)
+
+		if err := q.dir.write(next); err != nil {
+			return nil, err
+		}
+
+		q.due[id] = next.NextAttemptAt
+
+		return &claimed, nil
+	}
+}
+
+// nextDue returns the ID of the event with the earliest NextAttemptAt that is due at now
+func (q *FileQueue) nextDue(now time.Time) (string, bool) {
+	var (
+		next string
+		at   time.Time
+		ok   bool
+	)
+
+	for id, due := range q.due {
+		if due.After(now) {
+			continue
+		}
+
+		if !ok || due.Before(at) {
+			next, at, ok = id, due, true
+		}
+	}
+
+	return next, ok
+}
+
+// Ack implements Queue
+func (q *FileQueue) Ack(_ context.Context, id string) error {
+	q.dir.mu.Lock()
+	defer q.dir.mu.Unlock()
+
+	if err := q.dir.remove(id); err != nil {
+		return err
+	}
+
+	delete(q.due, id)
+
+	return nil
+}
+
+// Retry implements Queue
+func (q *FileQueue) Retry(_ context.Context, event *QueuedEvent) error {
+	q.dir.mu.Lock()
+	defer q.dir.mu.Unlock()
+
+	if err := q.dir.write(event); err != nil {
+		return err
+	}
+
+	q.due[event.ID] = event.NextAttemptAt
+
+	return nil
+}
+
+// FileDeadLetterStore is a DeadLetterStore that stores each event as a JSON file in a
+// directory. It is safe for concurrent use within one process. Like FileQueue, it renames
+// files it cannot parse with a ".corrupt" suffix.
+type FileDeadLetterStore struct {
+	dir fileDir
+}
+
+// NewFileDeadLetterStore creates a FileDeadLetterStore in dir, creating the directory if
+// needed
+func NewFileDeadLetterStore(dir string) (*FileDeadLetterStore, error) {
+	d, err := newFileDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileDeadLetterStore{dir: d}, nil
+}
+
+// Put implements DeadLetterStore
+func (s *FileDeadLetterStore) Put(_ context.Context, event *QueuedEvent) error {
+	s.dir.mu.Lock()
+	defer s.dir.mu.Unlock()
+
+	return s.dir.write(event)
+}
+
+// Get implements DeadLetterStore
+func (s *FileDeadLetterStore) Get(_ context.Context, id string) (*QueuedEvent, error) {
+	s.dir.mu.Lock()
+	defer s.dir.mu.Unlock()
+
+	return s.dir.read(id)
+}
+
+// List implements DeadLetterStore
+func (s *FileDeadLetterStore) List(_ context.Context) ([]*QueuedEvent, error) {
+	s.dir.mu.Lock()
+	defer s.dir.mu.Unlock()
+
+	events, err := s.dir.list()
+	if err != nil {
+		return nil, err
+	}
+
+	sortByDeadLetteredAt(events)
+
+	return events, nil
+}
+
+// Delete implements DeadLetterStore
+func (s *FileDeadLetterStore) Delete(_ context.Context, id string) error {
+	s.dir.mu.Lock()
+	defer s.dir.mu.Unlock()
+
+	return s.dir.remove(id)
+}
+
+// corruptSuffix is appended to the names of event files that cannot be parsed
+const corruptSuffix = ".corrupt"
+
+// errCorruptEvent is returned for an event file that cannot be parsed. The file has been
+// quarantined by the time it is returned.
+var errCorruptEvent = errors.New("corrupt queued event")
+
+// fileDir stores QueuedEvents as JSON files named after a hash of their ID, since IDs may
+// contain characters that are not valid in file names
+type fileDir struct {
+	mu   *sync.Mutex
+	path string
+}
+
+func newFileDir(path string) (fileDir, error) {
+	if err := os.MkdirAll(path, 0o700); err != nil {
+		return fileDir{}, fmt.Errorf("failed to create queue directory: %w", err)
+	}
+
+	return fileDir{mu: new(sync.Mutex), path: path}, nil
+}
+
+func (d fileDir) file(id string) string {
+	sum := sha256.Sum256([]byte(id))
+
+	return filepath.Join(d.path, hex.EncodeToString(sum[:])+".json")
+}
+
+func (d fileDir) read(id string) (*QueuedEvent, error) {
+	return d.readFile(d.file(id))
+}
+
+// readFile reads the event in path, quarantining the file if it cannot be parsed
+func (d fileDir) readFile(path string) (*QueuedEvent, error) {
+	event, err := readEventFile(path)
+	if !errors.Is(err, errCorruptEvent) {
+		return event, err
+	}
+
+	if renameErr := os.Rename(path, path+corruptSuffix); renameErr != nil {
+		return nil, fmt.Errorf("failed to quarantine queued event: %w", renameErr)
+	}
+	if syncErr := d.sync(); syncErr != nil {
+		return nil, syncErr
+	}
+
+	return nil, err
+}
+
+// sync flushes renames and removals in the directory to disk
+func (d fileDir) sync() error {
+	dir, err := os.Open(d.path)
+	if err != nil {
+		return fmt.Errorf("failed to sync queue directory: %w", err)
+	}
+
+	err = dir.Sync()
+	if closeErr := dir.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to sync queue directory: %w", err)
+	}
+
+	return nil
+}
+
+// write replaces the event's file atomically by renaming a synced temporary file over it,
+// then syncs the directory so the rename itself survives a crash
+func (d fileDir) write(event *QueuedEvent) error {
+	b, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal queued event: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(d.path, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write queued event: %w", err)
+	}
+
+	_, err = tmp.Write(b)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), d.file(event.ID))
+	}
+
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write queued event: %w", err)
+	}
+
+	return d.sync()
+}
+
+func (d fileDir) remove(id string) error {
+	if err := os.Remove(d.file(id)); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("failed to remove queued event: %w", err)
+	}
+
+	return d.sync()
+}
+
+func (d fileDir) list() ([]*QueuedEvent, error) {
+	entries, err := os.ReadDir(d.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list queued events: %w", err)
+	}
+
+	events := make([]*QueuedEvent, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+
+		event, err := d.readFile(filepath.Join(d.path, entry.Name()))
+		if errors.Is(err, ErrEventNotFound) || errors.Is(err, errCorruptEvent) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+func readEventFile(path string) (*QueuedEvent, error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrEventNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read queued event: %w", err)
+	}
+
+	var event QueuedEvent
+	if err := json.Unmarshal(b, &event); err != nil {
+		return nil, fmt.Errorf("%w %s: %w", errCorruptEvent, filepath.Base(path), err)
+	}
+
+	return &event, nil
+}
@@ -0,0 +1,272 @@
+package webhook
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testClock is a settable clock for queues and processors
+type testClock struct {
+	t time.Time
+}
+
+func newTestClock() *testClock {
+	return &testClock{t: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+}
+
+func (c *testClock) now() time.Time { return c.t }
+
+func (c *testClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func queuedEvent(id string, due time.Time) *QueuedEvent {
+	return &QueuedEvent{
+		ID:            id,
+		Event:         Event{Event: EventChargeSuccess, Data: []byte(`{"reference":"` + id + `"}`)},
+		ReceivedAt:    due,
+		NextAttemptAt: due,
+	}
+}
+
+func queueImplementations(t *testing.T, clock *testClock) map[string]Queue {
+	memory := NewMemoryQueue().VisibilityTimeout(time.Minute)
+	memory.now = clock.now
+
+	file, err := NewFileQueue(filepath.Join(t.TempDir(), "queue"))
+	require.NoError(t, err)
+	file.VisibilityTimeout(time.Minute)
+	file.now = clock.now
+
+	return map[string]Queue{
+		"memory": memory,
+		"file":   file,
+	}
+}
+
+func deadLetterImplementations(t *testing.T) map[string]DeadLetterStore {
+	file, err := NewFileDeadLetterStore(filepath.Join(t.TempDir(), "dead"))
+	require.NoError(t, err)
+
+	return map[string]DeadLetterStore{
+		"memory": NewMemoryDeadLetterStore(),
+		"file":   file,
+	}
+}
+
+func TestQueue_Contract(t *testing.T) {
+	ctx := context.Background()
+
+	for name := range queueImplementations(t, newTestClock()) {
+		t.Run(name, func(t *testing.T) {
+			t.Run("dequeues due events in order", func(t *testing.T) {
+				clock := newTestClock()
+				q := queueImplementations(t, clock)[name]
+
+				require.NoError(t, q.Enqueue(ctx, queuedEvent("b", clock.now().Add(-time.Second))))
+				require.NoError(t, q.Enqueue(ctx, queuedEvent("a", clock.now().Add(-time.Minute))))
+				require.NoError(t, q.Enqueue(ctx, queuedEvent("later", clock.now().Add(time.Hour))))
+
+				first, err := q.Dequeue(ctx)
+				require.NoError(t, err)
+				assert.Equal(t, "a", first.ID)
+				assert.JSONEq(t, `{"reference":"a"}`, string(first.Event.Data))
+
+				second, err := q.Dequeue(ctx)
+				require.NoError(t, err)
+				assert.Equal(t, "b", second.ID)
+
+				_, err = q.Dequeue(ctx)
+				assert.ErrorIs(t, err, ErrQueueEmpty, "claimed and future events are hidden")
+			})
+
+			t.Run("ignores duplicate enqueues", func(t *testing.T) {
+				clock := newTestClock()
+				q := queueImplementations(t, clock)[name]
+
+				require.NoError(t, q.Enqueue(ctx, queuedEvent("a", clock.now())))
+				require.NoError(t, q.Enqueue(ctx, queuedEvent("a", clock.now())))
+
+				_, err := q.Dequeue(ctx)
+				require.NoError(t, err)
+				_, err = q.Dequeue(ctx)
+				assert.ErrorIs(t, err, ErrQueueEmpty)
+			})
+
+			t.Run("redelivers after the visibility timeout", func(t *testing.T) {
+				clock := newTestClock()
+				q := queueImplementations(t, clock)[name]
+
+				require.NoError(t, q.Enqueue(ctx, queuedEvent("a", clock.now())))
+				_, err := q.Dequeue(ctx)
+				require.NoError(t, err)
+
+				clock.advance(time.Minute)
+
+				again, err := q.Dequeue(ctx)
+				require.NoError(t, err)
+				assert.Equal(t, "a", again.ID)
+			})
+
+			t.Run("ack removes the event", func(t *testing.T) {
+				clock := newTestClock()
+				q := queueImplementations(t, clock)[name]
+
+				require.NoError(t, q.Enqueue(ctx, queuedEvent("a", clock.now())))
+				e, err := q.Dequeue(ctx)
+				require.NoError(t, err)
+				require.NoError(t, q.Ack(ctx, e.ID))
+				require.NoError(t, q.Ack(ctx, e.ID), "acking twice is not an error")
+
+				clock.advance(time.Hour)
+				_, err = q.Dequeue(ctx)
+				assert.ErrorIs(t, err, ErrQueueEmpty)
+			})
+
+			t.Run("retry reschedules the event", func(t *testing.T) {
+				clock := newTestClock()
+				q := queueImplementations(t, clock)[name]
+
+				require.NoError(t, q.Enqueue(ctx, queuedEvent("a", clock.now())))
+				e, err := q.Dequeue(ctx)
+				require.NoError(t, err)
+
+				e.Attempts = 1
+				e.LastError = "database down"
+				e.NextAttemptAt = clock.now().Add(10 * time.Second)
+				require.NoError(t, q.Retry(ctx, e))
+
+				_, err = q.Dequeue(ctx)
+				assert.ErrorIs(t, err, ErrQueueEmpty)
+
+				clock.advance(10 * time.Second)
+				again, err := q.Dequeue(ctx)
+				require.NoError(t, err)
+				assert.Equal(t, 1, again.Attempts)
+				assert.Equal(t, "database down", again.LastError)
+			})
+		})
+	}
+}
+
+func TestDeadLetterStore_Contract(t *testing.T) {
+	ctx := context.Background()
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	for name, store := range deadLetterImplementations(t) {
+		t.Run(name, func(t *testing.T) {
+			_, err := store.Get(ctx, "missing")
+			assert.ErrorIs(t, err, ErrEventNotFound)
+
+			for i, id := range []string{"second", "first"} {
+				e := queuedEvent(id, base)
+				e.DeadLetteredAt = base.Add(-time.Duration(i) * time.Minute)
+				e.LastError = "boom"
+				require.NoError(t, store.Put(ctx, e))
+			}
+
+			got, err := store.Get(ctx, "first")
+			require.NoError(t, err)
+			assert.Equal(t, "boom", got.LastError)
+
+			list, err := store.List(ctx)
+			require.NoError(t, err)
+			require.Len(t, list, 2)
+			assert.Equal(t, "first", list[0].ID)
+			assert.Equal(t, "second", list[1].ID)
+
+			require.NoError(t, store.Delete(ctx, "first"))
+			require.NoError(t, store.Delete(ctx, "first"))
+
+			list, err = store.List(ctx)
+			require.NoError(t, err)
+			require.Len(t, list, 1)
+			assert.Equal(t, "second", list[0].ID)
+		})
+	}
+}
+
+func TestFileQueue_SurvivesRestart(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	q, err := NewFileQueue(dir)
+	require.NoError(t, err)
+	require.NoError(t, q.Enqueue(ctx, queuedEvent("charge.success:1:ref:abc/def", time.Now())))
+
+	reopened, err := NewFileQueue(dir)
+	require.NoError(t, err)
+
+	e, err := reopened.Dequeue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "charge.success:1:ref:abc/def", e.ID)
+}
+
+func TestFileQueue_IgnoresForeignFiles(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".tmp-123"), []byte("partial"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README"), []byte("notes"), 0o600))
+
+	q, err := NewFileQueue(dir)
+	require.NoError(t, err)
+
+	_, err = q.Dequeue(ctx)
+	assert.ErrorIs(t, err, ErrQueueEmpty)
+
+}
+
+func TestFileQueue_QuarantinesCorruptFiles(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	q, err := NewFileQueue(dir)
+	require.NoError(t, err)
+	require.NoError(t, q.Enqueue(ctx, queuedEvent("first", time.Now().Add(-time.Minute))))
+	require.NoError(t, q.Enqueue(ctx, queuedEvent("second", time.Now())))
+
+	// A file left half written, e.g. by a full disk, blocks neither reopening the queue nor
+	// dequeuing the events after it
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "partial.json"), []byte(`{"id":"par`), 0o600))
+	require.NoError(t, os.WriteFile(q.dir.file("first"), []byte("{"), 0o600))
+
+	reopened, err := NewFileQueue(dir)
+	require.NoError(t, err)
+
+	e, err := reopened.Dequeue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "second", e.ID)
+
+	_, err = os.Stat(filepath.Join(dir, "partial.json.corrupt"))
+	assert.NoError(t, err)
+	_, err = os.Stat(q.dir.file("first") + ".corrupt")
+	assert.NoError(t, err)
+
+	// Corrupted after the queue was opened
+	require.NoError(t, reopened.Ack(ctx, "second"))
+	require.NoError(t, reopened.Enqueue(ctx, queuedEvent("third", time.Now())))
+	require.NoError(t, os.WriteFile(q.dir.file("third"), []byte("{"), 0o600))
+
+	_, err = reopened.Dequeue(ctx)
+	assert.ErrorIs(t, err, ErrQueueEmpty)
+}
+
+func TestFileDeadLetterStore_QuarantinesCorruptFiles(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	store, err := NewFileDeadLetterStore(dir)
+	require.NoError(t, err)
+	require.NoError(t, store.Put(ctx, queuedEvent("dead", time.Now())))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "partial.json"), []byte("{"), 0o600))
+
+	list, err := store.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "dead", list[0].ID)
+}
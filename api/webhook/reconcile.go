@@ -0,0 +1,419 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/huysamen/paystack-go/api/paymentrequests"
+	"github.com/huysamen/paystack-go/api/refunds"
+	"github.com/huysamen/paystack-go/api/transactions"
+	"github.com/huysamen/paystack-go/api/transfers"
+	"github.com/huysamen/paystack-go/types"
+)
+
+// ErrReconciliationMismatch is wrapped by the error returned when a webhook event does not
+// match the object fetched from the API. Use errors.As with *MismatchError for the details.
+var ErrReconciliationMismatch = errors.New("webhook event does not match the API")
+
+// TransactionVerifier verifies transactions by reference. It is implemented by
+// *transactions.Client.
+type TransactionVerifier interface {
+	Verify(ctx context.Context, reference string) (*transactions.VerifyResponse, error)
+}
+
+// TransferVerifier verifies transfers by reference. It is implemented by
+// *transfers.Client.
+type TransferVerifier interface {
+	Verify(ctx context.Context, reference string) (*transfers.VerifyResponse, error)
+}
+
+// RefundFetcher fetches refunds. It is implemented by *refunds.Client.
+type RefundFetcher interface {
+	Fetch(ctx context.Context, refundID string) (*refunds.FetchResponse, error)
+}
+
+// PaymentRequestVerifier verifies payment requests by request code. It is implemented by
+// *paymentrequests.Client.
+type PaymentRequestVerifier interface {
+	Verify(ctx context.Context, code string) (*paymentrequests.VerifyResponse, error)
+}
+
+// Mismatch is a field whose value in the webhook differs from the value returned by the API
+type Mismatch struct {
+	Field   string
+	Webhook string
+	API     string
+}
+
+// MismatchError reports the fields of a webhook event that do not match the API
+type MismatchError struct {
+	Event      string
+	Reference  string
+	Mismatches []Mismatch
+}
+
+func (e *MismatchError) Error() string {
+	fields := make([]string, len(e.Mismatches))
+	for i, m := range e.Mismatches {
+		fields[i] = fmt.Sprintf("%s: webhook %q, API %q", m.Field, m.Webhook, m.API)
+	}
+
+	return fmt.Sprintf("%s: %s %s: %s", ErrReconciliationMismatch, e.Event, e.Reference, strings.Join(fields, ", "))
+}
+
+// Unwrap returns ErrReconciliationMismatch
+func (e *MismatchError) Unwrap() error {
+	return ErrReconciliationMismatch
+}
+
+// Reconciler re-fetches the object behind money-moving webhook events and checks that the
+// amount, currency, status and reference in the payload match the API, so a forged or
+// replayed payload is never trusted on its own. It covers charge.success, transfer.*,
+// refund.* and paymentrequest.* events; other events pass unchecked.
+//
+// The status the API reports is checked against the outcome the event type announces, e.g.
+// success for charge.success and failed for transfer.failed, so a payload without a final
+// status is still checked; a status in the payload is checked as well. Statuses only
+// mismatch when they contradict each other, e.g. a transfer.success for a transfer the API
+// reports as failed. An event that arrives after its object has moved on,
+// such as a refund.pending for a refund that has since been processed or a transfer.success
+// for a transfer that was later reversed, matches. When the API is behind the event, e.g. a
+// refund.processed for a refund the API still reports as pending, Reconcile returns an error
+// that is not a mismatch, so the event is retried.
+type Reconciler struct {
+	transactions    TransactionVerifier
+	transfers       TransferVerifier
+	refunds         RefundFetcher
+	paymentRequests PaymentRequestVerifier
+}
+
+// NewReconciler creates a Reconciler that fetches objects with the given clients, usually
+// the Transactions, Transfers, Refunds and PaymentRequests clients of a paystack.Client.
+// Events whose client is nil fail reconciliation.
+func NewReconciler(transactions TransactionVerifier, transfers TransferVerifier, refunds RefundFetcher, paymentRequests PaymentRequestVerifier) *Reconciler {
+	return &Reconciler{
+		transactions:    transactions,
+		transfers:       transfers,
+		refunds:         refunds,
+		paymentRequests: paymentRequests,
+	}
+}
+
+// Covers returns true if events of the given type are reconciled
+func (r *Reconciler) Covers(event string) bool {
+	switch event {
+	case EventChargeSuccess,
+		EventTransferSuccess, EventTransferFailed, EventTransferReversed,
+		EventRefundPending, EventRefundProcessing, EventRefundProcessed, EventRefundFailed,
+		EventPaymentRequestPending, EventPaymentRequestSuccess:
+		return true
+	default:
+		return false
+	}
+}
+
+// Reconcile fetches the object behind event and compares it with the payload. It returns a
+// *MismatchError when they differ, ErrInvalidPayload when the payload does not decode, and
+// other errors when the object could not be fetched. Events that are not covered return nil.
+func (r *Reconciler) Reconcile(ctx context.Context, event *Event) error {
+	if !r.Covers(event.Event) {
+		return nil
+	}
+
+	switch {
+	case event.Event == EventChargeSuccess:
+		return r.reconcileCharge(ctx, event)
+	case strings.HasPrefix(event.Event, "transfer."):
+		return r.reconcileTransfer(ctx, event)
+	case strings.HasPrefix(event.Event, "refund."):
+		return r.reconcileRefund(ctx, event)
+	default:
+		return r.reconcilePaymentRequest(ctx, event)
+	}
+}
+
+func (r *Reconciler) reconcileCharge(ctx context.Context, event *Event) error {
+	charge, err := ParseEventData[ChargeSuccessEvent](event)
+	if err != nil {
+		return fmt.Errorf("%w: decoding %s: %w", ErrInvalidPayload, event.Event, err)
+	}
+
+	reference := charge.Reference.String()
+	if r.transactions == nil {
+		return fmt.Errorf("cannot reconcile %s %s: no transaction verifier", event.Event, reference)
+	}
+
+	rsp, err := r.transactions.Verify(ctx, reference)
+	if err := checkResponse(event, reference, rsp, err); err != nil {
+		return err
+	}
+
+	tx := rsp.Data
+
+	err = compare(event, reference,
+		field("amount", charge.Amount.Int64(), tx.Amount.Int64()),
+		field("currency", charge.Currency.String(), tx.Currency.String()),
+		transactionLifecycle.field(event.Event, charge.Status.String(), tx.Status.String()),
+		field("reference", reference, tx.Reference.String()),
+	)
+	if err != nil {
+		return err
+	}
+
+	return transactionLifecycle.caughtUp(event, reference, charge.Status.String(), tx.Status.String())
+}
+
+func (r *Reconciler) reconcileTransfer(ctx context.Context, event *Event) error {
+	// The transfer events share their fields, so one type decodes all of them
+	transfer, err := ParseEventData[TransferSuccessEvent](event)
+	if err != nil {
+		return fmt.Errorf("%w: decoding %s: %w", ErrInvalidPayload, event.Event, err)
+	}
+
+	reference := transfer.Reference.String()
+	if r.transfers == nil {
+		return fmt.Errorf("cannot reconcile %s %s: no transfer verifier", event.Event, reference)
+	}
+
+	rsp, err := r.transfers.Verify(ctx, reference)
+	if err := checkResponse(event, reference, rsp, err); err != nil {
+		return err
+	}
+
+	t := rsp.Data
+
+	err = compare(event, reference,
+		field("amount", transfer.Amount.Int64(), t.Amount.Int64()),
+		field("currency", transfer.Currency.String(), t.Currency.String()),
+		transferLifecycle.field(event.Event, transfer.Status.String(), t.Status.String()),
+		field("reference", reference, t.Reference.String()),
+	)
+	if err != nil {
+		return err
+	}
+
+	return transferLifecycle.caughtUp(event, reference, transfer.Status.String(), t.Status.String())
+}
+
+// reconcileRefund fetches the refund by its refund_reference, falling back to its ID. The
+// fetched refund only carries the ID of its transaction, so the transaction is compared by
+// ID when the payload includes it.
+func (r *Reconciler) reconcileRefund(ctx context.Context, event *Event) error {
+	refund, err := ParseEventData[RefundPendingEvent](event)
+	if err != nil {
+		return fmt.Errorf("%w: decoding %s: %w", ErrInvalidPayload, event.Event, err)
+	}
+
+	reference := refund.RefundReference.Str
+	if reference == "" && refund.ID.Int64() != 0 {
+		reference = strconv.FormatInt(refund.ID.Int64(), 10)
+	}
+	if reference == "" {
+		return fmt.Errorf("%w: %s has neither a refund_reference nor an id", ErrInvalidPayload, event.Event)
+	}
+	if r.refunds == nil {
+		return fmt.Errorf("cannot reconcile %s %s: no refund fetcher", event.Event, reference)
+	}
+
+	rsp, err := r.refunds.Fetch(ctx, reference)
+	if err := checkResponse(event, reference, rsp, err); err != nil {
+		return err
+	}
+
+	fetched := rsp.Data
+	fields := []*Mismatch{
+		field("amount", refund.Amount.Int64(), fetched.Amount.Int64()),
+		field("currency", refund.Currency.String(), fetched.Currency.String()),
+		refundLifecycle.field(event.Event, refund.Status.String(), fetched.Status.String()),
+	}
+	if refund.Transaction != nil && refund.Transaction.ID.Uint64() != 0 {
+		fields = append(fields, field("transaction", int64(refund.Transaction.ID.Uint64()), fetched.Transaction.Int64()))
+	}
+
+	if err := compare(event, reference, fields...); err != nil {
+		return err
+	}
+
+	return refundLifecycle.caughtUp(event, reference, refund.Status.String(), fetched.Status.String())
+}
+
+func (r *Reconciler) reconcilePaymentRequest(ctx context.Context, event *Event) error {
+	request, err := ParseEventData[PaymentRequestEvent](event)
+	if err != nil {
+		return fmt.Errorf("%w: decoding %s: %w", ErrInvalidPayload, event.Event, err)
+	}
+
+	code := request.RequestCode.String()
+	if r.paymentRequests == nil {
+		return fmt.Errorf("cannot reconcile %s %s: no payment request verifier", event.Event, code)
+	}
+
+	rsp, err := r.paymentRequests.Verify(ctx, code)
+	if err := checkResponse(event, code, rsp, err); err != nil {
+		return err
+	}
+
+	pr := rsp.Data
+
+	err = compare(event, code,
+		field("amount", request.Amount.Int64(), pr.Amount.Int64()),
+		field("currency", request.Currency.String(), pr.Currency.String()),
+		paymentRequestLifecycle.field(event.Event, request.Status.String(), pr.Status.String()),
+		field("request_code", code, pr.RequestCode.String()),
+	)
+	if err != nil {
+		return err
+	}
+
+	return paymentRequestLifecycle.caughtUp(event, code, request.Status.String(), pr.Status.String())
+}
+
+// checkResponse turns a failed fetch into an error. Failures are not reported as
+// mismatches, since they are as likely to be caused by an outage or a misconfigured key as
+// by a forged event; the event is retried instead.
+func checkResponse[T any](event *Event, reference string, rsp *types.Response[T], err error) error {
+	if err == nil && rsp != nil {
+		err = rsp.Err()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch %s %s for reconciliation: %w", event.Event, reference, err)
+	}
+
+	return nil
+}
+
+// field returns a Mismatch when the webhook and API values differ, or nil. Strings are
+// compared case-insensitively, since currencies and statuses differ in case between
+// endpoints.
+func field[T int64 | string](name string, webhook, api T) *Mismatch {
+	w, a := fmt.Sprint(webhook), fmt.Sprint(api)
+
+	if strings.EqualFold(w, a) {
+		return nil
+	}
+
+	return &Mismatch{Field: name, Webhook: w, API: a}
+}
+
+func compare(event *Event, reference string, fields ...*Mismatch) error {
+	var mismatches []Mismatch
+	for _, m := range fields {
+		if m != nil {
+			mismatches = append(mismatches, *m)
+		}
+	}
+
+	if len(mismatches) == 0 {
+		return nil
+	}
+
+	return &MismatchError{
+		Event:      event.Event,
+		Reference:  reference,
+		Mismatches: mismatches,
+	}
+}
+
+// stage is the point an object has reached in its lifecycle
+type stage int
+
+const (
+	// stageOpen covers statuses that can still change, and unknown statuses
+	stageOpen stage = iota
+	stageSucceeded
+	stageFailed
+	// stageReversed follows a success, or for transfers a failure, when the money is
+	// returned
+	stageReversed
+)
+
+// lifecycle maps the statuses of an object to the stage they represent
+type lifecycle map[string]stage
+
+var (
+	transactionLifecycle = lifecycle{
+		"success":   stageSucceeded,
+		"failed":    stageFailed,
+		"abandoned": stageFailed,
+		"reversed":  stageReversed,
+	}
+	transferLifecycle = lifecycle{
+		"success":   stageSucceeded,
+		"failed":    stageFailed,
+		"abandoned": stageFailed,
+		"blocked":   stageFailed,
+		"rejected":  stageFailed,
+		"reversed":  stageReversed,
+	}
+	refundLifecycle = lifecycle{
+		"processed": stageSucceeded,
+		"failed":    stageFailed,
+	}
+	paymentRequestLifecycle = lifecycle{
+		"success": stageSucceeded,
+		"paid":    stageSucceeded,
+	}
+)
+
+// eventStages are the stages announced by event types. Events that report a status that
+// can still change, such as refund.pending, are not listed.
+var eventStages = map[string]stage{
+	EventChargeSuccess:         stageSucceeded,
+	EventTransferSuccess:       stageSucceeded,
+	EventTransferFailed:        stageFailed,
+	EventTransferReversed:      stageReversed,
+	EventRefundProcessed:       stageSucceeded,
+	EventRefundFailed:          stageFailed,
+	EventPaymentRequestSuccess: stageSucceeded,
+}
+
+// field returns a Mismatch when the stage announced by the event type, or the status in its
+// payload, contradicts the status returned by the API: a success against a failure. Later
+// stages in the API are expected, since events can arrive late.
+func (l lifecycle) field(eventType, webhook, api string) *Mismatch {
+	a := l.stage(api)
+
+	if contradicts(l.stage(webhook), a) {
+		return &Mismatch{Field: "status", Webhook: webhook, API: api}
+	}
+	if contradicts(eventStages[eventType], a) {
+		return &Mismatch{Field: "status", Webhook: eventType, API: api}
+	}
+
+	return nil
+}
+
+// caughtUp returns an error when the API has not reached the stage announced by the event
+// type or reported in its payload, e.g. a refund.processed for a refund the API still
+// reports as processing. The error is not a mismatch, so the event is retried.
+func (l lifecycle) caughtUp(event *Event, reference, webhook, api string) error {
+	a := l.stage(api)
+
+	if behind(eventStages[event.Event], a) || (!strings.EqualFold(webhook, api) && behind(l.stage(webhook), a)) {
+		return fmt.Errorf("cannot reconcile %s %s yet: the API reports status %q", event.Event, reference, api)
+	}
+
+	return nil
+}
+
+func (l lifecycle) stage(status string) stage {
+	return l[strings.ToLower(status)]
+}
+
+// contradicts reports whether the webhook and API stages cannot both be true
+func contradicts(webhook, api stage) bool {
+	return webhook != stageOpen && api != stageOpen && webhook != api && webhook != stageReversed && api != stageReversed
+}
+
+// behind reports whether the API has yet to reach the webhook's stage. Contradictions are
+// reported by field instead.
+func behind(webhook, api stage) bool {
+	if webhook == stageOpen || webhook == api || api == stageReversed {
+		return false
+	}
+
+	return webhook == stageReversed || api == stageOpen
+}
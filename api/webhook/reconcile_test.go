@@ -0,0 +1,468 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/huysamen/paystack-go/api/paymentrequests"
+	"github.com/huysamen/paystack-go/api/refunds"
+	"github.com/huysamen/paystack-go/api/transactions"
+	"github.com/huysamen/paystack-go/api/transfers"
+	"github.com/huysamen/paystack-go/enums"
+	"github.com/huysamen/paystack-go/types"
+	"github.com/huysamen/paystack-go/types/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAPI serves the objects behind the webhook fixtures, as the API would return them
+type fakeAPI struct {
+	transaction    types.Transaction
+	transfer       types.Transfer
+	refund         refunds.FetchResponseData
+	paymentRequest paymentrequests.VerifyResponseData
+	err            error
+	notFound       bool
+	fetched        []string
+}
+
+func newFakeAPI() *fakeAPI {
+	return &fakeAPI{
+		transaction: types.Transaction{
+			Amount:    data.NewInt(10000),
+			Currency:  enums.CurrencyNGN,
+			Status:    enums.TransactionStatusSuccess,
+			Reference: data.NewString("qTPrJoy9Bx"),
+		},
+		transfer: types.Transfer{
+			Amount:    data.NewInt(30000),
+			Currency:  enums.CurrencyNGN,
+			Status:    enums.TransferStatusSuccess,
+			Reference: data.NewString("1jhbs3ozmen0k7y5efmw"),
+		},
+		refund: refunds.FetchResponseData{
+			Amount:   data.NewInt(5000),
+			Currency: enums.CurrencyNGN,
+			Status:   enums.RefundStatusProcessed,
+		},
+		paymentRequest: paymentrequests.VerifyResponseData{
+			Amount:      data.NewInt(10000000),
+			Currency:    data.NewString("NGN"),
+			Status:      data.NewString("success"),
+			RequestCode: data.NewString("PRQ_y0paeo93jh99mho"),
+		},
+	}
+}
+
+func respond[T any](f *fakeAPI, id string, v T) (*types.Response[T], error) {
+	f.fetched = append(f.fetched, id)
+
+	if f.err != nil {
+		return nil, f.err
+	}
+	if f.notFound {
+		return &types.Response[T]{Message: "not found"}, nil
+	}
+
+	return &types.Response[T]{Status: data.NewBool(true), Data: v}, nil
+}
+
+type fakeTransactions struct{ *fakeAPI }
+
+func (f fakeTransactions) Verify(_ context.Context, reference string) (*transactions.VerifyResponse, error) {
+	return respond(f.fakeAPI, reference, f.transaction)
+}
+
+type fakeTransfers struct{ *fakeAPI }
+
+func (f fakeTransfers) Verify(_ context.Context, reference string) (*transfers.VerifyResponse, error) {
+	return respond(f.fakeAPI, reference, f.transfer)
+}
+
+type fakeRefunds struct{ *fakeAPI }
+
+func (f fakeRefunds) Fetch(_ context.Context, refundID string) (*refunds.FetchResponse, error) {
+	return respond(f.fakeAPI, refundID, f.refund)
+}
+
+type fakePaymentRequests struct{ *fakeAPI }
+
+func (f fakePaymentRequests) Verify(_ context.Context, code string) (*paymentrequests.VerifyResponse, error) {
+	return respond(f.fakeAPI, code, f.paymentRequest)
+}
+
+func (f *fakeAPI) reconciler() *Reconciler {
+	return NewReconciler(fakeTransactions{f}, fakeTransfers{f}, fakeRefunds{f}, fakePaymentRequests{f})
+}
+
+func TestReconciler_MatchingEvents(t *testing.T) {
+	tests := []struct {
+		fixture string
+		fetched string
+		setup   func(api *fakeAPI)
+	}{
+		{fixture: "charge.success", fetched: "qTPrJoy9Bx"},
+		{fixture: "transfer.success", fetched: "1jhbs3ozmen0k7y5efmw"},
+		{
+			fixture: "transfer.failed",
+			fetched: "1976435206",
+			setup: func(api *fakeAPI) {
+				api.transfer.Amount = data.NewInt(200000)
+				api.transfer.Status = enums.TransferStatusFailed
+				api.transfer.Reference = data.NewString("1976435206")
+			},
+		},
+		{fixture: "refund.processed", fetched: "132013318360"},
+		{fixture: "paymentrequest.success", fetched: "PRQ_y0paeo93jh99mho"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			api := newFakeAPI()
+			if tt.setup != nil {
+				tt.setup(api)
+			}
+
+			require.NoError(t, api.reconciler().Reconcile(context.Background(), readEventFixture(t, tt.fixture)))
+			assert.Equal(t, []string{tt.fetched}, api.fetched)
+		})
+	}
+}
+
+func TestReconciler_Mismatches(t *testing.T) {
+	tests := []struct {
+		fixture  string
+		setup    func(api *fakeAPI)
+		expected []Mismatch
+	}{
+		{
+			fixture: "charge.success",
+			setup: func(api *fakeAPI) {
+				api.transaction.Amount = data.NewInt(100)
+				api.transaction.Status = enums.TransactionStatusAbandoned
+			},
+			expected: []Mismatch{
+				{Field: "amount", Webhook: "10000", API: "100"},
+				{Field: "status", Webhook: "success", API: "abandoned"},
+			},
+		},
+		{
+			fixture: "transfer.success",
+			setup: func(api *fakeAPI) {
+				api.transfer.Currency = enums.CurrencyGHS
+				api.transfer.Status = enums.TransferStatusReversed
+			},
+			expected: []Mismatch{
+				{Field: "currency", Webhook: "NGN", API: "GHS"},
+			},
+		},
+		{
+			fixture: "refund.processed",
+			setup: func(api *fakeAPI) {
+				api.refund.Status = enums.RefundStatusFailed
+			},
+			expected: []Mismatch{
+				{Field: "status", Webhook: "processed", API: "failed"},
+			},
+		},
+		{
+			fixture: "paymentrequest.success",
+			setup: func(api *fakeAPI) {
+				api.paymentRequest.Amount = data.NewInt(1)
+				api.paymentRequest.RequestCode = data.NewString("PRQ_other")
+			},
+			expected: []Mismatch{
+				{Field: "amount", Webhook: "10000000", API: "1"},
+				{Field: "request_code", Webhook: "PRQ_y0paeo93jh99mho", API: "PRQ_other"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			api := newFakeAPI()
+			tt.setup(api)
+
+			err := api.reconciler().Reconcile(context.Background(), readEventFixture(t, tt.fixture))
+			assert.ErrorIs(t, err, ErrReconciliationMismatch)
+
+			var mismatch *MismatchError
+			require.ErrorAs(t, err, &mismatch)
+			assert.Equal(t, tt.fixture, mismatch.Event)
+			assert.Equal(t, tt.expected, mismatch.Mismatches)
+		})
+	}
+}
+
+func TestReconciler_StatusTransitions(t *testing.T) {
+	tests := []struct {
+		name    string
+		fixture string
+		// event and status replace the event type and status of the fixture when set, and
+		// noStatus removes the status
+		event    string
+		status   string
+		noStatus bool
+		setup    func(api *fakeAPI)
+		result   string // "match", "mismatch" or "behind"
+	}{
+		{
+			name:    "charge refunded since",
+			fixture: "charge.success",
+			setup:   func(api *fakeAPI) { api.transaction.Status = enums.TransactionStatusReversed },
+			result:  "match",
+		},
+		{
+			name:    "charge failed",
+			fixture: "charge.success",
+			setup:   func(api *fakeAPI) { api.transaction.Status = enums.TransactionStatusFailed },
+			result:  "mismatch",
+		},
+		{
+			name:    "charge not settled in the API yet",
+			fixture: "charge.success",
+			setup:   func(api *fakeAPI) { api.transaction.Status = enums.TransactionStatusProcessing },
+			result:  "behind",
+		},
+		{
+			name:    "transfer reversed since",
+			fixture: "transfer.success",
+			setup:   func(api *fakeAPI) { api.transfer.Status = enums.TransferStatusReversed },
+			result:  "match",
+		},
+		{
+			name:    "transfer failed",
+			fixture: "transfer.success",
+			setup:   func(api *fakeAPI) { api.transfer.Status = enums.TransferStatusFailed },
+			result:  "mismatch",
+		},
+		{
+			name:    "reversal not in the API yet",
+			fixture: "transfer.success",
+			event:   EventTransferReversed,
+			status:  "reversed",
+			result:  "behind",
+		},
+		{
+			name:    "late refund.pending after processing",
+			fixture: "refund.processed",
+			event:   EventRefundPending,
+			status:  "pending",
+			result:  "match",
+		},
+		{
+			name:    "late refund.processing after processing",
+			fixture: "refund.processed",
+			event:   EventRefundProcessing,
+			status:  "processing",
+			result:  "match",
+		},
+		{
+			name:    "refund.pending for a refund that failed since",
+			fixture: "refund.processed",
+			event:   EventRefundPending,
+			status:  "pending",
+			setup:   func(api *fakeAPI) { api.refund.Status = enums.RefundStatusFailed },
+			result:  "match",
+		},
+		{
+			name:    "refund processed against a failed refund",
+			fixture: "refund.processed",
+			setup:   func(api *fakeAPI) { api.refund.Status = enums.RefundStatusFailed },
+			result:  "mismatch",
+		},
+		{
+			name:    "refund processed before the API",
+			fixture: "refund.processed",
+			setup:   func(api *fakeAPI) { api.refund.Status = enums.RefundStatusProcessing },
+			result:  "behind",
+		},
+		{
+			name:     "charge without a status",
+			fixture:  "charge.success",
+			noStatus: true,
+			result:   "match",
+		},
+		{
+			name:     "charge without a status that failed",
+			fixture:  "charge.success",
+			noStatus: true,
+			setup:    func(api *fakeAPI) { api.transaction.Status = enums.TransactionStatusFailed },
+			result:   "mismatch",
+		},
+		{
+			name:     "charge without a status still pending in the API",
+			fixture:  "charge.success",
+			noStatus: true,
+			setup:    func(api *fakeAPI) { api.transaction.Status = enums.TransactionStatusPending },
+			result:   "behind",
+		},
+		{
+			name:    "charge with an ongoing status that failed",
+			fixture: "charge.success",
+			status:  "ongoing",
+			setup:   func(api *fakeAPI) { api.transaction.Status = enums.TransactionStatusFailed },
+			result:  "mismatch",
+		},
+		{
+			name:     "transfer without a status that failed",
+			fixture:  "transfer.success",
+			noStatus: true,
+			setup:    func(api *fakeAPI) { api.transfer.Status = enums.TransferStatusFailed },
+			result:   "mismatch",
+		},
+		{
+			name:     "transfer without a status still pending in the API",
+			fixture:  "transfer.success",
+			noStatus: true,
+			setup:    func(api *fakeAPI) { api.transfer.Status = enums.TransferStatusPending },
+			result:   "behind",
+		},
+		{
+			name:     "transfer.failed without a status for a successful transfer",
+			fixture:  "transfer.success",
+			event:    EventTransferFailed,
+			noStatus: true,
+			result:   "mismatch",
+		},
+		{
+			name:    "late paymentrequest.pending after payment",
+			fixture: "paymentrequest.success",
+			event:   EventPaymentRequestPending,
+			status:  "pending",
+			result:  "match",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newFakeAPI()
+			if tt.setup != nil {
+				tt.setup(api)
+			}
+
+			event := readEventFixture(t, tt.fixture)
+			if tt.event != "" {
+				event.Event = tt.event
+			}
+			if tt.status != "" || tt.noStatus {
+				var payload map[string]any
+				require.NoError(t, json.Unmarshal(event.Data, &payload))
+				payload["status"] = tt.status
+				if tt.noStatus {
+					delete(payload, "status")
+				}
+
+				b, err := json.Marshal(payload)
+				require.NoError(t, err)
+				event.Data = b
+			}
+
+			err := api.reconciler().Reconcile(context.Background(), event)
+
+			switch tt.result {
+			case "match":
+				assert.NoError(t, err)
+			case "mismatch":
+				var mismatch *MismatchError
+				require.ErrorAs(t, err, &mismatch)
+				require.Len(t, mismatch.Mismatches, 1)
+				assert.Equal(t, "status", mismatch.Mismatches[0].Field)
+			case "behind":
+				assert.ErrorContains(t, err, "cannot reconcile")
+				assert.NotErrorIs(t, err, ErrReconciliationMismatch, "the event is retried until the API catches up")
+			}
+		})
+	}
+}
+
+func TestReconciler_FetchFailures(t *testing.T) {
+	ctx := context.Background()
+	event := readEventFixture(t, "charge.success")
+
+	api := newFakeAPI()
+	api.err = errors.New("connection refused")
+	err := api.reconciler().Reconcile(ctx, event)
+	assert.ErrorContains(t, err, "connection refused")
+	assert.NotErrorIs(t, err, ErrReconciliationMismatch, "outages are retried, not reported as mismatches")
+
+	api = newFakeAPI()
+	api.notFound = true
+	err = api.reconciler().Reconcile(ctx, event)
+	assert.ErrorContains(t, err, "not found")
+	assert.NotErrorIs(t, err, ErrReconciliationMismatch)
+
+	err = NewReconciler(nil, nil, nil, nil).Reconcile(ctx, event)
+	assert.ErrorContains(t, err, "no transaction verifier")
+}
+
+func TestReconciler_SkipsUncoveredEvents(t *testing.T) {
+	api := newFakeAPI()
+	r := api.reconciler()
+
+	assert.False(t, r.Covers(EventSubscriptionCreate))
+	assert.True(t, r.Covers(EventRefundProcessing))
+
+	require.NoError(t, r.Reconcile(context.Background(), readEventFixture(t, "subscription.create")))
+	assert.Empty(t, api.fetched)
+}
+
+func TestHandler_Reconcile(t *testing.T) {
+	api := newFakeAPI()
+
+	var called bool
+	var status int
+	h := NewHandler(NewValidator(testSecret)).
+		Reconcile(api.reconciler()).
+		OnChargeSuccess(func(context.Context, *ChargeSuccessEvent) error {
+			called = true
+			return nil
+		}).
+		OnError(func(r *http.Request, e *Event, s int, err error) {
+			status = s
+		})
+
+	rec := serve(h, newSignedRequest(t, readFixtureBytes(t, "charge.success")))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, called)
+
+	called = false
+	api.transaction.Amount = data.NewInt(1)
+
+	rec = serve(h, newSignedRequest(t, readFixtureBytes(t, "charge.success")))
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, status)
+	assert.False(t, called, "handlers do not see events that fail reconciliation")
+
+	api.fetched = nil
+	rec = serve(h, newSignedRequest(t, readFixtureBytes(t, "transfer.success")))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, api.fetched, "events without a handler are not reconciled")
+}
+
+func TestProcessor_DeadLettersMismatches(t *testing.T) {
+	ctx := context.Background()
+
+	api := newFakeAPI()
+	api.transaction.Currency = enums.CurrencyUSD
+
+	h := NewHandler(NewValidator(testSecret)).
+		Reconcile(api.reconciler()).
+		OnChargeSuccess(func(context.Context, *ChargeSuccessEvent) error { return nil })
+
+	p, queue, deadLetters, _ := newTestProcessor(h)
+	require.NoError(t, p.Enqueue(ctx, readEventFixture(t, "charge.success")))
+
+	_, err := p.ProcessNext(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, queue.Len())
+
+	dead, err := deadLetters.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, dead, 1)
+	assert.Equal(t, 1, dead[0].Attempts)
+	assert.Contains(t, dead[0].LastError, "currency")
+}
@@ -0,0 +1,505 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/huysamen/paystack-go/api/disputes"
+	"github.com/huysamen/paystack-go/api/refunds"
+	"github.com/huysamen/paystack-go/api/transactions"
+	"github.com/huysamen/paystack-go/api/transfers"
+	"github.com/huysamen/paystack-go/enums"
+	"github.com/huysamen/paystack-go/types"
+	"github.com/huysamen/paystack-go/types/data"
+)
+
+const (
+	// DefaultRecoveryInterval is how often EventPoller.Run polls by default
+	DefaultRecoveryInterval = 5 * time.Minute
+	// DefaultRecoveryLookback is how far before its checkpoint an EventPoller lists objects
+	// by default
+	DefaultRecoveryLookback = 24 * time.Hour
+	// DefaultRecoveryPageSize is the number of objects an EventPoller requests per page
+	DefaultRecoveryPageSize = 100
+)
+
+// ErrDeduplicatorRequired is returned by EventPoller when its Handler has no Deduplicator
+var ErrDeduplicatorRequired = errors.New("event poller requires a handler with a deduplicator")
+
+// Checkpoint sources of an EventPoller
+const (
+	RecoverTransactions = "transactions"
+	RecoverTransfers    = "transfers"
+	RecoverRefunds      = "refunds"
+	RecoverDisputes     = "disputes"
+)
+
+// TransactionLister lists transactions. It is implemented by *transactions.Client.
+type TransactionLister interface {
+	List(ctx context.Context, builder transactions.ListRequestBuilder) (*transactions.ListResponse, error)
+}
+
+// TransferLister lists transfers. It is implemented by *transfers.Client.
+type TransferLister interface {
+	List(ctx context.Context, builder transfers.ListRequestBuilder) (*transfers.ListResponse, error)
+}
+
+// RefundLister lists refunds. It is implemented by *refunds.Client.
+type RefundLister interface {
+	List(ctx context.Context, builder refunds.ListRequestBuilder) (*refunds.ListResponse, error)
+}
+
+// DisputeLister lists disputes. It is implemented by *disputes.Client.
+type DisputeLister interface {
+	List(ctx context.Context, builder disputes.ListRequestBuilder) (*disputes.ListResponse, error)
+}
+
+// EventPoller recovers webhooks that were never delivered, e.g. during a deploy. It lists
+// recent transactions, transfers, refunds and disputes, synthesizes the events Paystack
+// would have sent for them and dispatches those to the Handler's typed handlers:
+//
+//   - charge.success for successful transactions
+//   - transfer.success, transfer.failed and transfer.reversed for finished transfers
+//   - refund.pending, refund.processing, refund.processed and refund.failed for refunds
+//   - charge.dispute.create for new disputes and charge.dispute.resolve for resolved ones
+//
+// Only the current state of each object is known, so intermediate events such as
+// refund.pending for a refund that has since been processed are not synthesized.
+//
+// The Handler must have a Deduplicator. Its StateKey check skips recovered events whose
+// webhook was already processed, and events recovered by an earlier poll.
+//
+// The list endpoints filter by creation date, so each poll lists from the saved checkpoint
+// minus the lookback. Objects that change state longer than the lookback after they were
+// created are not recovered.
+type EventPoller struct {
+	handler      *Handler
+	checkpoints  CheckpointStore
+	transactions TransactionLister
+	transfers    TransferLister
+	refunds      RefundLister
+	disputes     DisputeLister
+	lookback     time.Duration
+	interval     time.Duration
+	pageSize     int
+	onError      func(err error)
+	now          func() time.Time
+}
+
+// NewEventPoller creates an EventPoller that dispatches recovered events with handler and
+// saves its progress in checkpoints. Enable sources with Transactions, Transfers, Refunds
+// and Disputes.
+func NewEventPoller(handler *Handler, checkpoints CheckpointStore) *EventPoller {
+	return &EventPoller{
+		handler:     handler,
+		checkpoints: checkpoints,
+		lookback:    DefaultRecoveryLookback,
+		interval:    DefaultRecoveryInterval,
+		pageSize:    DefaultRecoveryPageSize,
+		now:         time.Now,
+	}
+}
+
+// Transactions recovers charge.success events with l
+func (p *EventPoller) Transactions(l TransactionLister) *EventPoller {
+	p.transactions = l
+
+	return p
+}
+
+// Transfers recovers transfer events with l
+func (p *EventPoller) Transfers(l TransferLister) *EventPoller {
+	p.transfers = l
+
+	return p
+}
+
+// Refunds recovers refund events with l
+func (p *EventPoller) Refunds(l RefundLister) *EventPoller {
+	p.refunds = l
+
+	return p
+}
+
+// Disputes recovers dispute events with l
+func (p *EventPoller) Disputes(l DisputeLister) *EventPoller {
+	p.disputes = l
+
+	return p
+}
+
+// Lookback sets how far before the checkpoint objects are listed
+func (p *EventPoller) Lookback(d time.Duration) *EventPoller {
+	p.lookback = d
+
+	return p
+}
+
+// Interval sets how often Run polls
+func (p *EventPoller) Interval(d time.Duration) *EventPoller {
+	p.interval = d
+
+	return p
+}
+
+// PageSize sets the number of objects requested per page
+func (p *EventPoller) PageSize(n int) *EventPoller {
+	p.pageSize = n
+
+	return p
+}
+
+// OnError sets a function that is called with the errors of polls made by Run
+func (p *EventPoller) OnError(fn func(err error)) *EventPoller {
+	p.onError = fn
+
+	return p
+}
+
+// PollOnce polls every enabled source once and returns the number of recovered events
+// that were dispatched. A source's checkpoint is only saved once all its events were
+// handled, so events whose handler fails are recovered again by the next poll.
+func (p *EventPoller) PollOnce(ctx context.Context) (int, error) {
+	if p.handler.dedup == nil {
+		return 0, ErrDeduplicatorRequired
+	}
+
+	sources := []struct {
+		name   string
+		events func(ctx context.Context, from time.Time) ([]*Event, error)
+	}{
+		{RecoverTransactions, p.transactionEvents},
+		{RecoverTransfers, p.transferEvents},
+		{RecoverRefunds, p.refundEvents},
+		{RecoverDisputes, p.disputeEvents},
+	}
+
+	var (
+		dispatched int
+		errs       []error
+	)
+
+	for _, source := range sources {
+		n, err := p.poll(ctx, source.name, source.events)
+		dispatched += n
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("recovering %s: %w", source.name, err))
+		}
+	}
+
+	return dispatched, errors.Join(errs...)
+}
+
+func (p *EventPoller) poll(ctx context.Context, source string, list func(context.Context, time.Time) ([]*Event, error)) (int, error) {
+	start := p.now().UTC()
+
+	checkpoint, err := p.checkpoints.Load(ctx, source)
+	if err != nil {
+		return 0, err
+	}
+	if checkpoint.IsZero() {
+		checkpoint = start
+	}
+
+	events, err := list(ctx, checkpoint.Add(-p.lookback).UTC())
+	if err != nil {
+		return 0, err
+	}
+	if events == nil {
+		// The source is not enabled
+		return 0, nil
+	}
+
+	dispatched := 0
+	for _, event := range events {
+		err := p.handler.dedup.Do(WithEvent(ctx, event), event, p.handler.dispatch)
+		if errors.Is(err, ErrDuplicateEvent) {
+			continue
+		}
+		if err != nil {
+			return dispatched, fmt.Errorf("%s event: %w", event.Event, err)
+		}
+
+		dispatched++
+	}
+
+	return dispatched, p.checkpoints.Save(ctx, source, start)
+}
+
+// Run polls every interval until ctx is cancelled, then returns ctx.Err(). Poll errors are
+// reported to the OnError function and polling continues.
+func (p *EventPoller) Run(ctx context.Context) error {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		if _, err := p.PollOnce(ctx); err != nil && p.onError != nil && ctx.Err() == nil {
+			p.onError(err)
+		}
+
+		timer.Reset(p.interval)
+	}
+}
+
+func (p *EventPoller) transactionEvents(ctx context.Context, from time.Time) ([]*Event, error) {
+	if p.transactions == nil {
+		return nil, nil
+	}
+
+	txs, err := listPages(p.pageSize, func(page int) (*transactions.ListResponse, error) {
+		return p.transactions.List(ctx, *transactions.NewListRequestBuilder().From(from).PerPage(p.pageSize).Page(page))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(txs, func(i, j int) bool {
+		return time.Time(txs[i].CreatedAt).Before(time.Time(txs[j].CreatedAt))
+	})
+
+	events := []*Event{}
+	for _, tx := range txs {
+		if tx.Status != enums.TransactionStatusSuccess {
+			continue
+		}
+
+		event, err := synthesize(EventChargeSuccess, tx)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+func (p *EventPoller) transferEvents(ctx context.Context, from time.Time) ([]*Event, error) {
+	if p.transfers == nil {
+		return nil, nil
+	}
+
+	list, err := listPages(p.pageSize, func(page int) (*transfers.ListResponse, error) {
+		return p.transfers.List(ctx, *transfers.NewListRequestBuilder().From(from).PerPage(p.pageSize).Page(page))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		return time.Time(list[i].CreatedAt).Before(time.Time(list[j].CreatedAt))
+	})
+
+	events := []*Event{}
+	for _, t := range list {
+		var name string
+
+		switch t.Status {
+		case enums.TransferStatusSuccess:
+			name = EventTransferSuccess
+		case enums.TransferStatusFailed:
+			name = EventTransferFailed
+		case enums.TransferStatusReversed:
+			name = EventTransferReversed
+		default:
+			continue
+		}
+
+		event, err := synthesize(name, t)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+func (p *EventPoller) refundEvents(ctx context.Context, from time.Time) ([]*Event, error) {
+	if p.refunds == nil {
+		return nil, nil
+	}
+
+	list, err := listPages(p.pageSize, func(page int) (*refunds.ListResponse, error) {
+		return p.refunds.List(ctx, *refunds.NewListRequestBuilder().From(from).PerPage(p.pageSize).Page(page))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		return time.Time(list[i].CreatedAt).Before(time.Time(list[j].CreatedAt))
+	})
+
+	events := []*Event{}
+	for _, r := range list {
+		var name string
+
+		switch r.Status {
+		case enums.RefundStatusPending:
+			name = EventRefundPending
+		case enums.RefundStatusProcessing:
+			name = EventRefundProcessing
+		case enums.RefundStatusProcessed:
+			name = EventRefundProcessed
+		case enums.RefundStatusFailed:
+			name = EventRefundFailed
+		default:
+			continue
+		}
+
+		event, err := synthesize(name, refundEvent(r))
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+func (p *EventPoller) disputeEvents(ctx context.Context, from time.Time) ([]*Event, error) {
+	if p.disputes == nil {
+		return nil, nil
+	}
+
+	list, err := listPages(p.pageSize, func(page int) (*disputes.ListResponse, error) {
+		return p.disputes.List(ctx, *disputes.NewListRequestBuilder().From(from).PerPage(p.pageSize).Page(page))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		return time.Time(list[i].CreatedAt).Before(time.Time(list[j].CreatedAt))
+	})
+
+	events := []*Event{}
+	for _, d := range list {
+		names := []string{EventChargeDisputeCreate}
+		if d.Status == enums.DisputeStatusResolved || d.ResolvedAt.Valid {
+			names = append(names, EventChargeDisputeResolve)
+		}
+
+		for _, name := range names {
+			event, err := synthesize(name, disputeEvent(d))
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, event)
+		}
+	}
+
+	return events, nil
+}
+
+// listPages requests pages of size pageSize until a short or final page
+func listPages[T any](pageSize int, list func(page int) (*types.Response[[]T], error)) ([]T, error) {
+	var all []T
+
+	for page := 1; ; page++ {
+		rsp, err := list(page)
+		if err := checkPolled(rsp, err); err != nil {
+			return nil, err
+		}
+
+		all = append(all, rsp.Data...)
+
+		if len(rsp.Data) < pageSize {
+			return all, nil
+		}
+		if meta := rsp.Meta; meta != nil && meta.PageCount.Valid && int64(page) >= meta.PageCount.Int {
+			return all, nil
+		}
+	}
+}
+
+// synthesize wraps v as the data of a webhook event. The API objects marshal to the same
+// shape as the webhook payloads, which differ only in fields the events do not carry.
+func synthesize(name string, v any) (*Event, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal recovered %s event: %w", name, err)
+	}
+
+	return &Event{Event: name, Data: b}, nil
+}
+
+// refundEvent converts a listed refund to the payload of a refund event. All refund events
+// share this shape.
+func refundEvent(r refunds.ListRefund) *RefundPendingEvent {
+	event := &RefundPendingEvent{
+		ID:             r.ID,
+		Integration:    r.Integration,
+		Domain:         r.Domain,
+		Transaction:    &types.Transaction{ID: data.NewUint(uint64(r.Transaction.Int64()))},
+		Amount:         r.Amount,
+		DeductedAmount: data.NewInt(r.DeductedAmount.Int),
+		FullyDeducted:  data.NewBool(r.FullyDeducted.Bool),
+		Currency:       data.NewString(r.Currency.String()),
+		Status:         data.NewString(r.Status.String()),
+		RefundedBy:     r.RefundedBy,
+		RefundedAt:     r.RefundedAt,
+		CreatedAt:      r.CreatedAt,
+		UpdatedAt:      r.UpdatedAt,
+	}
+
+	if r.Dispute.Valid {
+		event.Dispute = &types.Dispute{ID: data.NewInt(r.Dispute.Int)}
+	}
+	if r.ExpectedAt.Valid {
+		event.ExpectedAt = data.NewTime(r.ExpectedAt.Time)
+	}
+
+	return event
+}
+
+// disputeEvent converts a listed dispute to the payload of a dispute event
+func disputeEvent(d types.Dispute) *ChargeDisputeEvent {
+	event := &ChargeDisputeEvent{
+		ID:                   d.ID,
+		RefundAmount:         data.NewInt(d.RefundAmount.Int),
+		Status:               data.NewString(d.Status.String()),
+		Domain:               d.Domain,
+		Transaction:          d.Transaction,
+		TransactionReference: d.TransactionReference,
+		Customer:             d.Customer,
+		BIN:                  data.NewString(d.BIN.Str),
+		Last4:                data.NewString(d.LastFour.Str),
+		DueAt:                d.DueAt,
+		ResolvedAt:           d.ResolvedAt,
+		Evidence:             d.Evidence,
+		Attachments:          d.Attachments,
+		Note:                 d.Note,
+		CreatedAt:            d.CreatedAt,
+		UpdatedAt:            d.UpdatedAt,
+	}
+
+	if d.Currency != nil {
+		event.Currency = data.NewString(d.Currency.String())
+	}
+	if d.Resolution != nil {
+		event.Resolution = data.NewNullString(d.Resolution.String())
+	}
+	if d.Category != nil {
+		event.Category = data.NewString(d.Category.String())
+	}
+	for _, h := range d.History {
+		event.History = append(event.History, DisputeHistoryEntry{Status: data.NewString(h.Status.String()), By: h.By, CreatedAt: h.CreatedAt})
+	}
+	for _, m := range d.Messages {
+		event.Messages = append(event.Messages, DisputeMessage{Sender: m.Sender, Body: m.Body, CreatedAt: m.CreatedAt})
+	}
+
+	return event
+}
@@ -0,0 +1,369 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/huysamen/paystack-go/api/disputes"
+	"github.com/huysamen/paystack-go/api/refunds"
+	"github.com/huysamen/paystack-go/api/transactions"
+	"github.com/huysamen/paystack-go/api/transfers"
+	"github.com/huysamen/paystack-go/enums"
+	"github.com/huysamen/paystack-go/types"
+	"github.com/huysamen/paystack-go/types/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pagedList serves items in pages of size, starting over after the last page
+type pagedList[T any] struct {
+	mu    sync.Mutex
+	items []T
+	size  int
+	page  int
+	calls int
+	err   error
+}
+
+func (l *pagedList[T]) next() (*types.Response[[]T], error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.calls++
+	if l.err != nil {
+		return nil, l.err
+	}
+
+	start := min(l.page*l.size, len(l.items))
+	end := min(start+l.size, len(l.items))
+	page := l.items[start:end]
+
+	l.page++
+	if len(page) < l.size {
+		l.page = 0
+	}
+
+	return &types.Response[[]T]{Status: data.NewBool(true), Data: page}, nil
+}
+
+type transactionList struct{ *pagedList[types.Transaction] }
+
+func (l transactionList) List(context.Context, transactions.ListRequestBuilder) (*transactions.ListResponse, error) {
+	return l.next()
+}
+
+type transferList struct{ *pagedList[types.Transfer] }
+
+func (l transferList) List(context.Context, transfers.ListRequestBuilder) (*transfers.ListResponse, error) {
+	return l.next()
+}
+
+type refundList struct{ *pagedList[refunds.ListRefund] }
+
+func (l refundList) List(context.Context, refunds.ListRequestBuilder) (*refunds.ListResponse, error) {
+	return l.next()
+}
+
+type disputeList struct{ *pagedList[types.Dispute] }
+
+func (l disputeList) List(context.Context, disputes.ListRequestBuilder) (*disputes.ListResponse, error) {
+	return l.next()
+}
+
+func at(minute int) data.Time {
+	return data.NewTime(time.Date(2024, 5, 1, 12, minute, 0, 0, time.UTC))
+}
+
+// recordingHandler returns a deduplicating Handler that records the events it handles
+func recordingHandler() (*Handler, *[]string) {
+	var handled []string
+	record := func(_ context.Context, e *Event) error {
+		handled = append(handled, e.Event+":"+StateKey(e))
+		return nil
+	}
+
+	h := NewHandler(NewValidator(testSecret)).
+		Deduplicate(NewDeduplicator(NewMemoryDedupStore(), time.Hour)).
+		Fallback(record)
+
+	return h, &handled
+}
+
+func TestEventPoller_SynthesizesEvents(t *testing.T) {
+	ctx := context.Background()
+
+	var (
+		charge   *ChargeSuccessEvent
+		transfer *TransferReversedEvent
+		refund   *RefundPendingEvent
+		dispute  *ChargeDisputeEvent
+		resolved bool
+	)
+
+	h := NewHandler(NewValidator(testSecret)).
+		Deduplicate(NewDeduplicator(NewMemoryDedupStore(), time.Hour)).
+		OnChargeSuccess(func(_ context.Context, e *ChargeSuccessEvent) error {
+			charge = e
+			return nil
+		}).
+		OnTransferReversed(func(_ context.Context, e *TransferReversedEvent) error {
+			transfer = e
+			return nil
+		}).
+		OnRefundProcessing(func(_ context.Context, e *RefundPendingEvent) error {
+			refund = e
+			return nil
+		}).
+		OnChargeDisputeCreate(func(_ context.Context, e *ChargeDisputeEvent) error {
+			dispute = e
+			return nil
+		}).
+		OnChargeDisputeResolve(func(context.Context, *ChargeDisputeEvent) error {
+			resolved = true
+			return nil
+		})
+
+	txs := &pagedList[types.Transaction]{size: 10, items: []types.Transaction{
+		{ID: data.NewUint(1), Reference: data.NewString("abandoned"), Status: enums.TransactionStatusAbandoned, CreatedAt: at(1)},
+		{ID: data.NewUint(2), Reference: data.NewString("paid"), Amount: data.NewInt(5000), Currency: enums.CurrencyNGN, Status: enums.TransactionStatusSuccess, CreatedAt: at(2)},
+	}}
+	trfs := &pagedList[types.Transfer]{size: 10, items: []types.Transfer{
+		{ID: data.NewInt(3), Reference: data.NewString("pending"), Status: enums.TransferStatusPending, CreatedAt: at(1)},
+		{ID: data.NewInt(4), Reference: data.NewString("returned"), Amount: data.NewInt(700), Status: enums.TransferStatusReversed, CreatedAt: at(2)},
+	}}
+	rfs := &pagedList[refunds.ListRefund]{size: 10, items: []refunds.ListRefund{
+		{ID: data.NewInt(5), Transaction: data.NewInt(2), Amount: data.NewInt(2500), Currency: enums.CurrencyNGN, Status: enums.RefundStatusProcessing, CreatedAt: at(3)},
+	}}
+	dps := &pagedList[types.Dispute]{size: 10, items: []types.Dispute{
+		{ID: data.NewInt(6), Status: enums.DisputeStatusAwaitingMerchantFeedback, TransactionReference: data.NewNullString("paid"), CreatedAt: at(4)},
+	}}
+
+	p := NewEventPoller(h, NewMemoryCheckpointStore()).
+		Transactions(transactionList{txs}).
+		Transfers(transferList{trfs}).
+		Refunds(refundList{rfs}).
+		Disputes(disputeList{dps})
+
+	n, err := p.PollOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 4, n)
+
+	require.NotNil(t, charge)
+	assert.Equal(t, "paid", charge.Reference.String())
+	assert.Equal(t, int64(5000), charge.Amount.Int64())
+
+	require.NotNil(t, transfer)
+	assert.Equal(t, "returned", transfer.Reference.String())
+	assert.Equal(t, "reversed", transfer.Status.String())
+
+	require.NotNil(t, refund)
+	assert.Equal(t, int64(5), refund.ID.Int64())
+	assert.Equal(t, "processing", refund.Status.String())
+	assert.Equal(t, "NGN", refund.Currency.String())
+	require.NotNil(t, refund.Transaction)
+	assert.Equal(t, uint64(2), refund.Transaction.ID.Uint64())
+
+	require.NotNil(t, dispute)
+	assert.Equal(t, int64(6), dispute.ID.Int64())
+	assert.Equal(t, "paid", dispute.TransactionReference.Str)
+	assert.False(t, resolved)
+
+	// Nothing is dispatched twice, even as objects change
+	dps.items[0].Status = enums.DisputeStatusResolved
+
+	n, err = p.PollOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.True(t, resolved)
+}
+
+func TestEventPoller_SkipsDeliveredWebhooks(t *testing.T) {
+	ctx := context.Background()
+	h, handled := recordingHandler()
+
+	// The webhook for qTPrJoy9Bx was delivered before the poll
+	require.Equal(t, 200, serve(h, newSignedRequest(t, readFixtureBytes(t, "charge.success"))).Code)
+
+	txs := &pagedList[types.Transaction]{size: 10, items: []types.Transaction{
+		{ID: data.NewUint(302961), Reference: data.NewString("qTPrJoy9Bx"), Status: enums.TransactionStatusSuccess, CreatedAt: at(1)},
+		{ID: data.NewUint(302962), Reference: data.NewString("missed"), Status: enums.TransactionStatusSuccess, CreatedAt: at(2)},
+	}}
+
+	n, err := NewEventPoller(h, NewMemoryCheckpointStore()).Transactions(transactionList{txs}).PollOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, []string{
+		"charge.success:state:charge.success:qTPrJoy9Bx",
+		"charge.success:state:charge.success:missed",
+	}, *handled)
+
+	// A webhook delivered after its event was recovered is a duplicate
+	rec := serve(h, newSignedRequest(t, []byte(`{"event":"charge.success","data":{"id":302962,"reference":"missed","status":"success"}}`)))
+	assert.Equal(t, 200, rec.Code)
+	assert.Len(t, *handled, 2)
+}
+
+func TestEventPoller_Checkpoints(t *testing.T) {
+	ctx := context.Background()
+	clock := newTestClock()
+	checkpoints := NewMemoryCheckpointStore()
+
+	h, handled := recordingHandler()
+	failing := true
+	h.OnTransferSuccess(func(context.Context, *TransferSuccessEvent) error {
+		if failing {
+			return errors.New("database unavailable")
+		}
+		*handled = append(*handled, EventTransferSuccess)
+		return nil
+	})
+
+	trfs := &pagedList[types.Transfer]{size: 10, items: []types.Transfer{
+		{Reference: data.NewString("a"), Status: enums.TransferStatusSuccess, CreatedAt: at(1)},
+	}}
+
+	p := NewEventPoller(h, checkpoints).Transfers(transferList{trfs})
+	p.now = clock.now
+
+	_, err := p.PollOnce(ctx)
+	assert.ErrorContains(t, err, "database unavailable")
+	assert.ErrorContains(t, err, "recovering transfers")
+
+	checkpoint, err := checkpoints.Load(ctx, RecoverTransfers)
+	require.NoError(t, err)
+	assert.True(t, checkpoint.IsZero(), "checkpoints are not saved past failed events")
+
+	failing = false
+	clock.advance(time.Minute)
+
+	n, err := p.PollOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, []string{EventTransferSuccess}, *handled)
+
+	checkpoint, err = checkpoints.Load(ctx, RecoverTransfers)
+	require.NoError(t, err)
+	assert.Equal(t, clock.now().UTC(), checkpoint)
+
+	// Sources that are not enabled keep no checkpoint
+	checkpoint, err = checkpoints.Load(ctx, RecoverTransactions)
+	require.NoError(t, err)
+	assert.True(t, checkpoint.IsZero())
+}
+
+func TestEventPoller_Pagination(t *testing.T) {
+	h, handled := recordingHandler()
+
+	var items []types.Transaction
+	for i := 5; i > 0; i-- {
+		ref := string(rune('a' + i - 1))
+		items = append(items, types.Transaction{Reference: data.NewString(ref), Status: enums.TransactionStatusSuccess, CreatedAt: at(i)})
+	}
+	txs := &pagedList[types.Transaction]{size: 2, items: items}
+
+	n, err := NewEventPoller(h, NewMemoryCheckpointStore()).
+		PageSize(2).
+		Transactions(transactionList{txs}).
+		PollOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 5, n)
+	assert.Equal(t, 3, txs.calls)
+
+	// Events are dispatched oldest first
+	assert.Equal(t, "charge.success:state:charge.success:a", (*handled)[0])
+	assert.Equal(t, "charge.success:state:charge.success:e", (*handled)[4])
+}
+
+func TestEventPoller_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := NewEventPoller(NewHandler(NewValidator(testSecret)), NewMemoryCheckpointStore()).PollOnce(ctx)
+	assert.ErrorIs(t, err, ErrDeduplicatorRequired)
+
+	h, _ := recordingHandler()
+	txs := &pagedList[types.Transaction]{size: 10, err: errors.New("connection reset")}
+	trfs := &pagedList[types.Transfer]{size: 10, items: []types.Transfer{
+		{Reference: data.NewString("a"), Status: enums.TransferStatusFailed, CreatedAt: at(1)},
+	}}
+
+	n, err := NewEventPoller(h, NewMemoryCheckpointStore()).
+		Transactions(transactionList{txs}).
+		Transfers(transferList{trfs}).
+		PollOnce(ctx)
+	assert.ErrorContains(t, err, "recovering transactions: connection reset")
+	assert.Equal(t, 1, n, "a failing source does not stop the others")
+}
+
+func TestEventPoller_Run(t *testing.T) {
+	h, _ := recordingHandler()
+	txs := &pagedList[types.Transaction]{size: 10, err: errors.New("connection reset")}
+
+	errs := make(chan error, 10)
+	p := NewEventPoller(h, NewMemoryCheckpointStore()).
+		Transactions(transactionList{txs}).
+		Interval(time.Millisecond).
+		OnError(func(err error) {
+			select {
+			case errs <- err:
+			default:
+			}
+		})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- p.Run(ctx) }()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-errs:
+			assert.ErrorContains(t, err, "connection reset")
+		case <-time.After(5 * time.Second):
+			t.Fatal("poll errors were not reported")
+		}
+	}
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestFileCheckpointStore(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "state", "checkpoints.json")
+
+	store, err := NewFileCheckpointStore(path)
+	require.NoError(t, err)
+
+	checkpoint, err := store.Load(ctx, RecoverTransactions)
+	require.NoError(t, err)
+	assert.True(t, checkpoint.IsZero())
+
+	saved := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, store.Save(ctx, RecoverTransactions, saved))
+	require.NoError(t, store.Save(ctx, RecoverRefunds, saved.Add(time.Hour)))
+
+	// A new store reads what the previous process saved
+	store, err = NewFileCheckpointStore(path)
+	require.NoError(t, err)
+
+	checkpoint, err = store.Load(ctx, RecoverTransactions)
+	require.NoError(t, err)
+	assert.True(t, saved.Equal(checkpoint))
+
+	checkpoint, err = store.Load(ctx, RecoverRefunds)
+	require.NoError(t, err)
+	assert.True(t, saved.Add(time.Hour).Equal(checkpoint))
+}
+
+func TestSynthesize(t *testing.T) {
+	event, err := synthesize(EventTransferSuccess, types.Transfer{Reference: data.NewString("payout-1")})
+	require.NoError(t, err)
+	assert.Equal(t, EventTransferSuccess, event.Event)
+	assert.Equal(t, "state:transfer.success:payout-1", StateKey(event))
+
+	_, err = synthesize(EventChargeSuccess, make(chan int))
+	assert.ErrorContains(t, err, "charge.success")
+}
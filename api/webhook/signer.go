@@ -0,0 +1,63 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// SignatureHeader is the request header carrying the webhook signature
+const SignatureHeader = "x-paystack-signature"
+
+// Signer signs webhook payloads the way Paystack does, the inverse of
+// Validator.ValidateSignature. It is intended for testing webhook handlers.
+type Signer struct {
+	secretKey string
+}
+
+// NewSigner creates a Signer using the given secret key
+func NewSigner(secretKey string) *Signer {
+	return &Signer{
+		secretKey: secretKey,
+	}
+}
+
+// Sign returns the hex encoded HMAC-SHA512 signature of payload
+func (s *Signer) Sign(payload []byte) string {
+	return sign(s.secretKey, payload)
+}
+
+// NewRequest returns a signed POST request delivering event to url
+func (s *Signer) NewRequest(ctx context.Context, url string, event *Event) (*http.Request, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal webhook event: %w", err)
+	}
+
+	return s.NewRequestWithPayload(ctx, url, payload)
+}
+
+// NewRequestWithPayload returns a signed POST request delivering the raw payload to url
+func (s *Signer) NewRequestWithPayload(ctx context.Context, url string, payload []byte) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, s.Sign(payload))
+
+	return req, nil
+}
+
+func sign(secretKey string, payload []byte) string {
+	mac := hmac.New(sha512.New, []byte(secretKey))
+	mac.Write(payload)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
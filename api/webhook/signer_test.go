@@ -0,0 +1,74 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner_Sign(t *testing.T) {
+	tests := []struct {
+		name     string
+		secret   string
+		payload  string
+		expected string
+	}{
+		{
+			name:     "signs payload with HMAC-SHA512",
+			secret:   "key",
+			payload:  "The quick brown fox jumps over the lazy dog",
+			expected: "b42af09057bac1e2d41708e48a902e09b5ff7f12ab428a4fe86653c73dd248fb82f948a549f7b791a5b41915ee4d1ec3935357e4e2317250d0372afa2ebeeb3a",
+		},
+		{
+			name:     "signs empty payload",
+			secret:   "",
+			payload:  "",
+			expected: "b936cee86c9f87aa5d3c6f2e84cb5a4239a5fe50480a6ec66b70ab5b1f4ac6730c6c515421b327ec1d69402e53dfb49ad7381eb067b338fd7b0cb22247225d47",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signature := NewSigner(tt.secret).Sign([]byte(tt.payload))
+			assert.Equal(t, tt.expected, signature)
+			assert.True(t, NewValidator(tt.secret).ValidateSignature([]byte(tt.payload), signature))
+		})
+	}
+}
+
+func TestSigner_NewRequest(t *testing.T) {
+	event := readEventFixture(t, "transfer.success")
+
+	req, err := NewSigner(testSecret).NewRequest(context.Background(), "https://example.com/webhook", event)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.MethodPost, req.Method)
+	assert.Equal(t, "https://example.com/webhook", req.URL.String())
+
+	parsed, err := NewValidator(testSecret).ValidateRequest(req)
+	require.NoError(t, err)
+	assert.Equal(t, EventTransferSuccess, parsed.Event)
+	assert.JSONEq(t, string(event.Data), string(parsed.Data))
+
+	_, err = NewValidator("other_secret").ValidateRequest(req)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestSigner_NewRequestWithPayload(t *testing.T) {
+	payload := []byte(`{"event":"charge.success","data":{}}`)
+
+	req, err := NewSigner(testSecret).NewRequestWithPayload(context.Background(), "https://example.com/webhook", payload)
+	require.NoError(t, err)
+
+	body, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	assert.Equal(t, payload, body)
+	assert.Equal(t, NewSigner(testSecret).Sign(payload), req.Header.Get(SignatureHeader))
+
+	_, err = NewSigner(testSecret).NewRequestWithPayload(context.Background(), "://bad", payload)
+	assert.Error(t, err)
+}
@@ -2,8 +2,8 @@ package webhook
 
 import (
 	"encoding/json"
-
-	"github.com/huysamen/paystack-go/types"
+	"errors"
+	"fmt"
 )
 
 const (
@@ -32,8 +32,16 @@ const (
 	EventTransferFailed                = "transfer.failed"
 	EventTransferReversed              = "transfer.reversed"
 	EventTransferSuccess               = "transfer.success"
+
+	EventBankTransferRejected            = "bank.transfer.rejected"
+	EventDirectDebitAuthorizationActive  = "direct_debit.authorization.active"
+	EventDirectDebitAuthorizationCreated = "direct_debit.authorization.created"
+	EventTransferApprovalRequired        = "transferrequest.approval-required"
 )
 
+// ErrUnknownEvent is returned by Event.Decode for events without a typed payload
+var ErrUnknownEvent = errors.New("unknown webhook event")
+
 type Event struct {
 	Event string          `json:"event"`
 	Data  json.RawMessage `json:"data"`
@@ -120,10 +128,78 @@ func (e *Event) AsSubscriptionNotRenew() (*SubscriptionNotRenewEvent, error) {
 }
 
 func (e *Event) AsSubscriptionExpiringCards() (*SubscriptionExpiringCardsEvent, error) {
-	// Special-case array payload
-	var arr []map[string]any
-	if err := json.Unmarshal(e.Data, &arr); err == nil {
-		return &SubscriptionExpiringCardsEvent{Entries: types.NewMetadata(map[string]any{"entries": arr})}, nil
-	}
 	return ParseEventData[SubscriptionExpiringCardsEvent](e)
 }
+
+func (e *Event) AsDirectDebitAuthorization() (*DirectDebitAuthorizationEvent, error) {
+	return ParseEventData[DirectDebitAuthorizationEvent](e)
+}
+
+func (e *Event) AsTransferApproval() (*TransferApprovalEvent, error) {
+	return ParseEventData[TransferApprovalEvent](e)
+}
+
+func (e *Event) AsBankTransferRejected() (*BankTransferRejectedEvent, error) {
+	return ParseEventData[BankTransferRejectedEvent](e)
+}
+
+// decoders maps each known event to the As* method that decodes its payload
+var decoders = map[string]func(*Event) (any, error){
+	EventChargeSuccess:                   decodeAs((*Event).AsChargeSuccess),
+	EventChargeDisputeCreate:             decodeAs((*Event).AsChargeDispute),
+	EventChargeDisputeRemind:             decodeAs((*Event).AsChargeDispute),
+	EventChargeDisputeResolve:            decodeAs((*Event).AsChargeDispute),
+	EventCustomerIdentificationFailed:    decodeAs((*Event).AsCustomerIdentificationFailed),
+	EventCustomerIdentificationSuccess:   decodeAs((*Event).AsCustomerIdentificationSuccess),
+	EventDedicatedAccountAssignFailed:    decodeAs((*Event).AsDedicatedAccount),
+	EventDedicatedAccountAssignSuccess:   decodeAs((*Event).AsDedicatedAccount),
+	EventInvoiceCreate:                   decodeAs((*Event).AsInvoiceCreate),
+	EventInvoicePaymentFailed:            decodeAs((*Event).AsInvoicePaymentFailed),
+	EventInvoiceUpdate:                   decodeAs((*Event).AsInvoiceUpdate),
+	EventPaymentRequestPending:           decodeAs((*Event).AsPaymentRequest),
+	EventPaymentRequestSuccess:           decodeAs((*Event).AsPaymentRequest),
+	EventRefundFailed:                    decodeAs((*Event).AsRefundFailed),
+	EventRefundPending:                   decodeAs((*Event).AsRefundPending),
+	EventRefundProcessed:                 decodeAs((*Event).AsRefundProcessed),
+	EventRefundProcessing:                decodeAs((*Event).AsRefundPending),
+	EventSubscriptionCreate:              decodeAs((*Event).AsSubscriptionCreate),
+	EventSubscriptionDisable:             decodeAs((*Event).AsSubscriptionDisable),
+	EventSubscriptionExpiringCards:       decodeAs((*Event).AsSubscriptionExpiringCards),
+	EventSubscriptionNotRenew:            decodeAs((*Event).AsSubscriptionNotRenew),
+	EventTransferFailed:                  decodeAs((*Event).AsTransferFailed),
+	EventTransferReversed:                decodeAs((*Event).AsTransferReversed),
+	EventTransferSuccess:                 decodeAs((*Event).AsTransferSuccess),
+	EventBankTransferRejected:            decodeAs((*Event).AsBankTransferRejected),
+	EventDirectDebitAuthorizationActive:  decodeAs((*Event).AsDirectDebitAuthorization),
+	EventDirectDebitAuthorizationCreated: decodeAs((*Event).AsDirectDebitAuthorization),
+	EventTransferApprovalRequired:        decodeAs((*Event).AsTransferApproval),
+}
+
+func decodeAs[T any](as func(*Event) (*T, error)) func(*Event) (any, error) {
+	return func(e *Event) (any, error) {
+		v, err := as(e)
+		if err != nil {
+			return nil, err
+		}
+
+		return v, nil
+	}
+}
+
+// Decode returns the typed payload of a known event, e.g. *ChargeSuccessEvent for
+// charge.success, for use in a type switch. Events without a typed payload return an error
+// wrapping ErrUnknownEvent.
+func (e *Event) Decode() (any, error) {
+	decode, ok := decoders[e.Event]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEvent, e.Event)
+	}
+
+	return decode(e)
+}
+
+// IsKnown returns true if Decode returns a typed payload for the event
+func (e *Event) IsKnown() bool {
+	_, ok := decoders[e.Event]
+	return ok
+}
@@ -3,16 +3,23 @@ package webhook
 import (
 	"bytes"
 	"crypto/hmac"
-	"crypto/sha512"
-	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 )
 
+var (
+	// ErrInvalidSignature is returned when the x-paystack-signature header does not match the payload
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	// ErrInvalidPayload is returned when the webhook body is not a valid event envelope
+	ErrInvalidPayload = errors.New("invalid webhook payload")
+)
+
 type Validator struct {
 	secretKey string
+	allowlist *IPAllowlist
 }
 
 func NewValidator(secretKey string) *Validator {
@@ -21,22 +28,34 @@ func NewValidator(secretKey string) *Validator {
 	}
 }
 
+// WithIPAllowlist makes ValidateRequest reject requests from addresses not in allowlist
+// with an error wrapping ErrSourceNotAllowed, before the signature is checked
+func (v *Validator) WithIPAllowlist(allowlist *IPAllowlist) *Validator {
+	v.allowlist = allowlist
+
+	return v
+}
+
 func (v *Validator) ValidateSignature(payload []byte, signature string) bool {
-	mac := hmac.New(sha512.New, []byte(v.secretKey))
-	mac.Write(payload)
-	expectedSignature := hex.EncodeToString(mac.Sum(nil))
+	expectedSignature := sign(v.secretKey, payload)
 
 	return hmac.Equal([]byte(signature), []byte(expectedSignature))
 }
 
 func (v *Validator) ValidateRequest(r *http.Request) (*Event, error) {
+	if v.allowlist != nil {
+		if err := v.allowlist.Check(r); err != nil {
+			return nil, err
+		}
+	}
+
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read request body: %w", err)
 	}
 
-	if !v.ValidateSignature(body, r.Header.Get("x-paystack-signature")) {
-		return nil, fmt.Errorf("invalid webhook signature")
+	if !v.ValidateSignature(body, r.Header.Get(SignatureHeader)) {
+		return nil, ErrInvalidSignature
 	}
 
 	r.Body = io.NopCloser(bytes.NewReader(body))
@@ -44,7 +63,7 @@ func (v *Validator) ValidateRequest(r *http.Request) (*Event, error) {
 	var event Event
 
 	if err := json.Unmarshal(body, &event); err != nil {
-		return nil, fmt.Errorf("failed to parse webhook payload: %w", err)
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPayload, err)
 	}
 
 	return &event, nil
@@ -61,7 +80,7 @@ func ParseEvent(r *http.Request) (*Event, error) {
 	var event Event
 
 	if err := json.Unmarshal(body, &event); err != nil {
-		return nil, fmt.Errorf("failed to parse webhook payload: %w", err)
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPayload, err)
 	}
 
 	return &event, nil
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -29,6 +30,12 @@ func TestWebhook_ChargeSuccess(t *testing.T) {
 	assert.Equal(t, "success", ev.Status.String())
 	assert.Equal(t, "qTPrJoy9Bx", ev.Reference.String())
 	assert.Equal(t, int64(10000), ev.Amount.Int64())
+	require.NotNil(t, ev.Log)
+	assert.Equal(t, "pin", ev.Log.Authentication.String())
+	require.Len(t, ev.Log.History, 3)
+	assert.Equal(t, "Attempted to pay", ev.Log.History[1].Message.String())
+	require.NotNil(t, ev.Plan)
+	assert.Empty(t, ev.Plan.PlanCode.String())
 }
 
 func TestWebhook_CustomerIdentification_Failed_Success(t *testing.T) {
@@ -68,16 +75,22 @@ func TestWebhook_Invoice_Events(t *testing.T) {
 	v1, err := e1.AsInvoiceCreate()
 	require.NoError(t, err)
 	assert.True(t, v1.Paid.Bool())
+	require.NotNil(t, v1.Subscription)
+	assert.Equal(t, "SUB_fq7dbe8tju0i1v8", v1.Subscription.SubscriptionCode.String())
 
 	e2 := readEventFixture(t, "invoice.payment_failed")
 	v2, err := e2.AsInvoicePaymentFailed()
 	require.NoError(t, err)
 	assert.False(t, v2.Paid.Bool())
+	require.NotNil(t, v2.Subscription)
+	assert.Equal(t, "INV_3kfmqw48ca7b48k", v2.Subscription.OpenInvoice.String())
 
 	e3 := readEventFixture(t, "invoice.update")
 	v3, err := e3.AsInvoiceUpdate()
 	require.NoError(t, err)
 	assert.True(t, v3.Paid.Bool())
+	require.NotNil(t, v3.Transaction)
+	assert.Equal(t, "rdtmivs7zf", v3.Transaction.Reference.String())
 }
 
 func TestWebhook_PaymentRequest(t *testing.T) {
@@ -86,7 +99,32 @@ func TestWebhook_PaymentRequest(t *testing.T) {
 		v, err := e.AsPaymentRequest()
 		require.NoError(t, err)
 		assert.NotEmpty(t, v.RequestCode.String())
+		assert.Empty(t, v.LineItems)
+		assert.Empty(t, v.Tax)
 	}
+
+	v, err := readEventFixture(t, "paymentrequest.success").AsPaymentRequest()
+	require.NoError(t, err)
+	require.Len(t, v.Notifications, 1)
+	assert.Equal(t, "email", v.Notifications[0].Channel.String())
+	assert.True(t, v.Notifications[0].SentAt.Valid)
+}
+
+func TestWebhook_PaymentRequest_Invoice(t *testing.T) {
+	e := readEventFixture(t, "paymentrequest.success.invoice")
+	assert.Equal(t, EventPaymentRequestSuccess, e.Event)
+
+	v, err := e.AsPaymentRequest()
+	require.NoError(t, err)
+	assert.True(t, v.HasInvoice.Bool())
+	assert.Equal(t, int64(3), v.InvoiceNumber.Int)
+	assert.True(t, v.PDF_URL.Valid)
+	require.Len(t, v.LineItems, 2)
+	assert.Equal(t, "Design", v.LineItems[0].Name.String())
+	assert.Equal(t, int64(30000), v.LineItems[0].Amount.Int64())
+	require.Len(t, v.Tax, 1)
+	assert.Equal(t, int64(2000), v.Tax[0].Amount.Int64())
+	assert.Equal(t, "CUS_xwaj0txjryg393b", v.Customer.Metadata["customer_code"])
 }
 
 func TestWebhook_Refund_Events(t *testing.T) {
@@ -122,17 +160,25 @@ func TestWebhook_Subscription_Events(t *testing.T) {
 	v2, err := e2.AsSubscriptionDisable()
 	require.NoError(t, err)
 	assert.Equal(t, "complete", v2.Status.String())
+	require.NotNil(t, v2.Plan)
+	assert.Equal(t, "PLN_gx2wn530m0i3w3m", v2.Plan.PlanCode.String())
 
 	e3 := readEventFixture(t, "subscription.not_renew")
 	v3, err := e3.AsSubscriptionNotRenew()
 	require.NoError(t, err)
 	assert.Equal(t, "non-renewing", v3.Status.String())
+	require.NotNil(t, v3.Plan)
+	assert.Equal(t, "annually", string(v3.Plan.Interval))
+	assert.Empty(t, v3.Invoices)
 
 	e4 := readEventFixture(t, "subscription.expiring_cards")
 	v4, err := e4.AsSubscriptionExpiringCards()
 	require.NoError(t, err)
-	// Ensure we captured entries array in flexible metadata
-	assert.True(t, v4.Entries.Valid)
+	require.Len(t, v4.Entries, 1)
+	assert.Equal(t, "12/2021", v4.Entries[0].ExpiryDate.String())
+	assert.Equal(t, "SUB_lejj927x2kxciw1", v4.Entries[0].Subscription.SubscriptionCode.String())
+	assert.Equal(t, "PLN_pfmwz75o021slex", v4.Entries[0].Subscription.Plan.PlanCode.String())
+	assert.Equal(t, "bojackhoresman@gmail.com", v4.Entries[0].Customer.Email.String())
 }
 
 func TestWebhook_Transfer_Events(t *testing.T) {
@@ -140,6 +186,15 @@ func TestWebhook_Transfer_Events(t *testing.T) {
 	v1, err := e1.AsTransferSuccess()
 	require.NoError(t, err)
 	assert.Equal(t, "success", v1.Status.String())
+	require.NotNil(t, v1.Session)
+	assert.False(t, v1.Session.Provider.Valid)
+	assert.Nil(t, v1.SourceDetails)
+	assert.Nil(t, v1.Failures)
+
+	failed, err := (&Event{Event: EventTransferFailed, Data: []byte(`{"status":"failed","failures":{"reason":"Account is dormant"},"source_details":{"type":"bank_account","bank_code":"058"}}`)}).AsTransferFailed()
+	require.NoError(t, err)
+	assert.Equal(t, "Account is dormant", failed.Failures.Reason.String())
+	assert.Equal(t, "058", failed.SourceDetails.BankCode.String())
 
 	e2 := readEventFixture(t, "transfer.failed")
 	v2, err := e2.AsTransferFailed()
@@ -151,3 +206,131 @@ func TestWebhook_Transfer_Events(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "reversed", v3.Status.String())
 }
+
+func TestWebhook_ChargeDispute_History(t *testing.T) {
+	v, err := readEventFixture(t, "charge.dispute.resolve").AsChargeDispute()
+	require.NoError(t, err)
+	assert.Equal(t, "auto-accepted", v.Resolution.String())
+	require.Len(t, v.History, 1)
+	assert.False(t, v.History[0].CreatedAt.Time().IsZero())
+	require.Len(t, v.Messages, 1)
+	assert.False(t, v.Messages[0].CreatedAt.Time().IsZero())
+}
+
+func TestWebhook_ChargeDispute_Declined(t *testing.T) {
+	e := readEventFixture(t, "charge.dispute.resolve.declined")
+	assert.Equal(t, EventChargeDisputeResolve, e.Event)
+
+	v, err := e.AsChargeDispute()
+	require.NoError(t, err)
+	assert.Equal(t, "declined", v.Resolution.String())
+	assert.Zero(t, v.RefundAmount.Int64())
+	require.NotNil(t, v.Evidence)
+	assert.Equal(t, "Delivered two pairs of shoes", v.Evidence.ServiceDetails.String())
+	require.Len(t, v.History, 3)
+	assert.Equal(t, "awaiting-bank-feedback", v.History[1].Status.String())
+	assert.Equal(t, "success", v.Transaction.Status.String())
+}
+
+func TestWebhook_ChargeSuccess_Terminals(t *testing.T) {
+	e := readEventFixture(t, "charge.success.terminal")
+	assert.Equal(t, EventChargeSuccess, e.Event)
+
+	v, err := e.AsChargeSuccess()
+	require.NoError(t, err)
+	assert.Equal(t, "terminal", v.Source.Source.String())
+	assert.Equal(t, "2232WE17", v.Source.Identifier.String())
+	require.NotNil(t, v.PosTransactionData)
+	assert.Equal(t, "2232WE17", v.PosTransactionData.TerminalID.String())
+
+	e = readEventFixture(t, "charge.success.virtual_terminal")
+	assert.Equal(t, EventChargeSuccess, e.Event)
+
+	v, err = e.AsChargeSuccess()
+	require.NoError(t, err)
+	assert.Equal(t, "virtual_terminal", v.Source.Source.String())
+	assert.Equal(t, "VT_AQZKJ2R8", v.Source.Identifier.String())
+	assert.Nil(t, v.PosTransactionData)
+	assert.Equal(t, "Zenith Bank", v.Authorization.SenderBank.String())
+}
+
+func TestWebhook_DirectDebitAuthorization_Events(t *testing.T) {
+	e1 := readEventFixture(t, "direct_debit.authorization.created")
+	v1, err := e1.AsDirectDebitAuthorization()
+	require.NoError(t, err)
+	assert.Equal(t, "AUTH_JV4T9Wawdj", v1.AuthorizationCode.String())
+	assert.False(t, v1.Active.Bool())
+	assert.False(t, v1.Signature.Valid)
+
+	e2 := readEventFixture(t, "direct_debit.authorization.active")
+	v2, err := e2.AsDirectDebitAuthorization()
+	require.NoError(t, err)
+	assert.True(t, v2.Active.Bool())
+	assert.True(t, v2.Reusable.Bool())
+	assert.Equal(t, "ravi@demo.com", v2.Customer.Email.String())
+}
+
+func TestWebhook_TransferApprovalRequired(t *testing.T) {
+	v, err := readEventFixture(t, "transferrequest.approval-required").AsTransferApproval()
+	require.NoError(t, err)
+	assert.Equal(t, "otp", v.Status.String())
+	assert.Equal(t, "TRF_1ptvuv321ahaa7q", v.TransferCode.String())
+	assert.Equal(t, int64(8690817), v.Recipient.Int64())
+	assert.False(t, v.CreatedAt.Time().IsZero())
+}
+
+func TestWebhook_BankTransferRejected(t *testing.T) {
+	v, err := readEventFixture(t, "bank.transfer.rejected").AsBankTransferRejected()
+	require.NoError(t, err)
+	assert.Equal(t, "failed", v.Status.String())
+	assert.Equal(t, "1729532695174", v.Reference.String())
+	require.NotNil(t, v.Authorization)
+	assert.Equal(t, "Kuda Bank", v.Authorization.SenderBank.String())
+	assert.Equal(t, "1238161470", v.Authorization.ReceiverBankAccountNumber.String())
+}
+
+func TestEvent_Decode(t *testing.T) {
+	entries, err := os.ReadDir(filepath.Join("..", "..", "resources", "examples", "webhook"))
+	require.NoError(t, err)
+
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".json")
+
+		t.Run(name, func(t *testing.T) {
+			e := readEventFixture(t, name)
+			assert.True(t, e.IsKnown())
+
+			v, err := e.Decode()
+			require.NoError(t, err)
+			require.NotNil(t, v)
+
+			// Spot check the concrete types returned
+			switch e.Event {
+			case EventChargeSuccess:
+				assert.IsType(t, &ChargeSuccessEvent{}, v)
+			case EventSubscriptionExpiringCards:
+				assert.IsType(t, &SubscriptionExpiringCardsEvent{}, v)
+			case EventDirectDebitAuthorizationActive, EventDirectDebitAuthorizationCreated:
+				assert.IsType(t, &DirectDebitAuthorizationEvent{}, v)
+			case EventTransferApprovalRequired:
+				assert.IsType(t, &TransferApprovalEvent{}, v)
+			case EventBankTransferRejected:
+				assert.IsType(t, &BankTransferRejectedEvent{}, v)
+			}
+		})
+	}
+}
+
+func TestEvent_Decode_Errors(t *testing.T) {
+	e := &Event{Event: "something.new", Data: json.RawMessage(`{}`)}
+	assert.False(t, e.IsKnown())
+
+	_, err := e.Decode()
+	assert.ErrorIs(t, err, ErrUnknownEvent)
+	assert.Contains(t, err.Error(), "something.new")
+
+	e = &Event{Event: EventChargeSuccess, Data: json.RawMessage(`{"amount":{}}`)}
+	v, err := e.Decode()
+	assert.Error(t, err)
+	assert.Nil(t, v)
+}
@@ -0,0 +1,107 @@
+package paystack
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/huysamen/paystack-go/api/transactions"
+	"github.com/huysamen/paystack-go/api/transfers"
+	"github.com/huysamen/paystack-go/internal/apitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_BaseURL(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		call  func(ctx context.Context, c *Client) error
+		path  string
+		query string
+	}{
+		{
+			name: "transactions totals",
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.Transactions.Totals(ctx, *transactions.NewTotalsRequestBuilder().DateRange(from, to))
+				return err
+			},
+			path:  "/transaction/totals",
+			query: "from=2024-01-01T00%3A00%3A00Z&to=2024-01-31T00%3A00%3A00Z",
+		},
+		{
+			name: "transactions export",
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.Transactions.Export(ctx, *transactions.NewExportRequestBuilder().PerPage(10))
+				return err
+			},
+			path:  "/transaction/export",
+			query: "perPage=10",
+		},
+		{
+			name: "transfers list",
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.Transfers.List(ctx, *transfers.NewListRequestBuilder().PerPage(10))
+				return err
+			},
+			path:  "/transfer",
+			query: "perPage=10",
+		},
+		{
+			name: "check balance",
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.TransferControl.CheckBalance(ctx)
+				return err
+			},
+			path: "/balance",
+		},
+		{
+			name: "fetch balance ledger",
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.TransferControl.FetchBalanceLedger(ctx)
+				return err
+			},
+			path: "/balance/ledger",
+		},
+		{
+			name: "fetch terminal status",
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.Terminal.FetchTerminalStatus(ctx, "30")
+				return err
+			},
+			path: "/terminal/30/presence",
+		},
+		{
+			name: "fetch event status",
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.Terminal.FetchEventStatus(ctx, "30", "616d721e8c5cd40a0cdd54a6")
+				return err
+			},
+			path: "/terminal/30/events/616d721e8c5cd40a0cdd54a6",
+		},
+		{
+			name: "fetch split",
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.TransactionSplits.Fetch(ctx, "143")
+				return err
+			},
+			path: "/split/143",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := apitest.NewServer(t).Reply(apitest.OK("null"))
+			client := NewClient(NewConfig("sk_test").WithBaseURL(api.API().BaseURL))
+
+			require.NoError(t, tt.call(context.Background(), client))
+
+			requests := api.Requests()
+			require.Len(t, requests, 1)
+			assert.Equal(t, tt.path, requests[0].Path)
+			assert.Equal(t, tt.query, requests[0].Query)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package enums
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 )
@@ -40,6 +41,22 @@ func (b *Bearer) UnmarshalJSON(data []byte) error {
 	}
 }
 
+// Scan implements sql.Scanner. SQL NULL and empty strings scan to the zero value.
+func (b *Bearer) Scan(src any) error {
+	v, err := scanEnum[Bearer](src, "Bearer")
+	if err != nil {
+		return err
+	}
+
+	*b = v
+	return nil
+}
+
+// Value implements driver.Valuer. The zero value is stored as SQL NULL.
+func (b Bearer) Value() (driver.Value, error) {
+	return enumValue(b)
+}
+
 // IsValid returns true if the bearer is a valid known value
 func (b Bearer) IsValid() bool {
 	switch b {
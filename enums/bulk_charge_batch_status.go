@@ -0,0 +1,91 @@
+package enums
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+)
+
+// BulkChargeBatchStatus represents the status of a bulk charge batch
+type BulkChargeBatchStatus string
+
+const (
+	BulkChargeBatchStatusPending  BulkChargeBatchStatus = "pending"
+	BulkChargeBatchStatusActive   BulkChargeBatchStatus = "active"
+	BulkChargeBatchStatusPaused   BulkChargeBatchStatus = "paused"
+	BulkChargeBatchStatusComplete BulkChargeBatchStatus = "complete"
+)
+
+// String returns the string representation of BulkChargeBatchStatus
+func (bs BulkChargeBatchStatus) String() string {
+	return string(bs)
+}
+
+// MarshalJSON implements json.Marshaler
+func (bs BulkChargeBatchStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(bs))
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Unknown values are kept as they are; use
+// IsValid to check for them.
+func (bs *BulkChargeBatchStatus) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	*bs = BulkChargeBatchStatus(s)
+	return nil
+}
+
+// Scan implements sql.Scanner. SQL NULL and empty strings scan to the zero value, and
+// unknown values are kept as they are.
+func (bs *BulkChargeBatchStatus) Scan(src any) error {
+	v, err := scanString[BulkChargeBatchStatus](src, "BulkChargeBatchStatus")
+	if err != nil {
+		return err
+	}
+
+	*bs = v
+	return nil
+}
+
+// Value implements driver.Valuer. The zero value is stored as SQL NULL.
+func (bs BulkChargeBatchStatus) Value() (driver.Value, error) {
+	return enumValue(bs)
+}
+
+// IsValid returns true if the status is a known value
+func (bs BulkChargeBatchStatus) IsValid() bool {
+	switch bs {
+	case BulkChargeBatchStatusPending, BulkChargeBatchStatusActive, BulkChargeBatchStatusPaused, BulkChargeBatchStatusComplete:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsTerminal returns true if every charge in the batch was attempted
+func (bs BulkChargeBatchStatus) IsTerminal() bool {
+	switch bs {
+	case BulkChargeBatchStatusComplete:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsSuccessful returns true if the batch completed. Individual charges in a complete batch
+// may still have failed.
+func (bs BulkChargeBatchStatus) IsSuccessful() bool {
+	return bs == BulkChargeBatchStatusComplete
+}
+
+// AllBulkChargeBatchStatuses returns all known BulkChargeBatchStatus values
+func AllBulkChargeBatchStatuses() []BulkChargeBatchStatus {
+	return []BulkChargeBatchStatus{
+		BulkChargeBatchStatusPending,
+		BulkChargeBatchStatusActive,
+		BulkChargeBatchStatusPaused,
+		BulkChargeBatchStatusComplete,
+	}
+}
@@ -0,0 +1,88 @@
+package enums
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+)
+
+// BulkChargeStatus represents the status of a single charge in a bulk charge batch
+type BulkChargeStatus string
+
+const (
+	BulkChargeStatusPending BulkChargeStatus = "pending"
+	BulkChargeStatusSuccess BulkChargeStatus = "success"
+	BulkChargeStatusFailed  BulkChargeStatus = "failed"
+)
+
+// String returns the string representation of BulkChargeStatus
+func (bs BulkChargeStatus) String() string {
+	return string(bs)
+}
+
+// MarshalJSON implements json.Marshaler
+func (bs BulkChargeStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(bs))
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Unknown values are kept as they are; use
+// IsValid to check for them.
+func (bs *BulkChargeStatus) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	*bs = BulkChargeStatus(s)
+	return nil
+}
+
+// Scan implements sql.Scanner. SQL NULL and empty strings scan to the zero value, and
+// unknown values are kept as they are.
+func (bs *BulkChargeStatus) Scan(src any) error {
+	v, err := scanString[BulkChargeStatus](src, "BulkChargeStatus")
+	if err != nil {
+		return err
+	}
+
+	*bs = v
+	return nil
+}
+
+// Value implements driver.Valuer. The zero value is stored as SQL NULL.
+func (bs BulkChargeStatus) Value() (driver.Value, error) {
+	return enumValue(bs)
+}
+
+// IsValid returns true if the status is a known value
+func (bs BulkChargeStatus) IsValid() bool {
+	switch bs {
+	case BulkChargeStatusPending, BulkChargeStatusSuccess, BulkChargeStatusFailed:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsTerminal returns true if the charge was attempted: success or failed
+func (bs BulkChargeStatus) IsTerminal() bool {
+	switch bs {
+	case BulkChargeStatusSuccess, BulkChargeStatusFailed:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsSuccessful returns true if the charge succeeded
+func (bs BulkChargeStatus) IsSuccessful() bool {
+	return bs == BulkChargeStatusSuccess
+}
+
+// AllBulkChargeStatuses returns all known BulkChargeStatus values
+func AllBulkChargeStatuses() []BulkChargeStatus {
+	return []BulkChargeStatus{
+		BulkChargeStatusPending,
+		BulkChargeStatusSuccess,
+		BulkChargeStatusFailed,
+	}
+}
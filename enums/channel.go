@@ -1,6 +1,7 @@
 package enums
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 )
@@ -50,6 +51,22 @@ func (c *Channel) UnmarshalJSON(data []byte) error {
 	}
 }
 
+// Scan implements sql.Scanner. SQL NULL and empty strings scan to the zero value.
+func (c *Channel) Scan(src any) error {
+	v, err := scanEnum[Channel](src, "Channel")
+	if err != nil {
+		return err
+	}
+
+	*c = v
+	return nil
+}
+
+// Value implements driver.Valuer. The zero value is stored as SQL NULL.
+func (c Channel) Value() (driver.Value, error) {
+	return enumValue(c)
+}
+
 // IsValid returns true if the channel is a valid known value
 func (c Channel) IsValid() bool {
 	switch c {
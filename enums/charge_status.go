@@ -0,0 +1,105 @@
+package enums
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+)
+
+// ChargeStatus represents the status of a charge created with the Charge API. It tells which
+// step of the charge comes next.
+type ChargeStatus string
+
+const (
+	ChargeStatusSendPIN             ChargeStatus = "send_pin"
+	ChargeStatusSendOTP             ChargeStatus = "send_otp"
+	ChargeStatusSendPhone           ChargeStatus = "send_phone"
+	ChargeStatusSendBirthday        ChargeStatus = "send_birthday"
+	ChargeStatusSendAddress         ChargeStatus = "send_address"
+	ChargeStatusOpenURL             ChargeStatus = "open_url"
+	ChargeStatusPayOffline          ChargeStatus = "pay_offline"
+	ChargeStatusPending             ChargeStatus = "pending"
+	ChargeStatusPendingBankTransfer ChargeStatus = "pending_bank_transfer"
+	ChargeStatusSuccess             ChargeStatus = "success"
+	ChargeStatusFailed              ChargeStatus = "failed"
+)
+
+// String returns the string representation of ChargeStatus
+func (cs ChargeStatus) String() string {
+	return string(cs)
+}
+
+// MarshalJSON implements json.Marshaler
+func (cs ChargeStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(cs))
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Unknown values are kept as they are; use
+// IsValid to check for them.
+func (cs *ChargeStatus) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	*cs = ChargeStatus(s)
+	return nil
+}
+
+// Scan implements sql.Scanner. SQL NULL and empty strings scan to the zero value, and
+// unknown values are kept as they are.
+func (cs *ChargeStatus) Scan(src any) error {
+	v, err := scanString[ChargeStatus](src, "ChargeStatus")
+	if err != nil {
+		return err
+	}
+
+	*cs = v
+	return nil
+}
+
+// Value implements driver.Valuer. The zero value is stored as SQL NULL.
+func (cs ChargeStatus) Value() (driver.Value, error) {
+	return enumValue(cs)
+}
+
+// IsValid returns true if the status is a known value
+func (cs ChargeStatus) IsValid() bool {
+	switch cs {
+	case ChargeStatusSendPIN, ChargeStatusSendOTP, ChargeStatusSendPhone, ChargeStatusSendBirthday, ChargeStatusSendAddress, ChargeStatusOpenURL, ChargeStatusPayOffline, ChargeStatusPending, ChargeStatusPendingBankTransfer, ChargeStatusSuccess, ChargeStatusFailed:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsTerminal returns true if the charge needs no further action: success or failed
+func (cs ChargeStatus) IsTerminal() bool {
+	switch cs {
+	case ChargeStatusSuccess, ChargeStatusFailed:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsSuccessful returns true if the charge succeeded
+func (cs ChargeStatus) IsSuccessful() bool {
+	return cs == ChargeStatusSuccess
+}
+
+// AllChargeStatuses returns all known ChargeStatus values
+func AllChargeStatuses() []ChargeStatus {
+	return []ChargeStatus{
+		ChargeStatusSendPIN,
+		ChargeStatusSendOTP,
+		ChargeStatusSendPhone,
+		ChargeStatusSendBirthday,
+		ChargeStatusSendAddress,
+		ChargeStatusOpenURL,
+		ChargeStatusPayOffline,
+		ChargeStatusPending,
+		ChargeStatusPendingBankTransfer,
+		ChargeStatusSuccess,
+		ChargeStatusFailed,
+	}
+}
@@ -0,0 +1,99 @@
+package enums
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Country represents the ISO 3166-1 alpha-2 code of a country Paystack operates in
+type Country string
+
+const (
+	CountryNigeria     Country = "NG"
+	CountryGhana       Country = "GH"
+	CountrySouthAfrica Country = "ZA"
+	CountryKenya       Country = "KE"
+	CountryIvoryCoast  Country = "CI"
+	CountryEgypt       Country = "EG"
+	CountryRwanda      Country = "RW"
+)
+
+// String returns the string representation of the country
+func (c Country) String() string {
+	return string(c)
+}
+
+// MarshalJSON implements json.Marshaler
+func (c Country) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(c))
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (c *Country) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	// Paystack is inconsistent with casing between endpoints ("NG" vs "ng")
+	country := Country(strings.ToUpper(s))
+	switch country {
+	case CountryNigeria, CountryGhana, CountrySouthAfrica, CountryKenya,
+		CountryIvoryCoast, CountryEgypt, CountryRwanda:
+		*c = country
+		return nil
+	case "": // Allow empty string for null values
+		*c = country
+		return nil
+	default:
+		return fmt.Errorf("invalid Country value: %s", s)
+	}
+}
+
+// Scan implements sql.Scanner. SQL NULL and empty strings scan to the zero value.
+func (c *Country) Scan(src any) error {
+	if s, ok := src.(string); ok {
+		src = strings.ToUpper(s)
+	} else if b, ok := src.([]byte); ok {
+		src = strings.ToUpper(string(b))
+	}
+
+	v, err := scanEnum[Country](src, "Country")
+	if err != nil {
+		return err
+	}
+
+	*c = v
+	return nil
+}
+
+// Value implements driver.Valuer. The zero value is stored as SQL NULL.
+func (c Country) Value() (driver.Value, error) {
+	return enumValue(c)
+}
+
+// IsValid returns true if the country is a valid known value
+func (c Country) IsValid() bool {
+	switch c {
+	case CountryNigeria, CountryGhana, CountrySouthAfrica, CountryKenya,
+		CountryIvoryCoast, CountryEgypt, CountryRwanda, "":
+		return true
+	default:
+		return false
+	}
+}
+
+// AllCountries returns all valid Country values
+func AllCountries() []Country {
+	return []Country{
+		CountryNigeria,
+		CountryGhana,
+		CountrySouthAfrica,
+		CountryKenya,
+		CountryIvoryCoast,
+		CountryEgypt,
+		CountryRwanda,
+	}
+}
@@ -0,0 +1,60 @@
+package enums
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountry_UnmarshalJSON(t *testing.T) {
+	var v struct {
+		Upper Country `json:"upper"`
+		Lower Country `json:"lower"`
+		Empty Country `json:"empty"`
+	}
+
+	require.NoError(t, json.Unmarshal([]byte(`{"upper":"NG","lower":"gh","empty":""}`), &v))
+	assert.Equal(t, CountryNigeria, v.Upper)
+	assert.Equal(t, CountryGhana, v.Lower)
+	assert.Equal(t, Country(""), v.Empty)
+
+	var c Country
+	assert.ErrorContains(t, json.Unmarshal([]byte(`"US"`), &c), "invalid Country value")
+
+	require.NoError(t, c.Scan([]byte("za")))
+	assert.Equal(t, CountrySouthAfrica, c)
+	assert.True(t, c.IsValid())
+	assert.False(t, Country("US").IsValid())
+}
+
+func TestCountryInfo_Registry(t *testing.T) {
+	for _, c := range AllCountries() {
+		info, ok := c.Info()
+		require.True(t, ok, "missing registry entry for %s", c)
+
+		assert.Equal(t, c, info.Country)
+		assert.NotEmpty(t, info.Name, c)
+		assert.NotEmpty(t, info.Channels, c)
+		assert.NotEmpty(t, info.TransferRecipientTypes, c)
+
+		// The local currency must list the country as one it is used in
+		currency, ok := c.DefaultCurrency().Info()
+		require.True(t, ok, c)
+		assert.Contains(t, currency.Countries, c)
+		assert.True(t, info.SupportsCurrency(currency.Currency))
+	}
+
+	assert.Len(t, AllCountryInfo(), len(AllCountries()))
+
+	assert.Equal(t, CurrencyNGN, CountryNigeria.DefaultCurrency())
+	assert.Equal(t, CurrencyKES, CountryKenya.DefaultCurrency())
+	assert.Equal(t, Currency(""), Country("US").DefaultCurrency())
+
+	ng, _ := CountryNigeria.Info()
+	assert.True(t, ng.SupportsCurrency(CurrencyUSD))
+	assert.False(t, ng.SupportsCurrency(CurrencyGHS))
+	assert.True(t, ng.SupportsChannel(ChannelUSSD))
+	assert.False(t, ng.SupportsChannel(ChannelMobileMoney))
+}
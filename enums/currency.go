@@ -1,6 +1,7 @@
 package enums
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 )
@@ -14,6 +15,9 @@ const (
 	CurrencyUSD Currency = "USD"
 	CurrencyGHS Currency = "GHS"
 	CurrencyKES Currency = "KES"
+	CurrencyXOF Currency = "XOF"
+	CurrencyEGP Currency = "EGP"
+	CurrencyRWF Currency = "RWF"
 )
 
 // String returns the string representation of the currency
@@ -35,7 +39,8 @@ func (c *Currency) UnmarshalJSON(data []byte) error {
 
 	currency := Currency(s)
 	switch currency {
-	case CurrencyZAR, CurrencyNGN, CurrencyUSD, CurrencyGHS, CurrencyKES:
+	case CurrencyZAR, CurrencyNGN, CurrencyUSD, CurrencyGHS, CurrencyKES,
+		CurrencyXOF, CurrencyEGP, CurrencyRWF:
 		*c = currency
 		return nil
 	case "": // Allow empty string for null values
@@ -46,10 +51,27 @@ func (c *Currency) UnmarshalJSON(data []byte) error {
 	}
 }
 
+// Scan implements sql.Scanner. SQL NULL and empty strings scan to the zero value.
+func (c *Currency) Scan(src any) error {
+	v, err := scanEnum[Currency](src, "Currency")
+	if err != nil {
+		return err
+	}
+
+	*c = v
+	return nil
+}
+
+// Value implements driver.Valuer. The zero value is stored as SQL NULL.
+func (c Currency) Value() (driver.Value, error) {
+	return enumValue(c)
+}
+
 // IsValid returns true if the currency is a valid known value
 func (c Currency) IsValid() bool {
 	switch c {
-	case CurrencyZAR, CurrencyNGN, CurrencyUSD, CurrencyGHS, CurrencyKES, "":
+	case CurrencyZAR, CurrencyNGN, CurrencyUSD, CurrencyGHS, CurrencyKES,
+		CurrencyXOF, CurrencyEGP, CurrencyRWF, "":
 		return true
 	default:
 		return false
@@ -64,5 +86,8 @@ func AllCurrencies() []Currency {
 		CurrencyUSD,
 		CurrencyGHS,
 		CurrencyKES,
+		CurrencyXOF,
+		CurrencyEGP,
+		CurrencyRWF,
 	}
 }
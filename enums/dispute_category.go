@@ -1,6 +1,7 @@
 package enums
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 )
@@ -44,6 +45,22 @@ func (dc *DisputeCategory) UnmarshalJSON(data []byte) error {
 	}
 }
 
+// Scan implements sql.Scanner. SQL NULL and empty strings scan to the zero value.
+func (dc *DisputeCategory) Scan(src any) error {
+	v, err := scanEnum[DisputeCategory](src, "DisputeCategory")
+	if err != nil {
+		return err
+	}
+
+	*dc = v
+	return nil
+}
+
+// Value implements driver.Valuer. The zero value is stored as SQL NULL.
+func (dc DisputeCategory) Value() (driver.Value, error) {
+	return enumValue(dc)
+}
+
 // IsValid returns true if the dispute category is a valid known value
 func (dc DisputeCategory) IsValid() bool {
 	switch dc {
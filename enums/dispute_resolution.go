@@ -1,6 +1,7 @@
 package enums
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 )
@@ -40,6 +41,22 @@ func (dr *DisputeResolution) UnmarshalJSON(data []byte) error {
 	}
 }
 
+// Scan implements sql.Scanner. SQL NULL and empty strings scan to the zero value.
+func (dr *DisputeResolution) Scan(src any) error {
+	v, err := scanEnum[DisputeResolution](src, "DisputeResolution")
+	if err != nil {
+		return err
+	}
+
+	*dr = v
+	return nil
+}
+
+// Value implements driver.Valuer. The zero value is stored as SQL NULL.
+func (dr DisputeResolution) Value() (driver.Value, error) {
+	return enumValue(dr)
+}
+
 // IsValid returns true if the dispute resolution is a valid known value
 func (dr DisputeResolution) IsValid() bool {
 	switch dr {
@@ -1,6 +1,7 @@
 package enums
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 )
@@ -40,6 +41,22 @@ func (ds *DisputeSource) UnmarshalJSON(data []byte) error {
 	}
 }
 
+// Scan implements sql.Scanner. SQL NULL and empty strings scan to the zero value.
+func (ds *DisputeSource) Scan(src any) error {
+	v, err := scanEnum[DisputeSource](src, "DisputeSource")
+	if err != nil {
+		return err
+	}
+
+	*ds = v
+	return nil
+}
+
+// Value implements driver.Valuer. The zero value is stored as SQL NULL.
+func (ds DisputeSource) Value() (driver.Value, error) {
+	return enumValue(ds)
+}
+
 // IsValid returns true if the dispute source is a valid known value
 func (ds DisputeSource) IsValid() bool {
 	switch ds {
@@ -1,6 +1,7 @@
 package enums
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 )
@@ -44,6 +45,22 @@ func (ds *DisputeStatus) UnmarshalJSON(data []byte) error {
 	}
 }
 
+// Scan implements sql.Scanner. SQL NULL and empty strings scan to the zero value.
+func (ds *DisputeStatus) Scan(src any) error {
+	v, err := scanEnum[DisputeStatus](src, "DisputeStatus")
+	if err != nil {
+		return err
+	}
+
+	*ds = v
+	return nil
+}
+
+// Value implements driver.Valuer. The zero value is stored as SQL NULL.
+func (ds DisputeStatus) Value() (driver.Value, error) {
+	return enumValue(ds)
+}
+
 // IsValid returns true if the dispute status is a valid known value
 func (ds DisputeStatus) IsValid() bool {
 	switch ds {
@@ -1,6 +1,7 @@
 package enums
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 )
@@ -45,6 +46,22 @@ func (i *Interval) UnmarshalJSON(data []byte) error {
 	}
 }
 
+// Scan implements sql.Scanner. SQL NULL and empty strings scan to the zero value.
+func (i *Interval) Scan(src any) error {
+	v, err := scanEnum[Interval](src, "Interval")
+	if err != nil {
+		return err
+	}
+
+	*i = v
+	return nil
+}
+
+// Value implements driver.Valuer. The zero value is stored as SQL NULL.
+func (i Interval) Value() (driver.Value, error) {
+	return enumValue(i)
+}
+
 // IsValid returns true if the interval is a valid known value
 func (i Interval) IsValid() bool {
 	switch i {
@@ -1,6 +1,7 @@
 package enums
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 )
@@ -41,6 +42,22 @@ func (mas *MandateAuthorizationStatus) UnmarshalJSON(data []byte) error {
 	}
 }
 
+// Scan implements sql.Scanner. SQL NULL and empty strings scan to the zero value.
+func (mas *MandateAuthorizationStatus) Scan(src any) error {
+	v, err := scanEnum[MandateAuthorizationStatus](src, "MandateAuthorizationStatus")
+	if err != nil {
+		return err
+	}
+
+	*mas = v
+	return nil
+}
+
+// Value implements driver.Valuer. The zero value is stored as SQL NULL.
+func (mas MandateAuthorizationStatus) Value() (driver.Value, error) {
+	return enumValue(mas)
+}
+
 // IsValid returns true if the mandate authorization status is a valid known value
 func (mas MandateAuthorizationStatus) IsValid() bool {
 	switch mas {
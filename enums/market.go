@@ -0,0 +1,410 @@
+package enums
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// CurrencyInfo describes how Paystack handles a currency: how amounts are expressed,
+// the smallest amount that can be charged and which channels and transfer recipient
+// types can be used with it.
+type CurrencyInfo struct {
+	Currency Currency
+	Name     string
+	Symbol   string
+
+	// Exponent is the number of decimal places between the major unit and the subunit
+	// Paystack expects in the amount field. Paystack always uses two, even for currencies
+	// such as XOF and RWF that have no minor unit in ISO 4217.
+	Exponent int
+
+	// MinimumAmount is the smallest chargeable amount, in subunits
+	MinimumAmount int64
+
+	Countries              []Country
+	Channels               []Channel
+	TransferRecipientTypes []TransferRecipientType
+}
+
+// CountryInfo describes a market Paystack operates in
+type CountryInfo struct {
+	Country Country
+	Name    string
+
+	// Currencies lists the currencies merchants in this country can settle in.
+	// The first entry is the local currency.
+	Currencies []Currency
+
+	Channels               []Channel
+	TransferRecipientTypes []TransferRecipientType
+}
+
+var currencyRegistry = map[Currency]CurrencyInfo{
+	CurrencyNGN: {
+		Currency:      CurrencyNGN,
+		Name:          "Nigerian Naira",
+		Symbol:        "₦",
+		Exponent:      2,
+		MinimumAmount: 5000,
+		Countries:     []Country{CountryNigeria},
+		Channels: []Channel{
+			ChannelCard, ChannelBank, ChannelUSSD, ChannelQR, ChannelBankTransfer, ChannelApplePay,
+		},
+		TransferRecipientTypes: []TransferRecipientType{
+			TransferRecipientTypeNuban, TransferRecipientTypeAuthorization,
+		},
+	},
+	CurrencyGHS: {
+		Currency:      CurrencyGHS,
+		Name:          "Ghanaian Cedi",
+		Symbol:        "₵",
+		Exponent:      2,
+		MinimumAmount: 10,
+		Countries:     []Country{CountryGhana},
+		Channels: []Channel{
+			ChannelCard, ChannelMobileMoney, ChannelApplePay,
+		},
+		TransferRecipientTypes: []TransferRecipientType{
+			TransferRecipientTypeGhipss, TransferRecipientTypeMobileMoney, TransferRecipientTypeAuthorization,
+		},
+	},
+	CurrencyZAR: {
+		Currency:      CurrencyZAR,
+		Name:          "South African Rand",
+		Symbol:        "R",
+		Exponent:      2,
+		MinimumAmount: 100,
+		Countries:     []Country{CountrySouthAfrica},
+		Channels: []Channel{
+			ChannelCard, ChannelEFT, ChannelQR, ChannelApplePay,
+		},
+		TransferRecipientTypes: []TransferRecipientType{
+			TransferRecipientTypeBasa, TransferRecipientTypeAuthorization,
+		},
+	},
+	CurrencyKES: {
+		Currency:      CurrencyKES,
+		Name:          "Kenyan Shilling",
+		Symbol:        "KSh",
+		Exponent:      2,
+		MinimumAmount: 300,
+		Countries:     []Country{CountryKenya},
+		Channels: []Channel{
+			ChannelCard, ChannelMobileMoney, ChannelApplePay,
+		},
+		TransferRecipientTypes: []TransferRecipientType{
+			TransferRecipientTypeMobileMoney, TransferRecipientTypeKepss, TransferRecipientTypeAuthorization,
+		},
+	},
+	CurrencyUSD: {
+		Currency:      CurrencyUSD,
+		Name:          "United States Dollar",
+		Symbol:        "$",
+		Exponent:      2,
+		MinimumAmount: 200,
+		Countries:     []Country{CountryNigeria, CountryKenya},
+		Channels: []Channel{
+			ChannelCard, ChannelApplePay,
+		},
+		TransferRecipientTypes: []TransferRecipientType{
+			TransferRecipientTypeDomesticCGI, TransferRecipientTypeInternationalCGI,
+		},
+	},
+	CurrencyXOF: {
+		Currency:      CurrencyXOF,
+		Name:          "West African CFA Franc",
+		Symbol:        "CFA",
+		Exponent:      2,
+		MinimumAmount: 100,
+		Countries:     []Country{CountryIvoryCoast},
+		Channels: []Channel{
+			ChannelCard, ChannelMobileMoney,
+		},
+		TransferRecipientTypes: []TransferRecipientType{
+			TransferRecipientTypeMobileMoney,
+		},
+	},
+	CurrencyEGP: {
+		Currency:      CurrencyEGP,
+		Name:          "Egyptian Pound",
+		Symbol:        "E£",
+		Exponent:      2,
+		MinimumAmount: 200,
+		Countries:     []Country{CountryEgypt},
+		Channels: []Channel{
+			ChannelCard, ChannelMobileMoney,
+		},
+		TransferRecipientTypes: []TransferRecipientType{
+			TransferRecipientTypeBankAccount,
+		},
+	},
+	CurrencyRWF: {
+		Currency:      CurrencyRWF,
+		Name:          "Rwandan Franc",
+		Symbol:        "RF",
+		Exponent:      2,
+		MinimumAmount: 10000,
+		Countries:     []Country{CountryRwanda},
+		Channels: []Channel{
+			ChannelCard, ChannelMobileMoney,
+		},
+		TransferRecipientTypes: []TransferRecipientType{
+			TransferRecipientTypeMobileMoney,
+		},
+	},
+}
+
+var countryRegistry = map[Country]CountryInfo{
+	CountryNigeria: {
+		Country:                CountryNigeria,
+		Name:                   "Nigeria",
+		Currencies:             []Currency{CurrencyNGN, CurrencyUSD},
+		Channels:               currencyRegistry[CurrencyNGN].Channels,
+		TransferRecipientTypes: currencyRegistry[CurrencyNGN].TransferRecipientTypes,
+	},
+	CountryGhana: {
+		Country:                CountryGhana,
+		Name:                   "Ghana",
+		Currencies:             []Currency{CurrencyGHS},
+		Channels:               currencyRegistry[CurrencyGHS].Channels,
+		TransferRecipientTypes: currencyRegistry[CurrencyGHS].TransferRecipientTypes,
+	},
+	CountrySouthAfrica: {
+		Country:                CountrySouthAfrica,
+		Name:                   "South Africa",
+		Currencies:             []Currency{CurrencyZAR},
+		Channels:               currencyRegistry[CurrencyZAR].Channels,
+		TransferRecipientTypes: currencyRegistry[CurrencyZAR].TransferRecipientTypes,
+	},
+	CountryKenya: {
+		Country:                CountryKenya,
+		Name:                   "Kenya",
+		Currencies:             []Currency{CurrencyKES, CurrencyUSD},
+		Channels:               currencyRegistry[CurrencyKES].Channels,
+		TransferRecipientTypes: currencyRegistry[CurrencyKES].TransferRecipientTypes,
+	},
+	CountryIvoryCoast: {
+		Country:                CountryIvoryCoast,
+		Name:                   "Côte d'Ivoire",
+		Currencies:             []Currency{CurrencyXOF},
+		Channels:               currencyRegistry[CurrencyXOF].Channels,
+		TransferRecipientTypes: currencyRegistry[CurrencyXOF].TransferRecipientTypes,
+	},
+	CountryEgypt: {
+		Country:                CountryEgypt,
+		Name:                   "Egypt",
+		Currencies:             []Currency{CurrencyEGP},
+		Channels:               currencyRegistry[CurrencyEGP].Channels,
+		TransferRecipientTypes: currencyRegistry[CurrencyEGP].TransferRecipientTypes,
+	},
+	CountryRwanda: {
+		Country:                CountryRwanda,
+		Name:                   "Rwanda",
+		Currencies:             []Currency{CurrencyRWF},
+		Channels:               currencyRegistry[CurrencyRWF].Channels,
+		TransferRecipientTypes: currencyRegistry[CurrencyRWF].TransferRecipientTypes,
+	},
+}
+
+// Info returns the registry entry for the currency
+func (c Currency) Info() (CurrencyInfo, bool) {
+	info, ok := currencyRegistry[c]
+	return info, ok
+}
+
+// Info returns the registry entry for the country
+func (c Country) Info() (CountryInfo, bool) {
+	info, ok := countryRegistry[c]
+	return info, ok
+}
+
+// DefaultCurrency returns the local currency of the country, or an empty Currency if unknown
+func (c Country) DefaultCurrency() Currency {
+	info, ok := countryRegistry[c]
+	if !ok || len(info.Currencies) == 0 {
+		return ""
+	}
+
+	return info.Currencies[0]
+}
+
+// AllCurrencyInfo returns the registry entries for all currencies, in AllCurrencies order
+func AllCurrencyInfo() []CurrencyInfo {
+	infos := make([]CurrencyInfo, 0, len(currencyRegistry))
+	for _, c := range AllCurrencies() {
+		infos = append(infos, currencyRegistry[c])
+	}
+
+	return infos
+}
+
+// AllCountryInfo returns the registry entries for all countries, in AllCountries order
+func AllCountryInfo() []CountryInfo {
+	infos := make([]CountryInfo, 0, len(countryRegistry))
+	for _, c := range AllCountries() {
+		infos = append(infos, countryRegistry[c])
+	}
+
+	return infos
+}
+
+// SubunitFactor returns the number of subunits in one major unit (e.g. 100 kobo per naira)
+func (ci CurrencyInfo) SubunitFactor() int64 {
+	factor := int64(1)
+	for i := 0; i < ci.Exponent; i++ {
+		factor *= 10
+	}
+
+	return factor
+}
+
+// FormatAmount formats an amount in subunits as a decimal string in the major unit,
+// e.g. 150050 NGN becomes "1500.50"
+func (ci CurrencyInfo) FormatAmount(amount int64) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	factor := ci.SubunitFactor()
+	if ci.Exponent == 0 {
+		return sign + strconv.FormatInt(amount, 10)
+	}
+
+	return fmt.Sprintf("%s%d.%0*d", sign, amount/factor, ci.Exponent, amount%factor)
+}
+
+// FormatAmountWithSymbol formats an amount in subunits prefixed with the currency symbol,
+// e.g. 150050 NGN becomes "₦1500.50"
+func (ci CurrencyInfo) FormatAmountWithSymbol(amount int64) string {
+	formatted := ci.FormatAmount(amount)
+	if strings.HasPrefix(formatted, "-") {
+		return "-" + ci.Symbol + formatted[1:]
+	}
+
+	return ci.Symbol + formatted
+}
+
+// ParseAmount parses a decimal string in the major unit into subunits, e.g. "1500.5" NGN
+// becomes 150050. More decimal places than the currency exponent is an error.
+func (ci CurrencyInfo) ParseAmount(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+
+	whole, frac, hasFrac := strings.Cut(s, ".")
+	if whole == "" && (!hasFrac || frac == "") {
+		return 0, fmt.Errorf("invalid %s amount: %q", ci.Currency, s)
+	}
+	if len(frac) > ci.Exponent {
+		return 0, fmt.Errorf("invalid %s amount: %q has more than %d decimal places", ci.Currency, s, ci.Exponent)
+	}
+
+	var major int64
+	if whole != "" {
+		parsed, err := strconv.ParseUint(whole, 10, 63)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s amount: %q", ci.Currency, s)
+		}
+		major = int64(parsed)
+	}
+
+	var minor int64
+	if frac != "" {
+		parsed, err := strconv.ParseUint(frac+strings.Repeat("0", ci.Exponent-len(frac)), 10, 63)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s amount: %q", ci.Currency, s)
+		}
+		minor = int64(parsed)
+	}
+
+	factor := ci.SubunitFactor()
+	if major > (math.MaxInt64-minor)/factor {
+		return 0, fmt.Errorf("invalid %s amount: %q is too large", ci.Currency, s)
+	}
+
+	amount := major*factor + minor
+	if negative {
+		amount = -amount
+	}
+
+	return amount, nil
+}
+
+// SupportsChannel returns true if the channel can be used to collect payments in the currency
+func (ci CurrencyInfo) SupportsChannel(channel Channel) bool {
+	for _, c := range ci.Channels {
+		if c == channel {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SupportsTransferRecipientType returns true if transfers in the currency can be sent to the recipient type
+func (ci CurrencyInfo) SupportsTransferRecipientType(recipientType TransferRecipientType) bool {
+	for _, t := range ci.TransferRecipientTypes {
+		if t == recipientType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ValidateAmount returns an error if the amount, in subunits, is below the currency minimum
+func (ci CurrencyInfo) ValidateAmount(amount int64) error {
+	if amount < ci.MinimumAmount {
+		return fmt.Errorf("amount %s is below the %s minimum of %s",
+			ci.FormatAmount(amount), ci.Currency, ci.FormatAmount(ci.MinimumAmount))
+	}
+
+	return nil
+}
+
+// ValidateChannels returns an error naming the first channel that is not supported for the currency
+func (ci CurrencyInfo) ValidateChannels(channels ...Channel) error {
+	for _, c := range channels {
+		if !ci.SupportsChannel(c) {
+			return fmt.Errorf("channel %s is not supported for %s", c, ci.Currency)
+		}
+	}
+
+	return nil
+}
+
+// ValidateTransferRecipientType returns an error if the recipient type cannot receive transfers in the currency
+func (ci CurrencyInfo) ValidateTransferRecipientType(recipientType TransferRecipientType) error {
+	if !ci.SupportsTransferRecipientType(recipientType) {
+		return fmt.Errorf("transfer recipient type %s is not supported for %s", recipientType, ci.Currency)
+	}
+
+	return nil
+}
+
+// SupportsCurrency returns true if merchants in the country can settle in the currency
+func (ci CountryInfo) SupportsCurrency(currency Currency) bool {
+	for _, c := range ci.Currencies {
+		if c == currency {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SupportsChannel returns true if the channel is available to merchants in the country
+func (ci CountryInfo) SupportsChannel(channel Channel) bool {
+	for _, c := range ci.Channels {
+		if c == channel {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,114 @@
+package enums
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCurrencyInfo_Registry(t *testing.T) {
+	for _, c := range AllCurrencies() {
+		info, ok := c.Info()
+		require.True(t, ok, "missing registry entry for %s", c)
+
+		assert.Equal(t, c, info.Currency)
+		assert.NotEmpty(t, info.Name, c)
+		assert.NotEmpty(t, info.Symbol, c)
+		assert.Equal(t, 2, info.Exponent, c)
+		assert.Positive(t, info.MinimumAmount, c)
+		assert.NotEmpty(t, info.Countries, c)
+		assert.NotEmpty(t, info.Channels, c)
+		assert.NotEmpty(t, info.TransferRecipientTypes, c)
+	}
+
+	assert.Len(t, AllCurrencyInfo(), len(AllCurrencies()))
+
+	_, ok := Currency("XYZ").Info()
+	assert.False(t, ok)
+}
+
+func TestCurrencyInfo_FormatAmount(t *testing.T) {
+	ngn, _ := CurrencyNGN.Info()
+
+	tests := []struct {
+		amount int64
+		want   string
+		symbol string
+	}{
+		{amount: 150050, want: "1500.50", symbol: "₦1500.50"},
+		{amount: 5, want: "0.05", symbol: "₦0.05"},
+		{amount: 0, want: "0.00", symbol: "₦0.00"},
+		{amount: -150050, want: "-1500.50", symbol: "-₦1500.50"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, ngn.FormatAmount(tt.amount))
+		assert.Equal(t, tt.symbol, ngn.FormatAmountWithSymbol(tt.amount))
+	}
+
+	whole := CurrencyInfo{Currency: "XYZ", Exponent: 0}
+	assert.Equal(t, "1500", whole.FormatAmount(1500))
+}
+
+func TestCurrencyInfo_ParseAmount(t *testing.T) {
+	ngn, _ := CurrencyNGN.Info()
+
+	tests := []struct {
+		in   string
+		want int64
+		err  string
+	}{
+		{in: "1500.50", want: 150050},
+		{in: "1500.5", want: 150050},
+		{in: "1500", want: 150000},
+		{in: ".5", want: 50},
+		{in: " -12.34 ", want: -1234},
+		{in: "92233720368547758.07", want: 9223372036854775807},
+		{in: "92233720368547758.08", err: "too large"},
+		{in: "99999999999999999999", err: "invalid NGN amount"},
+		{in: "1.505", err: "more than 2 decimal places"},
+		{in: "", err: "invalid NGN amount"},
+		{in: ".", err: "invalid NGN amount"},
+		{in: "1,500", err: "invalid NGN amount"},
+		{in: "+1", err: "invalid NGN amount"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ngn.ParseAmount(tt.in)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.want, mustParse(t, ngn, ngn.FormatAmount(got)))
+		})
+	}
+}
+
+func mustParse(t *testing.T, ci CurrencyInfo, s string) int64 {
+	t.Helper()
+
+	amount, err := ci.ParseAmount(s)
+	require.NoError(t, err)
+
+	return amount
+}
+
+func TestCurrencyInfo_Validate(t *testing.T) {
+	ngn, _ := CurrencyNGN.Info()
+
+	assert.NoError(t, ngn.ValidateAmount(ngn.MinimumAmount))
+	assert.Error(t, ngn.ValidateAmount(ngn.MinimumAmount-1))
+
+	assert.NoError(t, ngn.ValidateChannels(ChannelCard, ChannelUSSD))
+	assert.ErrorContains(t, ngn.ValidateChannels(ChannelCard, ChannelMobileMoney), "mobile_money")
+
+	assert.NoError(t, ngn.ValidateTransferRecipientType(TransferRecipientTypeNuban))
+	assert.Error(t, ngn.ValidateTransferRecipientType(TransferRecipientTypeMobileMoney))
+
+	egp, _ := CurrencyEGP.Info()
+	assert.True(t, egp.SupportsTransferRecipientType(TransferRecipientTypeBankAccount))
+}
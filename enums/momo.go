@@ -1,6 +1,7 @@
 package enums
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 )
@@ -43,6 +44,22 @@ func (m *MoMo) UnmarshalJSON(data []byte) error {
 	}
 }
 
+// Scan implements sql.Scanner. SQL NULL and empty strings scan to the zero value.
+func (m *MoMo) Scan(src any) error {
+	v, err := scanEnum[MoMo](src, "MoMo")
+	if err != nil {
+		return err
+	}
+
+	*m = v
+	return nil
+}
+
+// Value implements driver.Valuer. The zero value is stored as SQL NULL.
+func (m MoMo) Value() (driver.Value, error) {
+	return enumValue(m)
+}
+
 // IsValid returns true if the mobile money provider is a valid known value
 func (m MoMo) IsValid() bool {
 	switch m {
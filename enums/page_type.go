@@ -1,6 +1,7 @@
 package enums
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 )
@@ -42,6 +43,22 @@ func (pt *PageType) UnmarshalJSON(data []byte) error {
 	}
 }
 
+// Scan implements sql.Scanner. SQL NULL and empty strings scan to the zero value.
+func (pt *PageType) Scan(src any) error {
+	v, err := scanEnum[PageType](src, "PageType")
+	if err != nil {
+		return err
+	}
+
+	*pt = v
+	return nil
+}
+
+// Value implements driver.Valuer. The zero value is stored as SQL NULL.
+func (pt PageType) Value() (driver.Value, error) {
+	return enumValue(pt)
+}
+
 // IsValid returns true if the page type is a valid known value
 func (pt PageType) IsValid() bool {
 	switch pt {
@@ -1,6 +1,7 @@
 package enums
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 )
@@ -41,6 +42,22 @@ func (rc *RefundChannel) UnmarshalJSON(data []byte) error {
 	}
 }
 
+// Scan implements sql.Scanner. SQL NULL and empty strings scan to the zero value.
+func (rc *RefundChannel) Scan(src any) error {
+	v, err := scanEnum[RefundChannel](src, "RefundChannel")
+	if err != nil {
+		return err
+	}
+
+	*rc = v
+	return nil
+}
+
+// Value implements driver.Valuer. The zero value is stored as SQL NULL.
+func (rc RefundChannel) Value() (driver.Value, error) {
+	return enumValue(rc)
+}
+
 // IsValid returns true if the refund channel is a valid known value
 func (rc RefundChannel) IsValid() bool {
 	switch rc {
@@ -1,6 +1,7 @@
 package enums
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 )
@@ -9,9 +10,11 @@ import (
 type RefundStatus string
 
 const (
-	RefundStatusPending   RefundStatus = "pending"
-	RefundStatusProcessed RefundStatus = "processed"
-	RefundStatusFailed    RefundStatus = "failed"
+	RefundStatusPending        RefundStatus = "pending"
+	RefundStatusProcessing     RefundStatus = "processing"
+	RefundStatusNeedsAttention RefundStatus = "needs-attention"
+	RefundStatusProcessed      RefundStatus = "processed"
+	RefundStatusFailed         RefundStatus = "failed"
 )
 
 // String returns the string representation of RefundStatus
@@ -33,7 +36,7 @@ func (rs *RefundStatus) UnmarshalJSON(data []byte) error {
 
 	status := RefundStatus(s)
 	switch status {
-	case RefundStatusPending, RefundStatusProcessed, RefundStatusFailed:
+	case RefundStatusPending, RefundStatusProcessing, RefundStatusNeedsAttention, RefundStatusProcessed, RefundStatusFailed:
 		*rs = status
 		return nil
 	default:
@@ -41,10 +44,26 @@ func (rs *RefundStatus) UnmarshalJSON(data []byte) error {
 	}
 }
 
+// Scan implements sql.Scanner. SQL NULL and empty strings scan to the zero value.
+func (rs *RefundStatus) Scan(src any) error {
+	v, err := scanEnum[RefundStatus](src, "RefundStatus")
+	if err != nil {
+		return err
+	}
+
+	*rs = v
+	return nil
+}
+
+// Value implements driver.Valuer. The zero value is stored as SQL NULL.
+func (rs RefundStatus) Value() (driver.Value, error) {
+	return enumValue(rs)
+}
+
 // IsValid returns true if the refund status is a valid known value
 func (rs RefundStatus) IsValid() bool {
 	switch rs {
-	case RefundStatusPending, RefundStatusProcessed, RefundStatusFailed:
+	case RefundStatusPending, RefundStatusProcessing, RefundStatusNeedsAttention, RefundStatusProcessed, RefundStatusFailed:
 		return true
 	default:
 		return false
@@ -55,6 +74,8 @@ func (rs RefundStatus) IsValid() bool {
 func AllRefundStatuses() []RefundStatus {
 	return []RefundStatus{
 		RefundStatusPending,
+		RefundStatusProcessing,
+		RefundStatusNeedsAttention,
 		RefundStatusProcessed,
 		RefundStatusFailed,
 	}
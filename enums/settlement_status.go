@@ -1,6 +1,7 @@
 package enums
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 )
@@ -41,6 +42,22 @@ func (ss *SettlementStatus) UnmarshalJSON(data []byte) error {
 	}
 }
 
+// Scan implements sql.Scanner. SQL NULL and empty strings scan to the zero value.
+func (ss *SettlementStatus) Scan(src any) error {
+	v, err := scanEnum[SettlementStatus](src, "SettlementStatus")
+	if err != nil {
+		return err
+	}
+
+	*ss = v
+	return nil
+}
+
+// Value implements driver.Valuer. The zero value is stored as SQL NULL.
+func (ss SettlementStatus) Value() (driver.Value, error) {
+	return enumValue(ss)
+}
+
 // IsValid returns true if the settlement status is a valid known value
 func (ss SettlementStatus) IsValid() bool {
 	switch ss {
@@ -0,0 +1,48 @@
+package enums
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// scanEnum converts a database/sql source value into E, accepting SQL NULL and empty
+// strings as the zero value and rejecting values that are not known members of E
+func scanEnum[E interface {
+	~string
+	IsValid() bool
+}](src any, name string) (E, error) {
+	e, err := scanString[E](src, name)
+	if err != nil {
+		return "", err
+	}
+
+	if e != "" && !e.IsValid() {
+		return "", fmt.Errorf("invalid %s value: %s", name, e)
+	}
+
+	return e, nil
+}
+
+// scanString converts a database/sql source value into E, accepting SQL NULL as the zero
+// value. It is used directly by enums that tolerate unknown values.
+func scanString[E ~string](src any, name string) (E, error) {
+	switch v := src.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return E(v), nil
+	case []byte:
+		return E(v), nil
+	default:
+		return "", fmt.Errorf("cannot scan %T into %s", src, name)
+	}
+}
+
+// enumValue converts e into a driver.Value, storing the zero value as SQL NULL
+func enumValue[E ~string](e E) (driver.Value, error) {
+	if e == "" {
+		return nil, nil
+	}
+
+	return string(e), nil
+}
@@ -0,0 +1,71 @@
+package enums
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatuses_TolerateUnknownValues(t *testing.T) {
+	var v struct {
+		Charge       ChargeStatus       `json:"charge"`
+		Transaction  TransactionStatus  `json:"transaction"`
+		Transfer     TransferStatus     `json:"transfer"`
+		Subscription SubscriptionStatus `json:"subscription"`
+		BulkCharge   BulkChargeStatus   `json:"bulk_charge"`
+	}
+
+	err := json.Unmarshal([]byte(`{"charge":"send_pin","transaction":"chargeback","transfer":"","subscription":null,"bulk_charge":"success"}`), &v)
+	require.NoError(t, err)
+
+	assert.Equal(t, ChargeStatusSendPIN, v.Charge)
+	assert.Equal(t, TransactionStatus("chargeback"), v.Transaction)
+	assert.False(t, v.Transaction.IsValid())
+	assert.False(t, v.Transaction.IsTerminal())
+	assert.Equal(t, TransferStatus(""), v.Transfer)
+	assert.Equal(t, SubscriptionStatus(""), v.Subscription)
+	assert.Equal(t, BulkChargeStatusSuccess, v.BulkCharge)
+
+	var s TransferStatus
+	require.NoError(t, s.Scan([]byte("on_hold")))
+	assert.Equal(t, TransferStatus("on_hold"), s)
+}
+
+func TestStatuses_IsTerminal(t *testing.T) {
+	tests := []struct {
+		status interface {
+			IsTerminal() bool
+			IsSuccessful() bool
+		}
+		terminal   bool
+		successful bool
+	}{
+		{ChargeStatusSendOTP, false, false},
+		{ChargeStatusPending, false, false},
+		{ChargeStatusPendingBankTransfer, false, false},
+		{ChargeStatusSuccess, true, true},
+		{ChargeStatusFailed, true, false},
+		{TransactionStatusAbandoned, false, false},
+		{TransactionStatusOngoing, false, false},
+		{TransactionStatusSuccess, true, true},
+		{TransactionStatusReversed, true, false},
+		{TransferStatusOTP, false, false},
+		{TransferStatusReceived, false, false},
+		{TransferStatusSuccess, true, true},
+		{TransferStatusBlocked, true, false},
+		{SubscriptionStatusAttention, false, false},
+		{SubscriptionStatusCancelled, true, false},
+		{SubscriptionStatusCompleted, true, true},
+		{BulkChargeStatusPending, false, false},
+		{BulkChargeStatusFailed, true, false},
+		{BulkChargeBatchStatusPaused, false, false},
+		{BulkChargeBatchStatusComplete, true, true},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.terminal, tt.status.IsTerminal(), "%T %v", tt.status, tt.status)
+		assert.Equal(t, tt.successful, tt.status.IsSuccessful(), "%T %v", tt.status, tt.status)
+	}
+}
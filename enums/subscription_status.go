@@ -0,0 +1,93 @@
+package enums
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+)
+
+// SubscriptionStatus represents the status of a subscription
+type SubscriptionStatus string
+
+const (
+	SubscriptionStatusActive      SubscriptionStatus = "active"
+	SubscriptionStatusNonRenewing SubscriptionStatus = "non-renewing"
+	SubscriptionStatusAttention   SubscriptionStatus = "attention"
+	SubscriptionStatusCompleted   SubscriptionStatus = "completed"
+	SubscriptionStatusCancelled   SubscriptionStatus = "cancelled"
+)
+
+// String returns the string representation of SubscriptionStatus
+func (ss SubscriptionStatus) String() string {
+	return string(ss)
+}
+
+// MarshalJSON implements json.Marshaler
+func (ss SubscriptionStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(ss))
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Unknown values are kept as they are; use
+// IsValid to check for them.
+func (ss *SubscriptionStatus) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	*ss = SubscriptionStatus(s)
+	return nil
+}
+
+// Scan implements sql.Scanner. SQL NULL and empty strings scan to the zero value, and
+// unknown values are kept as they are.
+func (ss *SubscriptionStatus) Scan(src any) error {
+	v, err := scanString[SubscriptionStatus](src, "SubscriptionStatus")
+	if err != nil {
+		return err
+	}
+
+	*ss = v
+	return nil
+}
+
+// Value implements driver.Valuer. The zero value is stored as SQL NULL.
+func (ss SubscriptionStatus) Value() (driver.Value, error) {
+	return enumValue(ss)
+}
+
+// IsValid returns true if the status is a known value
+func (ss SubscriptionStatus) IsValid() bool {
+	switch ss {
+	case SubscriptionStatusActive, SubscriptionStatusNonRenewing, SubscriptionStatusAttention, SubscriptionStatusCompleted, SubscriptionStatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsTerminal returns true if the subscription will not charge the customer again: completed
+// or cancelled
+func (ss SubscriptionStatus) IsTerminal() bool {
+	switch ss {
+	case SubscriptionStatusCompleted, SubscriptionStatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsSuccessful returns true if the subscription ran all its payments
+func (ss SubscriptionStatus) IsSuccessful() bool {
+	return ss == SubscriptionStatusCompleted
+}
+
+// AllSubscriptionStatuses returns all known SubscriptionStatus values
+func AllSubscriptionStatuses() []SubscriptionStatus {
+	return []SubscriptionStatus{
+		SubscriptionStatusActive,
+		SubscriptionStatusNonRenewing,
+		SubscriptionStatusAttention,
+		SubscriptionStatusCompleted,
+		SubscriptionStatusCancelled,
+	}
+}
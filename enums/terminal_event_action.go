@@ -1,6 +1,7 @@
 package enums
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 )
@@ -41,6 +42,22 @@ func (tea *TerminalEventAction) UnmarshalJSON(data []byte) error {
 	}
 }
 
+// Scan implements sql.Scanner. SQL NULL and empty strings scan to the zero value.
+func (tea *TerminalEventAction) Scan(src any) error {
+	v, err := scanEnum[TerminalEventAction](src, "TerminalEventAction")
+	if err != nil {
+		return err
+	}
+
+	*tea = v
+	return nil
+}
+
+// Value implements driver.Valuer. The zero value is stored as SQL NULL.
+func (tea TerminalEventAction) Value() (driver.Value, error) {
+	return enumValue(tea)
+}
+
 // IsValid returns true if the terminal event action is a valid known value
 func (tea TerminalEventAction) IsValid() bool {
 	switch tea {
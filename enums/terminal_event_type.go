@@ -1,6 +1,7 @@
 package enums
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 )
@@ -41,6 +42,22 @@ func (tet *TerminalEventType) UnmarshalJSON(data []byte) error {
 	}
 }
 
+// Scan implements sql.Scanner. SQL NULL and empty strings scan to the zero value.
+func (tet *TerminalEventType) Scan(src any) error {
+	v, err := scanEnum[TerminalEventType](src, "TerminalEventType")
+	if err != nil {
+		return err
+	}
+
+	*tet = v
+	return nil
+}
+
+// Value implements driver.Valuer. The zero value is stored as SQL NULL.
+func (tet TerminalEventType) Value() (driver.Value, error) {
+	return enumValue(tet)
+}
+
 // IsValid returns true if the terminal event type is a valid known value
 func (tet TerminalEventType) IsValid() bool {
 	switch tet {
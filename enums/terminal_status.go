@@ -1,6 +1,7 @@
 package enums
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 )
@@ -41,6 +42,22 @@ func (ts *TerminalStatus) UnmarshalJSON(data []byte) error {
 	}
 }
 
+// Scan implements sql.Scanner. SQL NULL and empty strings scan to the zero value.
+func (ts *TerminalStatus) Scan(src any) error {
+	v, err := scanEnum[TerminalStatus](src, "TerminalStatus")
+	if err != nil {
+		return err
+	}
+
+	*ts = v
+	return nil
+}
+
+// Value implements driver.Valuer. The zero value is stored as SQL NULL.
+func (ts TerminalStatus) Value() (driver.Value, error) {
+	return enumValue(ts)
+}
+
 // IsValid returns true if the terminal status is a valid known value
 func (ts TerminalStatus) IsValid() bool {
 	switch ts {
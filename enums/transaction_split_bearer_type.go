@@ -1,6 +1,7 @@
 package enums
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 )
@@ -41,6 +42,22 @@ func (tsbt *TransactionSplitBearerType) UnmarshalJSON(data []byte) error {
 	}
 }
 
+// Scan implements sql.Scanner. SQL NULL and empty strings scan to the zero value.
+func (tsbt *TransactionSplitBearerType) Scan(src any) error {
+	v, err := scanEnum[TransactionSplitBearerType](src, "TransactionSplitBearerType")
+	if err != nil {
+		return err
+	}
+
+	*tsbt = v
+	return nil
+}
+
+// Value implements driver.Valuer. The zero value is stored as SQL NULL.
+func (tsbt TransactionSplitBearerType) Value() (driver.Value, error) {
+	return enumValue(tsbt)
+}
+
 // IsValid returns true if the transaction split bearer type is a valid known value
 func (tsbt TransactionSplitBearerType) IsValid() bool {
 	switch tsbt {
@@ -1,6 +1,7 @@
 package enums
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 )
@@ -40,6 +41,22 @@ func (tst *TransactionSplitType) UnmarshalJSON(data []byte) error {
 	}
 }
 
+// Scan implements sql.Scanner. SQL NULL and empty strings scan to the zero value.
+func (tst *TransactionSplitType) Scan(src any) error {
+	v, err := scanEnum[TransactionSplitType](src, "TransactionSplitType")
+	if err != nil {
+		return err
+	}
+
+	*tst = v
+	return nil
+}
+
+// Value implements driver.Valuer. The zero value is stored as SQL NULL.
+func (tst TransactionSplitType) Value() (driver.Value, error) {
+	return enumValue(tst)
+}
+
 // IsValid returns true if the transaction split type is a valid known value
 func (tst TransactionSplitType) IsValid() bool {
 	switch tst {
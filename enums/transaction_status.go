@@ -0,0 +1,100 @@
+package enums
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+)
+
+// TransactionStatus represents the status of a transaction
+type TransactionStatus string
+
+const (
+	TransactionStatusAbandoned  TransactionStatus = "abandoned"
+	TransactionStatusFailed     TransactionStatus = "failed"
+	TransactionStatusOngoing    TransactionStatus = "ongoing"
+	TransactionStatusPending    TransactionStatus = "pending"
+	TransactionStatusProcessing TransactionStatus = "processing"
+	TransactionStatusQueued     TransactionStatus = "queued"
+	TransactionStatusReversed   TransactionStatus = "reversed"
+	TransactionStatusSuccess    TransactionStatus = "success"
+)
+
+// String returns the string representation of TransactionStatus
+func (ts TransactionStatus) String() string {
+	return string(ts)
+}
+
+// MarshalJSON implements json.Marshaler
+func (ts TransactionStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(ts))
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Unknown values are kept as they are; use
+// IsValid to check for them.
+func (ts *TransactionStatus) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	*ts = TransactionStatus(s)
+	return nil
+}
+
+// Scan implements sql.Scanner. SQL NULL and empty strings scan to the zero value, and
+// unknown values are kept as they are.
+func (ts *TransactionStatus) Scan(src any) error {
+	v, err := scanString[TransactionStatus](src, "TransactionStatus")
+	if err != nil {
+		return err
+	}
+
+	*ts = v
+	return nil
+}
+
+// Value implements driver.Valuer. The zero value is stored as SQL NULL.
+func (ts TransactionStatus) Value() (driver.Value, error) {
+	return enumValue(ts)
+}
+
+// IsValid returns true if the status is a known value
+func (ts TransactionStatus) IsValid() bool {
+	switch ts {
+	case TransactionStatusAbandoned, TransactionStatusFailed, TransactionStatusOngoing, TransactionStatusPending, TransactionStatusProcessing, TransactionStatusQueued, TransactionStatusReversed, TransactionStatusSuccess:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsTerminal returns true if the transaction has reached its final outcome: success, failed
+// or reversed. A success may still be reversed. Abandoned is not terminal, since it is also
+// the status of an initialized transaction the customer has not paid yet.
+func (ts TransactionStatus) IsTerminal() bool {
+	switch ts {
+	case TransactionStatusSuccess, TransactionStatusFailed, TransactionStatusReversed:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsSuccessful returns true if the transaction succeeded
+func (ts TransactionStatus) IsSuccessful() bool {
+	return ts == TransactionStatusSuccess
+}
+
+// AllTransactionStatuses returns all known TransactionStatus values
+func AllTransactionStatuses() []TransactionStatus {
+	return []TransactionStatus{
+		TransactionStatusAbandoned,
+		TransactionStatusFailed,
+		TransactionStatusOngoing,
+		TransactionStatusPending,
+		TransactionStatusProcessing,
+		TransactionStatusQueued,
+		TransactionStatusReversed,
+		TransactionStatusSuccess,
+	}
+}
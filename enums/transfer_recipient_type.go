@@ -1,6 +1,7 @@
 package enums
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 )
@@ -16,6 +17,8 @@ const (
 	TransferRecipientTypeDomesticCGI      TransferRecipientType = "domiciliary_cgi"
 	TransferRecipientTypeBankAccount      TransferRecipientType = "bank_account"
 	TransferRecipientTypeInternationalCGI TransferRecipientType = "international_cgi"
+	TransferRecipientTypeBasa             TransferRecipientType = "basa"
+	TransferRecipientTypeKepss            TransferRecipientType = "kepss"
 )
 
 // String returns the string representation of TransferRecipientType
@@ -39,7 +42,7 @@ func (trt *TransferRecipientType) UnmarshalJSON(data []byte) error {
 	switch recipientType {
 	case TransferRecipientTypeNuban, TransferRecipientTypeMobileMoney, TransferRecipientTypeAuthorization,
 		TransferRecipientTypeGhipss, TransferRecipientTypeDomesticCGI, TransferRecipientTypeBankAccount,
-		TransferRecipientTypeInternationalCGI:
+		TransferRecipientTypeInternationalCGI, TransferRecipientTypeBasa, TransferRecipientTypeKepss:
 		*trt = recipientType
 		return nil
 	default:
@@ -47,12 +50,28 @@ func (trt *TransferRecipientType) UnmarshalJSON(data []byte) error {
 	}
 }
 
+// Scan implements sql.Scanner. SQL NULL and empty strings scan to the zero value.
+func (trt *TransferRecipientType) Scan(src any) error {
+	v, err := scanEnum[TransferRecipientType](src, "TransferRecipientType")
+	if err != nil {
+		return err
+	}
+
+	*trt = v
+	return nil
+}
+
+// Value implements driver.Valuer. The zero value is stored as SQL NULL.
+func (trt TransferRecipientType) Value() (driver.Value, error) {
+	return enumValue(trt)
+}
+
 // IsValid returns true if the transfer recipient type is a valid known value
 func (trt TransferRecipientType) IsValid() bool {
 	switch trt {
 	case TransferRecipientTypeNuban, TransferRecipientTypeMobileMoney, TransferRecipientTypeAuthorization,
 		TransferRecipientTypeGhipss, TransferRecipientTypeDomesticCGI, TransferRecipientTypeBankAccount,
-		TransferRecipientTypeInternationalCGI:
+		TransferRecipientTypeInternationalCGI, TransferRecipientTypeBasa, TransferRecipientTypeKepss:
 		return true
 	default:
 		return false
@@ -69,5 +88,7 @@ func AllTransferRecipientTypes() []TransferRecipientType {
 		TransferRecipientTypeDomesticCGI,
 		TransferRecipientTypeBankAccount,
 		TransferRecipientTypeInternationalCGI,
+		TransferRecipientTypeBasa,
+		TransferRecipientTypeKepss,
 	}
 }
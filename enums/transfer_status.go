@@ -0,0 +1,103 @@
+package enums
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+)
+
+// TransferStatus represents the status of a transfer
+type TransferStatus string
+
+const (
+	TransferStatusPending    TransferStatus = "pending"
+	TransferStatusOTP        TransferStatus = "otp"
+	TransferStatusReceived   TransferStatus = "received"
+	TransferStatusProcessing TransferStatus = "processing"
+	TransferStatusSuccess    TransferStatus = "success"
+	TransferStatusFailed     TransferStatus = "failed"
+	TransferStatusReversed   TransferStatus = "reversed"
+	TransferStatusAbandoned  TransferStatus = "abandoned"
+	TransferStatusBlocked    TransferStatus = "blocked"
+	TransferStatusRejected   TransferStatus = "rejected"
+)
+
+// String returns the string representation of TransferStatus
+func (ts TransferStatus) String() string {
+	return string(ts)
+}
+
+// MarshalJSON implements json.Marshaler
+func (ts TransferStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(ts))
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Unknown values are kept as they are; use
+// IsValid to check for them.
+func (ts *TransferStatus) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	*ts = TransferStatus(s)
+	return nil
+}
+
+// Scan implements sql.Scanner. SQL NULL and empty strings scan to the zero value, and
+// unknown values are kept as they are.
+func (ts *TransferStatus) Scan(src any) error {
+	v, err := scanString[TransferStatus](src, "TransferStatus")
+	if err != nil {
+		return err
+	}
+
+	*ts = v
+	return nil
+}
+
+// Value implements driver.Valuer. The zero value is stored as SQL NULL.
+func (ts TransferStatus) Value() (driver.Value, error) {
+	return enumValue(ts)
+}
+
+// IsValid returns true if the status is a known value
+func (ts TransferStatus) IsValid() bool {
+	switch ts {
+	case TransferStatusPending, TransferStatusOTP, TransferStatusReceived, TransferStatusProcessing, TransferStatusSuccess, TransferStatusFailed, TransferStatusReversed, TransferStatusAbandoned, TransferStatusBlocked, TransferStatusRejected:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsTerminal returns true if the transfer has reached its final outcome. A success may still
+// be reversed.
+func (ts TransferStatus) IsTerminal() bool {
+	switch ts {
+	case TransferStatusSuccess, TransferStatusFailed, TransferStatusReversed, TransferStatusAbandoned, TransferStatusBlocked, TransferStatusRejected:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsSuccessful returns true if the transfer reached the recipient
+func (ts TransferStatus) IsSuccessful() bool {
+	return ts == TransferStatusSuccess
+}
+
+// AllTransferStatuses returns all known TransferStatus values
+func AllTransferStatuses() []TransferStatus {
+	return []TransferStatus{
+		TransferStatusPending,
+		TransferStatusOTP,
+		TransferStatusReceived,
+		TransferStatusProcessing,
+		TransferStatusSuccess,
+		TransferStatusFailed,
+		TransferStatusReversed,
+		TransferStatusAbandoned,
+		TransferStatusBlocked,
+		TransferStatusRejected,
+	}
+}
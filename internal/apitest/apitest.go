@@ -0,0 +1,225 @@
+// Package apitest provides a fake Paystack API for tests. A Server answers requests with
+// canned replies, in order, and records every request it receives.
+package apitest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/huysamen/paystack-go/api"
+)
+
+// Reply is a canned response
+type Reply struct {
+	Code int
+	Body string
+}
+
+// OK replies with a successful response wrapping data, a JSON value
+func OK(data string) Reply {
+	return Reply{http.StatusOK, fmt.Sprintf(`{"status":true,"message":"ok","data":%s}`, data)}
+}
+
+// Rejected replies with a 400 and message, like Paystack's validation and business errors
+func Rejected(message string) Reply {
+	return Failed(http.StatusBadRequest, message)
+}
+
+// Failed replies with a failed response with the status code and message
+func Failed(code int, message string) Reply {
+	return Reply{code, fmt.Sprintf(`{"status":false,"message":%q}`, message)}
+}
+
+// NotFound replies with a 404 and message
+func NotFound(message string) Reply {
+	return Failed(http.StatusNotFound, message)
+}
+
+// RateLimited replies with a 429
+func RateLimited() Reply {
+	return Failed(http.StatusTooManyRequests, "Too many requests")
+}
+
+// Unauthorized replies with a 401
+func Unauthorized() Reply {
+	return Failed(http.StatusUnauthorized, "Invalid key")
+}
+
+// ServerError replies with a 502 and no body
+var ServerError = Reply{Code: http.StatusBadGateway}
+
+// Fixture replies with a file under resources/examples/responses, e.g.
+// "charge/check_pending_200.json". Files named *_400.json are sent with a 400.
+func Fixture(t testing.TB, name string) Reply {
+	t.Helper()
+
+	b, err := os.ReadFile(filepath.Join(responsesDir(), filepath.FromSlash(name)))
+	if err != nil {
+		t.Fatalf("reading fixture %s: %v", name, err)
+	}
+
+	code := http.StatusOK
+	if strings.HasSuffix(name, "_400.json") {
+		code = http.StatusBadRequest
+	}
+
+	return Reply{code, string(b)}
+}
+
+func responsesDir() string {
+	_, file, _, _ := runtime.Caller(0)
+
+	return filepath.Join(filepath.Dir(file), "..", "..", "resources", "examples", "responses")
+}
+
+// Backoff polls every millisecond, for webhook.Awaiter and the waits built on it
+func Backoff(int) time.Duration { return time.Millisecond }
+
+// Request is a request received by a Server
+type Request struct {
+	Method string
+	Path   string
+	Query  string
+	Body   map[string]any
+}
+
+// Server is a fake Paystack API. Replies queued with On answer requests to their path;
+// replies queued with Reply answer requests to any other path. A request with no reply
+// left goes to the Fallback handler, or fails the test when there is none.
+type Server struct {
+	t   testing.TB
+	srv *httptest.Server
+
+	mu       sync.Mutex
+	replies  []Reply
+	paths    map[string][]Reply
+	fallback http.Handler
+	requests []Request
+}
+
+// NewServer starts a Server that is closed when the test ends
+func NewServer(t testing.TB) *Server {
+	s := &Server{t: t, paths: make(map[string][]Reply)}
+	s.srv = httptest.NewServer(s)
+	t.Cleanup(s.srv.Close)
+
+	return s
+}
+
+// Reply queues replies for requests to any path
+func (s *Server) Reply(replies ...Reply) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.replies = append(s.replies, replies...)
+
+	return s
+}
+
+// On queues replies for requests to path
+func (s *Server) On(path string, replies ...Reply) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.paths[path] = append(s.paths[path], replies...)
+
+	return s
+}
+
+// Fallback sets the handler for requests with no reply queued, e.g. a fake that keeps
+// state across requests. It is called without holding the server's lock, so requests to
+// it can overlap.
+func (s *Server) Fallback(h http.Handler) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fallback = h
+
+	return s
+}
+
+// API returns an api.API that sends requests to the server
+func (s *Server) API() *api.API {
+	return &api.API{Client: s.srv.Client(), Secret: "sk_test", BaseURL: s.srv.URL}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b, _ := io.ReadAll(r.Body)
+
+	var body map[string]any
+	_ = json.Unmarshal(b, &body)
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{Method: r.Method, Path: r.URL.Path, Query: r.URL.RawQuery, Body: body})
+
+	var next Reply
+	switch {
+	case len(s.paths[r.URL.Path]) > 0:
+		next, s.paths[r.URL.Path] = s.paths[r.URL.Path][0], s.paths[r.URL.Path][1:]
+	case len(s.replies) > 0:
+		next, s.replies = s.replies[0], s.replies[1:]
+	case s.fallback != nil:
+		fallback := s.fallback
+		s.mu.Unlock()
+
+		r.Body = io.NopCloser(bytes.NewReader(b))
+		fallback.ServeHTTP(w, r)
+		return
+	default:
+		s.mu.Unlock()
+		s.t.Errorf("unexpected request to %s", r.URL.Path)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(next.Code)
+	_, _ = w.Write([]byte(next.Body))
+}
+
+// Requests returns the requests received so far
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Request(nil), s.requests...)
+}
+
+// Paths returns the paths of the requests received so far
+func (s *Server) Paths() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var paths []string
+	for _, r := range s.requests {
+		paths = append(paths, r.Path)
+	}
+
+	return paths
+}
+
+// Body returns the body of the last request to path, or nil
+func (s *Server) Body(path string) map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := len(s.requests) - 1; i >= 0; i-- {
+		if s.requests[i].Path == path {
+			return s.requests[i].Body
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,565 @@
+// Package qrcode encodes data as QR codes (ISO/IEC 18004) in byte mode
+package qrcode
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Level is an error correction level
+type Level int
+
+const (
+	// Low recovers about 7% of the symbol
+	Low Level = iota
+	// Medium recovers about 15% of the symbol
+	Medium
+	// Quartile recovers about 25% of the symbol
+	Quartile
+	// High recovers about 30% of the symbol
+	High
+)
+
+// formatBits are the two bits that identify a level in the format information
+func (l Level) formatBits() int {
+	return [...]int{1, 0, 3, 2}[l]
+}
+
+// ErrTooLong is returned when data does not fit in a version 40 symbol
+var ErrTooLong = errors.New("data too long for a QR code")
+
+// Code is an encoded QR code symbol
+type Code struct {
+	// Version is the symbol version, from 1 to 40
+	Version int
+	// Size is the number of modules on each side
+	Size    int
+	modules [][]bool
+	// function marks the modules of finder, timing, alignment, format and version patterns
+	function [][]bool
+}
+
+// Dark reports whether the module at column x and row y is dark. Coordinates outside the
+// symbol are light.
+func (c *Code) Dark(x, y int) bool {
+	if x < 0 || y < 0 || x >= c.Size || y >= c.Size {
+		return false
+	}
+
+	return c.modules[y][x]
+}
+
+// Encode encodes data in byte mode at the smallest version that fits it at level
+func Encode(data []byte, level Level) (*Code, error) {
+	if level < Low || level > High {
+		return nil, fmt.Errorf("invalid error correction level %d", level)
+	}
+
+	version := 0
+	for v := 1; v <= 40; v++ {
+		if 4+countBits(v)+8*len(data) <= 8*dataCodewords(v, level) {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, ErrTooLong
+	}
+
+	// Byte mode segment, terminator and padding
+	var bits bitBuffer
+	bits.append(0b0100, 4)
+	bits.append(len(data), countBits(version))
+	for _, b := range data {
+		bits.append(int(b), 8)
+	}
+
+	capacity := 8 * dataCodewords(version, level)
+	bits.append(0, min(4, capacity-len(bits)))
+	bits.append(0, (8-len(bits)%8)%8)
+	for pad := 0xEC; len(bits) < capacity; pad ^= 0xEC ^ 0x11 {
+		bits.append(pad, 8)
+	}
+
+	c := newCode(version)
+	c.drawFunctionPatterns(level)
+	c.drawCodewords(interleave(bits.bytes(), version, level))
+
+	best, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		c.applyMask(mask)
+		c.drawFormatBits(level, mask)
+
+		if penalty := c.penalty(); bestPenalty < 0 || penalty < bestPenalty {
+			best, bestPenalty = mask, penalty
+		}
+
+		// Masking is its own inverse
+		c.applyMask(mask)
+	}
+
+	c.applyMask(best)
+	c.drawFormatBits(level, best)
+
+	return c, nil
+}
+
+func newCode(version int) *Code {
+	size := 4*version + 17
+
+	c := &Code{Version: version, Size: size}
+	c.modules = make([][]bool, size)
+	c.function = make([][]bool, size)
+	for y := range c.modules {
+		c.modules[y] = make([]bool, size)
+		c.function[y] = make([]bool, size)
+	}
+
+	return c
+}
+
+func (c *Code) setFunction(x, y int, dark bool) {
+	c.modules[y][x] = dark
+	c.function[y][x] = true
+}
+
+func (c *Code) drawFunctionPatterns(level Level) {
+	for i := 0; i < c.Size; i++ {
+		c.setFunction(6, i, i%2 == 0)
+		c.setFunction(i, 6, i%2 == 0)
+	}
+
+	c.drawFinder(3, 3)
+	c.drawFinder(c.Size-4, 3)
+	c.drawFinder(3, c.Size-4)
+
+	positions := alignmentPositions(c.Version)
+	last := len(positions) - 1
+	for i, x := range positions {
+		for j, y := range positions {
+			// Skip the corners taken by finder patterns
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			c.drawAlignment(x, y)
+		}
+	}
+
+	// Reserve the format areas; the bits are drawn once the mask is chosen
+	c.drawFormatBits(level, 0)
+	c.drawVersion()
+}
+
+// drawFinder draws a finder pattern and its separator centred on x, y
+func (c *Code) drawFinder(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx < 0 || yy < 0 || xx >= c.Size || yy >= c.Size {
+				continue
+			}
+
+			d := max(abs(dx), abs(dy))
+			c.setFunction(xx, yy, d != 2 && d != 4)
+		}
+	}
+}
+
+func (c *Code) drawAlignment(x, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			c.setFunction(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+		}
+	}
+}
+
+func (c *Code) drawFormatBits(level Level, mask int) {
+	bits := formatInfo(level, mask)
+
+	// Around the top left finder
+	for i := 0; i <= 5; i++ {
+		c.setFunction(8, i, bit(bits, i))
+	}
+	c.setFunction(8, 7, bit(bits, 6))
+	c.setFunction(8, 8, bit(bits, 7))
+	c.setFunction(7, 8, bit(bits, 8))
+	for i := 9; i < 15; i++ {
+		c.setFunction(14-i, 8, bit(bits, i))
+	}
+
+	// Split between the top right and bottom left finders
+	for i := 0; i < 8; i++ {
+		c.setFunction(c.Size-1-i, 8, bit(bits, i))
+	}
+	for i := 8; i < 15; i++ {
+		c.setFunction(8, c.Size-15+i, bit(bits, i))
+	}
+	c.setFunction(8, c.Size-8, true)
+}
+
+func (c *Code) drawVersion() {
+	if c.Version < 7 {
+		return
+	}
+
+	bits := versionInfo(c.Version)
+	for i := 0; i < 18; i++ {
+		a, b := c.Size-11+i%3, i/3
+		c.setFunction(a, b, bit(bits, i))
+		c.setFunction(b, a, bit(bits, i))
+	}
+}
+
+// drawCodewords places the codewords in the zigzag order of the symbol, in pairs of
+// columns from the right, skipping the vertical timing pattern
+func (c *Code) drawCodewords(codewords []byte) {
+	i := 0
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+
+		for vert := 0; vert < c.Size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = c.Size - 1 - vert
+				}
+
+				if !c.function[y][x] && i < len(codewords)*8 {
+					c.modules[y][x] = codewords[i>>3]>>(7-i&7)&1 == 1
+					i++
+				}
+			}
+		}
+	}
+}
+
+func (c *Code) applyMask(mask int) {
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if c.function[y][x] {
+				continue
+			}
+
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+
+			c.modules[y][x] = c.modules[y][x] != invert
+		}
+	}
+}
+
+// penalty scores the symbol with the four rules of the specification; masks with lower
+// scores are easier to scan
+func (c *Code) penalty() int {
+	score := 0
+	n := c.Size
+
+	at := func(horizontal bool, line, i int) bool {
+		if horizontal {
+			return c.modules[line][i]
+		}
+		return c.modules[i][line]
+	}
+
+	for _, horizontal := range []bool{true, false} {
+		for line := 0; line < n; line++ {
+			// Rule 1: runs of five or more modules of the same colour
+			run := 1
+			for i := 1; i < n; i++ {
+				if at(horizontal, line, i) == at(horizontal, line, i-1) {
+					run++
+					continue
+				}
+				if run >= 5 {
+					score += run - 2
+				}
+				run = 1
+			}
+			if run >= 5 {
+				score += run - 2
+			}
+
+			// Rule 3: finder-like 1:1:3:1:1 patterns with four light modules on one side
+			for i := 0; i+7 <= n; i++ {
+				if !finderLike(func(k int) bool { return at(horizontal, line, i+k) }) {
+					continue
+				}
+				if lightRun(func(k int) bool { return at(horizontal, line, k) }, i-4, i, n) ||
+					lightRun(func(k int) bool { return at(horizontal, line, k) }, i+7, i+11, n) {
+					score += 40
+				}
+			}
+		}
+	}
+
+	// Rule 2: 2x2 blocks of the same colour
+	for y := 0; y < n-1; y++ {
+		for x := 0; x < n-1; x++ {
+			d := c.modules[y][x]
+			if d == c.modules[y][x+1] && d == c.modules[y+1][x] && d == c.modules[y+1][x+1] {
+				score += 3
+			}
+		}
+	}
+
+	// Rule 4: deviation of the proportion of dark modules from 50%
+	dark := 0
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			if c.modules[y][x] {
+				dark++
+			}
+		}
+	}
+	deviation := abs(dark*20 - n*n*10)
+	score += (deviation / (n * n)) * 10
+
+	return score
+}
+
+func finderLike(dark func(int) bool) bool {
+	pattern := [7]bool{true, false, true, true, true, false, true}
+	for k, d := range pattern {
+		if dark(k) != d {
+			return false
+		}
+	}
+
+	return true
+}
+
+// lightRun reports whether modules from..to are light, counting modules beyond the edge of
+// the symbol as light
+func lightRun(dark func(int) bool, from, to, n int) bool {
+	for k := from; k < to; k++ {
+		if k >= 0 && k < n && dark(k) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// alignmentPositions returns the row and column centres of the alignment patterns
+func alignmentPositions(version int) []int {
+	if version == 1 {
+		return nil
+	}
+
+	count := version/7 + 2
+	step := (version*8 + count*3 + 5) / (count*4 - 4) * 2
+
+	positions := make([]int, count)
+	positions[0] = 6
+	for i, pos := count-1, 4*version+10; i >= 1; i, pos = i-1, pos-step {
+		positions[i] = pos
+	}
+
+	return positions
+}
+
+// formatInfo returns the 15 format bits: the level and mask with a BCH(15,5) code, XORed
+// with the fixed mask pattern
+func formatInfo(level Level, mask int) int {
+	data := level.formatBits()<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+
+	return (data<<10 | rem) ^ 0x5412
+}
+
+// versionInfo returns the 18 version bits: the version with a BCH(18,6) code
+func versionInfo(version int) int {
+	rem := version
+	for i := 0; i < 12; i++ {
+		rem = rem<<1 ^ (rem>>11)*0x1F25
+	}
+
+	return version<<12 | rem
+}
+
+// countBits is the length of the character count of a byte mode segment
+func countBits(version int) int {
+	if version <= 9 {
+		return 8
+	}
+
+	return 16
+}
+
+// rawDataModules is the number of modules available for data and error correction
+func rawDataModules(version int) int {
+	result := (16*version+128)*version + 64
+	if version >= 2 {
+		align := version/7 + 2
+		result -= (25*align-10)*align - 55
+		if version >= 7 {
+			result -= 36
+		}
+	}
+
+	return result
+}
+
+// dataCodewords is the number of data codewords in a symbol
+func dataCodewords(version int, level Level) int {
+	return rawDataModules(version)/8 - eccPerBlock[level][version]*eccBlocks[level][version]
+}
+
+// interleave splits data into blocks, appends the error correction codewords of each block
+// and interleaves the blocks
+func interleave(data []byte, version int, level Level) []byte {
+	blocks := eccBlocks[level][version]
+	eccLen := eccPerBlock[level][version]
+	raw := rawDataModules(version) / 8
+	short := blocks - raw%blocks
+	shortLen := raw / blocks
+
+	divisor := rsDivisor(eccLen)
+
+	all := make([][]byte, blocks)
+	k := 0
+	for i := range all {
+		n := shortLen - eccLen
+		if i >= short {
+			n++
+		}
+
+		block := append([]byte{}, data[k:k+n]...)
+		k += n
+
+		ecc := rsRemainder(block, divisor)
+		if i < short {
+			// Short blocks are padded so the blocks line up when interleaved
+			block = append(block, 0)
+		}
+		all[i] = append(block, ecc...)
+	}
+
+	result := make([]byte, 0, raw)
+	for i := range all[0] {
+		for j, block := range all {
+			if i != shortLen-eccLen || j >= short {
+				result = append(result, block[i])
+			}
+		}
+	}
+
+	return result
+}
+
+// rsDivisor returns the Reed-Solomon generator polynomial of the given degree, without its
+// leading term
+func rsDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = gfMultiply(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMultiply(root, 0x02)
+	}
+
+	return result
+}
+
+// rsRemainder returns the Reed-Solomon error correction codewords of data
+func rsRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+
+		for i, d := range divisor {
+			result[i] ^= gfMultiply(d, factor)
+		}
+	}
+
+	return result
+}
+
+// gfMultiply multiplies in GF(2^8) modulo x^8 + x^4 + x^3 + x^2 + 1
+func gfMultiply(x, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = z<<1 ^ (z>>7)*0x11D
+		z ^= int(y>>i&1) * int(x)
+	}
+
+	return byte(z)
+}
+
+type bitBuffer []bool
+
+func (b *bitBuffer) append(value, length int) {
+	for i := length - 1; i >= 0; i-- {
+		*b = append(*b, value>>i&1 == 1)
+	}
+}
+
+func (b bitBuffer) bytes() []byte {
+	result := make([]byte, len(b)/8)
+	for i, set := range b {
+		if set {
+			result[i>>3] |= 1 << (7 - i&7)
+		}
+	}
+
+	return result
+}
+
+func bit(value, i int) bool {
+	return value>>i&1 == 1
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+
+	return x
+}
+
+// eccPerBlock is the number of error correction codewords per block, by level and version
+var eccPerBlock = [4][41]int{
+	{-1, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+	{-1, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+}
+
+// eccBlocks is the number of error correction blocks, by level and version
+var eccBlocks = [4][41]int{
+	{-1, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+	{-1, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+	{-1, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+	{-1, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+}
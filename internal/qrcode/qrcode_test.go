@@ -0,0 +1,251 @@
+package qrcode
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRSRemainder(t *testing.T) {
+	// "HELLO WORLD" at 1-M, from the worked example of the specification
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+
+	assert.Equal(t, []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}, rsRemainder(data, rsDivisor(10)))
+}
+
+func TestFormatInfo(t *testing.T) {
+	tests := []struct {
+		level Level
+		mask  int
+		want  string
+	}{
+		{Low, 0, "111011111000100"},
+		{Medium, 0, "101010000010010"},
+		{Quartile, 0, "011010101011111"},
+		{High, 0, "001011010001001"},
+		{Low, 2, "111110110101010"},
+		{Low, 7, "110100101110110"},
+		{High, 7, "000100000111011"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, binary(formatInfo(tt.level, tt.mask), 15), "level %d mask %d", tt.level, tt.mask)
+	}
+}
+
+func TestVersionInfo(t *testing.T) {
+	assert.Equal(t, "000111110010010100", binary(versionInfo(7), 18))
+	assert.Equal(t, "101000110001101001", binary(versionInfo(40), 18))
+}
+
+func TestDataCodewords(t *testing.T) {
+	assert.Equal(t, 19, dataCodewords(1, Low))
+	assert.Equal(t, 16, dataCodewords(1, Medium))
+	assert.Equal(t, 9, dataCodewords(1, High))
+	assert.Equal(t, 2956, dataCodewords(40, Low))
+	assert.Equal(t, 1276, dataCodewords(40, High))
+
+	for level := Low; level <= High; level++ {
+		for v := 1; v <= 40; v++ {
+			raw := rawDataModules(v) / 8
+			assert.Less(t, dataCodewords(v, level), raw, "version %d level %d", v, level)
+			assert.Equal(t, raw, len(interleave(make([]byte, dataCodewords(v, level)), v, level)), "version %d level %d", v, level)
+		}
+	}
+}
+
+func TestAlignmentPositions(t *testing.T) {
+	assert.Empty(t, alignmentPositions(1))
+	assert.Equal(t, []int{6, 18}, alignmentPositions(2))
+	assert.Equal(t, []int{6, 22, 38}, alignmentPositions(7))
+	assert.Equal(t, []int{6, 26, 48, 70}, alignmentPositions(15))
+	assert.Equal(t, []int{6, 30, 58, 86, 114, 142, 170}, alignmentPositions(40))
+}
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		level   Level
+		version int
+	}{
+		{"smallest", "hello", Medium, 1},
+		{"full version 1", strings.Repeat("a", 17), Low, 1},
+		{"version 2", strings.Repeat("a", 18), Low, 2},
+		{"version info", strings.Repeat("a", 110), Medium, 7},
+		{"two byte count", strings.Repeat("a", 300), Medium, 13},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := Encode([]byte(tt.data), tt.level)
+			require.NoError(t, err)
+			assert.Equal(t, tt.version, c.Version)
+			assert.Equal(t, 4*tt.version+17, c.Size)
+
+			// Finder patterns in three corners
+			for _, corner := range [][2]int{{0, 0}, {c.Size - 7, 0}, {0, c.Size - 7}} {
+				x, y := corner[0], corner[1]
+				assert.True(t, c.Dark(x, y))
+				assert.False(t, c.Dark(x+1, y+1))
+				assert.True(t, c.Dark(x+3, y+3))
+			}
+
+			// Dark module and timing pattern
+			assert.True(t, c.Dark(8, c.Size-8))
+			assert.True(t, c.Dark(8, 6))
+			assert.False(t, c.Dark(9, 6))
+
+			assert.False(t, c.Dark(-1, 0))
+			assert.False(t, c.Dark(c.Size, 0))
+		})
+	}
+}
+
+func TestEncode_FormatMatchesMask(t *testing.T) {
+	c, err := Encode([]byte("https://paystack.com"), Quartile)
+	require.NoError(t, err)
+
+	// Both copies of the format information agree and decode to the level
+	var first, second int
+	for i := 0; i <= 5; i++ {
+		first |= b(c.Dark(8, i)) << i
+	}
+	first |= b(c.Dark(8, 7))<<6 | b(c.Dark(8, 8))<<7 | b(c.Dark(7, 8))<<8
+	for i := 9; i < 15; i++ {
+		first |= b(c.Dark(14-i, 8)) << i
+	}
+	for i := 0; i < 8; i++ {
+		second |= b(c.Dark(c.Size-1-i, 8)) << i
+	}
+	for i := 8; i < 15; i++ {
+		second |= b(c.Dark(8, c.Size-15+i)) << i
+	}
+
+	assert.Equal(t, first, second)
+	assert.Equal(t, Quartile.formatBits(), (first^0x5412)>>13)
+}
+
+func TestEncode_Errors(t *testing.T) {
+	_, err := Encode(make([]byte, 2954), Low)
+	assert.ErrorIs(t, err, ErrTooLong)
+
+	_, err = Encode(make([]byte, 2953), Low)
+	assert.NoError(t, err)
+
+	_, err = Encode([]byte("x"), Level(9))
+	assert.Error(t, err)
+}
+
+func binary(v, n int) string {
+	s := strconv.FormatInt(int64(v), 2)
+
+	return strings.Repeat("0", n-len(s)) + s
+}
+
+func b(dark bool) int {
+	if dark {
+		return 1
+	}
+
+	return 0
+}
+
+func TestEncode_RoundTrip(t *testing.T) {
+	for _, tt := range []struct {
+		data  string
+		level Level
+	}{
+		{"00020101021226540014NG.COM.PAYSTACK", Medium},
+		{strings.Repeat("paystack", 40), High},
+		{strings.Repeat("x", 1000), Low},
+	} {
+		c, err := Encode([]byte(tt.data), tt.level)
+		require.NoError(t, err)
+
+		assert.Equal(t, tt.data, string(decode(t, c)))
+	}
+}
+
+// decode reads the data back from a symbol, undoing the mask named by its format bits
+func decode(t *testing.T, c *Code) []byte {
+	var format int
+	for i := 0; i < 8; i++ {
+		format |= b(c.Dark(c.Size-1-i, 8)) << i
+	}
+	for i := 8; i < 15; i++ {
+		format |= b(c.Dark(8, c.Size-15+i)) << i
+	}
+	format ^= 0x5412
+
+	level := Level([]int{1, 0, 3, 2}[format>>13])
+	mask := format >> 10 & 7
+
+	c.applyMask(mask)
+	defer c.applyMask(mask)
+
+	var bits bitBuffer
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < c.Size; vert++ {
+			for j := 0; j < 2; j++ {
+				x, y := right-j, vert
+				if (right+1)&2 == 0 {
+					y = c.Size - 1 - vert
+				}
+				if !c.function[y][x] {
+					bits = append(bits, c.modules[y][x])
+				}
+			}
+		}
+	}
+	codewords := bits[:len(bits)/8*8].bytes()
+
+	// Undo the interleaving of the data codewords
+	blocks := eccBlocks[level][c.Version]
+	raw := rawDataModules(c.Version) / 8
+	short := blocks - raw%blocks
+	shortData := raw/blocks - eccPerBlock[level][c.Version]
+
+	var data []byte
+	for i := 0; i < blocks; i++ {
+		n := shortData
+		if i >= short {
+			n++
+		}
+		for k := 0; k < n; k++ {
+			idx := k*blocks + i
+			if k == shortData {
+				idx = k*blocks + i - short
+			}
+			data = append(data, codewords[idx])
+		}
+	}
+
+	var stream bitBuffer
+	for _, d := range data {
+		stream.append(int(d), 8)
+	}
+	require.Equal(t, bitBuffer{false, true, false, false}, stream[:4])
+
+	read := func(from, n int) int {
+		v := 0
+		for _, set := range stream[from : from+n] {
+			v = v<<1 | b(set)
+		}
+		return v
+	}
+
+	count := read(4, countBits(c.Version))
+	out := make([]byte, count)
+	for i := range out {
+		out[i] = byte(read(4+countBits(c.Version)+8*i, 8))
+	}
+
+	return out
+}
@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/huysamen/paystack-go/types"
+	"github.com/huysamen/paystack-go/types/drift"
 )
 
 const apiURL = "https://api.paystack.co"
@@ -112,89 +113,84 @@ func getHTTPErrorMessage(statusCode int, body []byte) string {
 // Get makes a GET request with context support
 func Get[O any](ctx context.Context, client *http.Client, secret, path string, baseURL ...string) (*types.Response[O], error) {
 	url := getBaseURL(baseURL...)
-	body, err := doReq(ctx, client, http.MethodGet, secret, url+path, nil)
+	body, code, err := doReq(ctx, client, http.MethodGet, secret, url+path, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	rsp := new(types.Response[O])
-
-	if len(body) > 0 {
-		err = json.Unmarshal(body, rsp)
-		if err != nil {
-			return nil, err
-		}
-	}
-
-	return rsp, nil
+	return decodeResponse[O](ctx, http.MethodGet, path, code, body)
 }
 
 // Post makes a POST request with context support
 func Post[I any, O any](ctx context.Context, client *http.Client, secret, path string, payload *I, baseURL ...string) (*types.Response[O], error) {
 	url := getBaseURL(baseURL...)
 
-	return putOrPost[I, O](ctx, client, http.MethodPost, secret, url+path, payload)
+	return putOrPost[I, O](ctx, client, http.MethodPost, secret, url, path, payload)
 }
 
 // Put makes a PUT request with context support
 func Put[I any, O any](ctx context.Context, client *http.Client, secret, path string, payload *I, baseURL ...string) (*types.Response[O], error) {
 	url := getBaseURL(baseURL...)
 
-	return putOrPost[I, O](ctx, client, http.MethodPut, secret, url+path, payload)
+	return putOrPost[I, O](ctx, client, http.MethodPut, secret, url, path, payload)
 }
 
 // Delete makes a DELETE request with context support
 func Delete[O any](ctx context.Context, client *http.Client, secret, path string, baseURL ...string) (*types.Response[O], error) {
 	url := getBaseURL(baseURL...)
-	body, err := doReq(ctx, client, http.MethodDelete, secret, url+path, nil)
+	body, code, err := doReq(ctx, client, http.MethodDelete, secret, url+path, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	rsp := new(types.Response[O])
-
-	if len(body) > 0 {
-		err = json.Unmarshal(body, rsp)
-		if err != nil {
-			return nil, err
-		}
-	}
-
-	return rsp, nil
+	return decodeResponse[O](ctx, http.MethodDelete, path, code, body)
 }
 
 // DeleteWithBody makes a DELETE request with a request body
 func DeleteWithBody[I any, O any](ctx context.Context, client *http.Client, secret, path string, payload *I, baseURL ...string) (*types.Response[O], error) {
 	url := getBaseURL(baseURL...)
-	body, err := doReq(ctx, client, http.MethodDelete, secret, url+path, payload)
+	body, code, err := doReq(ctx, client, http.MethodDelete, secret, url+path, payload)
 	if err != nil {
 		return nil, err
 	}
 
-	rsp := new(types.Response[O])
+	return decodeResponse[O](ctx, http.MethodDelete, path, code, body)
+}
 
-	if len(body) > 0 {
-		err = json.Unmarshal(body, rsp)
-		if err != nil {
-			return nil, err
-		}
+func putOrPost[I any, O any](ctx context.Context, client *http.Client, method, secret, baseURL, path string, payload *I) (*types.Response[O], error) {
+	body, code, err := doReq(ctx, client, method, secret, baseURL+path, payload)
+	if err != nil {
+		return nil, err
 	}
 
-	return rsp, nil
+	return decodeResponse[O](ctx, method, path, code, body)
 }
 
-func putOrPost[I any, O any](ctx context.Context, client *http.Client, method, secret, fullURL string, payload *I) (*types.Response[O], error) {
-	body, err := doReq(ctx, client, method, secret, fullURL, payload)
-	if err != nil {
-		return nil, err
+// decodeResponse unmarshals the response body and, when a drift reporter is set on the
+// context, reports any fields or coercions the response type does not account for. Client
+// errors without a JSON body, e.g. from a proxy, are returned as failed responses.
+func decodeResponse[O any](ctx context.Context, method, path string, code int, body []byte) (*types.Response[O], error) {
+	rsp := &types.Response[O]{StatusCode: code}
+
+	if len(body) == 0 || (code >= http.StatusBadRequest && !json.Valid(body)) {
+		if code >= http.StatusBadRequest {
+			rsp.Message = getHTTPErrorMessage(code, body)
+		}
+		return rsp, nil
 	}
 
-	rsp := new(types.Response[O])
+	if err := json.Unmarshal(body, rsp); err != nil {
+		return nil, err
+	}
 
-	if len(body) > 0 {
-		err = json.Unmarshal(body, rsp)
-		if err != nil {
-			return nil, err
+	if reporter := drift.ReporterFrom(ctx); reporter != nil {
+		if issues, err := drift.Check(body, rsp); err == nil && len(issues) > 0 {
+			endpoint, _, _ := strings.Cut(path, "?")
+			reporter(drift.Report{
+				Endpoint: method + " " + endpoint,
+				Type:     fmt.Sprintf("%T", rsp.Data),
+				Issues:   issues,
+			})
 		}
 	}
 
@@ -203,24 +199,24 @@ func putOrPost[I any, O any](ctx context.Context, client *http.Client, method, s
 
 // doReq performs the HTTP request. If the client's Transport is a header-injecting
 // RoundTripper, it can add default headers; otherwise we add minimal defaults here.
-func doReq(ctx context.Context, client *http.Client, method, secret, fullURL string, data any) ([]byte, error) {
+func doReq(ctx context.Context, client *http.Client, method, secret, fullURL string, data any) ([]byte, int, error) {
 	var req *http.Request
 	var err error
 
 	if data != nil {
 		d, err := json.Marshal(data)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 
 		req, err = http.NewRequestWithContext(ctx, method, fullURL, bytes.NewBuffer(d))
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 	} else {
 		req, err = http.NewRequestWithContext(ctx, method, fullURL, nil)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 	}
 
@@ -235,23 +231,23 @@ func doReq(ctx context.Context, client *http.Client, method, secret, fullURL str
 
 	rsp, err := client.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	defer func() { _ = rsp.Body.Close() }()
 
 	body, err := io.ReadAll(rsp.Body)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	// For server errors (5xx), return actual Go errors since these are system issues
 	if rsp.StatusCode >= 500 {
-		return nil, fmt.Errorf("paystack server error (HTTP %d): %s", rsp.StatusCode, getHTTPErrorMessage(rsp.StatusCode, body))
+		return nil, rsp.StatusCode, fmt.Errorf("paystack server error (HTTP %d): %s", rsp.StatusCode, getHTTPErrorMessage(rsp.StatusCode, body))
 	}
 
 	// For all other status codes (including 4xx client errors), return the body
 	// The response will be parsed by the calling function and API errors will be
 	// represented as Response objects with status: false
-	return body, nil
+	return body, rsp.StatusCode, nil
 }
@@ -0,0 +1,99 @@
+package net
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGet_Responses(t *testing.T) {
+	tests := []struct {
+		name         string
+		code         int
+		body         string
+		status       bool
+		message      string
+		retryable    bool
+		notFound     bool
+		unauthorized bool
+	}{
+		{
+			name:    "success",
+			code:    http.StatusOK,
+			body:    `{"status":true,"message":"Balances retrieved","data":null}`,
+			status:  true,
+			message: "Balances retrieved",
+		},
+		{
+			name:    "json client error",
+			code:    http.StatusBadRequest,
+			body:    `{"status":false,"message":"Invalid authorization code"}`,
+			message: "Invalid authorization code",
+		},
+		{
+			name:     "json not found",
+			code:     http.StatusNotFound,
+			body:     `{"status":false,"message":"Transfer not found"}`,
+			message:  "Transfer not found",
+			notFound: true,
+		},
+		{
+			name:      "bodyless rate limit",
+			code:      http.StatusTooManyRequests,
+			message:   "Rate limited: Too many requests, please try again later",
+			retryable: true,
+		},
+		{
+			name:         "text forbidden from a proxy",
+			code:         http.StatusForbidden,
+			body:         "Access denied",
+			message:      "Access denied",
+			unauthorized: true,
+		},
+		{
+			name:    "html client error",
+			code:    http.StatusBadRequest,
+			body:    "<html><body>" + strings.Repeat("Bad request ", 30) + "</body></html>",
+			message: "Bad request: The request was invalid or malformed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.code)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			rsp, err := Get[any](context.Background(), srv.Client(), "sk_test", "/balance", srv.URL)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.code, rsp.StatusCode)
+			assert.Equal(t, tt.status, rsp.Status.Bool())
+			assert.Equal(t, tt.message, rsp.Message)
+			assert.Equal(t, tt.retryable, rsp.Retryable())
+			assert.Equal(t, tt.notFound, rsp.NotFound())
+			assert.Equal(t, tt.unauthorized, rsp.Unauthorized())
+			if !tt.status {
+				assert.Error(t, rsp.Err())
+			}
+		})
+	}
+}
+
+func TestGet_ServerError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	rsp, err := Get[any](context.Background(), srv.Client(), "sk_test", "/balance", srv.URL)
+	assert.Nil(t, rsp)
+	assert.ErrorContains(t, err, "HTTP 502")
+}
@@ -23,6 +23,10 @@ type Authorization struct {
 	AccountName               data.NullString `json:"account_name"`
 	ReceiverBankAccountNumber data.NullString `json:"receiver_bank_account_number,omitempty"`
 	ReceiverBank              data.NullString `json:"receiver_bank,omitempty"`
+	SenderBank                data.NullString `json:"sender_bank,omitempty"`
+	SenderBankAccountNumber   data.NullString `json:"sender_bank_account_number,omitempty"`
+	SenderCountry             data.NullString `json:"sender_country,omitempty"`
+	SenderName                data.NullString `json:"sender_name,omitempty"`
 }
 
 // MandateAuthorization represents a mandate authorization
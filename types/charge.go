@@ -7,24 +7,34 @@ import (
 
 // Charge represents the charge data in API responses
 type Charge struct {
-	ID              data.Int       `json:"id"`
-	Domain          data.String    `json:"domain"`
-	Status          data.String    `json:"status"`
-	Reference       data.String    `json:"reference"`
-	Amount          data.Int       `json:"amount"`
-	Message         data.String    `json:"message"`
-	GatewayResponse data.String    `json:"gateway_response"`
-	PaidAt          data.NullTime  `json:"paid_at"`
-	CreatedAt       data.NullTime  `json:"created_at"`
-	Channel         enums.Channel  `json:"channel"`
-	Currency        enums.Currency `json:"currency"`
-	IPAddress       data.String    `json:"ip_address"`
-	Metadata        Metadata       `json:"metadata"`
-	Log             Metadata       `json:"log"`
-	Fees            data.Int       `json:"fees"`
-	RequestedAmount data.Int       `json:"requested_amount"`
-	TransactionDate data.NullTime  `json:"transaction_date"`
-	Plan            *Plan          `json:"plan"`
-	Authorization   *Authorization `json:"authorization"`
-	Customer        *Customer      `json:"customer"`
+	ID              data.Int           `json:"id"`
+	Domain          data.String        `json:"domain"`
+	Status          enums.ChargeStatus `json:"status"`
+	Reference       data.String        `json:"reference"`
+	Amount          data.Int           `json:"amount"`
+	Message         data.String        `json:"message"`
+	GatewayResponse data.String        `json:"gateway_response"`
+	DisplayText     data.NullString    `json:"display_text"`
+	URL             data.NullString    `json:"url"`
+	QRCode          data.NullString    `json:"qr_code"`
+	USSDCode        data.NullString    `json:"ussd_code"`
+	// AccountName, AccountNumber, Bank and AccountExpiresAt describe the temporary account
+	// a customer pays into for a bank transfer charge
+	AccountName      data.NullString `json:"account_name"`
+	AccountNumber    data.NullString `json:"account_number"`
+	Bank             *Bank           `json:"bank"`
+	AccountExpiresAt data.NullTime   `json:"account_expires_at"`
+	PaidAt           data.NullTime   `json:"paid_at"`
+	CreatedAt        data.NullTime   `json:"created_at"`
+	Channel          enums.Channel   `json:"channel"`
+	Currency         enums.Currency  `json:"currency"`
+	IPAddress        data.String     `json:"ip_address"`
+	Metadata         Metadata        `json:"metadata"`
+	Log              Metadata        `json:"log"`
+	Fees             data.Int        `json:"fees"`
+	RequestedAmount  data.Int        `json:"requested_amount"`
+	TransactionDate  data.NullTime   `json:"transaction_date"`
+	Plan             *Plan           `json:"plan"`
+	Authorization    *Authorization  `json:"authorization"`
+	Customer         *Customer       `json:"customer"`
 }
@@ -3,6 +3,8 @@ package types
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"strings"
 
 	"github.com/huysamen/paystack-go/types/data"
 )
@@ -13,6 +15,8 @@ type Response[T any] struct {
 	Message string    `json:"message"`
 	Data    T         `json:"data"`
 	Meta    *Meta     `json:"meta,omitempty"`
+	// StatusCode is the HTTP status code of the response
+	StatusCode int `json:"-"`
 }
 
 // IsSuccess reports whether the API call succeeded according to the response wrapper
@@ -32,6 +36,44 @@ func (r Response[T]) Err() error {
 	return nil
 }
 
+// NotFound reports whether the request failed because the resource does not exist. Paystack
+// answers some lookups of unknown references with a 400, so the message is checked too.
+func (r Response[T]) NotFound() bool {
+	if r.Status.Bool() {
+		return false
+	}
+
+	switch r.StatusCode {
+	case http.StatusNotFound:
+		return true
+	case 0, http.StatusBadRequest:
+		return strings.Contains(strings.ToLower(r.Message), "not found")
+	default:
+		return false
+	}
+}
+
+// Retryable reports whether the request failed for a reason that passes, e.g. rate
+// limiting, so it may be sent again unchanged
+func (r Response[T]) Retryable() bool {
+	if r.Status.Bool() {
+		return false
+	}
+
+	switch r.StatusCode {
+	case http.StatusRequestTimeout, http.StatusTooEarly, http.StatusTooManyRequests:
+		return true
+	default:
+		return false
+	}
+}
+
+// Unauthorized reports whether the request was refused for the secret key rather than its
+// content
+func (r Response[T]) Unauthorized() bool {
+	return !r.Status.Bool() && (r.StatusCode == http.StatusUnauthorized || r.StatusCode == http.StatusForbidden)
+}
+
 // Meta represents pagination and other metadata
 type Meta struct {
 	Next      data.NullString `json:"next,omitempty"`
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/huysamen/paystack-go/types/data"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -71,3 +72,29 @@ func TestMeta_UnmarshalJSON(t *testing.T) {
 		assert.False(t, meta.Next.Valid, "Next should be invalid for empty JSON")
 	})
 }
+
+func TestResponse_Failures(t *testing.T) {
+	tests := []struct {
+		name         string
+		rsp          Response[any]
+		notFound     bool
+		retryable    bool
+		unauthorized bool
+	}{
+		{name: "success", rsp: Response[any]{Status: data.NewBool(true), StatusCode: 200}},
+		{name: "404", rsp: Response[any]{Message: "Transfer not found", StatusCode: 404}, notFound: true},
+		{name: "400 not found", rsp: Response[any]{Message: "Transaction reference not found", StatusCode: 400}, notFound: true},
+		{name: "400 rejected", rsp: Response[any]{Message: "Invalid authorization code", StatusCode: 400}},
+		{name: "decoded without status code", rsp: Response[any]{Message: "Transaction reference not found"}, notFound: true},
+		{name: "429", rsp: Response[any]{Message: "Not found in rate window", StatusCode: 429}, retryable: true},
+		{name: "401", rsp: Response[any]{Message: "Invalid key", StatusCode: 401}, unauthorized: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.notFound, tt.rsp.NotFound())
+			assert.Equal(t, tt.retryable, tt.rsp.Retryable())
+			assert.Equal(t, tt.unauthorized, tt.rsp.Unauthorized())
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package data
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 	"strconv"
@@ -81,6 +82,23 @@ func (b Bool) MarshalJSON() ([]byte, error) {
 	return json.Marshal(bool(b))
 }
 
+// Scan implements sql.Scanner for Bool
+// SQL NULL is converted to false
+func (b *Bool) Scan(src any) error {
+	boolVal, _, err := scanBool(src)
+	if err != nil {
+		return err
+	}
+
+	*b = Bool(boolVal)
+	return nil
+}
+
+// Value implements driver.Valuer for Bool
+func (b Bool) Value() (driver.Value, error) {
+	return bool(b), nil
+}
+
 // Bool returns the bool value
 func (b Bool) Bool() bool {
 	return bool(b)
@@ -1,6 +1,7 @@
 package data
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"testing"
 
@@ -409,3 +410,91 @@ func TestBool_InStruct(t *testing.T) {
 		assert.Contains(t, string(data), `"value":true`)
 	})
 }
+
+func TestBool_Scan(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      any
+		expected Bool
+		wantErr  bool
+	}{
+		{
+			name:     "scans true",
+			src:      true,
+			expected: Bool(true),
+		},
+		{
+			name:     "scans false",
+			src:      false,
+			expected: Bool(false),
+		},
+		{
+			name:     "scans non-zero int64 as true",
+			src:      int64(1),
+			expected: Bool(true),
+		},
+		{
+			name:     "scans postgres text true",
+			src:      []byte("t"),
+			expected: Bool(true),
+		},
+		{
+			name:     "scans postgres text false",
+			src:      "f",
+			expected: Bool(false),
+		},
+		{
+			name:     "scans NULL as false",
+			src:      nil,
+			expected: Bool(false),
+		},
+		{
+			name:     "returns error for unrecognised string",
+			src:      "maybe",
+			expected: Bool(false),
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v Bool
+			err := v.Scan(tt.src)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, v)
+		})
+	}
+}
+
+func TestBool_Value(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    Bool
+		expected driver.Value
+	}{
+		{
+			name:     "returns true",
+			input:    Bool(true),
+			expected: true,
+		},
+		{
+			name:     "returns false",
+			input:    Bool(false),
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.input.Value()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
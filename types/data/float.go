@@ -1,8 +1,8 @@
 package data
 
 import (
+	"database/sql/driver"
 	"encoding/json"
-	"fmt"
 	"strconv"
 )
 
@@ -12,54 +12,38 @@ import (
 type Float float64
 
 // UnmarshalJSON implements json.Unmarshaler for Float
+// Null values and empty strings are converted to the zero value
 func (f *Float) UnmarshalJSON(data []byte) error {
-	// Handle null - convert to zero
-	if data == nil || string(data) == "null" {
-		*f = 0
-		return nil
+	var v float64
+	if _, err := decodeNull(data, &v, "Float"); err != nil {
+		return err
 	}
 
-	// Try to unmarshal as float64 first
-	var floatVal float64
+	*f = Float(v)
 
-	if err := json.Unmarshal(data, &floatVal); err == nil {
-		*f = Float(floatVal)
-		return nil
-	}
-
-	// Try to unmarshal as integer (JSON numbers can be int)
-	var intVal int64
-
-	if err := json.Unmarshal(data, &intVal); err == nil {
-		*f = Float(float64(intVal))
-		return nil
-	}
-
-	// Try to unmarshal as string and parse as float
-	var s string
-
-	if err := json.Unmarshal(data, &s); err == nil {
-		// Handle empty string and "null" string as zero
-		if s == "" || s == "null" {
-			*f = 0
-			return nil
-		}
+	return nil
+}
 
-		// Try to parse string as float
-		if parsed, err := strconv.ParseFloat(s, 64); err == nil {
-			*f = Float(parsed)
-			return nil
-		}
+// MarshalJSON implements json.Marshaler for Float
+func (f Float) MarshalJSON() ([]byte, error) {
+	return json.Marshal(float64(f))
+}
 
-		return fmt.Errorf("cannot parse string %q as float", s)
+// Scan implements sql.Scanner for Float
+// SQL NULL is converted to zero
+func (f *Float) Scan(src any) error {
+	floatVal, _, err := scanFloat(src)
+	if err != nil {
+		return err
 	}
 
-	return fmt.Errorf("cannot unmarshal %s into Float", string(data))
+	*f = Float(floatVal)
+	return nil
 }
 
-// MarshalJSON implements json.Marshaler for Float
-func (f Float) MarshalJSON() ([]byte, error) {
-	return json.Marshal(float64(f))
+// Value implements driver.Valuer for Float
+func (f Float) Value() (driver.Value, error) {
+	return float64(f), nil
 }
 
 // Float64 returns the float64 value
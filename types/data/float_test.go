@@ -1,6 +1,7 @@
 package data
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"math"
 	"testing"
@@ -397,3 +398,81 @@ func TestFloat_InStruct(t *testing.T) {
 		assert.Contains(t, string(data), `"value":456.78`)
 	})
 }
+
+func TestFloat_Scan(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      any
+		expected Float
+		wantErr  bool
+	}{
+		{
+			name:     "scans float64",
+			src:      float64(3.14),
+			expected: Float(3.14),
+		},
+		{
+			name:     "scans int64",
+			src:      int64(3),
+			expected: Float(3),
+		},
+		{
+			name:     "scans numeric bytes (NUMERIC columns)",
+			src:      []byte("12.50"),
+			expected: Float(12.5),
+		},
+		{
+			name:     "scans NULL as zero",
+			src:      nil,
+			expected: Float(0),
+		},
+		{
+			name:     "returns error for non-numeric string",
+			src:      "abc",
+			expected: Float(0),
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v Float
+			err := v.Scan(tt.src)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, v)
+		})
+	}
+}
+
+func TestFloat_Value(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    Float
+		expected driver.Value
+	}{
+		{
+			name:     "returns float64",
+			input:    Float(3.14),
+			expected: float64(3.14),
+		},
+		{
+			name:     "returns zero",
+			input:    Float(0),
+			expected: float64(0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.input.Value()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
@@ -1,8 +1,8 @@
 package data
 
 import (
+	"database/sql/driver"
 	"encoding/json"
-	"fmt"
 	"strconv"
 )
 
@@ -12,60 +12,38 @@ import (
 type Int int64
 
 // UnmarshalJSON implements json.Unmarshaler for Int
+// Null values and empty strings are converted to the zero value
 func (i *Int) UnmarshalJSON(data []byte) error {
-	// Handle null - convert to zero
-	if data == nil || string(data) == "null" {
-		*i = 0
-		return nil
+	var v int64
+	if _, err := decodeNull(data, &v, "Int"); err != nil {
+		return err
 	}
 
-	// Try to unmarshal as integer first
-	var intVal int64
+	*i = Int(v)
 
-	if err := json.Unmarshal(data, &intVal); err == nil {
-		*i = Int(intVal)
-		return nil
-	}
-
-	// Try to unmarshal as float64 (JSON numbers default to float64)
-	var floatVal float64
-
-	if err := json.Unmarshal(data, &floatVal); err == nil {
-		*i = Int(int64(floatVal)) // Truncate to integer
-		return nil
-	}
-
-	// Try to unmarshal as string and parse as integer
-	var s string
-
-	if err := json.Unmarshal(data, &s); err == nil {
-		// Handle empty string and "null" string as zero
-		if s == "" || s == "null" {
-			*i = 0
-			return nil
-		}
-
-		// Try to parse string as integer
-		if parsed, err := strconv.ParseInt(s, 10, 64); err == nil {
-			*i = Int(parsed)
-			return nil
-		}
+	return nil
+}
 
-		// Try to parse string as float and truncate to integer
-		if parsed, err := strconv.ParseFloat(s, 64); err == nil {
-			*i = Int(int64(parsed))
-			return nil
-		}
+// MarshalJSON implements json.Marshaler for Int
+func (i Int) MarshalJSON() ([]byte, error) {
+	return json.Marshal(int64(i))
+}
 
-		return fmt.Errorf("cannot parse string %q as integer", s)
+// Scan implements sql.Scanner for Int
+// SQL NULL is converted to zero
+func (i *Int) Scan(src any) error {
+	intVal, _, err := scanInt(src)
+	if err != nil {
+		return err
 	}
 
-	return fmt.Errorf("cannot unmarshal %s into Int", string(data))
+	*i = Int(intVal)
+	return nil
 }
 
-// MarshalJSON implements json.Marshaler for Int
-func (i Int) MarshalJSON() ([]byte, error) {
-	return json.Marshal(int64(i))
+// Value implements driver.Valuer for Int
+func (i Int) Value() (driver.Value, error) {
+	return int64(i), nil
 }
 
 // Int64 returns the int64 value
@@ -1,6 +1,7 @@
 package data
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"testing"
 
@@ -358,3 +359,92 @@ func TestInt_InStruct(t *testing.T) {
 		assert.Contains(t, string(data), `"value":456`)
 	})
 }
+
+func TestInt_Scan(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      any
+		expected Int
+		wantErr  bool
+	}{
+		{
+			name:     "scans int64",
+			src:      int64(42),
+			expected: Int(42),
+		},
+		{
+			name:     "scans float64 (truncated)",
+			src:      float64(42.9),
+			expected: Int(42),
+		},
+		{
+			name:     "scans numeric bytes",
+			src:      []byte("-17"),
+			expected: Int(-17),
+		},
+		{
+			name:     "scans numeric string",
+			src:      "123",
+			expected: Int(123),
+		},
+		{
+			name:     "scans NULL as zero",
+			src:      nil,
+			expected: Int(0),
+		},
+		{
+			name:     "returns error for non-numeric string",
+			src:      "abc",
+			expected: Int(0),
+			wantErr:  true,
+		},
+		{
+			name:     "returns error for unsupported type",
+			src:      struct{}{},
+			expected: Int(0),
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v Int
+			err := v.Scan(tt.src)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, v)
+		})
+	}
+}
+
+func TestInt_Value(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    Int
+		expected driver.Value
+	}{
+		{
+			name:     "returns int64",
+			input:    Int(42),
+			expected: int64(42),
+		},
+		{
+			name:     "returns zero",
+			input:    Int(0),
+			expected: int64(0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.input.Value()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
@@ -0,0 +1,424 @@
+package data
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Null represents a value of type T that may be null in JSON or SQL
+// string, int64, uint64, float64, bool and time.Time use the same lenient parsing as the
+// NullString, NullInt, NullUint, NullFloat, NullBool and NullTime wrappers. Other types are
+// decoded with encoding/json and database/sql, with null, "" and "null" treated as null.
+type Null[T any] struct {
+	V     T
+	Valid bool // true if V is not null
+}
+
+// NewNull creates a new valid Null with the given value
+func NewNull[T any](value T) Null[T] {
+	return Null[T]{
+		V:     value,
+		Valid: true,
+	}
+}
+
+// NullFromPtr creates a Null from a pointer, which is null when the pointer is nil
+func NullFromPtr[T any](value *T) Null[T] {
+	if value == nil {
+		return Null[T]{}
+	}
+
+	return NewNull(*value)
+}
+
+// Map applies fn to the value of n if it is valid, otherwise returns an invalid Null[U]
+func Map[T, U any](n Null[T], fn func(T) U) Null[U] {
+	if !n.Valid {
+		return Null[U]{}
+	}
+
+	return NewNull(fn(n.V))
+}
+
+// UnmarshalJSON implements json.Unmarshaler for Null
+func (n *Null[T]) UnmarshalJSON(data []byte) error {
+	var zero T
+
+	valid, err := decodeNull(data, &n.V, fmt.Sprintf("%T", *n))
+	if err != nil {
+		return err
+	}
+
+	if !valid {
+		n.V = zero
+	}
+
+	n.Valid = valid
+
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler for Null
+func (n Null[T]) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+
+	if t, ok := any(n.V).(time.Time); ok {
+		return json.Marshal(formatTime(t))
+	}
+
+	return json.Marshal(n.V)
+}
+
+// Scan implements sql.Scanner for Null
+// SQL NULL is preserved as Valid=false
+func (n *Null[T]) Scan(src any) error {
+	var (
+		zero  T
+		valid bool
+		err   error
+	)
+
+	switch p := any(&n.V).(type) {
+	case *string:
+		*p, valid, err = scanString(src)
+	case *int64:
+		*p, valid, err = scanInt(src)
+	case *uint64:
+		*p, valid, err = scanUint(src)
+	case *float64:
+		*p, valid, err = scanFloat(src)
+	case *bool:
+		*p, valid, err = scanBool(src)
+	case *time.Time:
+		*p, valid, err = scanTime(src)
+	case sql.Scanner:
+		valid = src != nil
+		if valid {
+			err = p.Scan(src)
+		}
+	default:
+		valid = src != nil
+		if valid {
+			v, ok := src.(T)
+			if !ok {
+				err = fmt.Errorf("cannot scan %T into %T", src, *n)
+			}
+			n.V = v
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if !valid {
+		n.V = zero
+	}
+
+	n.Valid = valid
+
+	return nil
+}
+
+// Value implements driver.Valuer for Null
+// Returns SQL NULL when not valid; uint64 values above math.MaxInt64 return an error
+func (n Null[T]) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+
+	if u, ok := any(n.V).(uint64); ok {
+		return uintValue(u)
+	}
+
+	return driver.DefaultParameterConverter.ConvertValue(n.V)
+}
+
+// ValueOr returns the value if valid, otherwise returns the fallback value
+func (n Null[T]) ValueOr(fallback T) T {
+	if n.Valid {
+		return n.V
+	}
+
+	return fallback
+}
+
+// OrElse returns the value if valid, otherwise returns the result of calling fn.
+// Unlike ValueOr the fallback is only computed when needed.
+func (n Null[T]) OrElse(fn func() T) T {
+	if n.Valid {
+		return n.V
+	}
+
+	return fn()
+}
+
+// Ptr returns a pointer to a copy of the value if valid, otherwise nil
+func (n Null[T]) Ptr() *T {
+	if !n.Valid {
+		return nil
+	}
+
+	v := n.V
+
+	return &v
+}
+
+// String returns the string representation of the value, or "null" if not valid
+func (n Null[T]) String() string {
+	if !n.Valid {
+		return "null"
+	}
+
+	switch v := any(n.V).(type) {
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return formatTime(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// decodeNull decodes data into v and reports whether it held a non-null value.
+// name identifies the target type in error messages.
+func decodeNull[T any](data []byte, v *T, name string) (bool, error) {
+	if data == nil || string(data) == "null" {
+		return false, nil
+	}
+
+	var (
+		valid bool
+		err   error
+	)
+
+	switch p := any(v).(type) {
+	case *string:
+		*p, valid, err = decodeNullString(data, name)
+	case *int64:
+		*p, valid, err = decodeNullInt(data, name)
+	case *uint64:
+		*p, valid, err = decodeNullUint(data, name)
+	case *float64:
+		*p, valid, err = decodeNullFloat(data, name)
+	case *bool:
+		*p, valid, err = decodeNullBool(data, name)
+	case *time.Time:
+		*p, valid, err = decodeNullTime(data, name)
+	default:
+		// Handle empty string and "null" string as null
+		var s string
+		if json.Unmarshal(data, &s) == nil && (s == "" || s == "null") {
+			return false, nil
+		}
+
+		if err := json.Unmarshal(data, v); err != nil {
+			return false, err
+		}
+
+		valid = true
+	}
+
+	return valid, err
+}
+
+// The decode helpers below implement the lenient JSON parsing shared by Null and the
+// NullX wrappers. Each returns valid=false for null, "" and "null".
+
+func decodeNullString(data []byte, name string) (string, bool, error) {
+	// Try to unmarshal as string first
+	var s string
+
+	if err := json.Unmarshal(data, &s); err == nil {
+		if s == "" || s == "null" {
+			return "", false, nil
+		}
+
+		return s, true, nil
+	}
+
+	// Try to unmarshal as number and convert to string
+	var n float64
+
+	if err := json.Unmarshal(data, &n); err == nil {
+		return fmt.Sprintf("%g", n), true, nil // Use %g for clean number representation
+	}
+
+	return "", false, fmt.Errorf("cannot unmarshal %s into %s", string(data), name)
+}
+
+func decodeNullInt(data []byte, name string) (int64, bool, error) {
+	// Try to unmarshal as integer first
+	var i int64
+
+	if err := json.Unmarshal(data, &i); err == nil {
+		return i, true, nil
+	}
+
+	// Try to unmarshal as float64 (JSON numbers default to float64)
+	var f float64
+
+	if err := json.Unmarshal(data, &f); err == nil {
+		return int64(f), true, nil // Truncate to integer
+	}
+
+	// Try to unmarshal as string and parse as integer
+	var s string
+
+	if err := json.Unmarshal(data, &s); err == nil {
+		if s == "" || s == "null" {
+			return 0, false, nil
+		}
+
+		if parsed, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return parsed, true, nil
+		}
+
+		if parsed, err := strconv.ParseFloat(s, 64); err == nil {
+			return int64(parsed), true, nil
+		}
+
+		return 0, false, fmt.Errorf("cannot parse string %q as integer", s)
+	}
+
+	return 0, false, fmt.Errorf("cannot unmarshal %s into %s", string(data), name)
+}
+
+func decodeNullUint(data []byte, name string) (uint64, bool, error) {
+	// Try to unmarshal as uint64 first
+	var u uint64
+
+	if err := json.Unmarshal(data, &u); err == nil {
+		return u, true, nil
+	}
+
+	// Try to unmarshal as float64 (JSON numbers default to float64)
+	var f float64
+
+	if err := json.Unmarshal(data, &f); err == nil {
+		if f < 0 {
+			return 0, false, fmt.Errorf("cannot convert negative number %v to uint", f)
+		}
+
+		return uint64(f), true, nil // Truncate to uint64
+	}
+
+	// Try to unmarshal as string and parse as uint
+	var s string
+
+	if err := json.Unmarshal(data, &s); err == nil {
+		if s == "" || s == "null" {
+			return 0, false, nil
+		}
+
+		if parsed, err := strconv.ParseUint(s, 10, 64); err == nil {
+			return parsed, true, nil
+		}
+
+		if parsed, err := strconv.ParseFloat(s, 64); err == nil {
+			if parsed < 0 {
+				return 0, false, fmt.Errorf("cannot convert negative number %v to uint", parsed)
+			}
+
+			return uint64(parsed), true, nil
+		}
+
+		return 0, false, fmt.Errorf("cannot parse string %q as uint", s)
+	}
+
+	return 0, false, fmt.Errorf("cannot unmarshal %s into %s", string(data), name)
+}
+
+func decodeNullFloat(data []byte, name string) (float64, bool, error) {
+	// Try to unmarshal as float64 first
+	var f float64
+
+	if err := json.Unmarshal(data, &f); err == nil {
+		return f, true, nil
+	}
+
+	// Try to unmarshal as string and parse as float
+	var s string
+
+	if err := json.Unmarshal(data, &s); err == nil {
+		if s == "" || s == "null" {
+			return 0, false, nil
+		}
+
+		if parsed, err := strconv.ParseFloat(s, 64); err == nil {
+			return parsed, true, nil
+		}
+
+		return 0, false, fmt.Errorf("cannot parse string %q as float", s)
+	}
+
+	return 0, false, fmt.Errorf("cannot unmarshal %s into %s", string(data), name)
+}
+
+func decodeNullBool(data []byte, name string) (bool, bool, error) {
+	// Try to unmarshal as boolean first
+	var b bool
+
+	if err := json.Unmarshal(data, &b); err == nil {
+		return b, true, nil
+	}
+
+	// Try to unmarshal as string
+	var s string
+
+	if err := json.Unmarshal(data, &s); err == nil {
+		if s == "" || s == "null" {
+			return false, false, nil
+		}
+
+		// Consider "true", "success", and "1" as true values, everything else as false
+		return s == "true" || s == "success" || s == "1", true, nil
+	}
+
+	// Try to unmarshal as number - any non-zero number is considered true
+	var n float64
+
+	if err := json.Unmarshal(data, &n); err == nil {
+		return n != 0, true, nil
+	}
+
+	return false, false, fmt.Errorf("cannot unmarshal %s into %s", string(data), name)
+}
+
+func decodeNullTime(data []byte, name string) (time.Time, bool, error) {
+	var s string
+
+	if err := json.Unmarshal(data, &s); err == nil {
+		if s == "" || s == "null" {
+			return time.Time{}, false, nil
+		}
+
+		return parseTimeString(s)
+	}
+
+	return time.Time{}, false, fmt.Errorf("cannot unmarshal %s into %s", string(data), name)
+}
+
+// formatTime formats t as RFC3339, using RFC3339Nano if there are nanoseconds
+func formatTime(t time.Time) string {
+	format := time.RFC3339
+	if t.Nanosecond() != 0 {
+		format = time.RFC3339Nano
+	}
+
+	return t.Format(format)
+}
@@ -0,0 +1,410 @@
+package data
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testStatus string
+
+func TestNull_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		target   func() any
+		expected any
+		wantErr  bool
+	}{
+		{
+			name:     "string",
+			input:    `"hello"`,
+			target:   func() any { return new(Null[string]) },
+			expected: &Null[string]{V: "hello", Valid: true},
+		},
+		{
+			name:     "string from number",
+			input:    `42`,
+			target:   func() any { return new(Null[string]) },
+			expected: &Null[string]{V: "42", Valid: true},
+		},
+		{
+			name:     "empty string as null",
+			input:    `""`,
+			target:   func() any { return new(Null[string]) },
+			expected: &Null[string]{},
+		},
+		{
+			name:     "int64 from quoted number",
+			input:    `"123"`,
+			target:   func() any { return new(Null[int64]) },
+			expected: &Null[int64]{V: 123, Valid: true},
+		},
+		{
+			name:     "int64 from float (truncated)",
+			input:    `12.9`,
+			target:   func() any { return new(Null[int64]) },
+			expected: &Null[int64]{V: 12, Valid: true},
+		},
+		{
+			name:     "uint64",
+			input:    `18446744073709551615`,
+			target:   func() any { return new(Null[uint64]) },
+			expected: &Null[uint64]{V: 18446744073709551615, Valid: true},
+		},
+		{
+			name:    "uint64 rejects negative numbers",
+			input:   `-1`,
+			target:  func() any { return new(Null[uint64]) },
+			wantErr: true,
+		},
+		{
+			name:     "float64 from quoted number",
+			input:    `"12.50"`,
+			target:   func() any { return new(Null[float64]) },
+			expected: &Null[float64]{V: 12.5, Valid: true},
+		},
+		{
+			name:     "bool from success string",
+			input:    `"success"`,
+			target:   func() any { return new(Null[bool]) },
+			expected: &Null[bool]{V: true, Valid: true},
+		},
+		{
+			name:     "bool from number",
+			input:    `0`,
+			target:   func() any { return new(Null[bool]) },
+			expected: &Null[bool]{V: false, Valid: true},
+		},
+		{
+			name:     "time",
+			input:    `"2024-01-15 10:30:00"`,
+			target:   func() any { return new(Null[time.Time]) },
+			expected: &Null[time.Time]{V: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC), Valid: true},
+		},
+		{
+			name:    "time rejects invalid string",
+			input:   `"not a time"`,
+			target:  func() any { return new(Null[time.Time]) },
+			wantErr: true,
+		},
+		{
+			name:     "other types use encoding/json",
+			input:    `"success"`,
+			target:   func() any { return new(Null[testStatus]) },
+			expected: &Null[testStatus]{V: "success", Valid: true},
+		},
+		{
+			name:     "other types treat empty string as null",
+			input:    `""`,
+			target:   func() any { return new(Null[testStatus]) },
+			expected: &Null[testStatus]{},
+		},
+		{
+			name:     "other types treat null as null",
+			input:    `null`,
+			target:   func() any { return new(Null[[]string]) },
+			expected: &Null[[]string]{},
+		},
+		{
+			name:    "other types return encoding/json errors",
+			input:   `{"a":1}`,
+			target:  func() any { return new(Null[testStatus]) },
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := tt.target()
+			err := json.Unmarshal([]byte(tt.input), target)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, target)
+		})
+	}
+}
+
+func TestNull_UnmarshalJSON_ResetsValue(t *testing.T) {
+	n := NewNull(int64(42))
+
+	require.NoError(t, json.Unmarshal([]byte(`null`), &n))
+	assert.Equal(t, Null[int64]{}, n)
+}
+
+func TestNull_UnmarshalJSON_ErrorNamesType(t *testing.T) {
+	var n Null[int64]
+
+	err := json.Unmarshal([]byte(`[1]`), &n)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "data.Null[int64]")
+}
+
+func TestNull_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    any
+		expected string
+	}{
+		{
+			name:     "valid string",
+			input:    NewNull("hello"),
+			expected: `"hello"`,
+		},
+		{
+			name:     "valid int64",
+			input:    NewNull(int64(42)),
+			expected: `42`,
+		},
+		{
+			name:     "valid time",
+			input:    NewNull(time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)),
+			expected: `"2024-01-15T10:30:00Z"`,
+		},
+		{
+			name:     "valid time with nanoseconds",
+			input:    NewNull(time.Date(2024, 1, 15, 10, 30, 0, 500, time.UTC)),
+			expected: `"2024-01-15T10:30:00.0000005Z"`,
+		},
+		{
+			name:     "invalid",
+			input:    Null[string]{V: "ignored"},
+			expected: `null`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(data))
+		})
+	}
+}
+
+func TestNull_Scan(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      any
+		target   func() any
+		expected any
+		wantErr  bool
+	}{
+		{
+			name:     "string",
+			src:      []byte("hello"),
+			target:   func() any { return new(Null[string]) },
+			expected: &Null[string]{V: "hello", Valid: true},
+		},
+		{
+			name:     "int64 from bytes",
+			src:      []byte("42"),
+			target:   func() any { return new(Null[int64]) },
+			expected: &Null[int64]{V: 42, Valid: true},
+		},
+		{
+			name:     "bool from postgres text",
+			src:      "t",
+			target:   func() any { return new(Null[bool]) },
+			expected: &Null[bool]{V: true, Valid: true},
+		},
+		{
+			name:     "NULL",
+			src:      nil,
+			target:   func() any { return new(Null[float64]) },
+			expected: &Null[float64]{},
+		},
+		{
+			name:     "other types by assertion",
+			src:      testStatus("success"),
+			target:   func() any { return new(Null[testStatus]) },
+			expected: &Null[testStatus]{V: "success", Valid: true},
+		},
+		{
+			name:     "other types delegate to sql.Scanner",
+			src:      "7",
+			target:   func() any { return new(Null[Int]) },
+			expected: &Null[Int]{V: 7, Valid: true},
+		},
+		{
+			name:    "other types reject mismatched sources",
+			src:     int64(1),
+			target:  func() any { return new(Null[testStatus]) },
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := tt.target()
+			err := target.(interface{ Scan(any) error }).Scan(tt.src)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, target)
+		})
+	}
+}
+
+func TestNull_Value(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    driver.Valuer
+		expected driver.Value
+		wantErr  bool
+	}{
+		{
+			name:     "valid string",
+			input:    NewNull("hello"),
+			expected: "hello",
+		},
+		{
+			name:     "valid uint64",
+			input:    NewNull(uint64(42)),
+			expected: int64(42),
+		},
+		{
+			name:    "uint64 overflow",
+			input:   NewNull(uint64(18446744073709551615)),
+			wantErr: true,
+		},
+		{
+			name:     "named string type",
+			input:    NewNull(testStatus("success")),
+			expected: "success",
+		},
+		{
+			name:     "type implementing driver.Valuer",
+			input:    NewNull(Float(1.5)),
+			expected: float64(1.5),
+		},
+		{
+			name:     "invalid",
+			input:    Null[string]{V: "ignored"},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.input.Value()
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestNull_Helpers(t *testing.T) {
+	valid := NewNull(int64(42))
+	invalid := Null[int64]{}
+
+	t.Run("ValueOr", func(t *testing.T) {
+		assert.Equal(t, int64(42), valid.ValueOr(7))
+		assert.Equal(t, int64(7), invalid.ValueOr(7))
+	})
+
+	t.Run("OrElse", func(t *testing.T) {
+		called := false
+		fallback := func() int64 {
+			called = true
+			return 7
+		}
+
+		assert.Equal(t, int64(42), valid.OrElse(fallback))
+		assert.False(t, called)
+		assert.Equal(t, int64(7), invalid.OrElse(fallback))
+		assert.True(t, called)
+	})
+
+	t.Run("Ptr", func(t *testing.T) {
+		p := valid.Ptr()
+		require.NotNil(t, p)
+		assert.Equal(t, int64(42), *p)
+
+		*p = 1
+		assert.Equal(t, int64(42), valid.V, "Ptr returns a copy")
+
+		assert.Nil(t, invalid.Ptr())
+	})
+
+	t.Run("NullFromPtr", func(t *testing.T) {
+		v := int64(42)
+		assert.Equal(t, valid, NullFromPtr(&v))
+		assert.Equal(t, invalid, NullFromPtr[int64](nil))
+	})
+
+	t.Run("Map", func(t *testing.T) {
+		toKobo := func(v int64) float64 { return float64(v) / 100 }
+
+		assert.Equal(t, NewNull(0.42), Map(valid, toKobo))
+		assert.Equal(t, Null[float64]{}, Map(invalid, toKobo))
+	})
+
+	t.Run("String", func(t *testing.T) {
+		assert.Equal(t, "42", valid.String())
+		assert.Equal(t, "null", invalid.String())
+		assert.Equal(t, "1.5", NewNull(1.5).String())
+		assert.Equal(t, "true", NewNull(true).String())
+		assert.Equal(t, "success", NewNull(testStatus("success")).String())
+	})
+}
+
+func TestNull_Wrappers(t *testing.T) {
+	ts := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	t.Run("convert to and from Null", func(t *testing.T) {
+		assert.Equal(t, NewNull("a"), NewNullString("a").Null())
+		assert.Equal(t, NewNull(int64(1)), NewNullInt(1).Null())
+		assert.Equal(t, NewNull(uint64(1)), NewNullUint(1).Null())
+		assert.Equal(t, NewNull(1.5), NewNullFloat(1.5).Null())
+		assert.Equal(t, NewNull(true), NewNullBool(true).Null())
+		assert.Equal(t, NewNull(ts), NewNullTime(ts).Null())
+
+		assert.Equal(t, NewNullString("a"), NullStringFrom(NewNull("a")))
+		assert.Equal(t, NewNullInt(1), NullIntFrom(NewNull(int64(1))))
+		assert.Equal(t, NewNullUint(1), NullUintFrom(NewNull(uint64(1))))
+		assert.Equal(t, NewNullFloat(1.5), NullFloatFrom(NewNull(1.5)))
+		assert.Equal(t, NewNullBool(true), NullBoolFrom(NewNull(true)))
+		assert.Equal(t, NewNullTime(ts), NullTimeFrom(NewNull(ts)))
+		assert.Equal(t, NullString{}, NullStringFrom(Null[string]{}))
+	})
+
+	t.Run("Ptr", func(t *testing.T) {
+		require.NotNil(t, NewNullString("a").Ptr())
+		assert.Equal(t, "a", *NewNullString("a").Ptr())
+		assert.Nil(t, NullInt{Int: 1}.Ptr())
+		assert.Equal(t, ts, *NewNullTime(ts).Ptr())
+	})
+
+	t.Run("OrElse", func(t *testing.T) {
+		assert.Equal(t, 2.5, NullFloat{}.OrElse(func() float64 { return 2.5 }))
+		assert.True(t, NewNullBool(true).OrElse(func() bool { return false }))
+	})
+
+	t.Run("Map", func(t *testing.T) {
+		amount := NewNullInt(50000)
+		assert.Equal(t, NewNull("500"), Map(amount.Null(), func(v int64) string {
+			return strconv.FormatInt(v/100, 10)
+		}))
+	})
+}
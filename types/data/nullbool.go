@@ -1,100 +1,81 @@
 package data
 
 import (
-	"encoding/json"
-	"fmt"
+	"database/sql/driver"
 )
 
 // NullBool represents a bool that may be null in JSON
 // Unlike MultiBool, this preserves null values instead of converting them to false
 // Accepts: booleans, string representations ("true", "success", "1" as true), and null
+// It is a thin wrapper around Null[bool], kept for its named field; use Null() for the generic helpers.
 type NullBool struct {
 	Bool  bool
 	Valid bool // true if Bool is not null
 }
 
+// Null returns the value as a Null[bool]
+func (nb NullBool) Null() Null[bool] {
+	return Null[bool]{V: nb.Bool, Valid: nb.Valid}
+}
+
 // UnmarshalJSON implements json.Unmarshaler for NullBool
 func (nb *NullBool) UnmarshalJSON(data []byte) error {
-	// Handle null
-	if data == nil || string(data) == "null" {
-		nb.Bool = false
-		nb.Valid = false
-
-		return nil
-	}
+	var n Null[bool]
 
-	// Try to unmarshal as boolean first
-	var b bool
-
-	if err := json.Unmarshal(data, &b); err == nil {
-		nb.Bool = b
-		nb.Valid = true
-
-		return nil
+	valid, err := decodeNull(data, &n.V, "NullBool")
+	if err != nil {
+		return err
 	}
 
-	// Try to unmarshal as string
-	var s string
+	nb.Bool = n.V
+	nb.Valid = valid
 
-	if err := json.Unmarshal(data, &s); err == nil {
-		// Handle empty string and "null" string as null
-		if s == "" || s == "null" {
-			nb.Bool = false
-			nb.Valid = false
-
-			return nil
-		}
+	return nil
+}
 
-		// Consider "true", "success", and "1" as true values, everything else as false
-		nb.Bool = (s == "true" || s == "success" || s == "1")
-		nb.Valid = true
+// MarshalJSON implements json.Marshaler for NullBool
+func (nb NullBool) MarshalJSON() ([]byte, error) {
+	return nb.Null().MarshalJSON()
+}
 
-		return nil
+// Scan implements sql.Scanner for NullBool
+// SQL NULL is preserved as Valid=false
+func (nb *NullBool) Scan(src any) error {
+	var n Null[bool]
+	if err := n.Scan(src); err != nil {
+		return err
 	}
 
-	// Try to unmarshal as number (following MultiBool pattern of handling numbers)
-	var n float64
-
-	if err := json.Unmarshal(data, &n); err == nil {
-		// Any non-zero number is considered true
-		nb.Bool = n != 0
-		nb.Valid = true
+	nb.Bool = n.V
+	nb.Valid = n.Valid
 
-		return nil
-	}
-
-	return fmt.Errorf("cannot unmarshal %s into NullBool", string(data))
+	return nil
 }
 
-// MarshalJSON implements json.Marshaler for NullBool
-func (nb NullBool) MarshalJSON() ([]byte, error) {
-	if !nb.Valid {
-		return []byte("null"), nil
-	}
-
-	return json.Marshal(nb.Bool)
+// Value implements driver.Valuer for NullBool
+// Returns SQL NULL when not valid
+func (nb NullBool) Value() (driver.Value, error) {
+	return nb.Null().Value()
 }
 
 // ValueOr returns the boolean value if valid, otherwise returns the fallback value
 func (nb NullBool) ValueOr(fallback bool) bool {
-	if nb.Valid {
-		return nb.Bool
-	}
+	return nb.Null().ValueOr(fallback)
+}
+
+// OrElse returns the boolean value if valid, otherwise returns the result of calling fn
+func (nb NullBool) OrElse(fn func() bool) bool {
+	return nb.Null().OrElse(fn)
+}
 
-	return fallback
+// Ptr returns a pointer to a copy of the boolean value if valid, otherwise nil
+func (nb NullBool) Ptr() *bool {
+	return nb.Null().Ptr()
 }
 
 // String returns the string representation of the boolean, or "null" if not valid
 func (nb NullBool) String() string {
-	if !nb.Valid {
-		return "null"
-	}
-
-	if nb.Bool {
-		return "true"
-	}
-
-	return "false"
+	return nb.Null().String()
 }
 
 // NewNullBool creates a new valid NullBool with the given value
@@ -104,3 +85,11 @@ func NewNullBool(value bool) NullBool {
 		Valid: true,
 	}
 }
+
+// NullBoolFrom creates a NullBool from a Null[bool]
+func NullBoolFrom(n Null[bool]) NullBool {
+	return NullBool{
+		Bool:  n.V,
+		Valid: n.Valid,
+	}
+}
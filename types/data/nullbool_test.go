@@ -1,6 +1,7 @@
 package data
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"testing"
 
@@ -504,3 +505,86 @@ func TestNullBool_CompareWithMultiBool(t *testing.T) {
 		assert.Equal(t, "null", string(nbData))
 	})
 }
+
+func TestNullBool_Scan(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      any
+		expected NullBool
+		wantErr  bool
+	}{
+		{
+			name:     "scans true",
+			src:      true,
+			expected: NullBool{Bool: true, Valid: true},
+		},
+		{
+			name:     "scans false as valid",
+			src:      false,
+			expected: NullBool{Bool: false, Valid: true},
+		},
+		{
+			name:     "scans zero int64 as false",
+			src:      int64(0),
+			expected: NullBool{Bool: false, Valid: true},
+		},
+		{
+			name:     "scans NULL as invalid",
+			src:      nil,
+			expected: NullBool{},
+		},
+		{
+			name:     "returns error for unrecognised string",
+			src:      "maybe",
+			expected: NullBool{},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v NullBool
+			err := v.Scan(tt.src)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, v)
+		})
+	}
+}
+
+func TestNullBool_Value(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    NullBool
+		expected driver.Value
+	}{
+		{
+			name:     "returns true when valid",
+			input:    NullBool{Bool: true, Valid: true},
+			expected: true,
+		},
+		{
+			name:     "returns false when valid",
+			input:    NullBool{Bool: false, Valid: true},
+			expected: false,
+		},
+		{
+			name:     "returns NULL when invalid",
+			input:    NullBool{Bool: true, Valid: false},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.input.Value()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
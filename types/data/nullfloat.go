@@ -1,89 +1,80 @@
 package data
 
 import (
-	"encoding/json"
-	"fmt"
-	"strconv"
+	"database/sql/driver"
 )
 
 // NullFloat represents a float64 that may be null in JSON
 // Accepts: floats, integers, string representations of numbers, and null
+// It is a thin wrapper around Null[float64], kept for its named field; use Null() for the generic helpers.
 type NullFloat struct {
 	Float float64
 	Valid bool // true if Float is not null
 }
 
+// Null returns the value as a Null[float64]
+func (nf NullFloat) Null() Null[float64] {
+	return Null[float64]{V: nf.Float, Valid: nf.Valid}
+}
+
 // UnmarshalJSON implements json.Unmarshaler for NullFloat
 func (nf *NullFloat) UnmarshalJSON(data []byte) error {
-	// Handle null
-	if data == nil || string(data) == "null" {
-		nf.Float = 0.0
-		nf.Valid = false
+	var n Null[float64]
 
-		return nil
+	valid, err := decodeNull(data, &n.V, "NullFloat")
+	if err != nil {
+		return err
 	}
 
-	// Try to unmarshal as float64 first
-	var f float64
-
-	if err := json.Unmarshal(data, &f); err == nil {
-		nf.Float = f
-		nf.Valid = true
-
-		return nil
-	}
-
-	// Try to unmarshal as string and parse as float
-	var s string
-
-	if err := json.Unmarshal(data, &s); err == nil {
-		// Handle empty string and "null" string as null
-		if s == "" || s == "null" {
-			nf.Float = 0.0
-			nf.Valid = false
+	nf.Float = n.V
+	nf.Valid = valid
 
-			return nil
-		}
-
-		// Try to parse string as float
-		if parsed, err := strconv.ParseFloat(s, 64); err == nil {
-			nf.Float = parsed
-			nf.Valid = true
-
-			return nil
-		}
-
-		return fmt.Errorf("cannot parse string %q as float", s)
-	}
-
-	return fmt.Errorf("cannot unmarshal %s into NullFloat", string(data))
+	return nil
 }
 
 // MarshalJSON implements json.Marshaler for NullFloat
 func (nf NullFloat) MarshalJSON() ([]byte, error) {
-	if !nf.Valid {
-		return []byte("null"), nil
+	return nf.Null().MarshalJSON()
+}
+
+// Scan implements sql.Scanner for NullFloat
+// SQL NULL is preserved as Valid=false
+func (nf *NullFloat) Scan(src any) error {
+	var n Null[float64]
+	if err := n.Scan(src); err != nil {
+		return err
 	}
 
-	return json.Marshal(nf.Float)
+	nf.Float = n.V
+	nf.Valid = n.Valid
+
+	return nil
+}
+
+// Value implements driver.Valuer for NullFloat
+// Returns SQL NULL when not valid
+func (nf NullFloat) Value() (driver.Value, error) {
+	return nf.Null().Value()
 }
 
 // ValueOr returns the float value if valid, otherwise returns the fallback value
 func (nf NullFloat) ValueOr(fallback float64) float64 {
-	if nf.Valid {
-		return nf.Float
-	}
+	return nf.Null().ValueOr(fallback)
+}
+
+// OrElse returns the float value if valid, otherwise returns the result of calling fn
+func (nf NullFloat) OrElse(fn func() float64) float64 {
+	return nf.Null().OrElse(fn)
+}
 
-	return fallback
+// Ptr returns a pointer to a copy of the float value if valid, otherwise nil
+func (nf NullFloat) Ptr() *float64 {
+	return nf.Null().Ptr()
 }
 
 // String returns the string representation of the float, or "null" if not valid
 func (nf NullFloat) String() string {
-	if !nf.Valid {
-		return "null"
-	}
-
-	return strconv.FormatFloat(nf.Float, 'f', -1, 64)
+	return nf.Null().String()
 }
 
 // NewNullFloat creates a new valid NullFloat with the given value
@@ -93,3 +84,11 @@ func NewNullFloat(value float64) NullFloat {
 		Valid: true,
 	}
 }
+
+// NullFloatFrom creates a NullFloat from a Null[float64]
+func NullFloatFrom(n Null[float64]) NullFloat {
+	return NullFloat{
+		Float: n.V,
+		Valid: n.Valid,
+	}
+}
@@ -1,6 +1,7 @@
 package data
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"testing"
 
@@ -527,3 +528,76 @@ func TestNullFloat_SpecialValues(t *testing.T) {
 		assert.True(t, nf.Float != nf.Float) // NaN check
 	})
 }
+
+func TestNullFloat_Scan(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      any
+		expected NullFloat
+		wantErr  bool
+	}{
+		{
+			name:     "scans float64",
+			src:      float64(3.14),
+			expected: NullFloat{Float: 3.14, Valid: true},
+		},
+		{
+			name:     "scans numeric bytes (NUMERIC columns)",
+			src:      []byte("12.50"),
+			expected: NullFloat{Float: 12.5, Valid: true},
+		},
+		{
+			name:     "scans NULL as invalid",
+			src:      nil,
+			expected: NullFloat{},
+		},
+		{
+			name:     "returns error for unsupported type",
+			src:      struct{}{},
+			expected: NullFloat{},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v NullFloat
+			err := v.Scan(tt.src)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, v)
+		})
+	}
+}
+
+func TestNullFloat_Value(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    NullFloat
+		expected driver.Value
+	}{
+		{
+			name:     "returns float64 when valid",
+			input:    NullFloat{Float: 3.14, Valid: true},
+			expected: float64(3.14),
+		},
+		{
+			name:     "returns NULL when invalid",
+			input:    NullFloat{Float: 3.14, Valid: false},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.input.Value()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
@@ -1,108 +1,81 @@
 package data
 
 import (
-	"encoding/json"
-	"fmt"
-	"strconv"
+	"database/sql/driver"
 )
 
 // NullInt represents an int64 that may be null in JSON
 // Unlike MultiInt, this preserves null values instead of converting them to zero
 // Accepts: integers, floats (truncated), string representations of numbers, and null
+// It is a thin wrapper around Null[int64], kept for its named field; use Null() for the generic helpers.
 type NullInt struct {
 	Int   int64
 	Valid bool // true if Int is not null
 }
 
+// Null returns the value as a Null[int64]
+func (ni NullInt) Null() Null[int64] {
+	return Null[int64]{V: ni.Int, Valid: ni.Valid}
+}
+
 // UnmarshalJSON implements json.Unmarshaler for NullInt
 func (ni *NullInt) UnmarshalJSON(data []byte) error {
-	// Handle null
-	if data == nil || string(data) == "null" {
-		ni.Int = 0
-		ni.Valid = false
+	var n Null[int64]
 
-		return nil
+	valid, err := decodeNull(data, &n.V, "NullInt")
+	if err != nil {
+		return err
 	}
 
-	// Try to unmarshal as integer first
-	var i int64
-
-	if err := json.Unmarshal(data, &i); err == nil {
-		ni.Int = i
-		ni.Valid = true
-
-		return nil
-	}
+	ni.Int = n.V
+	ni.Valid = valid
 
-	// Try to unmarshal as float64 (JSON numbers default to float64)
-	var f float64
+	return nil
+}
 
-	if err := json.Unmarshal(data, &f); err == nil {
-		ni.Int = int64(f) // Truncate to integer
-		ni.Valid = true
+// MarshalJSON implements json.Marshaler for NullInt
+func (ni NullInt) MarshalJSON() ([]byte, error) {
+	return ni.Null().MarshalJSON()
+}
 
-		return nil
+// Scan implements sql.Scanner for NullInt
+// SQL NULL is preserved as Valid=false
+func (ni *NullInt) Scan(src any) error {
+	var n Null[int64]
+	if err := n.Scan(src); err != nil {
+		return err
 	}
 
-	// Try to unmarshal as string and parse as integer
-	var s string
-
-	if err := json.Unmarshal(data, &s); err == nil {
-		// Handle empty string as null
-		if s == "" || s == "null" {
-			ni.Int = 0
-			ni.Valid = false
-
-			return nil
-		}
-
-		// Try to parse string as integer
-		if parsed, err := strconv.ParseInt(s, 10, 64); err == nil {
-			ni.Int = parsed
-			ni.Valid = true
+	ni.Int = n.V
+	ni.Valid = n.Valid
 
-			return nil
-		}
-
-		// Try to parse string as float and truncate to integer
-		if parsed, err := strconv.ParseFloat(s, 64); err == nil {
-			ni.Int = int64(parsed)
-			ni.Valid = true
-
-			return nil
-		}
-
-		return fmt.Errorf("cannot parse string %q as integer", s)
-	}
-
-	return fmt.Errorf("cannot unmarshal %s into NullInt", string(data))
+	return nil
 }
 
-// MarshalJSON implements json.Marshaler for NullInt
-func (ni NullInt) MarshalJSON() ([]byte, error) {
-	if !ni.Valid {
-		return []byte("null"), nil
-	}
-
-	return json.Marshal(ni.Int)
+// Value implements driver.Valuer for NullInt
+// Returns SQL NULL when not valid
+func (ni NullInt) Value() (driver.Value, error) {
+	return ni.Null().Value()
 }
 
 // ValueOr returns the integer value if valid, otherwise returns the fallback value
 func (ni NullInt) ValueOr(fallback int64) int64 {
-	if ni.Valid {
-		return ni.Int
-	}
+	return ni.Null().ValueOr(fallback)
+}
 
-	return fallback
+// OrElse returns the integer value if valid, otherwise returns the result of calling fn
+func (ni NullInt) OrElse(fn func() int64) int64 {
+	return ni.Null().OrElse(fn)
+}
+
+// Ptr returns a pointer to a copy of the integer value if valid, otherwise nil
+func (ni NullInt) Ptr() *int64 {
+	return ni.Null().Ptr()
 }
 
 // String returns the string representation of the integer, or "null" if not valid
 func (ni NullInt) String() string {
-	if !ni.Valid {
-		return "null"
-	}
-
-	return strconv.FormatInt(ni.Int, 10)
+	return ni.Null().String()
 }
 
 // NewNullInt creates a new valid NullInt with the given value
@@ -112,3 +85,11 @@ func NewNullInt(value int64) NullInt {
 		Valid: true,
 	}
 }
+
+// NullIntFrom creates a NullInt from a Null[int64]
+func NullIntFrom(n Null[int64]) NullInt {
+	return NullInt{
+		Int:   n.V,
+		Valid: n.Valid,
+	}
+}
@@ -1,6 +1,7 @@
 package data
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"testing"
 
@@ -436,3 +437,86 @@ func TestNullInt_CompareWithMultiInt(t *testing.T) {
 		// MultiInt tests removed
 	})
 }
+
+func TestNullInt_Scan(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      any
+		expected NullInt
+		wantErr  bool
+	}{
+		{
+			name:     "scans int64",
+			src:      int64(42),
+			expected: NullInt{Int: 42, Valid: true},
+		},
+		{
+			name:     "scans zero as valid",
+			src:      int64(0),
+			expected: NullInt{Int: 0, Valid: true},
+		},
+		{
+			name:     "scans numeric bytes",
+			src:      []byte("-17"),
+			expected: NullInt{Int: -17, Valid: true},
+		},
+		{
+			name:     "scans NULL as invalid",
+			src:      nil,
+			expected: NullInt{},
+		},
+		{
+			name:     "returns error for non-numeric string",
+			src:      "abc",
+			expected: NullInt{},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v NullInt
+			err := v.Scan(tt.src)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, v)
+		})
+	}
+}
+
+func TestNullInt_Value(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    NullInt
+		expected driver.Value
+	}{
+		{
+			name:     "returns int64 when valid",
+			input:    NullInt{Int: 42, Valid: true},
+			expected: int64(42),
+		},
+		{
+			name:     "returns zero when valid",
+			input:    NullInt{Int: 0, Valid: true},
+			expected: int64(0),
+		},
+		{
+			name:     "returns NULL when invalid",
+			input:    NullInt{Int: 42, Valid: false},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.input.Value()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
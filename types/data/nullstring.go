@@ -1,84 +1,81 @@
 package data
 
 import (
-	"encoding/json"
-	"fmt"
+	"database/sql/driver"
 )
 
 // NullString represents a string that may be null in JSON
 // Unlike MultiString, this preserves null values instead of converting them to empty strings
 // Accepts: strings, numbers (converted to strings), and null
+// It is a thin wrapper around Null[string], kept for its named field; use Null() for the generic helpers.
 type NullString struct {
 	Str   string
 	Valid bool // true if Str is not null
 }
 
+// Null returns the value as a Null[string]
+func (ns NullString) Null() Null[string] {
+	return Null[string]{V: ns.Str, Valid: ns.Valid}
+}
+
 // UnmarshalJSON implements json.Unmarshaler for NullString
 func (ns *NullString) UnmarshalJSON(data []byte) error {
-	// Handle null
-	if data == nil || string(data) == "null" {
-		ns.Str = ""
-		ns.Valid = false
+	var n Null[string]
 
-		return nil
+	valid, err := decodeNull(data, &n.V, "NullString")
+	if err != nil {
+		return err
 	}
 
-	// Try to unmarshal as string first
-	var s string
-
-	if err := json.Unmarshal(data, &s); err == nil {
-		// Handle empty string and "null" string as null
-		if s == "" || s == "null" {
-			ns.Str = ""
-			ns.Valid = false
+	ns.Str = n.V
+	ns.Valid = valid
 
-			return nil
-		}
+	return nil
+}
 
-		ns.Str = s
-		ns.Valid = true
+// MarshalJSON implements json.Marshaler for NullString
+func (ns NullString) MarshalJSON() ([]byte, error) {
+	return ns.Null().MarshalJSON()
+}
 
-		return nil
+// Scan implements sql.Scanner for NullString
+// SQL NULL is preserved as Valid=false
+func (ns *NullString) Scan(src any) error {
+	var n Null[string]
+	if err := n.Scan(src); err != nil {
+		return err
 	}
 
-	// Try to unmarshal as number and convert to string
-	var n float64
-
-	if err := json.Unmarshal(data, &n); err == nil {
-		ns.Str = fmt.Sprintf("%g", n) // Use %g for clean number representation
-		ns.Valid = true
-
-		return nil
-	}
+	ns.Str = n.V
+	ns.Valid = n.Valid
 
-	return fmt.Errorf("cannot unmarshal %s into NullString", string(data))
+	return nil
 }
 
-// MarshalJSON implements json.Marshaler for NullString
-func (ns NullString) MarshalJSON() ([]byte, error) {
-	if !ns.Valid {
-		return []byte("null"), nil
-	}
-
-	return json.Marshal(ns.Str)
+// Value implements driver.Valuer for NullString
+// Returns SQL NULL when not valid
+func (ns NullString) Value() (driver.Value, error) {
+	return ns.Null().Value()
 }
 
 // ValueOr returns the string value if valid, otherwise returns the fallback value
 func (ns NullString) ValueOr(fallback string) string {
-	if ns.Valid {
-		return ns.Str
-	}
+	return ns.Null().ValueOr(fallback)
+}
+
+// OrElse returns the string value if valid, otherwise returns the result of calling fn
+func (ns NullString) OrElse(fn func() string) string {
+	return ns.Null().OrElse(fn)
+}
 
-	return fallback
+// Ptr returns a pointer to a copy of the string value if valid, otherwise nil
+func (ns NullString) Ptr() *string {
+	return ns.Null().Ptr()
 }
 
 // String returns the string representation, or "null" if not valid
 func (ns NullString) String() string {
-	if !ns.Valid {
-		return "null"
-	}
-
-	return ns.Str
+	return ns.Null().String()
 }
 
 // NewNullString creates a new valid NullString with the given value
@@ -88,3 +85,11 @@ func NewNullString(value string) NullString {
 		Valid: true,
 	}
 }
+
+// NullStringFrom creates a NullString from a Null[string]
+func NullStringFrom(n Null[string]) NullString {
+	return NullString{
+		Str:   n.V,
+		Valid: n.Valid,
+	}
+}
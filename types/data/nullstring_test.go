@@ -1,6 +1,7 @@
 package data
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"testing"
 
@@ -536,3 +537,91 @@ func TestNullString_CompareWithMultiString(t *testing.T) {
 		assert.Equal(t, "null", result.Null.String())
 	})
 }
+
+func TestNullString_Scan(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      any
+		expected NullString
+		wantErr  bool
+	}{
+		{
+			name:     "scans string",
+			src:      "hello",
+			expected: NullString{Str: "hello", Valid: true},
+		},
+		{
+			name:     "scans empty string as valid",
+			src:      "",
+			expected: NullString{Str: "", Valid: true},
+		},
+		{
+			name:     "scans bytes",
+			src:      []byte("hello"),
+			expected: NullString{Str: "hello", Valid: true},
+		},
+		{
+			name:     "scans int64",
+			src:      int64(42),
+			expected: NullString{Str: "42", Valid: true},
+		},
+		{
+			name:     "scans NULL as invalid",
+			src:      nil,
+			expected: NullString{},
+		},
+		{
+			name:     "returns error for unsupported type",
+			src:      struct{}{},
+			expected: NullString{},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v NullString
+			err := v.Scan(tt.src)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, v)
+		})
+	}
+}
+
+func TestNullString_Value(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    NullString
+		expected driver.Value
+	}{
+		{
+			name:     "returns string when valid",
+			input:    NullString{Str: "hello", Valid: true},
+			expected: "hello",
+		},
+		{
+			name:     "returns empty string when valid",
+			input:    NullString{Str: "", Valid: true},
+			expected: "",
+		},
+		{
+			name:     "returns NULL when invalid",
+			input:    NullString{Str: "hello", Valid: false},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.input.Value()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
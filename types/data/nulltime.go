@@ -1,94 +1,82 @@
 package data
 
 import (
-	"encoding/json"
-	"fmt"
+	"database/sql/driver"
 	"time"
 )
 
 // NullTime represents a time.Time that may be null in JSON
 // Unlike MultiDateTime, this preserves null values instead of converting them to zero time
 // Accepts: RFC3339 strings, various datetime formats, and null
+// It is a thin wrapper around Null[time.Time], kept for its named field; use Null() for the generic helpers.
 type NullTime struct {
 	Time  time.Time
 	Valid bool // true if Time is not null
 }
 
+// Null returns the value as a Null[time.Time]
+func (nt NullTime) Null() Null[time.Time] {
+	return Null[time.Time]{V: nt.Time, Valid: nt.Valid}
+}
+
 // UnmarshalJSON implements json.Unmarshaler for NullTime
 func (nt *NullTime) UnmarshalJSON(data []byte) error {
-	// Handle null
-	if data == nil || string(data) == "null" {
-		nt.Time = time.Time{}
-		nt.Valid = false
+	var n Null[time.Time]
 
-		return nil
+	valid, err := decodeNull(data, &n.V, "NullTime")
+	if err != nil {
+		return err
 	}
 
-	// Try to unmarshal as string first
-	var s string
-
-	if err := json.Unmarshal(data, &s); err == nil {
-		// Handle empty string and "null" string as null
-		if s == "" || s == "null" {
-			nt.Time = time.Time{}
-			nt.Valid = false
-
-			return nil
-		}
-
-		// Try to parse the string as time using various formats
-		for _, format := range timeFormats {
-			if parsedTime, err := time.Parse(format, s); err == nil {
-				nt.Time = parsedTime
-				nt.Valid = true
-
-				return nil
-			}
-		}
-
-		return fmt.Errorf("cannot parse string %q as time", s)
-	}
+	nt.Time = n.V
+	nt.Valid = valid
 
-	return fmt.Errorf("cannot unmarshal %s into NullTime", string(data))
+	return nil
 }
 
 // MarshalJSON implements json.Marshaler for NullTime
 func (nt NullTime) MarshalJSON() ([]byte, error) {
-	if !nt.Valid {
-		return []byte("null"), nil
-	}
+	return nt.Null().MarshalJSON()
+}
 
-	// Use RFC3339Nano if there are nanoseconds, otherwise RFC3339
-	format := time.RFC3339
-	if nt.Time.Nanosecond() != 0 {
-		format = time.RFC3339Nano
+// Scan implements sql.Scanner for NullTime
+// SQL NULL is preserved as Valid=false
+func (nt *NullTime) Scan(src any) error {
+	var n Null[time.Time]
+	if err := n.Scan(src); err != nil {
+		return err
 	}
 
-	return json.Marshal(nt.Time.Format(format))
+	nt.Time = n.V
+	nt.Valid = n.Valid
+
+	return nil
+}
+
+// Value implements driver.Valuer for NullTime
+// Returns SQL NULL when not valid
+func (nt NullTime) Value() (driver.Value, error) {
+	return nt.Null().Value()
 }
 
 // ValueOr returns the time value if valid, otherwise returns the fallback value
 func (nt NullTime) ValueOr(fallback time.Time) time.Time {
-	if nt.Valid {
-		return nt.Time
-	}
+	return nt.Null().ValueOr(fallback)
+}
+
+// OrElse returns the time value if valid, otherwise returns the result of calling fn
+func (nt NullTime) OrElse(fn func() time.Time) time.Time {
+	return nt.Null().OrElse(fn)
+}
 
-	return fallback
+// Ptr returns a pointer to a copy of the time value if valid, otherwise nil
+func (nt NullTime) Ptr() *time.Time {
+	return nt.Null().Ptr()
 }
 
 // String returns the string representation of the time, or "null" if not valid
 func (nt NullTime) String() string {
-	if !nt.Valid {
-		return "null"
-	}
-
-	// Use RFC3339Nano if there are nanoseconds, otherwise RFC3339
-	format := time.RFC3339
-	if nt.Time.Nanosecond() != 0 {
-		format = time.RFC3339Nano
-	}
-
-	return nt.Time.Format(format)
+	return nt.Null().String()
 }
 
 // IsZero returns true if the time is not valid or is the zero time
@@ -113,25 +101,25 @@ func NewNullTime(value time.Time) NullTime {
 	}
 }
 
+// NullTimeFrom creates a NullTime from a Null[time.Time]
+func NullTimeFrom(n Null[time.Time]) NullTime {
+	return NullTime{
+		Time:  n.V,
+		Valid: n.Valid,
+	}
+}
+
 // NewNullTimeFromString creates a new NullTime by parsing the given string
 func NewNullTimeFromString(value string) (NullTime, error) {
 	// Handle empty string and "null" string as null
 	if value == "" || value == "null" {
-		return NullTime{
-			Time:  time.Time{},
-			Valid: false,
-		}, nil
+		return NullTime{}, nil
 	}
 
-	// Try to parse the string as time using various formats
-	for _, format := range timeFormats {
-		if parsedTime, err := time.Parse(format, value); err == nil {
-			return NullTime{
-				Time:  parsedTime,
-				Valid: true,
-			}, nil
-		}
+	parsed, _, err := parseTimeString(value)
+	if err != nil {
+		return NullTime{}, err
 	}
 
-	return NullTime{}, fmt.Errorf("cannot parse string %q as time", value)
+	return NewNullTime(parsed), nil
 }
@@ -1,6 +1,7 @@
 package data
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"testing"
 	"time"
@@ -685,3 +686,76 @@ func TestNullTime_CompareWithMultiDateTime(t *testing.T) {
 		assert.Equal(t, "null", result.Null.String())
 	})
 }
+
+func TestNullTime_Scan(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      any
+		expected NullTime
+		wantErr  bool
+	}{
+		{
+			name:     "scans time.Time",
+			src:      time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+			expected: NullTime{Time: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC), Valid: true},
+		},
+		{
+			name:     "scans RFC3339 string",
+			src:      "2024-01-15T10:30:00Z",
+			expected: NullTime{Time: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC), Valid: true},
+		},
+		{
+			name:     "scans NULL as invalid",
+			src:      nil,
+			expected: NullTime{},
+		},
+		{
+			name:     "returns error for unsupported type",
+			src:      int64(1),
+			expected: NullTime{},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v NullTime
+			err := v.Scan(tt.src)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, v)
+		})
+	}
+}
+
+func TestNullTime_Value(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    NullTime
+		expected driver.Value
+	}{
+		{
+			name:     "returns time.Time when valid",
+			input:    NullTime{Time: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC), Valid: true},
+			expected: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "returns NULL when invalid",
+			input:    NullTime{Time: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC), Valid: false},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.input.Value()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
@@ -1,102 +1,81 @@
 package data
 
 import (
-	"encoding/json"
-	"fmt"
-	"strconv"
+	"database/sql/driver"
 )
 
 // NullUint represents a uint64 that may be null in JSON
 // Unlike Uint, this preserves null values instead of converting them to zero
 // Accepts: unsigned integers, floats (truncated), string representations of numbers, and null
+// It is a thin wrapper around Null[uint64], kept for its named field; use Null() for the generic helpers.
 type NullUint struct {
 	Uint  uint64
 	Valid bool // true if Uint is not null
 }
 
+// Null returns the value as a Null[uint64]
+func (nu NullUint) Null() Null[uint64] {
+	return Null[uint64]{V: nu.Uint, Valid: nu.Valid}
+}
+
 // UnmarshalJSON implements json.Unmarshaler for NullUint
 func (nu *NullUint) UnmarshalJSON(data []byte) error {
-	// Handle null
-	if data == nil || string(data) == "null" {
-		nu.Uint = 0
-		nu.Valid = false
-		return nil
-	}
+	var n Null[uint64]
 
-	// Try to unmarshal as uint64 first
-	var ui uint64
-	if err := json.Unmarshal(data, &ui); err == nil {
-		nu.Uint = ui
-		nu.Valid = true
-		return nil
+	valid, err := decodeNull(data, &n.V, "NullUint")
+	if err != nil {
+		return err
 	}
 
-	// Try to unmarshal as float64 (JSON numbers default to float64)
-	var f float64
-	if err := json.Unmarshal(data, &f); err == nil {
-		if f < 0 {
-			return fmt.Errorf("cannot convert negative number %v to uint", f)
-		}
-		nu.Uint = uint64(f) // Truncate to uint64
-		nu.Valid = true
-		return nil
-	}
-
-	// Try to unmarshal as string and parse as uint
-	var s string
-	if err := json.Unmarshal(data, &s); err == nil {
-		// Handle empty string as null
-		if s == "" || s == "null" {
-			nu.Uint = 0
-			nu.Valid = false
-			return nil
-		}
-
-		// Try to parse string as uint64
-		if parsed, err := strconv.ParseUint(s, 10, 64); err == nil {
-			nu.Uint = parsed
-			nu.Valid = true
-			return nil
-		}
-
-		// Try to parse string as float and truncate to uint64
-		if parsed, err := strconv.ParseFloat(s, 64); err == nil {
-			if parsed < 0 {
-				return fmt.Errorf("cannot convert negative number %v to uint", parsed)
-			}
-			nu.Uint = uint64(parsed)
-			nu.Valid = true
-			return nil
-		}
-
-		return fmt.Errorf("cannot parse string %q as uint", s)
-	}
+	nu.Uint = n.V
+	nu.Valid = valid
 
-	return fmt.Errorf("cannot unmarshal %s into NullUint", string(data))
+	return nil
 }
 
 // MarshalJSON implements json.Marshaler for NullUint
 func (nu NullUint) MarshalJSON() ([]byte, error) {
-	if !nu.Valid {
-		return []byte("null"), nil
+	return nu.Null().MarshalJSON()
+}
+
+// Scan implements sql.Scanner for NullUint
+// SQL NULL is preserved as Valid=false
+func (nu *NullUint) Scan(src any) error {
+	var n Null[uint64]
+	if err := n.Scan(src); err != nil {
+		return err
 	}
-	return json.Marshal(nu.Uint)
+
+	nu.Uint = n.V
+	nu.Valid = n.Valid
+
+	return nil
+}
+
+// Value implements driver.Valuer for NullUint
+// Returns SQL NULL when not valid; values above math.MaxInt64 return an error
+func (nu NullUint) Value() (driver.Value, error) {
+	return nu.Null().Value()
 }
 
 // ValueOr returns the uint value if valid, otherwise returns the fallback value
 func (nu NullUint) ValueOr(fallback uint64) uint64 {
-	if nu.Valid {
-		return nu.Uint
-	}
-	return fallback
+	return nu.Null().ValueOr(fallback)
+}
+
+// OrElse returns the uint value if valid, otherwise returns the result of calling fn
+func (nu NullUint) OrElse(fn func() uint64) uint64 {
+	return nu.Null().OrElse(fn)
+}
+
+// Ptr returns a pointer to a copy of the uint value if valid, otherwise nil
+func (nu NullUint) Ptr() *uint64 {
+	return nu.Null().Ptr()
 }
 
 // String returns the string representation of the uint, or "null" if not valid
 func (nu NullUint) String() string {
-	if !nu.Valid {
-		return "null"
-	}
-	return strconv.FormatUint(nu.Uint, 10)
+	return nu.Null().String()
 }
 
 // NewNullUint creates a new valid NullUint with the given value
@@ -106,3 +85,11 @@ func NewNullUint(value uint64) NullUint {
 		Valid: true,
 	}
 }
+
+// NullUintFrom creates a NullUint from a Null[uint64]
+func NullUintFrom(n Null[uint64]) NullUint {
+	return NullUint{
+		Uint:  n.V,
+		Valid: n.Valid,
+	}
+}
@@ -1,6 +1,7 @@
 package data
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"testing"
 
@@ -429,3 +430,76 @@ func TestNullUint_CompareWithUint(t *testing.T) {
 		assert.Equal(t, `null`, string(nuMarshaled), "NullUint should marshal null as null")
 	})
 }
+
+func TestNullUint_Scan(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      any
+		expected NullUint
+		wantErr  bool
+	}{
+		{
+			name:     "scans int64",
+			src:      int64(42),
+			expected: NullUint{Uint: 42, Valid: true},
+		},
+		{
+			name:     "scans numeric string",
+			src:      "7",
+			expected: NullUint{Uint: 7, Valid: true},
+		},
+		{
+			name:     "scans NULL as invalid",
+			src:      nil,
+			expected: NullUint{},
+		},
+		{
+			name:     "returns error for negative float64",
+			src:      float64(-1.5),
+			expected: NullUint{},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v NullUint
+			err := v.Scan(tt.src)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, v)
+		})
+	}
+}
+
+func TestNullUint_Value(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    NullUint
+		expected driver.Value
+	}{
+		{
+			name:     "returns int64 when valid",
+			input:    NullUint{Uint: 42, Valid: true},
+			expected: int64(42),
+		},
+		{
+			name:     "returns NULL when invalid",
+			input:    NullUint{Uint: 42, Valid: false},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.input.Value()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package data
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The scan helpers below convert the values database/sql drivers hand to sql.Scanner
+// implementations. Each returns valid=false for SQL NULL so the non-null types can map
+// it to their zero value and the null types can preserve it.
+
+func scanString(src any) (s string, valid bool, err error) {
+	switch v := src.(type) {
+	case nil:
+		return "", false, nil
+	case string:
+		return v, true, nil
+	case []byte:
+		return string(v), true, nil
+	case int64:
+		return strconv.FormatInt(v, 10), true, nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true, nil
+	case bool:
+		return strconv.FormatBool(v), true, nil
+	case time.Time:
+		return v.Format(time.RFC3339Nano), true, nil
+	default:
+		return "", false, fmt.Errorf("cannot scan %T into string", src)
+	}
+}
+
+func scanInt(src any) (i int64, valid bool, err error) {
+	switch v := src.(type) {
+	case nil:
+		return 0, false, nil
+	case int64:
+		return v, true, nil
+	case float64:
+		return int64(v), true, nil // Truncate to integer
+	case bool:
+		if v {
+			return 1, true, nil
+		}
+		return 0, true, nil
+	case string:
+		return parseIntString(v)
+	case []byte:
+		return parseIntString(string(v))
+	default:
+		return 0, false, fmt.Errorf("cannot scan %T into integer", src)
+	}
+}
+
+func parseIntString(s string) (int64, bool, error) {
+	s = strings.TrimSpace(s)
+
+	if parsed, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return parsed, true, nil
+	}
+
+	if parsed, err := strconv.ParseFloat(s, 64); err == nil {
+		return int64(parsed), true, nil
+	}
+
+	return 0, false, fmt.Errorf("cannot parse string %q as integer", s)
+}
+
+func scanUint(src any) (u uint64, valid bool, err error) {
+	switch v := src.(type) {
+	case nil:
+		return 0, false, nil
+	case int64:
+		if v < 0 {
+			return 0, false, fmt.Errorf("cannot convert negative number %v to uint", v)
+		}
+		return uint64(v), true, nil
+	case float64:
+		if v < 0 {
+			return 0, false, fmt.Errorf("cannot convert negative number %v to uint", v)
+		}
+		return uint64(v), true, nil
+	case string:
+		return parseUintString(v)
+	case []byte:
+		return parseUintString(string(v))
+	default:
+		return 0, false, fmt.Errorf("cannot scan %T into uint", src)
+	}
+}
+
+func parseUintString(s string) (uint64, bool, error) {
+	s = strings.TrimSpace(s)
+
+	if parsed, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return parsed, true, nil
+	}
+
+	if parsed, err := strconv.ParseFloat(s, 64); err == nil {
+		if parsed < 0 {
+			return 0, false, fmt.Errorf("cannot convert negative number %v to uint", parsed)
+		}
+		return uint64(parsed), true, nil
+	}
+
+	return 0, false, fmt.Errorf("cannot parse string %q as uint", s)
+}
+
+// uintValue converts a uint64 to a driver.Value, which has no unsigned integer type
+func uintValue(u uint64) (int64, error) {
+	if u > math.MaxInt64 {
+		return 0, fmt.Errorf("uint value %d overflows int64", u)
+	}
+
+	return int64(u), nil
+}
+
+func scanFloat(src any) (f float64, valid bool, err error) {
+	switch v := src.(type) {
+	case nil:
+		return 0, false, nil
+	case float64:
+		return v, true, nil
+	case int64:
+		return float64(v), true, nil
+	case string:
+		return parseFloatString(v)
+	case []byte:
+		return parseFloatString(string(v))
+	default:
+		return 0, false, fmt.Errorf("cannot scan %T into float", src)
+	}
+}
+
+func parseFloatString(s string) (float64, bool, error) {
+	parsed, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("cannot parse string %q as float", s)
+	}
+
+	return parsed, true, nil
+}
+
+func scanBool(src any) (b bool, valid bool, err error) {
+	switch v := src.(type) {
+	case nil:
+		return false, false, nil
+	case bool:
+		return v, true, nil
+	case int64:
+		return v != 0, true, nil
+	case float64:
+		return v != 0, true, nil
+	case string:
+		return parseBoolString(v)
+	case []byte:
+		return parseBoolString(string(v))
+	default:
+		return false, false, fmt.Errorf("cannot scan %T into boolean", src)
+	}
+}
+
+func parseBoolString(s string) (bool, bool, error) {
+	// Covers the forms used by common drivers, e.g. Postgres "t"/"f" and MySQL "1"/"0"
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "true", "t", "1", "yes", "y", "success":
+		return true, true, nil
+	case "false", "f", "0", "no", "n", "failure":
+		return false, true, nil
+	}
+
+	return false, false, fmt.Errorf("cannot parse string %q as boolean", s)
+}
+
+func scanTime(src any) (t time.Time, valid bool, err error) {
+	switch v := src.(type) {
+	case nil:
+		return time.Time{}, false, nil
+	case time.Time:
+		return v, true, nil
+	case string:
+		return parseTimeString(v)
+	case []byte:
+		return parseTimeString(string(v))
+	default:
+		return time.Time{}, false, fmt.Errorf("cannot scan %T into time", src)
+	}
+}
+
+func parseTimeString(s string) (time.Time, bool, error) {
+	for _, format := range timeFormats {
+		if parsed, err := time.Parse(format, s); err == nil {
+			return parsed, true, nil
+		}
+	}
+
+	return time.Time{}, false, fmt.Errorf("cannot parse string %q as time", s)
+}
@@ -1,6 +1,7 @@
 package data
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 )
@@ -48,6 +49,23 @@ func (s String) MarshalJSON() ([]byte, error) {
 	return json.Marshal(string(s))
 }
 
+// Scan implements sql.Scanner for String
+// SQL NULL is converted to an empty string
+func (s *String) Scan(src any) error {
+	str, _, err := scanString(src)
+	if err != nil {
+		return err
+	}
+
+	*s = String(str)
+	return nil
+}
+
+// Value implements driver.Valuer for String
+func (s String) Value() (driver.Value, error) {
+	return string(s), nil
+}
+
 // String returns the string value (implements fmt.Stringer)
 func (s String) String() string {
 	return string(s)
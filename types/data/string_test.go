@@ -1,6 +1,7 @@
 package data
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"testing"
 
@@ -342,3 +343,86 @@ func TestString_SpecialNumberFormats(t *testing.T) {
 		})
 	}
 }
+
+func TestString_Scan(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      any
+		expected String
+		wantErr  bool
+	}{
+		{
+			name:     "scans string",
+			src:      "hello",
+			expected: String("hello"),
+		},
+		{
+			name:     "scans bytes",
+			src:      []byte("hello"),
+			expected: String("hello"),
+		},
+		{
+			name:     "scans int64",
+			src:      int64(42),
+			expected: String("42"),
+		},
+		{
+			name:     "scans float64",
+			src:      float64(1.5),
+			expected: String("1.5"),
+		},
+		{
+			name:     "scans NULL as empty string",
+			src:      nil,
+			expected: String(""),
+		},
+		{
+			name:     "returns error for unsupported type",
+			src:      struct{}{},
+			expected: String(""),
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v String
+			err := v.Scan(tt.src)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, v)
+		})
+	}
+}
+
+func TestString_Value(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    String
+		expected driver.Value
+	}{
+		{
+			name:     "returns string",
+			input:    String("hello"),
+			expected: "hello",
+		},
+		{
+			name:     "returns empty string",
+			input:    String(""),
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.input.Value()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
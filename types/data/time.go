@@ -1,6 +1,7 @@
 package data
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -24,40 +25,38 @@ var timeFormats = []string{
 type Time time.Time
 
 // UnmarshalJSON implements json.Unmarshaler for Time
+// Null values and empty strings are converted to the zero value
 func (t *Time) UnmarshalJSON(data []byte) error {
-	// Handle null - convert to zero time
-	if data == nil || string(data) == "null" {
-		*t = Time(time.Time{})
-		return nil
+	var v time.Time
+	if _, err := decodeNull(data, &v, "Time"); err != nil {
+		return err
 	}
 
-	// Try to unmarshal as string and parse as time
-	var s string
+	*t = Time(v)
 
-	if err := json.Unmarshal(data, &s); err == nil {
-		// Handle empty string and "null" string as zero time
-		if s == "" || s == "null" {
-			*t = Time(time.Time{})
-			return nil
-		}
+	return nil
+}
 
-		// Try various time formats
-		for _, format := range timeFormats {
-			if parsed, err := time.Parse(format, s); err == nil {
-				*t = Time(parsed)
-				return nil
-			}
-		}
+// MarshalJSON implements json.Marshaler for Time
+func (t Time) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Time(t).Format(time.RFC3339))
+}
 
-		return fmt.Errorf("cannot parse string %q as time", s)
+// Scan implements sql.Scanner for Time
+// SQL NULL is converted to zero time
+func (t *Time) Scan(src any) error {
+	parsed, _, err := scanTime(src)
+	if err != nil {
+		return err
 	}
 
-	return fmt.Errorf("cannot unmarshal %s into Time", string(data))
+	*t = Time(parsed)
+	return nil
 }
 
-// MarshalJSON implements json.Marshaler for Time
-func (t Time) MarshalJSON() ([]byte, error) {
-	return json.Marshal(time.Time(t).Format(time.RFC3339))
+// Value implements driver.Valuer for Time
+func (t Time) Value() (driver.Value, error) {
+	return time.Time(t), nil
 }
 
 // Time returns the time.Time value
@@ -1,6 +1,7 @@
 package data
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"testing"
 	"time"
@@ -396,3 +397,81 @@ func TestTime_EdgeCases(t *testing.T) {
 		assert.True(t, expected.Equal(tm.Time()))
 	})
 }
+
+func TestTime_Scan(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      any
+		expected Time
+		wantErr  bool
+	}{
+		{
+			name:     "scans time.Time",
+			src:      time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+			expected: Time(time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)),
+		},
+		{
+			name:     "scans RFC3339 string",
+			src:      "2024-01-15T10:30:00Z",
+			expected: Time(time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)),
+		},
+		{
+			name:     "scans datetime bytes",
+			src:      []byte("2024-01-15 10:30:00"),
+			expected: Time(time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)),
+		},
+		{
+			name:     "scans NULL as zero time",
+			src:      nil,
+			expected: Time{},
+		},
+		{
+			name:     "returns error for invalid string",
+			src:      "not a time",
+			expected: Time{},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v Time
+			err := v.Scan(tt.src)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, v)
+		})
+	}
+}
+
+func TestTime_Value(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    Time
+		expected driver.Value
+	}{
+		{
+			name:     "returns time.Time",
+			input:    Time(time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)),
+			expected: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "returns zero time",
+			input:    Time{},
+			expected: time.Time{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.input.Value()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
@@ -1,8 +1,8 @@
 package data
 
 import (
+	"database/sql/driver"
 	"encoding/json"
-	"fmt"
 	"strconv"
 )
 
@@ -12,63 +12,39 @@ import (
 type Uint uint64
 
 // UnmarshalJSON implements json.Unmarshaler for Uint
+// Null values and empty strings are converted to the zero value
 func (u *Uint) UnmarshalJSON(data []byte) error {
-	// Handle null
-	if data == nil || string(data) == "null" {
-		*u = 0
-		return nil
+	var v uint64
+	if _, err := decodeNull(data, &v, "Uint"); err != nil {
+		return err
 	}
 
-	// Try to unmarshal as uint64 first
-	var ui uint64
-	if err := json.Unmarshal(data, &ui); err == nil {
-		*u = Uint(ui)
-		return nil
-	}
-
-	// Try to unmarshal as float64 (JSON numbers default to float64)
-	var f float64
-	if err := json.Unmarshal(data, &f); err == nil {
-		if f < 0 {
-			return fmt.Errorf("cannot convert negative number %v to uint", f)
-		}
-		*u = Uint(uint64(f)) // Truncate to uint64
-		return nil
-	}
+	*u = Uint(v)
 
-	// Try to unmarshal as string and parse as uint
-	var s string
-	if err := json.Unmarshal(data, &s); err == nil {
-		// Handle empty string or "null" as zero
-		if s == "" || s == "null" {
-			*u = 0
-			return nil
-		}
-
-		// Try to parse string as uint64
-		if parsed, err := strconv.ParseUint(s, 10, 64); err == nil {
-			*u = Uint(parsed)
-			return nil
-		}
+	return nil
+}
 
-		// Try to parse string as float and truncate to uint64
-		if parsed, err := strconv.ParseFloat(s, 64); err == nil {
-			if parsed < 0 {
-				return fmt.Errorf("cannot convert negative number %v to uint", parsed)
-			}
-			*u = Uint(uint64(parsed))
-			return nil
-		}
+// MarshalJSON implements json.Marshaler for Uint
+func (u Uint) MarshalJSON() ([]byte, error) {
+	return json.Marshal(uint64(u))
+}
 
-		return fmt.Errorf("cannot parse string %q as uint", s)
+// Scan implements sql.Scanner for Uint
+// SQL NULL is converted to zero
+func (u *Uint) Scan(src any) error {
+	ui, _, err := scanUint(src)
+	if err != nil {
+		return err
 	}
 
-	return fmt.Errorf("cannot unmarshal %s into Uint", string(data))
+	*u = Uint(ui)
+	return nil
 }
 
-// MarshalJSON implements json.Marshaler for Uint
-func (u Uint) MarshalJSON() ([]byte, error) {
-	return json.Marshal(uint64(u))
+// Value implements driver.Valuer for Uint
+// Values above math.MaxInt64 cannot be represented and return an error
+func (u Uint) Value() (driver.Value, error) {
+	return uintValue(uint64(u))
 }
 
 // Uint64 returns the uint64 value
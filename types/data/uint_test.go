@@ -1,6 +1,7 @@
 package data
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"testing"
 
@@ -337,3 +338,82 @@ func TestNewUint(t *testing.T) {
 		})
 	}
 }
+
+func TestUint_Scan(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      any
+		expected Uint
+		wantErr  bool
+	}{
+		{
+			name:     "scans int64",
+			src:      int64(42),
+			expected: Uint(42),
+		},
+		{
+			name:     "scans numeric bytes",
+			src:      []byte("18446744073709551615"),
+			expected: Uint(18446744073709551615),
+		},
+		{
+			name:     "scans NULL as zero",
+			src:      nil,
+			expected: Uint(0),
+		},
+		{
+			name:     "returns error for negative int64",
+			src:      int64(-1),
+			expected: Uint(0),
+			wantErr:  true,
+		},
+		{
+			name:     "returns error for non-numeric string",
+			src:      "abc",
+			expected: Uint(0),
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v Uint
+			err := v.Scan(tt.src)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, v)
+		})
+	}
+}
+
+func TestUint_Value(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    Uint
+		expected driver.Value
+	}{
+		{
+			name:     "returns int64",
+			input:    Uint(42),
+			expected: int64(42),
+		},
+		{
+			name:     "returns zero",
+			input:    Uint(0),
+			expected: int64(0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.input.Value()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
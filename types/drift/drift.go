@@ -0,0 +1,323 @@
+// Package drift detects schema drift between Paystack JSON payloads and the Go types
+// they are decoded into.
+//
+// The lenient types in types/data silently absorb type mismatches (a quoted number
+// decoded into data.Int, a number decoded into data.String) and encoding/json silently
+// drops fields the target struct does not declare. Check walks a payload alongside its
+// target type and reports both, so new or changed fields surface instead of vanishing.
+package drift
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/huysamen/paystack-go/types"
+	"github.com/huysamen/paystack-go/types/data"
+)
+
+// IssueKind identifies the kind of drift found
+type IssueKind string
+
+const (
+	// IssueUnknownField is reported for JSON object keys the target struct does not declare
+	IssueUnknownField IssueKind = "unknown_field"
+	// IssueCoercion is reported when a types/data type had to convert the JSON value
+	// from a different JSON type, e.g. a quoted number into data.Int
+	IssueCoercion IssueKind = "coercion"
+)
+
+// Issue describes a single drift finding
+type Issue struct {
+	Kind IssueKind
+	// Path is the location of the value in the payload, e.g. "data.customer.phone"
+	Path string
+	// Detail describes the coercion performed (e.g. "string-to-int"), empty for unknown fields
+	Detail string
+}
+
+// String returns a human readable description of the issue
+func (i Issue) String() string {
+	if i.Detail == "" {
+		return fmt.Sprintf("%s: %s", i.Kind, i.Path)
+	}
+
+	return fmt.Sprintf("%s: %s (%s)", i.Kind, i.Path, i.Detail)
+}
+
+// Report collects the issues found while decoding a single response
+type Report struct {
+	// Endpoint identifies where the payload came from, e.g. "GET /transaction/verify/ref"
+	Endpoint string
+	// Type is the Go type the payload was decoded into
+	Type   string
+	Issues []Issue
+}
+
+// HasDrift returns true if the report contains any issues
+func (r Report) HasDrift() bool {
+	return len(r.Issues) > 0
+}
+
+// UnknownFields returns the paths of all unknown fields in the report
+func (r Report) UnknownFields() []string {
+	return r.paths(IssueUnknownField)
+}
+
+// Coercions returns the paths of all coerced values in the report
+func (r Report) Coercions() []string {
+	return r.paths(IssueCoercion)
+}
+
+func (r Report) paths(kind IssueKind) []string {
+	var paths []string
+	for _, i := range r.Issues {
+		if i.Kind == kind {
+			paths = append(paths, i.Path)
+		}
+	}
+
+	return paths
+}
+
+// target kinds of the lenient types whose coercions are reported
+const (
+	kindString = "string"
+	kindInt    = "int"
+	kindUint   = "uint"
+	kindFloat  = "float"
+	kindBool   = "bool"
+	kindTime   = "time"
+	kindObject = "object"
+)
+
+var lenientTypes = map[reflect.Type]string{
+	reflect.TypeOf(data.String("")):        kindString,
+	reflect.TypeOf(data.NullString{}):      kindString,
+	reflect.TypeOf(data.Int(0)):            kindInt,
+	reflect.TypeOf(data.NullInt{}):         kindInt,
+	reflect.TypeOf(data.Uint(0)):           kindUint,
+	reflect.TypeOf(data.NullUint{}):        kindUint,
+	reflect.TypeOf(data.Float(0)):          kindFloat,
+	reflect.TypeOf(data.NullFloat{}):       kindFloat,
+	reflect.TypeOf(data.Bool(false)):       kindBool,
+	reflect.TypeOf(data.NullBool{}):        kindBool,
+	reflect.TypeOf(data.Time{}):            kindTime,
+	reflect.TypeOf(data.NullTime{}):        kindTime,
+	reflect.TypeOf(data.Null[string]{}):    kindString,
+	reflect.TypeOf(data.Null[int64]{}):     kindInt,
+	reflect.TypeOf(data.Null[uint64]{}):    kindUint,
+	reflect.TypeOf(data.Null[float64]{}):   kindFloat,
+	reflect.TypeOf(data.Null[bool]{}):      kindBool,
+	reflect.TypeOf(data.Null[time.Time]{}): kindTime,
+	reflect.TypeOf(types.Metadata{}):       kindObject,
+	reflect.TypeOf((*any)(nil)).Elem():     "",
+	reflect.TypeOf(json.RawMessage(nil)):   "",
+}
+
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// Check walks the JSON payload alongside the type of v and returns any drift found.
+// v is typically a pointer to the value the payload was, or will be, decoded into.
+func Check(payload []byte, v any) ([]Issue, error) {
+	return CheckType(payload, reflect.TypeOf(v))
+}
+
+// CheckType walks the JSON payload alongside t and returns any drift found
+func CheckType(payload []byte, t reflect.Type) ([]Issue, error) {
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.UseNumber()
+
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	w := &walker{}
+	w.walk(value, t, "")
+
+	return w.issues, nil
+}
+
+type walker struct {
+	issues []Issue
+}
+
+func (w *walker) add(kind IssueKind, path, detail string) {
+	if path == "" {
+		path = "$"
+	}
+
+	w.issues = append(w.issues, Issue{Kind: kind, Path: path, Detail: detail})
+}
+
+func (w *walker) walk(value any, t reflect.Type, path string) {
+	if value == nil || t == nil {
+		return
+	}
+
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if kind, ok := lenientTypes[t]; ok {
+		if kind != "" {
+			w.checkCoercion(value, kind, path)
+		}
+		return
+	}
+
+	// Other types with their own decoding rules (enums, types.Meta) are treated as opaque
+	if reflect.PointerTo(t).Implements(unmarshalerType) || t.Implements(unmarshalerType) {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return
+		}
+
+		fields := structFields(t)
+
+		for _, k := range sortedKeys(obj) {
+			field, ok := fields[k]
+			if !ok {
+				field, ok = fields[strings.ToLower(k)]
+			}
+			if !ok {
+				w.add(IssueUnknownField, join(path, k), "")
+				continue
+			}
+
+			w.walk(obj[k], field, join(path, k))
+		}
+	case reflect.Slice, reflect.Array:
+		arr, ok := value.([]any)
+		if !ok {
+			return
+		}
+
+		for i, v := range arr {
+			w.walk(v, t.Elem(), fmt.Sprintf("%s[%d]", path, i))
+		}
+	case reflect.Map:
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return
+		}
+
+		for _, k := range sortedKeys(obj) {
+			w.walk(obj[k], t.Elem(), join(path, k))
+		}
+	}
+}
+
+func (w *walker) checkCoercion(value any, kind, path string) {
+	switch v := value.(type) {
+	case string:
+		// Empty and "null" strings are the documented way these types express null
+		if v == "" || v == "null" {
+			return
+		}
+		switch kind {
+		case kindString, kindTime:
+			return
+		}
+		w.add(IssueCoercion, path, "string-to-"+kind)
+	case json.Number:
+		switch kind {
+		case kindInt, kindUint:
+			if strings.ContainsAny(v.String(), ".eE") {
+				w.add(IssueCoercion, path, "float-to-"+kind)
+			}
+			return
+		case kindFloat:
+			return
+		}
+		w.add(IssueCoercion, path, "number-to-"+kind)
+	case bool:
+		if kind != kindBool {
+			w.add(IssueCoercion, path, "bool-to-"+kind)
+		}
+	case map[string]any:
+		if kind != kindObject {
+			w.add(IssueCoercion, path, "object-to-"+kind)
+		}
+	case []any:
+		w.add(IssueCoercion, path, "array-to-"+kind)
+	}
+}
+
+// structFields maps the JSON names of t's fields, including promoted fields of embedded
+// structs, to their types. Lower-cased names are included to mirror the case-insensitive
+// matching of encoding/json.
+func structFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, _, _ := strings.Cut(tag, ",")
+
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				for k, v := range structFields(ft) {
+					if _, exists := fields[k]; !exists {
+						fields[k] = v
+					}
+				}
+				continue
+			}
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		fields[name] = f.Type
+		if lower := strings.ToLower(name); lower != name {
+			if _, exists := fields[lower]; !exists {
+				fields[lower] = f.Type
+			}
+		}
+	}
+
+	return fields
+}
+
+func sortedKeys(obj map[string]any) []string {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
@@ -0,0 +1,164 @@
+package drift
+
+import (
+	"context"
+	"testing"
+
+	"github.com/huysamen/paystack-go/types"
+	"github.com/huysamen/paystack-go/types/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCustomer struct {
+	ID    data.Int    `json:"id"`
+	Email data.String `json:"email"`
+}
+
+type testPayload struct {
+	Amount   data.Int         `json:"amount"`
+	Paid     data.NullBool    `json:"paid"`
+	Note     data.NullString  `json:"note"`
+	Metadata types.Metadata   `json:"metadata"`
+	Customer *testCustomer    `json:"customer"`
+	Items    []testCustomer   `json:"items"`
+	Raw      any              `json:"raw"`
+	PerPage  int              `json:"perPage"`
+	Fee      data.Null[int64] `json:"fee"`
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []Issue
+	}{
+		{
+			name:  "no drift",
+			input: `{"amount":100,"paid":true,"note":null,"metadata":{"a":1},"customer":{"id":1,"email":"a@b.c"}}`,
+		},
+		{
+			name:  "reports unknown top level field",
+			input: `{"amount":100,"new_field":"x"}`,
+			expected: []Issue{
+				{Kind: IssueUnknownField, Path: "new_field"},
+			},
+		},
+		{
+			name:  "reports unknown nested and array fields",
+			input: `{"customer":{"id":1,"phone":"123"},"items":[{"id":1},{"id":2,"code":"x"}]}`,
+			expected: []Issue{
+				{Kind: IssueUnknownField, Path: "customer.phone"},
+				{Kind: IssueUnknownField, Path: "items[1].code"},
+			},
+		},
+		{
+			name:  "reports string to int coercion",
+			input: `{"amount":"100"}`,
+			expected: []Issue{
+				{Kind: IssueCoercion, Path: "amount", Detail: "string-to-int"},
+			},
+		},
+		{
+			name:  "reports float to int coercion",
+			input: `{"amount":100.5}`,
+			expected: []Issue{
+				{Kind: IssueCoercion, Path: "amount", Detail: "float-to-int"},
+			},
+		},
+		{
+			name:  "reports number to bool and number to string coercions",
+			input: `{"paid":1,"note":42}`,
+			expected: []Issue{
+				{Kind: IssueCoercion, Path: "note", Detail: "number-to-string"},
+				{Kind: IssueCoercion, Path: "paid", Detail: "number-to-bool"},
+			},
+		},
+		{
+			name:  "reports coercions of generic null types",
+			input: `{"fee":"25"}`,
+			expected: []Issue{
+				{Kind: IssueCoercion, Path: "fee", Detail: "string-to-int"},
+			},
+		},
+		{
+			name:  "reports string encoded metadata",
+			input: `{"metadata":"{\"a\":1}"}`,
+			expected: []Issue{
+				{Kind: IssueCoercion, Path: "metadata", Detail: "string-to-object"},
+			},
+		},
+		{
+			name:  "tolerates empty strings and nulls",
+			input: `{"amount":"","paid":null,"note":"","metadata":""}`,
+		},
+		{
+			name:  "matches field names case insensitively",
+			input: `{"AMOUNT":1,"perpage":10}`,
+		},
+		{
+			name:  "does not descend into untyped fields",
+			input: `{"raw":{"anything":"goes"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues, err := Check([]byte(tt.input), new(testPayload))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, issues)
+		})
+	}
+}
+
+func TestCheck_ResponseWrapper(t *testing.T) {
+	input := `{"status":true,"message":"ok","data":[{"id":"7","email":"a@b.c","extra":1}],"meta":{"perPage":50,"unknown":1}}`
+
+	issues, err := Check([]byte(input), new(types.Response[[]testCustomer]))
+	require.NoError(t, err)
+
+	assert.Equal(t, []Issue{
+		{Kind: IssueUnknownField, Path: "data[0].extra"},
+		{Kind: IssueCoercion, Path: "data[0].id", Detail: "string-to-int"},
+	}, issues)
+}
+
+func TestCheck_InvalidJSON(t *testing.T) {
+	_, err := Check([]byte(`{`), new(testPayload))
+	assert.Error(t, err)
+}
+
+func TestReport(t *testing.T) {
+	r := Report{
+		Issues: []Issue{
+			{Kind: IssueUnknownField, Path: "data.a"},
+			{Kind: IssueCoercion, Path: "data.b", Detail: "string-to-int"},
+		},
+	}
+
+	assert.True(t, r.HasDrift())
+	assert.Equal(t, []string{"data.a"}, r.UnknownFields())
+	assert.Equal(t, []string{"data.b"}, r.Coercions())
+	assert.Equal(t, "coercion: data.b (string-to-int)", r.Issues[1].String())
+	assert.False(t, Report{}.HasDrift())
+}
+
+func TestWithReporter(t *testing.T) {
+	assert.Nil(t, ReporterFrom(context.Background()))
+
+	c := NewCollector()
+	ctx := WithReporter(context.Background(), c.Report)
+
+	reporter := ReporterFrom(ctx)
+	require.NotNil(t, reporter)
+
+	reporter(Report{Endpoint: "GET /transaction"})
+	reporter(Report{Endpoint: "GET /transaction"})
+	reporter(Report{Endpoint: "POST /refund"})
+
+	assert.Len(t, c.Reports(), 3)
+	assert.Len(t, c.ByEndpoint()["GET /transaction"], 2)
+
+	c.Reset()
+	assert.Empty(t, c.Reports())
+}
@@ -0,0 +1,160 @@
+// Package drifttest provides test helpers that fail a test when a JSON fixture has
+// drifted from the Go type it is decoded into.
+package drifttest
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/huysamen/paystack-go/types/drift"
+)
+
+// CheckFixture decodes the fixture at path into v and reports every drift issue as a
+// test error. v must be a pointer, e.g. new(transactions.VerifyResponse).
+func CheckFixture(t testing.TB, path string, v any) []drift.Issue {
+	t.Helper()
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading fixture %s: %v", path, err)
+	}
+
+	return CheckPayload(t, path, b, v)
+}
+
+// CheckPayload decodes payload into v and reports every drift issue as a test error,
+// using name to identify the payload in failure messages
+func CheckPayload(t testing.TB, name string, payload []byte, v any) []drift.Issue {
+	t.Helper()
+
+	if err := json.Unmarshal(payload, v); err != nil {
+		t.Errorf("%s: decoding into %T: %v", name, v, err)
+		return nil
+	}
+
+	issues, err := drift.Check(payload, v)
+	if err != nil {
+		t.Errorf("%s: checking drift: %v", name, err)
+		return nil
+	}
+
+	for _, issue := range issues {
+		t.Errorf("%s: %T: %s", name, v, issue)
+	}
+
+	return issues
+}
+
+// Baseline is a set of accepted drift issues read from a file, used to adopt drift
+// checking in a suite whose fixtures already contain known drift. Issues in the
+// baseline are tolerated, new issues fail the test and entries that no longer occur
+// are reported as stale so the baseline only ever shrinks.
+//
+// The file holds one "<name>\t<issue>" line per accepted issue. Lines starting with
+// "#" and blank lines are ignored.
+type Baseline struct {
+	path  string
+	known map[string]bool
+	seen  map[string]bool
+}
+
+// LoadBaseline reads the baseline at path. A missing file is treated as empty.
+func LoadBaseline(t testing.TB, path string) *Baseline {
+	t.Helper()
+
+	b := &Baseline{path: path, known: make(map[string]bool), seen: make(map[string]bool)}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return b
+		}
+		t.Fatalf("reading baseline %s: %v", path, err)
+	}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		b.known[line] = true
+	}
+
+	return b
+}
+
+// CheckFixture decodes the fixture at path into v and fails the test for every drift
+// issue not present in the baseline. name identifies the fixture in the baseline.
+func (b *Baseline) CheckFixture(t testing.TB, name, path string, v any) {
+	t.Helper()
+
+	payload, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading fixture %s: %v", path, err)
+	}
+
+	b.CheckPayload(t, name, payload, v)
+}
+
+// CheckPayload decodes payload into v and fails the test for every drift issue not
+// present in the baseline. name identifies the payload in the baseline.
+func (b *Baseline) CheckPayload(t testing.TB, name string, payload []byte, v any) {
+	t.Helper()
+
+	if err := json.Unmarshal(payload, v); err != nil {
+		t.Errorf("%s: decoding into %T: %v", name, v, err)
+		return
+	}
+
+	issues, err := drift.Check(payload, v)
+	if err != nil {
+		t.Errorf("%s: checking drift: %v", name, err)
+		return
+	}
+
+	for _, issue := range issues {
+		key := name + "\t" + issue.String()
+		b.seen[key] = true
+
+		if !b.known[key] {
+			t.Errorf("%s: %T: %s", name, v, issue)
+		}
+	}
+}
+
+// Finish fails the test for baseline entries that were not seen, meaning the drift was
+// fixed and the entry should be removed. When update is true the baseline file is
+// rewritten with exactly the issues seen instead.
+func (b *Baseline) Finish(t testing.TB, update bool) {
+	t.Helper()
+
+	seen := make([]string, 0, len(b.seen))
+	for key := range b.seen {
+		seen = append(seen, key)
+	}
+	sort.Strings(seen)
+
+	if update {
+		content := "# Known schema drift, one \"<fixture>\\t<issue>\" per line. Regenerate with -update.\n" +
+			strings.Join(seen, "\n") + "\n"
+		if err := os.WriteFile(b.path, []byte(content), 0o644); err != nil {
+			t.Fatalf("writing baseline %s: %v", b.path, err)
+		}
+		return
+	}
+
+	stale := make([]string, 0)
+	for key := range b.known {
+		if !b.seen[key] {
+			stale = append(stale, key)
+		}
+	}
+	sort.Strings(stale)
+
+	for _, key := range stale {
+		t.Errorf("stale baseline entry, drift no longer occurs: %s", key)
+	}
+}
@@ -0,0 +1,280 @@
+package drifttest_test
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/huysamen/paystack-go/api/applepay"
+	"github.com/huysamen/paystack-go/api/bulkcharges"
+	"github.com/huysamen/paystack-go/api/charge"
+	"github.com/huysamen/paystack-go/api/customers"
+	"github.com/huysamen/paystack-go/api/dedicatedvirtualaccounts"
+	"github.com/huysamen/paystack-go/api/directdebit"
+	"github.com/huysamen/paystack-go/api/disputes"
+	"github.com/huysamen/paystack-go/api/integration"
+	"github.com/huysamen/paystack-go/api/miscellaneous"
+	"github.com/huysamen/paystack-go/api/paymentpages"
+	"github.com/huysamen/paystack-go/api/paymentrequests"
+	"github.com/huysamen/paystack-go/api/plans"
+	"github.com/huysamen/paystack-go/api/products"
+	"github.com/huysamen/paystack-go/api/refunds"
+	"github.com/huysamen/paystack-go/api/settlements"
+	"github.com/huysamen/paystack-go/api/subaccounts"
+	"github.com/huysamen/paystack-go/api/subscriptions"
+	"github.com/huysamen/paystack-go/api/terminal"
+	"github.com/huysamen/paystack-go/api/transactions"
+	"github.com/huysamen/paystack-go/api/transactionsplits"
+	"github.com/huysamen/paystack-go/api/transferrecipients"
+	"github.com/huysamen/paystack-go/api/transfers"
+	"github.com/huysamen/paystack-go/api/transferscontrol"
+	"github.com/huysamen/paystack-go/api/verification"
+	"github.com/huysamen/paystack-go/api/virtualterminal"
+	"github.com/huysamen/paystack-go/api/webhook"
+	"github.com/huysamen/paystack-go/types/drift/drifttest"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "rewrite testdata/known_drift.txt with the drift currently found")
+
+var examplesDir = filepath.Join("..", "..", "..", "resources", "examples")
+
+// responseTypes maps "<package>/<fixture prefix>" to the response type the fixture is
+// decoded into. The longest matching prefix wins, so "customers/fetch_mandate" takes
+// precedence over "customers/fetch".
+var responseTypes = map[string]func() any{
+	"applepay/list_domains":                         func() any { return new(applepay.ListDomainsResponse) },
+	"applepay/register_domain":                      func() any { return new(applepay.RegisterDomainResponse) },
+	"applepay/unregister_domain":                    func() any { return new(applepay.UnregisterDomainResponse) },
+	"bulkcharges/fetch":                             func() any { return new(bulkcharges.FetchResponse) },
+	"bulkcharges/fetch_charges_in_batch":            func() any { return new(bulkcharges.FetchInBatchResponse) },
+	"bulkcharges/initiate":                          func() any { return new(bulkcharges.InitiateResponse) },
+	"bulkcharges/list":                              func() any { return new(bulkcharges.ListResponse) },
+	"bulkcharges/pause":                             func() any { return new(bulkcharges.PauseResponse) },
+	"bulkcharges/resume":                            func() any { return new(bulkcharges.ResumeResponse) },
+	"charge/check_pending":                          func() any { return new(charge.CheckPendingResponse) },
+	"charge/check_pensing":                          func() any { return new(charge.CheckPendingResponse) },
+	"charge/create":                                 func() any { return new(charge.CreateChargeResponse) },
+	"charge/submit_address":                         func() any { return new(charge.SubmitAddressResponse) },
+	"charge/submit_birthday":                        func() any { return new(charge.SubmitBirthdayResponse) },
+	"charge/submit_otp":                             func() any { return new(charge.SubmitOTPResponse) },
+	"charge/submit_phone":                           func() any { return new(charge.SubmitPhoneResponse) },
+	"charge/submit_pin":                             func() any { return new(charge.SubmitPINResponse) },
+	"customers/create":                              func() any { return new(customers.CreateResponse) },
+	"customers/deactivate_authorization":            func() any { return new(customers.DeactivateAuthorizationResponse) },
+	"customers/directdebit_activation_charge":       func() any { return new(customers.DirectDebitActivationChargeResponse) },
+	"customers/fetch":                               func() any { return new(customers.FetchCustomerResponse) },
+	"customers/fetch_mandate_authorizations":        func() any { return new(customers.FetchMandateAuthorizationsResponse) },
+	"customers/initialize_authorization":            func() any { return new(customers.InitializeAuthorizationResponse) },
+	"customers/initialize_direct_debit":             func() any { return new(customers.InitializeDirectDebitResponse) },
+	"customers/list":                                func() any { return new(customers.ListResponse) },
+	"customers/update":                              func() any { return new(customers.UpdateResponse) },
+	"customers/validate":                            func() any { return new(customers.CustomerValidateResponse) },
+	"customers/verify_authorization":                func() any { return new(customers.VerifyAuthorizationResponse) },
+	"customers/whitelist_blacklist":                 func() any { return new(customers.RiskActionResponse) },
+	"dedicatedvirtualaccounts/assign":               func() any { return new(dedicatedvirtualaccounts.AssignDedicatedVirtualAccountResponse) },
+	"dedicatedvirtualaccounts/create":               func() any { return new(dedicatedvirtualaccounts.CreateResponse) },
+	"dedicatedvirtualaccounts/deactivate":           func() any { return new(dedicatedvirtualaccounts.DeactivateResponse) },
+	"dedicatedvirtualaccounts/fetch":                func() any { return new(dedicatedvirtualaccounts.FetchResponse) },
+	"dedicatedvirtualaccounts/fetch_bank_providers": func() any { return new(dedicatedvirtualaccounts.FetchBankProvidersResponse) },
+	"dedicatedvirtualaccounts/list":                 func() any { return new(dedicatedvirtualaccounts.ListResponse) },
+	"dedicatedvirtualaccounts/remove_split":         func() any { return new(dedicatedvirtualaccounts.RemoveSplitResponse) },
+	"dedicatedvirtualaccounts/requery":              func() any { return new(dedicatedvirtualaccounts.RequeryResponse) },
+	"dedicatedvirtualaccounts/split_transaction":    func() any { return new(dedicatedvirtualaccounts.SplitTransactionResponse) },
+	"directdebit/list_mandate_authorizations":       func() any { return new(directdebit.ListMandateAuthorizationsResponse) },
+	"directdebit/trigger_activation_charge":         func() any { return new(directdebit.TriggerActivationChargeResponse) },
+	"disputes/add_evidence":                         func() any { return new(disputes.AddEvidenceResponse) },
+	"disputes/export":                               func() any { return new(disputes.ExportResponse) },
+	"disputes/fetch":                                func() any { return new(disputes.FetchResponse) },
+	"disputes/get_upload_url":                       func() any { return new(disputes.GetUploadURLResponse) },
+	"disputes/list":                                 func() any { return new(disputes.ListResponse) },
+	"disputes/list_transaction":                     func() any { return new(disputes.ListTransactionResponse) },
+	"disputes/resolve":                              func() any { return new(disputes.ResolveResponse) },
+	"disputes/update":                               func() any { return new(disputes.UpdateResponse) },
+	"integration/fetch":                             func() any { return new(integration.FetchTimeoutResponse) },
+	"integration/update":                            func() any { return new(integration.UpdateTimeoutResponse) },
+	"miscellaneous/list_banks":                      func() any { return new(miscellaneous.ListBanksResponse) },
+	"miscellaneous/list_countries":                  func() any { return new(miscellaneous.ListCountriesResponse) },
+	"miscellaneous/list_states":                     func() any { return new(miscellaneous.ListStatesResponse) },
+	"paymentpages/add_products":                     func() any { return new(paymentpages.AddProductsResponse) },
+	"paymentpages/check_slug":                       func() any { return new(paymentpages.CheckSlugAvailabilityResponse) },
+	"paymentpages/create":                           func() any { return new(paymentpages.CreateResponse) },
+	"paymentpages/fetch":                            func() any { return new(paymentpages.FetchResponse) },
+	"paymentpages/list":                             func() any { return new(paymentpages.ListResponse) },
+	"paymentpages/update":                           func() any { return new(paymentpages.UpdateResponse) },
+	"paymentrequests/archive":                       func() any { return new(paymentrequests.ArchiveResponse) },
+	"paymentrequests/create":                        func() any { return new(paymentrequests.CreateResponse) },
+	"paymentrequests/fetch":                         func() any { return new(paymentrequests.FetchResponse) },
+	"paymentrequests/finalize":                      func() any { return new(paymentrequests.FinalizeResponse) },
+	"paymentrequests/list":                          func() any { return new(paymentrequests.ListResponse) },
+	"paymentrequests/send_notification":             func() any { return new(paymentrequests.SendNotificationResponse) },
+	"paymentrequests/totals":                        func() any { return new(paymentrequests.TotalsResponse) },
+	"paymentrequests/update":                        func() any { return new(paymentrequests.UpdateResponse) },
+	"paymentrequests/verify":                        func() any { return new(paymentrequests.VerifyResponse) },
+	"plans/create":                                  func() any { return new(plans.CreateResponse) },
+	"plans/fetch":                                   func() any { return new(plans.FetchResponse) },
+	"plans/list":                                    func() any { return new(plans.ListResponse) },
+	"plans/update":                                  func() any { return new(plans.UpdateResponse) },
+	"products/create":                               func() any { return new(products.CreateResponse) },
+	"products/fetch":                                func() any { return new(products.FetchResponse) },
+	"products/list":                                 func() any { return new(products.ListResponse) },
+	"products/update":                               func() any { return new(products.UpdateResponse) },
+	"refunds/create":                                func() any { return new(refunds.CreateResponse) },
+	"refunds/fetch":                                 func() any { return new(refunds.FetchResponse) },
+	"refunds/list":                                  func() any { return new(refunds.ListResponse) },
+	"settlements/list":                              func() any { return new(settlements.ListResponse) },
+	"settlements/list_transactions":                 func() any { return new(settlements.ListTransactionsResponse) },
+	"subaccounts/create":                            func() any { return new(subaccounts.CreateResponse) },
+	"subaccounts/fetch":                             func() any { return new(subaccounts.FetchResponse) },
+	"subaccounts/list":                              func() any { return new(subaccounts.ListResponse) },
+	"subaccounts/update":                            func() any { return new(subaccounts.UpdateResponse) },
+	"subscriptions/create":                          func() any { return new(subscriptions.CreateResponse) },
+	"subscriptions/disable":                         func() any { return new(subscriptions.DisableResponse) },
+	"subscriptions/enable":                          func() any { return new(subscriptions.EnableResponse) },
+	"subscriptions/fetch":                           func() any { return new(subscriptions.FetchResponse) },
+	"subscriptions/generate_update_link":            func() any { return new(subscriptions.GenerateUpdateLinkResponse) },
+	"subscriptions/list":                            func() any { return new(subscriptions.ListResponse) },
+	"subscriptions/send_update_link":                func() any { return new(subscriptions.SendUpdateLinkResponse) },
+	"terminal/commission":                           func() any { return new(terminal.CommissionDeviceResponse) },
+	"terminal/decommission":                         func() any { return new(terminal.DecommissionDeviceResponse) },
+	"terminal/fetch":                                func() any { return new(terminal.FetchResponse) },
+	"terminal/fetch_event_status":                   func() any { return new(terminal.FetchEventStatusResponse) },
+	"terminal/fetch_status":                         func() any { return new(terminal.FetchTerminalStatusResponse) },
+	"terminal/list":                                 func() any { return new(terminal.ListResponse) },
+	"terminal/send_event":                           func() any { return new(terminal.SendEventResponse) },
+	"terminal/update":                               func() any { return new(terminal.UpdateResponse) },
+	"transactions/charge_authorization":             func() any { return new(transactions.ChargeAuthorizationResponse) },
+	"transactions/export":                           func() any { return new(transactions.ExportResponse) },
+	"transactions/fetch":                            func() any { return new(transactions.FetchResponse) },
+	"transactions/initialize":                       func() any { return new(transactions.InitializeResponse) },
+	"transactions/list":                             func() any { return new(transactions.ListResponse) },
+	"transactions/partial_debit":                    func() any { return new(transactions.PartialDebitResponse) },
+	"transactions/totals":                           func() any { return new(transactions.TotalsResponse) },
+	"transactions/verify":                           func() any { return new(transactions.VerifyResponse) },
+	"transactions/view_timeline":                    func() any { return new(transactions.TimelineResponse) },
+	"transactionsplits/add_update_subaccount":       func() any { return new(transactionsplits.AddSubaccountResponse) },
+	"transactionsplits/create":                      func() any { return new(transactionsplits.CreateResponse) },
+	"transactionsplits/fetch":                       func() any { return new(transactionsplits.FetchResponse) },
+	"transactionsplits/list":                        func() any { return new(transactionsplits.ListResponse) },
+	"transactionsplits/remove_subaccount":           func() any { return new(transactionsplits.RemoveSubaccountResponse) },
+	"transactionsplits/update":                      func() any { return new(transactionsplits.UpdateResponse) },
+	"transferrecipients/bulk_create":                func() any { return new(transferrecipients.BulkCreateResponse) },
+	"transferrecipients/create":                     func() any { return new(transferrecipients.CreateResponse) },
+	"transferrecipients/fetch":                      func() any { return new(transferrecipients.FetchResponse) },
+	"transferrecipients/list":                       func() any { return new(transferrecipients.ListResponse) },
+	"transfers/bulk":                                func() any { return new(transfers.BulkResponse) },
+	"transfers/fetch":                               func() any { return new(transfers.FetchResponse) },
+	"transfers/finalize":                            func() any { return new(transfers.FinalizeResponse) },
+	"transfers/initiate":                            func() any { return new(transfers.InitiateResponse) },
+	"transfers/list":                                func() any { return new(transfers.ListResponse) },
+	"transfers/verify":                              func() any { return new(transfers.VerifyResponse) },
+	"transferscontrol/check_balance":                func() any { return new(transferscontrol.CheckBalanceResponse) },
+	"transferscontrol/disable_otp":                  func() any { return new(transferscontrol.DisableOTPResponse) },
+	"transferscontrol/enable_otp":                   func() any { return new(transferscontrol.EnableOTPResponse) },
+	"transferscontrol/fetch_balance_ledger":         func() any { return new(transferscontrol.FetchBalanceLedgerResponse) },
+	"transferscontrol/finalize_otp":                 func() any { return new(transferscontrol.FinalizeDisableOTPResponse) },
+	"transferscontrol/resend_otp":                   func() any { return new(transferscontrol.ResendOTPResponse) },
+	"verification/resolve_account":                  func() any { return new(verification.ResolveAccountResponse) },
+	"verification/resolve_card_bin":                 func() any { return new(verification.ResolveCardBINResponse) },
+	"verification/validate_account":                 func() any { return new(verification.ValidateAccountResponse) },
+	"virtualterminal/add_split_code":                func() any { return new(virtualterminal.AddSplitCodeResponse) },
+	"virtualterminal/assign_destination":            func() any { return new(virtualterminal.AssignDestinationResponse) },
+	"virtualterminal/create":                        func() any { return new(virtualterminal.CreateResponse) },
+	"virtualterminal/deactivate":                    func() any { return new(virtualterminal.DeactivateResponse) },
+	"virtualterminal/fetch":                         func() any { return new(virtualterminal.FetchResponse) },
+	"virtualterminal/list":                          func() any { return new(virtualterminal.ListResponse) },
+	"virtualterminal/remove_split_code":             func() any { return new(virtualterminal.RemoveSplitCodeResponse) },
+	"virtualterminal/unassign_destination":          func() any { return new(virtualterminal.UnassignDestinationResponse) },
+	"virtualterminal/update":                        func() any { return new(virtualterminal.UpdateResponse) },
+}
+
+// webhookTypes maps webhook fixtures to the type their data field is decoded into
+var webhookTypes = map[string]func() any{
+	webhook.EventChargeSuccess:                 func() any { return new(webhook.ChargeSuccessEvent) },
+	webhook.EventChargeDisputeCreate:           func() any { return new(webhook.ChargeDisputeEvent) },
+	webhook.EventChargeDisputeRemind:           func() any { return new(webhook.ChargeDisputeEvent) },
+	webhook.EventChargeDisputeResolve:          func() any { return new(webhook.ChargeDisputeEvent) },
+	webhook.EventCustomerIdentificationFailed:  func() any { return new(webhook.CustomerIdentificationFailedEvent) },
+	webhook.EventCustomerIdentificationSuccess: func() any { return new(webhook.CustomerIdentificationSuccessEvent) },
+	webhook.EventDedicatedAccountAssignFailed:  func() any { return new(webhook.DedicatedAccountEvent) },
+	webhook.EventDedicatedAccountAssignSuccess: func() any { return new(webhook.DedicatedAccountEvent) },
+	webhook.EventInvoiceCreate:                 func() any { return new(webhook.InvoiceCreateEvent) },
+	webhook.EventInvoicePaymentFailed:          func() any { return new(webhook.InvoicePaymentFailedEvent) },
+	webhook.EventInvoiceUpdate:                 func() any { return new(webhook.InvoiceUpdateEvent) },
+	webhook.EventPaymentRequestPending:         func() any { return new(webhook.PaymentRequestEvent) },
+	webhook.EventPaymentRequestSuccess:         func() any { return new(webhook.PaymentRequestEvent) },
+	webhook.EventRefundFailed:                  func() any { return new(webhook.RefundFailedEvent) },
+	webhook.EventRefundPending:                 func() any { return new(webhook.RefundPendingEvent) },
+	webhook.EventRefundProcessed:               func() any { return new(webhook.RefundProcessedEvent) },
+	webhook.EventRefundProcessing:              func() any { return new(webhook.RefundPendingEvent) },
+	webhook.EventSubscriptionCreate:            func() any { return new(webhook.SubscriptionCreateEvent) },
+	webhook.EventSubscriptionDisable:           func() any { return new(webhook.SubscriptionDisableEvent) },
+	// The expiring cards payload is a bare array of entries
+	webhook.EventSubscriptionExpiringCards:       func() any { return new([]webhook.ExpiringCard) },
+	webhook.EventSubscriptionNotRenew:            func() any { return new(webhook.SubscriptionNotRenewEvent) },
+	webhook.EventTransferFailed:                  func() any { return new(webhook.TransferFailedEvent) },
+	webhook.EventTransferReversed:                func() any { return new(webhook.TransferReversedEvent) },
+	webhook.EventTransferSuccess:                 func() any { return new(webhook.TransferSuccessEvent) },
+	webhook.EventBankTransferRejected:            func() any { return new(webhook.BankTransferRejectedEvent) },
+	webhook.EventDirectDebitAuthorizationActive:  func() any { return new(webhook.DirectDebitAuthorizationEvent) },
+	webhook.EventDirectDebitAuthorizationCreated: func() any { return new(webhook.DirectDebitAuthorizationEvent) },
+	webhook.EventTransferApprovalRequired:        func() any { return new(webhook.TransferApprovalEvent) },
+}
+
+func responseTypeFor(name string) func() any {
+	best := ""
+	for prefix := range responseTypes {
+		if strings.HasPrefix(name, prefix) && len(prefix) > len(best) {
+			best = prefix
+		}
+	}
+
+	return responseTypes[best]
+}
+
+func TestFixtures_NoSchemaDrift(t *testing.T) {
+	baseline := drifttest.LoadBaseline(t, filepath.Join("testdata", "known_drift.txt"))
+
+	responses, err := filepath.Glob(filepath.Join(examplesDir, "responses", "*", "*.json"))
+	require.NoError(t, err)
+	require.NotEmpty(t, responses)
+
+	for _, p := range responses {
+		name := filepath.Base(filepath.Dir(p)) + "/" + strings.TrimSuffix(filepath.Base(p), ".json")
+
+		newResponse := responseTypeFor(name)
+		if newResponse == nil {
+			t.Errorf("%s: no response type registered for fixture", name)
+			continue
+		}
+
+		baseline.CheckFixture(t, name, p, newResponse())
+	}
+
+	events, err := filepath.Glob(filepath.Join(examplesDir, "webhook", "*.json"))
+	require.NoError(t, err)
+	require.NotEmpty(t, events)
+
+	for _, p := range events {
+		name := "webhook/" + strings.TrimSuffix(filepath.Base(p), ".json")
+
+		b, err := os.ReadFile(p)
+		require.NoError(t, err)
+
+		var event webhook.Event
+		require.NoError(t, json.Unmarshal(b, &event), name)
+
+		newEvent, ok := webhookTypes[event.Event]
+		if !ok {
+			t.Errorf("%s: no event type registered for %q", name, event.Event)
+			continue
+		}
+
+		baseline.CheckPayload(t, name, event.Data, newEvent())
+	}
+
+	baseline.Finish(t, *update)
+}
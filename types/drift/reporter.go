@@ -0,0 +1,77 @@
+package drift
+
+import (
+	"context"
+	"sync"
+)
+
+// Reporter receives a Report for every decoded response that contains drift
+type Reporter func(Report)
+
+type reporterKey struct{}
+
+// WithReporter returns a context that enables drift detection for requests made with it.
+// Every API response decoded under the context is checked against its response type and
+// reporter is called for each one that has drift. Detection is off when no reporter is set.
+func WithReporter(ctx context.Context, reporter Reporter) context.Context {
+	return context.WithValue(ctx, reporterKey{}, reporter)
+}
+
+// ReporterFrom returns the Reporter set on ctx, or nil if drift detection is off
+func ReporterFrom(ctx context.Context) Reporter {
+	if ctx == nil {
+		return nil
+	}
+
+	reporter, _ := ctx.Value(reporterKey{}).(Reporter)
+
+	return reporter
+}
+
+// Collector accumulates reports. It is safe for concurrent use.
+type Collector struct {
+	mu      sync.Mutex
+	reports []Report
+}
+
+// NewCollector creates an empty Collector
+func NewCollector() *Collector {
+	return &Collector{}
+}
+
+// Report records r. Pass c.Report to WithReporter to collect reports.
+func (c *Collector) Report(r Report) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.reports = append(c.reports, r)
+}
+
+// Reports returns a copy of the reports collected so far
+func (c *Collector) Reports() []Report {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]Report(nil), c.reports...)
+}
+
+// ByEndpoint groups the collected reports by endpoint
+func (c *Collector) ByEndpoint() map[string][]Report {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	grouped := make(map[string][]Report)
+	for _, r := range c.reports {
+		grouped[r.Endpoint] = append(grouped[r.Endpoint], r)
+	}
+
+	return grouped
+}
+
+// Reset discards all collected reports
+func (c *Collector) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.reports = nil
+}
@@ -1,8 +1,11 @@
 package types
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 )
 
 // NullMetadata represents arbitrary key-value pairs with nullability
@@ -85,6 +88,45 @@ func (m Metadata) MarshalJSON() ([]byte, error) {
 	return json.Marshal(m.Metadata)
 }
 
+// Scan implements sql.Scanner, reading metadata stored as JSON text. SQL NULL scans
+// to an invalid Metadata.
+func (m *Metadata) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*m = Metadata{}
+		return nil
+	case string:
+		return m.Scan([]byte(v))
+	case []byte:
+		if len(v) == 0 {
+			*m = Metadata{}
+			return nil
+		}
+		return m.UnmarshalJSON(v)
+	default:
+		return fmt.Errorf("cannot scan %T into Metadata", src)
+	}
+}
+
+// Value implements driver.Valuer, storing metadata as JSON text. Invalid metadata is
+// stored as SQL NULL.
+func (m Metadata) Value() (driver.Value, error) {
+	if !m.Valid {
+		return nil, nil
+	}
+
+	if m.Metadata == nil {
+		return "{}", nil
+	}
+
+	b, err := json.Marshal(m.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(b), nil
+}
+
 // IsEmpty returns true if metadata is nil or empty
 func (m Metadata) IsEmpty() bool {
 	return len(m.Metadata) == 0
@@ -94,3 +136,333 @@ func (m Metadata) IsEmpty() bool {
 func NewMetadata(md map[string]any) Metadata {
 	return Metadata{Metadata: md, Valid: true}
 }
+
+// Well-known metadata keys interpreted by Paystack
+const (
+	MetadataKeyCustomFields  = "custom_fields"
+	MetadataKeyCancelAction  = "cancel_action"
+	MetadataKeyReferrer      = "referrer"
+	MetadataKeyCustomFilters = "custom_filters"
+)
+
+// Limits applied by Validate. Paystack does not publish hard limits, these are
+// conservative bounds that keep requests well clear of payload size rejections.
+const (
+	MetadataMaxBytes = 64 * 1024
+	MetadataMaxDepth = 10
+)
+
+// NewMetadataFrom creates a valid Metadata from any value that marshals to a JSON
+// object, typically a struct with json tags
+func NewMetadataFrom(v any) (Metadata, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return Metadata{}, fmt.Errorf("cannot marshal %T into Metadata: %w", v, err)
+	}
+
+	var md map[string]any
+	if err := json.Unmarshal(b, &md); err != nil {
+		return Metadata{}, fmt.Errorf("cannot convert %T into Metadata: value is not a JSON object", v)
+	}
+	if md == nil {
+		return Metadata{}, fmt.Errorf("cannot convert %T into Metadata: value is null", v)
+	}
+
+	return NewMetadata(md), nil
+}
+
+// MetadataOf decodes the metadata into a value of type T.
+// See Metadata.Decode for how string-encoded JSON values are handled.
+func MetadataOf[T any](m Metadata) (T, error) {
+	var v T
+	err := m.Decode(&v)
+
+	return v, err
+}
+
+// Decode decodes the metadata into v, which must be a pointer, by round-tripping
+// through JSON. Values Paystack returns as string-encoded JSON (e.g. custom_fields
+// sent as a JSON string by a payment page) are expanded if v expects an object or
+// array in their place. Invalid metadata leaves v unchanged.
+func (m Metadata) Decode(v any) error {
+	if !m.Valid || m.Metadata == nil {
+		return nil
+	}
+
+	b, err := json.Marshal(m.Metadata)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(b, v); err == nil {
+		return nil
+	}
+
+	b, err = json.Marshal(expandEncodedJSON(m.Metadata))
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, v)
+}
+
+// Get returns the raw value stored under key
+func (m Metadata) Get(key string) (any, bool) {
+	if !m.Valid || m.Metadata == nil {
+		return nil, false
+	}
+
+	v, ok := m.Metadata[key]
+
+	return v, ok
+}
+
+// DecodeKey decodes the value stored under key into v, which must be a pointer.
+// A string holding a JSON object or array is decoded as that object or array.
+func (m Metadata) DecodeKey(key string, v any) error {
+	raw, ok := m.Get(key)
+	if !ok {
+		return fmt.Errorf("metadata key %q not found", key)
+	}
+
+	b, err := json.Marshal(expandEncodedJSON(raw))
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("cannot decode metadata key %q: %w", key, err)
+	}
+
+	return nil
+}
+
+// GetString returns the value stored under key as a string. Numbers and booleans
+// are formatted, other types are not converted.
+func (m Metadata) GetString(key string) (string, bool) {
+	raw, ok := m.Get(key)
+	if !ok {
+		return "", false
+	}
+
+	switch v := raw.(type) {
+	case string:
+		return v, true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case int:
+		return strconv.Itoa(v), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case bool:
+		return strconv.FormatBool(v), true
+	default:
+		return "", false
+	}
+}
+
+// GetInt returns the value stored under key as an integer. Numeric strings are parsed
+// and fractional values are truncated.
+func (m Metadata) GetInt(key string) (int64, bool) {
+	raw, ok := m.Get(key)
+	if !ok {
+		return 0, false
+	}
+
+	switch v := raw.(type) {
+	case float64:
+		return int64(v), true
+	case int:
+		return int64(v), true
+	case int64:
+		return v, true
+	case string:
+		if parsed, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return parsed, true
+		}
+		if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+			return int64(parsed), true
+		}
+	}
+
+	return 0, false
+}
+
+// GetBool returns the value stored under key as a boolean. The strings "true" and
+// "false" and the numbers 1 and 0 are accepted.
+func (m Metadata) GetBool(key string) (bool, bool) {
+	raw, ok := m.Get(key)
+	if !ok {
+		return false, false
+	}
+
+	switch v := raw.(type) {
+	case bool:
+		return v, true
+	case string:
+		if parsed, err := strconv.ParseBool(v); err == nil {
+			return parsed, true
+		}
+	case float64:
+		return v != 0, true
+	case int:
+		return v != 0, true
+	}
+
+	return false, false
+}
+
+// GetMetadata returns the object stored under key as Metadata. A string holding a
+// JSON object is accepted.
+func (m Metadata) GetMetadata(key string) (Metadata, bool) {
+	raw, ok := m.Get(key)
+	if !ok {
+		return Metadata{}, false
+	}
+
+	switch v := expandEncodedJSON(raw).(type) {
+	case map[string]any:
+		return NewMetadata(v), true
+	default:
+		return Metadata{}, false
+	}
+}
+
+// CustomFields returns the custom_fields entry, tolerating its string-encoded form
+func (m Metadata) CustomFields() ([]CustomField, error) {
+	if _, ok := m.Get(MetadataKeyCustomFields); !ok {
+		return nil, nil
+	}
+
+	var fields []CustomField
+	if err := m.DecodeKey(MetadataKeyCustomFields, &fields); err != nil {
+		return nil, err
+	}
+
+	return fields, nil
+}
+
+// CustomFilters returns the custom_filters entry, tolerating its string-encoded form
+func (m Metadata) CustomFilters() (*CustomFilters, error) {
+	if _, ok := m.Get(MetadataKeyCustomFilters); !ok {
+		return nil, nil
+	}
+
+	var filters CustomFilters
+	if err := m.DecodeKey(MetadataKeyCustomFilters, &filters); err != nil {
+		return nil, err
+	}
+
+	return &filters, nil
+}
+
+// CancelAction returns the cancel_action URL
+func (m Metadata) CancelAction() (string, bool) {
+	return m.GetString(MetadataKeyCancelAction)
+}
+
+// Referrer returns the referrer URL
+func (m Metadata) Referrer() (string, bool) {
+	return m.GetString(MetadataKeyReferrer)
+}
+
+// Validate checks the metadata against MetadataMaxBytes and MetadataMaxDepth and
+// verifies that custom_fields, if present, is well-formed
+func (m Metadata) Validate() error {
+	return m.ValidateLimits(MetadataMaxBytes, MetadataMaxDepth)
+}
+
+// ValidateLimits checks the encoded size and nesting depth of the metadata against
+// the given limits, and verifies that custom_fields, if present, is well-formed.
+// A limit of zero or less disables that check.
+func (m Metadata) ValidateLimits(maxBytes, maxDepth int) error {
+	if !m.Valid {
+		return nil
+	}
+
+	b, err := json.Marshal(m.Metadata)
+	if err != nil {
+		return fmt.Errorf("metadata is not JSON encodable: %w", err)
+	}
+
+	if maxBytes > 0 && len(b) > maxBytes {
+		return fmt.Errorf("metadata is %d bytes, exceeding the limit of %d", len(b), maxBytes)
+	}
+
+	if maxDepth > 0 {
+		var decoded any
+		if err := json.Unmarshal(b, &decoded); err != nil {
+			return err
+		}
+		if depth := jsonDepth(decoded); depth > maxDepth {
+			return fmt.Errorf("metadata is nested %d levels deep, exceeding the limit of %d", depth, maxDepth)
+		}
+	}
+
+	fields, err := m.CustomFields()
+	if err != nil {
+		return err
+	}
+	for i, f := range fields {
+		if f.DisplayName == "" || f.VariableName == "" {
+			return fmt.Errorf("custom_fields[%d] requires display_name and variable_name", i)
+		}
+	}
+
+	return nil
+}
+
+// expandEncodedJSON returns the decoded value of strings that hold a JSON object or
+// array, recursing into maps and slices. Other values are returned unchanged.
+func expandEncodedJSON(v any) any {
+	switch val := v.(type) {
+	case string:
+		trimmed := strings.TrimSpace(val)
+		if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+			var decoded any
+			if err := json.Unmarshal([]byte(trimmed), &decoded); err == nil {
+				return expandEncodedJSON(decoded)
+			}
+		}
+		return val
+	case map[string]any:
+		expanded := make(map[string]any, len(val))
+		for k, item := range val {
+			expanded[k] = expandEncodedJSON(item)
+		}
+		return expanded
+	case []any:
+		expanded := make([]any, len(val))
+		for i, item := range val {
+			expanded[i] = expandEncodedJSON(item)
+		}
+		return expanded
+	default:
+		return v
+	}
+}
+
+// jsonDepth returns the nesting depth of a decoded JSON value, counting the
+// outermost object or array as one
+func jsonDepth(v any) int {
+	deepest := 0
+
+	switch val := v.(type) {
+	case map[string]any:
+		for _, item := range val {
+			if d := jsonDepth(item); d > deepest {
+				deepest = d
+			}
+		}
+		return deepest + 1
+	case []any:
+		for _, item := range val {
+			if d := jsonDepth(item); d > deepest {
+				deepest = d
+			}
+		}
+		return deepest + 1
+	default:
+		return 0
+	}
+}
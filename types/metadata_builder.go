@@ -0,0 +1,76 @@
+package types
+
+// MetadataBuilder builds Metadata using Paystack's well-known keys alongside
+// arbitrary custom keys
+type MetadataBuilder struct {
+	md           map[string]any
+	customFields []CustomField
+}
+
+// NewMetadataBuilder creates an empty MetadataBuilder
+func NewMetadataBuilder() *MetadataBuilder {
+	return &MetadataBuilder{
+		md: make(map[string]any),
+	}
+}
+
+// CustomField appends an entry to custom_fields, shown on the dashboard and receipts
+func (b *MetadataBuilder) CustomField(displayName, variableName string, value any) *MetadataBuilder {
+	b.customFields = append(b.customFields, CustomField{
+		DisplayName:  displayName,
+		VariableName: variableName,
+		Value:        value,
+	})
+
+	return b
+}
+
+// CancelAction sets the URL the customer is redirected to when they cancel checkout
+func (b *MetadataBuilder) CancelAction(url string) *MetadataBuilder {
+	b.md[MetadataKeyCancelAction] = url
+
+	return b
+}
+
+// Referrer sets the URL of the page the payment was initiated from
+func (b *MetadataBuilder) Referrer(url string) *MetadataBuilder {
+	b.md[MetadataKeyReferrer] = url
+
+	return b
+}
+
+// CustomFilters restricts the payment options shown on checkout
+func (b *MetadataBuilder) CustomFilters(filters CustomFilters) *MetadataBuilder {
+	b.md[MetadataKeyCustomFilters] = filters
+
+	return b
+}
+
+// Set sets an arbitrary key. Setting a well-known key directly replaces any value
+// set through its dedicated method.
+func (b *MetadataBuilder) Set(key string, value any) *MetadataBuilder {
+	if key == MetadataKeyCustomFields {
+		b.customFields = nil
+	}
+	b.md[key] = value
+
+	return b
+}
+
+// Build returns the Metadata. Values are normalised through JSON so the result
+// matches what a response carrying the same metadata decodes to.
+func (b *MetadataBuilder) Build() Metadata {
+	md := make(map[string]any, len(b.md)+1)
+	for k, v := range b.md {
+		md[k] = v
+	}
+	if len(b.customFields) > 0 {
+		md[MetadataKeyCustomFields] = b.customFields
+	}
+
+	if normalised, err := NewMetadataFrom(md); err == nil {
+		return normalised
+	}
+
+	return NewMetadata(md)
+}
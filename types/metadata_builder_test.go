@@ -0,0 +1,60 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/huysamen/paystack-go/types/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetadataBuilder(t *testing.T) {
+	t.Run("builds well-known keys", func(t *testing.T) {
+		m := NewMetadataBuilder().
+			CustomField("Invoice ID", "invoice_id", "INV-1").
+			CustomField("Cart ID", "cart_id", 398).
+			CancelAction("https://example.com/cancel").
+			Referrer("https://example.com/checkout").
+			CustomFilters(CustomFilters{Recurring: true, Banks: []data.String{"057"}}).
+			Set("order_id", "ord_1").
+			Build()
+
+		require.True(t, m.Valid)
+		require.NoError(t, m.Validate())
+
+		b, err := json.Marshal(m)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"custom_fields": [
+				{"display_name": "Invoice ID", "variable_name": "invoice_id", "value": "INV-1"},
+				{"display_name": "Cart ID", "variable_name": "cart_id", "value": 398}
+			],
+			"cancel_action": "https://example.com/cancel",
+			"referrer": "https://example.com/checkout",
+			"custom_filters": {"recurring": true, "banks": ["057"]},
+			"order_id": "ord_1"
+		}`, string(b))
+
+		fields, err := m.CustomFields()
+		require.NoError(t, err)
+		assert.Len(t, fields, 2)
+	})
+
+	t.Run("builds empty metadata", func(t *testing.T) {
+		m := NewMetadataBuilder().Build()
+		assert.True(t, m.Valid)
+		assert.True(t, m.IsEmpty())
+	})
+
+	t.Run("set replaces custom fields", func(t *testing.T) {
+		m := NewMetadataBuilder().
+			CustomField("Invoice ID", "invoice_id", "INV-1").
+			Set(MetadataKeyCustomFields, []CustomField{{DisplayName: "Cart", VariableName: "cart"}}).
+			Build()
+
+		fields, err := m.CustomFields()
+		require.NoError(t, err)
+		assert.Equal(t, []CustomField{{DisplayName: "Cart", VariableName: "cart"}}, fields)
+	})
+}
@@ -1,6 +1,7 @@
 package types
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"testing"
 
@@ -344,3 +345,334 @@ func TestMetadata_EdgeCases(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestMetadata_Decode(t *testing.T) {
+	type orderMetadata struct {
+		OrderID  string        `json:"order_id"`
+		Quantity int           `json:"quantity"`
+		Fields   []CustomField `json:"custom_fields"`
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected orderMetadata
+		wantErr  bool
+	}{
+		{
+			name:  "decodes object",
+			input: `{"order_id":"ord_1","quantity":2,"custom_fields":[{"display_name":"Cart","variable_name":"cart","value":"abc"}]}`,
+			expected: orderMetadata{
+				OrderID:  "ord_1",
+				Quantity: 2,
+				Fields:   []CustomField{{DisplayName: "Cart", VariableName: "cart", Value: "abc"}},
+			},
+		},
+		{
+			name:  "decodes string-encoded metadata",
+			input: `"{\"order_id\":\"ord_2\",\"quantity\":1}"`,
+			expected: orderMetadata{
+				OrderID:  "ord_2",
+				Quantity: 1,
+			},
+		},
+		{
+			name:  "expands string-encoded nested values",
+			input: `{"order_id":"ord_3","custom_fields":"[{\"display_name\":\"Cart\",\"variable_name\":\"cart\"}]"}`,
+			expected: orderMetadata{
+				OrderID: "ord_3",
+				Fields:  []CustomField{{DisplayName: "Cart", VariableName: "cart"}},
+			},
+		},
+		{
+			name:     "leaves target unchanged for null metadata",
+			input:    `null`,
+			expected: orderMetadata{},
+		},
+		{
+			name:    "returns error for mismatched types",
+			input:   `{"quantity":"many"}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var m Metadata
+			require.NoError(t, json.Unmarshal([]byte(tt.input), &m))
+
+			got, err := MetadataOf[orderMetadata](m)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+
+			var decoded orderMetadata
+			require.NoError(t, m.Decode(&decoded))
+			assert.Equal(t, tt.expected, decoded)
+		})
+	}
+}
+
+func TestNewMetadataFrom(t *testing.T) {
+	type orderMetadata struct {
+		OrderID  string `json:"order_id"`
+		Quantity int    `json:"quantity,omitempty"`
+	}
+
+	t.Run("converts struct", func(t *testing.T) {
+		m, err := NewMetadataFrom(orderMetadata{OrderID: "ord_1", Quantity: 3})
+		require.NoError(t, err)
+		assert.True(t, m.Valid)
+		assert.Equal(t, map[string]any{"order_id": "ord_1", "quantity": float64(3)}, m.Metadata)
+
+		back, err := MetadataOf[orderMetadata](m)
+		require.NoError(t, err)
+		assert.Equal(t, orderMetadata{OrderID: "ord_1", Quantity: 3}, back)
+	})
+
+	t.Run("rejects non-object values", func(t *testing.T) {
+		_, err := NewMetadataFrom([]string{"a"})
+		assert.Error(t, err)
+
+		_, err = NewMetadataFrom(nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestMetadata_Accessors(t *testing.T) {
+	input := `{
+		"name": "Ada",
+		"count": "42",
+		"total": 12.5,
+		"enabled": "true",
+		"flag": 1,
+		"nested": "{\"a\":1}",
+		"object": {"b": 2},
+		"list": [1, 2],
+		"cancel_action": "https://example.com/cancel",
+		"referrer": "https://example.com/checkout",
+		"custom_fields": "[{\"display_name\":\"Invoice\",\"variable_name\":\"invoice\",\"value\":\"INV-1\"}]",
+		"custom_filters": {"recurring": true, "banks": ["057"]}
+	}`
+
+	var m Metadata
+	require.NoError(t, json.Unmarshal([]byte(input), &m))
+
+	s, ok := m.GetString("name")
+	assert.True(t, ok)
+	assert.Equal(t, "Ada", s)
+
+	s, ok = m.GetString("total")
+	assert.True(t, ok)
+	assert.Equal(t, "12.5", s)
+
+	_, ok = m.GetString("object")
+	assert.False(t, ok)
+
+	i, ok := m.GetInt("count")
+	assert.True(t, ok)
+	assert.Equal(t, int64(42), i)
+
+	i, ok = m.GetInt("total")
+	assert.True(t, ok)
+	assert.Equal(t, int64(12), i)
+
+	_, ok = m.GetInt("name")
+	assert.False(t, ok)
+
+	b, ok := m.GetBool("enabled")
+	assert.True(t, ok)
+	assert.True(t, b)
+
+	b, ok = m.GetBool("flag")
+	assert.True(t, ok)
+	assert.True(t, b)
+
+	nested, ok := m.GetMetadata("nested")
+	assert.True(t, ok)
+	assert.Equal(t, float64(1), nested.Metadata["a"])
+
+	object, ok := m.GetMetadata("object")
+	assert.True(t, ok)
+	assert.Equal(t, float64(2), object.Metadata["b"])
+
+	_, ok = m.GetMetadata("list")
+	assert.False(t, ok)
+
+	var list []int
+	require.NoError(t, m.DecodeKey("list", &list))
+	assert.Equal(t, []int{1, 2}, list)
+	assert.Error(t, m.DecodeKey("missing", &list))
+
+	cancel, ok := m.CancelAction()
+	assert.True(t, ok)
+	assert.Equal(t, "https://example.com/cancel", cancel)
+
+	referrer, ok := m.Referrer()
+	assert.True(t, ok)
+	assert.Equal(t, "https://example.com/checkout", referrer)
+
+	fields, err := m.CustomFields()
+	require.NoError(t, err)
+	assert.Equal(t, []CustomField{{DisplayName: "Invoice", VariableName: "invoice", Value: "INV-1"}}, fields)
+
+	filters, err := m.CustomFilters()
+	require.NoError(t, err)
+	require.NotNil(t, filters)
+	assert.True(t, filters.Recurring.Bool())
+	assert.Equal(t, "057", filters.Banks[0].String())
+
+	_, ok = Metadata{}.Get("name")
+	assert.False(t, ok)
+}
+
+func TestMetadata_Validate(t *testing.T) {
+	deep := map[string]any{"v": 1}
+	for i := 0; i < MetadataMaxDepth; i++ {
+		deep = map[string]any{"n": deep}
+	}
+
+	tests := []struct {
+		name     string
+		metadata Metadata
+		wantErr  bool
+	}{
+		{
+			name:     "accepts invalid metadata",
+			metadata: Metadata{},
+		},
+		{
+			name:     "accepts small metadata",
+			metadata: NewMetadata(map[string]any{"order_id": "ord_1"}),
+		},
+		{
+			name:     "rejects oversized metadata",
+			metadata: NewMetadata(map[string]any{"blob": string(make([]byte, MetadataMaxBytes))}),
+			wantErr:  true,
+		},
+		{
+			name:     "rejects deeply nested metadata",
+			metadata: NewMetadata(deep),
+			wantErr:  true,
+		},
+		{
+			name: "rejects custom fields without names",
+			metadata: NewMetadata(map[string]any{
+				"custom_fields": []any{map[string]any{"value": "x"}},
+			}),
+			wantErr: true,
+		},
+		{
+			name:     "rejects values that cannot be encoded",
+			metadata: NewMetadata(map[string]any{"fn": func() {}}),
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.metadata.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	assert.NoError(t, NewMetadata(deep).ValidateLimits(0, 0))
+}
+
+func TestMetadata_Scan(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      any
+		expected Metadata
+		wantErr  bool
+	}{
+		{
+			name:     "scans JSON object bytes",
+			src:      []byte(`{"order_id":"123"}`),
+			expected: Metadata{Metadata: map[string]any{"order_id": "123"}, Valid: true},
+		},
+		{
+			name:     "scans JSON object string",
+			src:      `{"count":2}`,
+			expected: Metadata{Metadata: map[string]any{"count": float64(2)}, Valid: true},
+		},
+		{
+			name:     "scans NULL as invalid",
+			src:      nil,
+			expected: Metadata{},
+		},
+		{
+			name:     "scans empty string as invalid",
+			src:      "",
+			expected: Metadata{},
+		},
+		{
+			name:    "returns error for unsupported type",
+			src:     int64(1),
+			wantErr: true,
+		},
+		{
+			name:    "returns error for malformed JSON",
+			src:     []byte(`{"order_id"`),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var m Metadata
+			err := m.Scan(tt.src)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected.Valid, m.Valid)
+			if tt.expected.Valid {
+				assert.Equal(t, tt.expected.Metadata, m.Metadata)
+			}
+		})
+	}
+}
+
+func TestMetadata_Value(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    Metadata
+		expected driver.Value
+	}{
+		{
+			name:     "returns JSON text when valid",
+			input:    NewMetadata(map[string]any{"order_id": "123"}),
+			expected: `{"order_id":"123"}`,
+		},
+		{
+			name:     "returns empty object for valid nil map",
+			input:    Metadata{Valid: true},
+			expected: `{}`,
+		},
+		{
+			name:     "returns NULL when invalid",
+			input:    Metadata{Metadata: map[string]any{"a": 1}},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.input.Value()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
@@ -10,6 +10,7 @@ type CustomFilters struct {
 	Recurring                     data.Bool     `json:"recurring,omitempty"`
 	Banks                         []data.String `json:"banks,omitempty"`
 	CardBrands                    []data.String `json:"card_brands,omitempty"`
+	SupportedBankProviders        []data.String `json:"supported_bank_providers,omitempty"`
 	SupportedMobileMoneyProviders []enums.MoMo  `json:"supported_mobile_money_providers,omitempty"`
 }
 
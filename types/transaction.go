@@ -7,44 +7,46 @@ import (
 
 // Transaction represents a Paystack transaction with comprehensive field coverage
 type Transaction struct {
-	ID                 data.Uint           `json:"id"`
-	Domain             data.String         `json:"domain"`
-	Status             data.String         `json:"status"`
-	Reference          data.String         `json:"reference"`
-	Amount             data.Int            `json:"amount"`
-	Message            data.NullString     `json:"message"`
-	GatewayResponse    data.String         `json:"gateway_response"`
-	PaidAt             data.NullTime       `json:"paid_at,omitempty"`
-	CreatedAt          data.Time           `json:"created_at"`
-	Channel            enums.Channel       `json:"channel"`
-	Currency           enums.Currency      `json:"currency"`
-	IPAddress          data.String         `json:"ip_address"`
-	Metadata           Metadata            `json:"metadata"`
-	Log                *TransactionLog     `json:"log,omitempty"`
-	Fees               data.Int            `json:"fees"`
-	FeesSplit          *FeesSplit          `json:"fees_split,omitempty"`
-	Customer           Customer            `json:"customer"`
-	Authorization      Authorization       `json:"authorization"`
-	Plan               *Plan               `json:"plan,omitempty"`
-	Split              *TransactionSplit   `json:"split,omitempty"`
-	Subaccount         *Subaccount         `json:"subaccount,omitempty"`
-	OrderID            data.NullString     `json:"order_id,omitempty"`
-	RequestedAmount    data.Int            `json:"requested_amount"`
-	Source             *TransactionSource  `json:"source,omitempty"`
-	Connect            *ConnectData        `json:"connect,omitempty"`
-	POSTransactionData *POSTransactionData `json:"pos_transaction_data,omitempty"`
+	ID                 data.Uint               `json:"id"`
+	Domain             data.String             `json:"domain"`
+	Status             enums.TransactionStatus `json:"status"`
+	Reference          data.String             `json:"reference"`
+	Amount             data.Int                `json:"amount"`
+	Message            data.NullString         `json:"message"`
+	GatewayResponse    data.String             `json:"gateway_response"`
+	PaidAt             data.NullTime           `json:"paid_at,omitempty"`
+	CreatedAt          data.Time               `json:"created_at"`
+	Channel            enums.Channel           `json:"channel"`
+	Currency           enums.Currency          `json:"currency"`
+	IPAddress          data.String             `json:"ip_address"`
+	Metadata           Metadata                `json:"metadata"`
+	Log                *TransactionLog         `json:"log,omitempty"`
+	Fees               data.Int                `json:"fees"`
+	FeesSplit          *FeesSplit              `json:"fees_split,omitempty"`
+	Customer           Customer                `json:"customer"`
+	Authorization      Authorization           `json:"authorization"`
+	Plan               *Plan                   `json:"plan,omitempty"`
+	Split              *TransactionSplit       `json:"split,omitempty"`
+	Subaccount         *Subaccount             `json:"subaccount,omitempty"`
+	OrderID            data.NullString         `json:"order_id,omitempty"`
+	RequestedAmount    data.Int                `json:"requested_amount"`
+	Source             *TransactionSource      `json:"source,omitempty"`
+	Connect            *ConnectData            `json:"connect,omitempty"`
+	POSTransactionData *POSTransactionData     `json:"pos_transaction_data,omitempty"`
 }
 
 // TransactionLog represents the transaction processing log
 type TransactionLog struct {
-	StartTime data.Int              `json:"start_time"`
-	TimeSpent data.Int              `json:"time_spent"`
-	Attempts  data.Int              `json:"attempts"`
-	Errors    data.Int              `json:"errors"`
-	Success   data.Bool             `json:"success"`
-	Mobile    data.Bool             `json:"mobile"`
-	Input     any                   `json:"input"` // todo: this comes through as multiple data types
-	History   []TransactionLogEntry `json:"history"`
+	StartTime      data.Int              `json:"start_time"`
+	TimeSpent      data.Int              `json:"time_spent"`
+	Attempts       data.Int              `json:"attempts"`
+	Errors         data.Int              `json:"errors"`
+	Success        data.Bool             `json:"success"`
+	Mobile         data.Bool             `json:"mobile"`
+	Channel        data.NullString       `json:"channel"`
+	Authentication data.NullString       `json:"authentication"`
+	Input          any                   `json:"input"` // todo: this comes through as multiple data types
+	History        []TransactionLogEntry `json:"history"`
 }
 
 // TransactionLogEntry represents an entry in the transaction log
@@ -69,6 +71,13 @@ type FeesSplitParams struct {
 	PercentageCharge  data.String `json:"percentage_charge"`
 }
 
+// FeeBreakdown represents one component of the fees charged on a transaction
+type FeeBreakdown struct {
+	Amount  data.Int    `json:"amount"`
+	Formula Metadata    `json:"formula"`
+	Type    data.String `json:"type"`
+}
+
 // TransactionSource represents the source of a transaction
 type TransactionSource struct {
 	Source     data.String     `json:"source"`
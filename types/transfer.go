@@ -7,20 +7,20 @@ import (
 
 // Transfer represents a Paystack transfer
 type Transfer struct {
-	ID            data.Int        `json:"id"`
-	Integration   data.Int        `json:"integration"`
-	Domain        data.String     `json:"domain"`
-	Amount        data.Int        `json:"amount"`
-	Currency      enums.Currency  `json:"currency"`
-	Source        data.String     `json:"source"`
-	SourceDetails Metadata        `json:"source_details"`
-	Reason        data.String     `json:"reason"`
-	Status        data.String     `json:"status"`
-	Failures      Metadata        `json:"failures"`
-	TransferCode  data.String     `json:"transfer_code"`
-	TitanCode     data.NullString `json:"titan_code"`
-	TransferredAt data.NullTime   `json:"transferred_at,omitempty"`
-	Reference     data.String     `json:"reference"`
+	ID            data.Int             `json:"id"`
+	Integration   data.Int             `json:"integration"`
+	Domain        data.String          `json:"domain"`
+	Amount        data.Int             `json:"amount"`
+	Currency      enums.Currency       `json:"currency"`
+	Source        data.String          `json:"source"`
+	SourceDetails Metadata             `json:"source_details"`
+	Reason        data.String          `json:"reason"`
+	Status        enums.TransferStatus `json:"status"`
+	Failures      Metadata             `json:"failures"`
+	TransferCode  data.String          `json:"transfer_code"`
+	TitanCode     data.NullString      `json:"titan_code"`
+	TransferredAt data.NullTime        `json:"transferred_at,omitempty"`
+	Reference     data.String          `json:"reference"`
 	// Standard transfer fetch yields full object
 	Recipient Recipient `json:"recipient"`
 	CreatedAt data.Time `json:"createdAt"`